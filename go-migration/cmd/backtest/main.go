@@ -10,14 +10,14 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/backtest"
+	"github.com/kwanRoshi/B/go-migration/internal/config"
 	"github.com/kwanRoshi/B/go-migration/internal/market"
 	"github.com/kwanRoshi/B/go-migration/internal/market/solana"
 	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 func main() {
@@ -26,6 +26,10 @@ func main() {
 	symbol := flag.String("symbol", "BTCUSDT", "trading symbol")
 	startDate := flag.String("start", "", "start date (YYYY-MM-DD)")
 	endDate := flag.String("end", "", "end date (YYYY-MM-DD)")
+	parquetDir := flag.String("parquet", "", "directory to export trades and equity curve as Parquet (disabled if empty)")
+	source := flag.String("source", "csv", "historical data source (csv, json, postgres)")
+	dataPath := flag.String("data", "", "NDJSON file for the json source (defaults to data/<symbol>.ndjson)")
+	sizing := flag.String("sizing", "fixed_fractional", "position sizing method (fixed_fractional, kelly, volatility_target)")
 	flag.Parse()
 
 	// Load configuration
@@ -33,6 +37,9 @@ func main() {
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file: %s", err)
 	}
+	if err := config.Validate(viper.GetViper()); err != nil {
+		log.Fatalf("Error in config file %s: %s", *configFile, err)
+	}
 
 	// Initialize logger
 	logger, err := zap.NewProduction()
@@ -65,41 +72,43 @@ func main() {
 		cancel()
 	}()
 
-	// Connect to MongoDB
-	mongoCtx, mongoCancel := context.WithTimeout(ctx, 10*time.Second)
-	defer mongoCancel()
-
-	mongoURI := viper.GetString("database.mongodb.uri")
-	mongoClient, err := mongo.Connect(mongoCtx, options.Client().ApplyURI(mongoURI))
+	// Initialize storage
+	storage, err := backtest.NewMongoStorage(backtest.MongoConfig{
+		URI:      viper.GetString("database.mongodb.uri"),
+		Database: viper.GetString("database.mongodb.database"),
+	}, logger)
 	if err != nil {
 		logger.Fatal("Failed to connect to MongoDB", zap.Error(err))
 	}
-	defer mongoClient.Disconnect(ctx)
-
-	// Initialize storage
-	database := viper.GetString("database.mongodb.database")
-	storage := backtest.NewMongoStorage(mongoClient, database)
+	defer storage.Close(context.Background())
 
 	// Initialize market data provider
 	solanaConfig := solana.Config{
-		BaseURL:      viper.GetString("market.providers.solana.base_url"),
-		WebSocketURL: viper.GetString("market.providers.solana.ws_url"),
-		DexSources:   viper.GetStringSlice("market.providers.solana.dex_sources"),
-		TimeoutSec:   int(viper.GetDuration("market.providers.solana.timeout").Seconds()),
+		BaseURL:            viper.GetString("market.providers.solana.base_url"),
+		WebSocketURL:       viper.GetString("market.providers.solana.ws_url"),
+		DexSources:         viper.GetStringSlice("market.providers.solana.dex_sources"),
+		TimeoutSec:         int(viper.GetDuration("market.providers.solana.timeout").Seconds()),
+		MinLiquidity:       viper.GetFloat64("market.providers.solana.min_liquidity"),
+		PriceSlippageLimit: viper.GetFloat64("market.providers.solana.price_slippage_limit"),
 	}
 	solanaProvider := solana.NewProvider(solanaConfig, logger)
-	_ = market.NewHandler(solanaProvider, logger) // Create handler but don't use it in backtest
+	_ = market.NewHandler([]types.MarketDataProvider{solanaProvider}, logger) // Create handler but don't use it in backtest
 
 	// Initialize pricing engine
 	pricingConfig := pricing.Config{
 		UpdateInterval: viper.GetDuration("pricing.engine.update_interval"),
 		HistorySize:   viper.GetInt("pricing.engine.history_size"),
 		Indicators:    viper.GetStringSlice("pricing.engine.indicators"),
+		MaxSymbols:    viper.GetInt("pricing.engine.max_symbols"),
 		SignalParams: pricing.SignalParams{
 			MinConfidence:  viper.GetFloat64("pricing.engine.min_confidence"),
 			MaxVolatility:  viper.GetFloat64("pricing.engine.max_volatility"),
+			MinAgreement:   viper.GetInt("pricing.engine.min_agreement"),
+			Cooldown:       viper.GetDuration("pricing.engine.cooldown"),
 		},
 	}
+	pricingConfig.SignalParams.TimeRange.Start = viper.GetString("pricing.engine.time_range.start")
+	pricingConfig.SignalParams.TimeRange.End = viper.GetString("pricing.engine.time_range.end")
 	pricingEngine := pricing.NewEngine(pricingConfig, logger)
 
 	// Initialize backtest engine
@@ -109,9 +118,11 @@ func main() {
 		InitialBalance: viper.GetFloat64("trading.risk.max_position_size"),
 		Commission:     viper.GetFloat64("trading.order.commission"),
 		Slippage:       viper.GetFloat64("trading.order.slippage"),
-		DataSource:     "csv",
+		DataSource:     *source,
+		DataPath:       *dataPath,
 		Symbol:         *symbol,
 		Interval:       viper.GetDuration("market.handler.update_interval"),
+		Sizing:         backtest.SizingConfig{Method: backtest.SizingMethod(*sizing)},
 	}
 
 	backtestEngine := backtest.NewEngine(backtestConfig, logger, pricingEngine, storage)
@@ -133,12 +144,25 @@ func main() {
 		zap.Float64("win_rate", result.WinRate),
 		zap.Float64("profit_factor", result.ProfitFactor),
 		zap.Float64("sharpe_ratio", result.SharpeRatio),
+		zap.Float64("sortino_ratio", result.SortinoRatio),
 		zap.Float64("max_drawdown", result.MaxDrawdown),
 		zap.Float64("total_return", result.TotalReturn),
-		zap.Float64("annualized_return", result.AnnualizedReturn))
+		zap.Float64("annualized_return", result.AnnualizedReturn),
+		zap.Float64("expectancy_r", result.RStats.Expectancy),
+		zap.Any("r_distribution", result.RStats.Distribution),
+		zap.Float64("turnover", result.Turnover.Turnover),
+		zap.Float64("fee_drag", result.Turnover.FeeDrag))
 
 	// Save results
 	if err := storage.SaveResult(ctx, result); err != nil {
 		logger.Error("Failed to save results", zap.Error(err))
 	}
+
+	if *parquetDir != "" {
+		if err := backtest.ExportParquet(result, *parquetDir); err != nil {
+			logger.Error("Failed to export Parquet", zap.Error(err))
+		} else {
+			logger.Info("Exported Parquet", zap.String("dir", *parquetDir))
+		}
+	}
 }
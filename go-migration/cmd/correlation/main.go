@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/backtest"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func main() {
+	// Parse command line flags
+	var (
+		symbols = flag.String("symbols", "", "Comma-separated list of symbols (e.g., SOL/USDC,BONK/SOL)")
+		format  = flag.String("format", "json", "Output format (json/csv)")
+		output  = flag.String("output", "", "Output file (defaults to stdout)")
+	)
+	flag.Parse()
+
+	if *symbols == "" {
+		fmt.Println("At least one symbol is required")
+		os.Exit(1)
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Printf("Invalid format: %s. Must be 'json' or 'csv'\n", *format)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	// Load price history for every symbol from its CSV feed
+	prices := make(map[string][]*types.PriceLevel)
+	for _, symbol := range strings.Split(*symbols, ",") {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			continue
+		}
+
+		feed, err := backtest.NewCSVDataFeed(symbol)
+		if err != nil {
+			logger.Fatal("Failed to create CSV data feed",
+				zap.Error(err),
+				zap.String("symbol", symbol),
+			)
+		}
+
+		for feed.Next() {
+			current := feed.Current()
+			prices[symbol] = append(prices[symbol], &types.PriceLevel{
+				Symbol:    symbol,
+				Price:     current.Price,
+				Volume:    current.Volume,
+				Timestamp: current.Timestamp,
+			})
+		}
+		feed.Close()
+	}
+
+	matrix := analysis.CorrelationMatrix(analysis.AlignReturns(prices))
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			logger.Fatal("Failed to create output file",
+				zap.Error(err),
+				zap.String("output", *output),
+			)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		err = analysis.WriteCorrelationCSV(w, matrix)
+	} else {
+		err = analysis.WriteCorrelationJSON(w, matrix)
+	}
+	if err != nil {
+		logger.Fatal("Failed to export correlation matrix", zap.Error(err))
+	}
+}
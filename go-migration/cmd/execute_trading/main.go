@@ -10,8 +10,10 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/kwanRoshi/B/go-migration/internal/config"
 	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
 	"github.com/kwanRoshi/B/go-migration/internal/metrics"
 	"github.com/kwanRoshi/B/go-migration/internal/monitoring"
@@ -23,11 +25,20 @@ import (
 )
 
 func main() {
+	configFile := flag.String("config", "configs/config.yaml", "path to config file")
+	autoClose := flag.Bool("auto-close", true, "sell positions in tokens whose feed goes stale")
+	autoCloseTimeout := flag.Duration("auto-close-timeout", 30*time.Second, "bound on each stale position close attempt")
 	flag.Parse()
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
+	v := viper.New()
+	v.SetConfigFile(*configFile)
+	if err := v.ReadInConfig(); err != nil {
+		logger.Fatal("Failed to read config file", zap.String("path", *configFile), zap.Error(err))
+	}
+
 	apiKey := os.Getenv("PUMP_API_KEY")
 	if apiKey == "" {
 		logger.Fatal("PUMP_API_KEY environment variable not set")
@@ -57,42 +68,45 @@ func main() {
 		logger.Fatal("Failed to start pump monitor", zap.Error(err))
 	}
 
-	riskLimits := risk.Limits{
-		MaxPositionSize:  decimal.NewFromFloat(1000.0),
-		MaxDrawdown:      decimal.NewFromFloat(0.15),
-		MaxDailyLoss:     decimal.NewFromFloat(500.0),
-		MaxLeverage:      decimal.NewFromFloat(1.0),
-		MinMarginLevel:   decimal.NewFromFloat(1.5),
-		MaxConcentration: decimal.NewFromFloat(0.2),
+	riskLimits, err := config.RiskLimits(v)
+	if err != nil {
+		logger.Fatal("Invalid risk limits", zap.Error(err))
 	}
 	riskMgr := risk.NewManager(riskLimits, logger)
 
+	// Beta, regime and cluster checks regress on pump.fun price history
+	returns, err := config.ReturnsSource(v, pumpProvider)
+	if err != nil {
+		logger.Fatal("Invalid risk returns", zap.Error(err))
+	}
+	riskMgr.SetReturnsSource(returns)
+
 	pumpConfig := &types.PumpTradingConfig{
-		MaxMarketCap: decimal.NewFromFloat(30000.0),
+		MaxMarketCap: pumpProvider.MaxMarketCap(),
 		MinVolume:    decimal.NewFromFloat(1000.0),
 		WebSocket:    wsConfig,
-		Risk: struct {
-			MaxPositionSize   decimal.Decimal   `yaml:"max_position_size"`
-			MinPositionSize   decimal.Decimal   `yaml:"min_position_size"`
-			StopLossPercent   decimal.Decimal   `yaml:"stop_loss_percent"`
-			TakeProfitLevels  []decimal.Decimal `yaml:"take_profit_levels"`
-		}{
-			MaxPositionSize:  decimal.NewFromFloat(1000.0),
-			MinPositionSize:  decimal.NewFromFloat(100.0),
-			StopLossPercent:  decimal.NewFromFloat(15.0),
-			TakeProfitLevels: []decimal.Decimal{
-				decimal.NewFromFloat(2.0),
-				decimal.NewFromFloat(3.0),
-				decimal.NewFromFloat(5.0),
-			},
-		},
 	}
+	if v.IsSet("market.providers.pump.max_slippage") {
+		pumpConfig.MaxSlippage = decimal.NewFromFloat(v.GetFloat64("market.providers.pump.max_slippage"))
+	}
+	pumpConfig.Risk.MaxPositionSize = decimal.NewFromFloat(1000.0)
+	pumpConfig.Risk.MinPositionSize = decimal.NewFromFloat(100.0)
+	pumpConfig.Risk.StopLossPercent = decimal.NewFromFloat(15.0)
+	pumpConfig.Risk.TakeProfitLevels = []decimal.Decimal{
+		decimal.NewFromFloat(2.0),
+		decimal.NewFromFloat(3.0),
+		decimal.NewFromFloat(5.0),
+	}
+
+	stopLoss, err := config.NormalizeStopLoss("risk.stop_loss_percent", pumpConfig.Risk.StopLossPercent, logger)
+	if err != nil {
+		logger.Fatal("Invalid stop loss", zap.Error(err))
+	}
+	pumpConfig.Risk.StopLossPercent = stopLoss
 
 	tradingConfig := trading.Config{
-		Commission:     0.001,
-		MinOrderSize:   0.1,
-		RiskMultiplier: 1.0,
-		MaxDrawdown:    0.15,
+		Commission:   0.001,
+		MinOrderSize: 0.1,
 	}
 
 	storage := storage.NewMemoryStorage()
@@ -104,7 +118,14 @@ func main() {
 	}
 	defer pumpExecutor.Stop()
 
+	// Sell out of tokens whose feed dies instead of holding a delisted asset
+	if err := monitor.SetAutoClose(monitoring.AutoCloseConfig{Enabled: *autoClose, Timeout: *autoCloseTimeout}, pumpExecutor); err != nil {
+		logger.Fatal("Failed to configure auto close", zap.Error(err))
+	}
+
 	pumpStrategy := strategy.NewPumpStrategy(pumpConfig, pumpExecutor, logger)
+	// Size entries within the slippage budget on each token's bonding curve
+	pumpStrategy.SetBondingCurveSource(pumpProvider)
 	if err := pumpStrategy.Init(ctx); err != nil {
 		logger.Fatal("Failed to initialize pump strategy", zap.Error(err))
 	}
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,8 +16,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 
+	"github.com/kwanRoshi/B/go-migration/internal/config"
 	"github.com/kwanRoshi/B/go-migration/internal/market"
 	"github.com/shopspring/decimal"
+	"github.com/kwanRoshi/B/go-migration/internal/market/kafka"
 	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
 	"github.com/kwanRoshi/B/go-migration/internal/market/solana"
 	"github.com/kwanRoshi/B/go-migration/internal/metrics"
@@ -47,6 +51,9 @@ func main() {
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file: %s", err)
 	}
+	if err := config.Validate(viper.GetViper()); err != nil {
+		log.Fatalf("Error in config file %s: %s", *configFile, err)
+	}
 
 	// Initialize logger
 	logger, err := zap.NewProduction()
@@ -55,6 +62,11 @@ func main() {
 	}
 	defer logger.Sync()
 
+	// Bound the symbols exported by the per-token metrics
+	if viper.IsSet("monitoring.max_symbols") {
+		metrics.SymbolSeries.SetMax(viper.GetInt("monitoring.max_symbols"))
+	}
+
 	// Create root context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -80,10 +92,14 @@ func main() {
 
 	// Initialize Solana provider
 	solanaConfig := solana.Config{
-		BaseURL:      viper.GetString("market.providers.solana.base_url"),
-		WebSocketURL: viper.GetString("market.providers.solana.ws_url"),
-		DexSources:   viper.GetStringSlice("market.providers.solana.dex_sources"),
-		TimeoutSec:   int(viper.GetDuration("market.providers.solana.timeout").Seconds()),
+		BaseURL:            viper.GetString("market.providers.solana.base_url"),
+		WebSocketURL:       viper.GetString("market.providers.solana.ws_url"),
+		DexSources:         viper.GetStringSlice("market.providers.solana.dex_sources"),
+		TimeoutSec:         int(viper.GetDuration("market.providers.solana.timeout").Seconds()),
+		MinLiquidity:       viper.GetFloat64("market.providers.solana.min_liquidity"),
+		PriceSlippageLimit: viper.GetFloat64("market.providers.solana.price_slippage_limit"),
+		RaydiumURL:         viper.GetString("market.providers.solana.raydium_url"),
+		Mints:              viper.GetStringMapString("market.providers.solana.mints"),
 	}
 	solanaProvider := solana.NewProvider(solanaConfig, logger)
 
@@ -92,38 +108,60 @@ func main() {
 		BaseURL:      viper.GetString("market.providers.pump.base_url"),
 		WebSocketURL: viper.GetString("market.providers.pump.ws_url"),
 		TimeoutSec:   int(viper.GetDuration("market.providers.pump.timeout").Seconds()),
+		MaxMarketCap: decimal.NewFromFloat(viper.GetFloat64("market.providers.pump.max_market_cap")),
 	}, logger)
 
-	// Initialize market data handler with both providers
-	marketHandler := market.NewHandler([]types.MarketDataProvider{solanaProvider, pumpProvider}, logger)
+	providers := []types.MarketDataProvider{solanaProvider, pumpProvider}
+
+	// Initialize the Kafka feed when enabled
+	var kafkaProvider *kafka.Provider
+	if viper.GetBool("market.providers.kafka.enabled") {
+		var kafkaConfig kafka.Config
+		if err := config.UnmarshalKey(viper.GetViper(), "market.providers.kafka", "yaml", &kafkaConfig); err != nil {
+			logger.Fatal("Failed to load kafka config", zap.Error(err))
+		}
+		kafkaProvider, err = kafka.NewProvider(kafkaConfig, logger)
+		if err != nil {
+			logger.Fatal("Failed to create kafka provider", zap.Error(err))
+		}
+		if err := kafkaProvider.Start(ctx); err != nil {
+			logger.Fatal("Failed to start kafka provider", zap.Error(err))
+		}
+		providers = append(providers, kafkaProvider)
+	}
+
+	// Initialize market data handler with the providers
+	marketHandler := market.NewHandler(providers, logger)
+
+	// Rank provider failover by data quality
+	var qualityConfig market.QualityConfig
+	if err := config.UnmarshalKey(viper.GetViper(), "market.quality", "json", &qualityConfig); err != nil {
+		logger.Fatal("Invalid market config", zap.Error(err))
+	}
+	marketHandler.SetQuality(qualityConfig)
+
+	// Drop provider prices far from the recent median
+	var anomalyConfig market.AnomalyConfig
+	if err := config.UnmarshalKey(viper.GetViper(), "market.anomaly", "json", &anomalyConfig); err != nil {
+		logger.Fatal("Invalid market config", zap.Error(err))
+	}
+	marketHandler.SetAnomalyFilter(anomalyConfig)
 
 	// Initialize pricing engine
 	pricingConfig := pricing.Config{
 		UpdateInterval: viper.GetDuration("pricing.engine.update_interval"),
 		HistorySize:   viper.GetInt("pricing.engine.history_size"),
 		Indicators:    viper.GetStringSlice("pricing.engine.indicators"),
-		SignalParams: pricing.SignalParams{
-			MinConfidence:  viper.GetFloat64("pricing.engine.min_confidence"),
-			MaxVolatility:  viper.GetFloat64("pricing.engine.max_volatility"),
-		},
+		MaxSymbols:    viper.GetInt("pricing.engine.max_symbols"),
+		SignalParams:  signalParams(viper.GetViper()),
 	}
-	pricingEngine := pricing.NewEngine(pricingConfig, logger)
-
-	// Subscribe to symbols
-	symbols := []string{"SOL/USDC", "BONK/SOL"} // Solana symbols
-	pumpSymbols := []string{"PUMP/SOL"} // pump.fun symbols
-	symbols = append(symbols, pumpSymbols...)
-	for _, symbol := range symbols {
-		ctx := context.Background()
-		updates, err := marketHandler.SubscribePrices(ctx, []string{symbol})
-		if err != nil {
-			logger.Error("Failed to subscribe to symbol",
-				zap.String("symbol", symbol),
-				zap.Error(err))
-			continue
-		}
-		go handleUpdates(ctx, logger, updates)
+	if err := config.UnmarshalKey(viper.GetViper(), "pricing.engine.feedback", "json", &pricingConfig.Feedback); err != nil {
+		logger.Fatal("Invalid pricing config", zap.Error(err))
 	}
+	if err := config.UnmarshalKey(viper.GetViper(), "pricing.engine.retention", "json", &pricingConfig.Retention); err != nil {
+		logger.Fatal("Invalid pricing config", zap.Error(err))
+	}
+	pricingEngine := pricing.NewEngine(pricingConfig, logger)
 
 	// Start signal processing
 	go handleSignals(ctx, logger, pricingEngine)
@@ -140,26 +178,32 @@ func main() {
 		zap.String("mode", *mode),
 		zap.String("strategy", *strategy))
 	
-	limits := types.RiskConfig{
-		MaxPositionSize:     decimal.NewFromFloat(1000.0),
-		MaxDrawdown:         decimal.NewFromFloat(0.1),
-		MaxDailyLoss:        decimal.NewFromFloat(100.0),
-		MaxLeverage:         decimal.NewFromFloat(1.0),
-		MinMarginLevel:      decimal.NewFromFloat(1.5),
-		MaxConcentration:    decimal.NewFromFloat(0.2),
-		StopLoss: struct {
-			Initial  decimal.Decimal `yaml:"initial"`
-			Trailing decimal.Decimal `yaml:"trailing"`
-		}{
-			Initial:  decimal.NewFromFloat(0.02),
-			Trailing: decimal.NewFromFloat(0.01),
-		},
-		TakeProfitLevels: []types.ProfitLevel{
-			{Multiplier: decimal.NewFromFloat(1.015), Percentage: decimal.NewFromFloat(0.5)},
-			{Multiplier: decimal.NewFromFloat(1.03), Percentage: decimal.NewFromFloat(0.5)},
-		},
+	limits, err := riskLimits(viper.GetViper(), logger)
+	if err != nil {
+		logger.Fatal("Invalid risk limits", zap.Error(err))
 	}
 	riskManager := risk.NewRiskManager(&limits, logger)
+
+	// Reload the risk limits and signal parameters when the config file
+	// changes, without a restart. Both are read from the same keys as at
+	// startup, and a change is only applied once both validate.
+	config.Watch(viper.GetViper(), logger, func(v *viper.Viper) error {
+		update, err := riskLimits(v, logger)
+		if err != nil {
+			return err
+		}
+		params := signalParams(v)
+		if err := risk.ValidateReload(update); err != nil {
+			return err
+		}
+		if err := pricing.ValidateSignalParams(params); err != nil {
+			return err
+		}
+		if err := riskManager.Reload(update); err != nil {
+			return err
+		}
+		return pricingEngine.ReloadSignalParams(params)
+	})
 	
 	wsConfig := ws.Config{
 		Port:           viper.GetInt("server.websocket.port"),
@@ -170,8 +214,10 @@ func main() {
 	}
 
 	var pumpTradingConfig = &types.PumpTradingConfig{
-		MaxMarketCap: decimal.NewFromFloat(30000),
-		MinVolume:    decimal.NewFromFloat(1000),
+		MaxMarketCap:  pumpProvider.MaxMarketCap(),
+		MinVolume:     decimal.NewFromFloat(1000),
+		PaperMode:     *mode == "test",
+		PaperSlippage: decimal.NewFromFloat(viper.GetFloat64("trading.order.slippage")),
 		WebSocket: types.WSConfig{
 			ReconnectTimeout: 10 * time.Second,
 			PingInterval:    15 * time.Second,
@@ -188,6 +234,7 @@ func main() {
 			StopLossPercent   decimal.Decimal   `yaml:"stop_loss_percent"`
 			TakeProfitLevels  []decimal.Decimal `yaml:"take_profit_levels"`
 			BatchSizes        []decimal.Decimal `yaml:"batch_sizes"`
+			MaxExecutionSlippage decimal.Decimal `yaml:"max_execution_slippage"`
 		}{
 			MaxPositionSize:   decimal.NewFromFloat(1000),
 			MinPositionSize:   decimal.NewFromFloat(10),
@@ -196,6 +243,11 @@ func main() {
 			BatchSizes:        []decimal.Decimal{decimal.NewFromFloat(0.5), decimal.NewFromFloat(0.5)},
 		},
 	}
+	pumpStopLoss, err := config.NormalizeStopLoss("risk.stop_loss_percent", pumpTradingConfig.Risk.StopLossPercent, logger)
+	if err != nil {
+		logger.Fatal("Invalid stop loss", zap.Error(err))
+	}
+	pumpTradingConfig.Risk.StopLossPercent = pumpStopLoss
 	pumpExecutor := executor.NewPumpExecutor(logger, pumpProvider, riskManager, pumpTradingConfig, apiKey)
 	if err := pumpExecutor.Start(); err != nil {
 		logger.Fatal("Failed to start pump.fun executor", zap.Error(err))
@@ -203,21 +255,74 @@ func main() {
 	defer pumpExecutor.Stop()
 	
 	// Initialize trading engine
-	engineConfig := trading.Config{
-		Commission:     viper.GetFloat64("trading.order.commission"),
-		Slippage:      viper.GetFloat64("trading.order.slippage"),
-		MaxOrderSize:   viper.GetFloat64("trading.risk.max_order_size"),
-		MinOrderSize:   viper.GetFloat64("trading.risk.min_order_size"),
-		MaxPositions:   viper.GetInt("trading.risk.max_positions"),
-		UpdateInterval: viper.GetDuration("trading.engine.update_interval"),
+	engineConfig, err := tradingConfig(viper.GetViper())
+	if err != nil {
+		logger.Fatal("Invalid trading config", zap.Error(err))
 	}
 	tradingEngine := trading.NewEngine(engineConfig, logger, tradingStorage)
 
+	// Capture every signal the pricing engine emits so it can be replayed
+	// through the trading engine
+	if viper.GetBool("pricing.engine.capture_signals") {
+		signalStorage := mongodb.NewSignalStorage(mongoClient, database, logger)
+		pricingEngine.SetSignalTap(signalStorage)
+		tradingEngine.SetSignalStore(signalStorage)
+	}
+
+	// Feed closed trades back into the pricing engine's indicator
+	// reliability, restoring the factors learned before a restart
+	if pricingConfig.Feedback.Enabled {
+		reliabilityStorage := mongodb.NewReliabilityStorage(mongoClient, database, logger)
+		factors, err := reliabilityStorage.LoadReliability(ctx)
+		if err != nil {
+			logger.Error("Failed to load indicator reliability", zap.Error(err))
+		}
+		pricingEngine.SetReliability(factors)
+		tradingEngine.SetSignalOutcomes(&signalFeedback{
+			pricing: pricingEngine,
+			storage: reliabilityStorage,
+			logger:  logger,
+		})
+	}
+
+	// Mirror signals into a paper executor to compare against live results
+	if engineConfig.Shadow.Enabled {
+		shadowTradingConfig := *pumpTradingConfig
+		shadowTradingConfig.PaperMode = true
+		shadowExecutor := executor.NewPumpExecutor(logger, pumpProvider, riskManager, &shadowTradingConfig, apiKey)
+		if err := shadowExecutor.Start(); err != nil {
+			logger.Fatal("Failed to start shadow executor", zap.Error(err))
+		}
+		defer shadowExecutor.Stop()
+		tradingEngine.SetShadowExecutor(shadowExecutor)
+	}
+
 	// Register pump.fun executor with trading engine
 	if err := tradingEngine.RegisterExecutor("pump.fun", pumpExecutor); err != nil {
 		logger.Fatal("Failed to register pump.fun executor", zap.Error(err))
 	}
 
+	// Subscribe to symbols, feeding prices to the engine's conditional
+	// orders and to the pricing engine, which tracks each symbol for as long
+	// as its subscription lasts
+	symbols := []string{"SOL/USDC", "BONK/SOL"} // Solana symbols
+	pumpSymbols := []string{"PUMP/SOL"} // pump.fun symbols
+	symbols = append(symbols, pumpSymbols...)
+	for _, symbol := range symbols {
+		updates, err := marketHandler.SubscribePrices(ctx, []string{symbol})
+		if err != nil {
+			logger.Error("Failed to subscribe to symbol",
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			continue
+		}
+		pricingEngine.AddSymbol(symbol)
+		go func(symbol string, updates <-chan *types.PriceUpdate) {
+			handleUpdates(ctx, logger, updates, tradingEngine, pricingEngine)
+			pricingEngine.RemoveSymbol(symbol)
+		}(symbol, updates)
+	}
+
 	// Create trading service and servers
 	tradingService := trading.NewService(tradingEngine, logger)
 	grpcServer := grpc.NewServer(tradingService, logger)
@@ -229,11 +334,41 @@ func main() {
 		logger.Fatal("Failed to start monitoring service", zap.Error(err))
 	}
 
+	// Raise alerts on the engine's positions, delivered to the configured
+	// webhooks and logged
+	alertMonitor := monitoring.NewMonitor(tradingEngine, logger)
+	if v := viper.GetViper(); v.IsSet("monitoring.alerts.drawdown_threshold") {
+		threshold := decimal.NewFromFloat(v.GetFloat64("monitoring.alerts.drawdown_threshold"))
+		resolve := decimal.NewFromFloat(v.GetFloat64("monitoring.alerts.drawdown_resolve"))
+		if err := alertMonitor.SetDrawdownThresholds(threshold, resolve); err != nil {
+			logger.Fatal("Invalid drawdown alert thresholds", zap.Error(err))
+		}
+	}
+	if url := viper.GetString("monitoring.alerts.webhook_url"); url != "" {
+		alertMonitor.RegisterHandler(monitoring.NewWebhookHandler(url))
+	}
+	if url := viper.GetString("monitoring.alerts.slack_webhook_url"); url != "" {
+		alertMonitor.RegisterHandler(monitoring.NewSlackHandler(url))
+	}
+	var latencyConfig monitoring.FillLatencyConfig
+	if err := config.UnmarshalKey(viper.GetViper(), "monitoring.alerts.fill_latency", "yaml", &latencyConfig); err != nil {
+		logger.Fatal("Invalid fill latency alert config", zap.Error(err))
+	}
+	if latencyConfig.SLA > 0 {
+		if err := alertMonitor.SetFillLatencySLA(latencyConfig); err != nil {
+			logger.Fatal("Invalid fill latency alert config", zap.Error(err))
+		}
+		pumpExecutor.SetFillLatencyRecorder(alertMonitor)
+	}
+	if err := alertMonitor.Start(ctx); err != nil {
+		logger.Fatal("Failed to start alert monitor", zap.Error(err))
+	}
+	go handleAlerts(ctx, logger, alertMonitor.GetAlerts())
+
 	// Start trading engine
 	if err := tradingEngine.Start(ctx); err != nil {
 		logger.Fatal("Failed to start trading engine", zap.Error(err))
 	}
-	defer tradingEngine.Stop()
 
 	// Start servers
 	go wsServer.Start()
@@ -241,10 +376,22 @@ func main() {
 		logger.Fatal("Failed to start gRPC server", zap.Error(err))
 	}
 
+	// Dump the position book on SIGUSR1 for diagnostics during incidents
+	dumpChan := make(chan os.Signal, 1)
+	signal.Notify(dumpChan, syscall.SIGUSR1)
+	go func() {
+		for range dumpChan {
+			if err := tradingEngine.DumpState(viper.GetString("diagnostics.dump_path")); err != nil {
+				logger.Error("Failed to dump engine state", zap.Error(err))
+			}
+		}
+	}()
+
 	// Wait for shutdown signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
+	signal.Stop(dumpChan)
 
 	// Graceful shutdown
 	logger.Info("Shutting down...")
@@ -254,12 +401,126 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	// Add cleanup code here
+	if kafkaProvider != nil {
+		if err := kafkaProvider.Stop(); err != nil {
+			logger.Error("Failed to stop kafka provider", zap.Error(err))
+		}
+	}
+
+	// Let in-flight trades finish and persist positions before storage goes away
+	if err := tradingEngine.Stop(shutdownCtx); err != nil {
+		logger.Error("Failed to drain trading engine", zap.Error(err))
+	}
+
 	if err := mongoClient.Disconnect(shutdownCtx); err != nil {
 		logger.Error("Failed to disconnect from MongoDB", zap.Error(err))
 	}
 }
 
+// signalParams reads the pricing engine's signal parameters from v
+func signalParams(v *viper.Viper) pricing.SignalParams {
+	params := pricing.SignalParams{
+		MinConfidence: v.GetFloat64("pricing.engine.min_confidence"),
+		MaxVolatility: v.GetFloat64("pricing.engine.max_volatility"),
+		MinAgreement:  v.GetInt("pricing.engine.min_agreement"),
+		Cooldown:      v.GetDuration("pricing.engine.cooldown"),
+	}
+	params.TimeRange.Start = v.GetString("pricing.engine.time_range.start")
+	params.TimeRange.End = v.GetString("pricing.engine.time_range.end")
+	return params
+}
+
+// tradingConfig reads the trading engine config from trading in v. The
+// order limits, trading.risk.max_positions and the update interval come
+// from their own sections, and every other section of trading decodes
+// into the Config field of the same yaml name.
+func tradingConfig(v *viper.Viper) (trading.Config, error) {
+	engineConfig := trading.Config{
+		Commission:     v.GetFloat64("trading.order.commission"),
+		Slippage:       v.GetFloat64("trading.order.slippage"),
+		MaxOrderSize:   v.GetFloat64("trading.order.max_order_size"),
+		MinOrderSize:   v.GetFloat64("trading.order.min_order_size"),
+		MaxPositions:   v.GetInt("trading.risk.max_positions"),
+		UpdateInterval: v.GetDuration("trading.engine.update_interval"),
+	}
+	if err := config.UnmarshalKey(v, "trading", "yaml", &engineConfig); err != nil {
+		return trading.Config{}, err
+	}
+
+	// Viper lowercases map keys, while symbols are quoted in upper-case
+	// assets
+	rates := make(map[string]decimal.Decimal, len(engineConfig.Portfolio.Rates))
+	for asset, rate := range engineConfig.Portfolio.Rates {
+		rates[strings.ToUpper(asset)] = rate
+	}
+	engineConfig.Portfolio.Rates = rates
+	return engineConfig, nil
+}
+
+// riskLimits returns the risk limits with trading.risk.max_position_size,
+// trading.risk.stop_loss and trading.risk.take_profit_levels from v applied
+// over the defaults. The stop loss may be given as a fraction or a
+// percentage, and configured take-profit levels each sell an equal share.
+func riskLimits(v *viper.Viper, logger *zap.Logger) (types.RiskConfig, error) {
+	limits := types.RiskConfig{
+		MaxPositionSize:     decimal.NewFromFloat(1000.0),
+		MaxDrawdown:         decimal.NewFromFloat(0.1),
+		MaxDailyLoss:        decimal.NewFromFloat(100.0),
+		MaxLeverage:         decimal.NewFromFloat(1.0),
+		MinMarginLevel:      decimal.NewFromFloat(1.5),
+		MaxConcentration:    decimal.NewFromFloat(0.2),
+		StopLoss: struct {
+			Initial  decimal.Decimal `yaml:"initial"`
+			Trailing decimal.Decimal `yaml:"trailing"`
+		}{
+			Initial:  decimal.NewFromFloat(0.02),
+			Trailing: decimal.NewFromFloat(0.01),
+		},
+		TakeProfitLevels: []types.ProfitLevel{
+			{Multiplier: decimal.NewFromFloat(1.015), Percentage: decimal.NewFromFloat(0.5)},
+			{Multiplier: decimal.NewFromFloat(1.03), Percentage: decimal.NewFromFloat(0.5)},
+		},
+	}
+
+	if v.IsSet("trading.risk.max_position_size") {
+		limits.MaxPositionSize = decimal.NewFromFloat(v.GetFloat64("trading.risk.max_position_size"))
+	}
+	if v.IsSet("trading.risk.stop_loss") {
+		stopLoss, err := config.NormalizeStopLoss("trading.risk.stop_loss", decimal.NewFromFloat(v.GetFloat64("trading.risk.stop_loss")), logger)
+		if err != nil {
+			return types.RiskConfig{}, err
+		}
+		limits.StopLoss.Initial = stopLoss
+	}
+	if levels := v.GetStringSlice("trading.risk.take_profit_levels"); len(levels) > 0 {
+		share := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(len(levels))))
+		limits.TakeProfitLevels = make([]types.ProfitLevel, 0, len(levels))
+		for _, level := range levels {
+			multiplier, err := decimal.NewFromString(level)
+			if err != nil {
+				return types.RiskConfig{}, fmt.Errorf("invalid take profit level %q: %w", level, err)
+			}
+			limits.TakeProfitLevels = append(limits.TakeProfitLevels, types.ProfitLevel{Multiplier: multiplier, Percentage: share})
+		}
+	}
+	return limits, nil
+}
+
+// signalFeedback records the outcomes of traded signals in the pricing
+// engine and persists the reliability factors they update
+type signalFeedback struct {
+	pricing *pricing.Engine
+	storage *mongodb.ReliabilityStorage
+	logger  *zap.Logger
+}
+
+func (f *signalFeedback) RecordSignalOutcome(signal *types.Signal, profitable bool) {
+	f.pricing.RecordSignalOutcome(signal, profitable)
+	if err := f.storage.SaveReliability(context.Background(), f.pricing.Reliability()); err != nil {
+		f.logger.Error("Failed to save indicator reliability", zap.Error(err))
+	}
+}
+
 // handleSignals processes trading signals from the pricing engine
 func handleSignals(ctx context.Context, logger *zap.Logger, engine *pricing.Engine) {
 	signals := engine.GetSignals()
@@ -278,18 +539,49 @@ func handleSignals(ctx context.Context, logger *zap.Logger, engine *pricing.Engi
 	}
 }
 
-// handleUpdates processes price updates from market data providers
-func handleUpdates(ctx context.Context, logger *zap.Logger, updates <-chan *types.PriceUpdate) {
+// handleAlerts logs the alerts the monitor raises
+func handleAlerts(ctx context.Context, logger *zap.Logger, alerts <-chan *monitoring.Alert) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-alerts:
+			logger.Warn("Alert",
+				zap.String("type", string(alert.Type)),
+				zap.String("symbol", alert.Symbol),
+				zap.String("current", alert.Current.String()),
+				zap.String("threshold", alert.Threshold.String()),
+				zap.Bool("resolved", alert.Resolved))
+		}
+	}
+}
+
+// handleUpdates processes price updates from market data providers,
+// activating the engine's conditional orders their prices trigger
+func handleUpdates(ctx context.Context, logger *zap.Logger, updates <-chan *types.PriceUpdate, engine *trading.Engine, pricingEngine *pricing.Engine) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case update := <-updates:
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
 			logger.Debug("Received price update",
 				zap.String("symbol", update.Symbol),
 				zap.String("price", update.Price.String()),
 				zap.String("volume", update.Volume.String()),
 				zap.Time("timestamp", update.Timestamp))
+			if err := pricingEngine.ProcessUpdate(update); err != nil {
+				logger.Error("Failed to process price update",
+					zap.String("symbol", update.Symbol),
+					zap.Error(err))
+			}
+			if err := engine.OnPrice(ctx, update.Symbol, update.Price); err != nil {
+				logger.Error("Failed to activate triggered orders",
+					zap.String("symbol", update.Symbol),
+					zap.Error(err))
+			}
 		}
 	}
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
-	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -31,7 +30,9 @@ type SolanaTransaction struct {
 }
 
 func main() {
+	maxSymbols := flag.Int("max-symbols", metrics.DefaultMaxSymbols, "Maximum distinct symbols exported by per-token metrics (0 disables the cap)")
 	flag.Parse()
+	metrics.SymbolSeries.SetMax(*maxSymbols)
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
@@ -117,6 +118,7 @@ func main() {
 		TimeoutSec:   30,
 		BaseURL:      "https://frontend-api.pump.fun",
 		WebSocketURL: "wss://frontend-api.pump.fun/ws",
+		ShareWebSocket: true,
 	}, logger)
 
 	if err := pumpProvider.Connect(ctx); err != nil {
@@ -124,92 +126,27 @@ func main() {
 	}
 	defer pumpProvider.Close()
 
-	updates := make(chan *types.TokenUpdate, 100)
-	go func() {
-		for update := range pumpProvider.GetTokenUpdates() {
-			select {
-			case updates <- update:
-			default:
-				logger.Warn("Update channel full, dropping update")
-			}
-		}
-	}()
-
-	go func() {
-		for update := range updates {
-			monitorService.OnTokenUpdate(update)
-
-			if update.MarketCap.LessThan(decimal.NewFromFloat(30000)) {
-				logger.Info("New low cap token detected",
-					zap.String("symbol", update.Symbol),
-					zap.Float64("market_cap", update.MarketCap.InexactFloat64()))
-			}
-
-			if update.Volume.GreaterThan(decimal.NewFromFloat(1000)) {
-				logger.Info("High volume token detected",
-					zap.String("symbol", update.Symbol),
-					zap.Float64("volume", update.Volume.InexactFloat64()))
-			}
-		}
-	}()
-
-	// Monitor pump.fun trading activity
+	// Monitor pump.fun token updates
 	go func() {
 		defer wg.Done()
 
-		pumpProvider := pump.NewProvider(pump.Config{
-			APIKey:       apiKey,
-			TimeoutSec:   30,
-			BaseURL:      "https://frontend-api.pump.fun",
-			WebSocketURL: "wss://frontend-api.pump.fun/ws",
-		}, logger)
-
-		if err := pumpProvider.Connect(ctx); err != nil {
-			logger.Fatal("Failed to connect to pump.fun", zap.Error(err))
-		}
-		defer pumpProvider.Close()
-
-		pingTicker := time.NewTicker(time.Second * 30)
-		defer pingTicker.Stop()
-
-		updates := make(chan *types.TokenUpdate, 100)
-		trades := make(chan *types.Trade, 100)
-
-		// Keep connection alive with periodic pings
-		go func() {
-			for {
-				select {
-				case <-ctx.Done():
+		updates := pumpProvider.GetTokenUpdates()
+		for {
+			var update *types.TokenUpdate
+			select {
+			case <-ctx.Done():
+				return
+			case u, ok := <-updates:
+				if !ok {
 					return
-				case <-pingTicker.C:
-					if err := pumpProvider.Ping(); err != nil {
-						logger.Error("WebSocket ping failed", zap.Error(err))
-						if err := pumpProvider.Reconnect(ctx); err != nil {
-							logger.Error("WebSocket reconnection failed", zap.Error(err))
-						}
-					}
 				}
+				update = u
 			}
-		}()
-
-		// Process token updates
-		go func() {
-			for update := range pumpProvider.GetTokenUpdates() {
-				select {
-				case updates <- update:
-				default:
-					logger.Warn("Update channel full, dropping update")
-				}
-			}
-		}()
 
-		// Monitor token updates and trading activity
-		go func() {
-			for update := range updates {
-				monitorService.OnTokenUpdate(update)
+			monitorService.OnTokenUpdate(update)
 
 			marketCap := decimal.NewFromFloat(update.MarketCap)
-			if marketCap.LessThan(decimal.NewFromFloat(30000)) {
+			if marketCap.LessThan(pumpProvider.MaxMarketCap()) {
 				logger.Info("New low cap token detected",
 					zap.String("symbol", update.Symbol),
 					zap.Float64("market_cap", update.MarketCap))
@@ -225,10 +162,10 @@ func main() {
 			}
 
 			// Monitor significant price changes
-			if update.PriceChange24h > 20.0 || update.PriceChange24h < -20.0 {
+			if update.PriceChange.Day > 20.0 || update.PriceChange.Day < -20.0 {
 				logger.Info("Significant price change detected",
 					zap.String("symbol", update.Symbol),
-					zap.Float64("price_change_24h", update.PriceChange24h))
+					zap.Float64("price_change_24h", update.PriceChange.Day))
 				metrics.SignificantPriceChanges.Inc()
 			}
 
@@ -236,35 +173,19 @@ func main() {
 			metrics.TokenPrice.WithLabelValues("pump_fun", update.Symbol).Set(update.Price)
 			metrics.TokenVolume.WithLabelValues("pump_fun", update.Symbol).Set(update.Volume)
 			metrics.TokenMarketCap.WithLabelValues("pump_fun", update.Symbol).Set(update.MarketCap)
-			metrics.TokenPriceChangeDay.WithLabelValues("pump_fun", update.Symbol).Set(update.PriceChange24h)
+			metrics.TokenPriceChangeDay.WithLabelValues("pump_fun", update.Symbol).Set(update.PriceChange.Day)
+			metrics.SymbolSeries.Track(update.Symbol, metrics.TokenPrice, "pump_fun", update.Symbol)
+			metrics.SymbolSeries.Track(update.Symbol, metrics.TokenVolume, "pump_fun", update.Symbol)
+			metrics.SymbolSeries.Track(update.Symbol, metrics.TokenMarketCap, "pump_fun", update.Symbol)
+			metrics.SymbolSeries.Track(update.Symbol, metrics.TokenPriceChangeDay, "pump_fun", update.Symbol)
 			metrics.LastUpdateTimestamp.Set(float64(time.Now().Unix()))
 
-				if update.PriceChange.Hour > 20.0 {
-					logger.Info("Significant price increase detected",
-						zap.String("symbol", update.Symbol),
-						zap.Float64("hour_change", update.PriceChange.Hour))
-					metrics.SignificantPriceChanges.Inc()
-				}
-			}
-		}()
-
-		// Monitor trades
-		go func() {
-			for trade := range trades {
-				monitorService.OnTrade(trade)
-				logger.Info("Trade executed",
-					zap.String("symbol", trade.Symbol),
-					zap.Float64("size", trade.Size.InexactFloat64()),
-					zap.Float64("price", trade.Price.InexactFloat64()))
-				
-				metrics.TradeExecutions.Inc()
-				metrics.TradingVolume.Add(trade.Size.InexactFloat64() * trade.Price.InexactFloat64())
+			if update.PriceChange.Hour > 20.0 {
+				logger.Info("Significant price increase detected",
+					zap.String("symbol", update.Symbol),
+					zap.Float64("hour_change", update.PriceChange.Hour))
+				metrics.SignificantPriceChanges.Inc()
 			}
-		}()
-
-		select {
-		case <-ctx.Done():
-			return
 		}
 	}()
 
@@ -272,7 +193,7 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	logger.Info("Starting monitoring verification",
-		zap.Bool("websocket_connected", true),
+		zap.String("data_source", pumpProvider.DataSourceMode()),
 		zap.String("provider", "pump.fun"))
 
 	metrics.MonitoringServiceStatus.Set(1)
@@ -280,10 +201,6 @@ func main() {
 	statusTicker := time.NewTicker(time.Second * 5)
 	defer statusTicker.Stop()
 
-	logger.Info("Starting monitoring verification",
-		zap.Bool("websocket_connected", true),
-		zap.String("provider", "pump.fun"))
-
 	for {
 		select {
 		case <-sigChan:
@@ -304,11 +221,12 @@ func main() {
 					zap.String("symbol", symbol),
 					zap.Float64("size", pos.Size.InexactFloat64()),
 					zap.Float64("pnl", pos.UnrealizedPnL.InexactFloat64()))
-				
+
 				// Update position metrics
-				metrics.PositionSize.WithLabelValues(symbol).Set(pos.Size.InexactFloat64())
-				metrics.UnrealizedPnL.WithLabelValues(symbol).Set(pos.UnrealizedPnL.InexactFloat64())
-				metrics.RiskExposure.WithLabelValues(symbol).Set(pos.Size.Mul(decimal.NewFromFloat(update.Price)).InexactFloat64())
+				metrics.PumpPositionSize.WithLabelValues(symbol).Set(pos.Size.InexactFloat64())
+				metrics.PumpUnrealizedPnL.WithLabelValues(symbol).Set(pos.UnrealizedPnL.InexactFloat64())
+				metrics.SymbolSeries.Track(symbol, metrics.PumpPositionSize, symbol)
+				metrics.SymbolSeries.Track(symbol, metrics.PumpUnrealizedPnL, symbol)
 			}
 
 			// Verify strategy independence
@@ -316,41 +234,12 @@ func main() {
 			metrics.ActivePositions.Set(float64(len(positions)))
 			metrics.LastUpdateTimestamp.Set(float64(time.Now().Unix()))
 
-			// Monitor WebSocket connection health
-			if err := pumpProvider.Ping(); err != nil {
-				logger.Error("WebSocket connection health check failed", zap.Error(err))
-				metrics.WebsocketConnections.Set(0)
-				if err := pumpProvider.Reconnect(ctx); err != nil {
-					logger.Error("WebSocket reconnection failed", zap.Error(err))
-				} else {
-					metrics.WebsocketConnections.Set(1)
-				}
-			} else {
-				metrics.WebsocketConnections.Set(1)
-			}
-
-			// Monitor strategy isolation
-			for _, pos := range positions {
-				if pos.Strategy == "pump_fun" {
-					logger.Info("Pump.fun strategy position",
-						zap.String("symbol", pos.Symbol),
-						zap.Float64("size", pos.Size.InexactFloat64()),
-						zap.Float64("pnl", pos.UnrealizedPnL.InexactFloat64()))
-					metrics.StrategyPnL.WithLabelValues("pump_fun").Set(pos.UnrealizedPnL.InexactFloat64())
-				}
-			}
-
-			// Verify core strategy is unaffected
+			// Every monitored position belongs to the pump.fun strategy
+			pnl := decimal.Zero
 			for _, pos := range positions {
-				if pos.Strategy != "pump_fun" {
-					logger.Info("Core strategy position",
-						zap.String("symbol", pos.Symbol),
-						zap.String("strategy", pos.Strategy),
-						zap.Float64("size", pos.Size.InexactFloat64()),
-						zap.Float64("pnl", pos.UnrealizedPnL.InexactFloat64()))
-					metrics.StrategyPnL.WithLabelValues(pos.Strategy).Set(pos.UnrealizedPnL.InexactFloat64())
-				}
+				pnl = pnl.Add(pos.UnrealizedPnL)
 			}
+			metrics.StrategyPnL.WithLabelValues("pump_fun").Set(pnl.InexactFloat64())
 			metrics.LastUpdateTimestamp.SetToCurrentTime()
 		}
 	}
@@ -2,23 +2,34 @@ package main
 
 import (
     "context"
+    "flag"
     "os"
     "time"
 
     "github.com/shopspring/decimal"
+    "github.com/spf13/viper"
     "go.uber.org/zap"
 
+    "github.com/kwanRoshi/B/go-migration/internal/config"
     "github.com/kwanRoshi/B/go-migration/internal/market/pump"
-    "github.com/kwanRoshi/B/go-migration/internal/metrics"
     "github.com/kwanRoshi/B/go-migration/internal/risk"
     "github.com/kwanRoshi/B/go-migration/internal/trading/executor"
     "github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 func main() {
+    configFile := flag.String("config", "configs/config.yaml", "path to config file")
+    flag.Parse()
+
     logger, _ := zap.NewDevelopment()
     defer logger.Sync()
 
+    v := viper.New()
+    v.SetConfigFile(*configFile)
+    if err := v.ReadInConfig(); err != nil {
+        logger.Fatal("Failed to read config file", zap.String("path", *configFile), zap.Error(err))
+    }
+
     ctx := context.Background()
 
     logger.Info("Starting real-time trading verification")
@@ -33,22 +44,36 @@ func main() {
     provider := pump.NewProvider(pumpConfig, logger)
 
     // Initialize risk manager
-    limits := risk.Limits{
-        MaxPositionSize:  1000.0,
-        MaxDrawdown:      0.1,
-        MaxDailyLoss:     100.0,
-        MaxLeverage:      1.0,
-        MinMarginLevel:   1.5,
-        MaxConcentration: 0.2,
+    limits, err := config.RiskLimits(v)
+    if err != nil {
+        logger.Fatal("Invalid risk limits", zap.Error(err))
     }
     riskManager := risk.NewManager(limits, logger)
+    returns, err := config.ReturnsSource(v, provider)
+    if err != nil {
+        logger.Fatal("Invalid risk returns", zap.Error(err))
+    }
+    riskManager.SetReturnsSource(returns)
 
     // Initialize trading config
-    tradingConfig := &types.PumpTradingConfig{
-        StopLossPercent:   15.0,
-        TakeProfitLevels: []float64{2.0, 3.0, 5.0},
-        BatchSizes:       []float64{0.2, 0.25, 0.2},
+    tradingConfig := &types.PumpTradingConfig{}
+    tradingConfig.Risk.StopLossPercent = decimal.NewFromFloat(15.0)
+    tradingConfig.Risk.TakeProfitLevels = []decimal.Decimal{
+        decimal.NewFromFloat(2.0),
+        decimal.NewFromFloat(3.0),
+        decimal.NewFromFloat(5.0),
+    }
+    tradingConfig.Risk.BatchSizes = []decimal.Decimal{
+        decimal.NewFromFloat(0.2),
+        decimal.NewFromFloat(0.25),
+        decimal.NewFromFloat(0.2),
+    }
+
+    stopLoss, err := config.NormalizeStopLoss("risk.stop_loss_percent", tradingConfig.Risk.StopLossPercent, logger)
+    if err != nil {
+        logger.Fatal("Invalid stop loss", zap.Error(err))
     }
+    tradingConfig.Risk.StopLossPercent = stopLoss
 
     // Initialize executor with API key
     apiKey := os.Getenv("PUMP_FUN_API_KEY")
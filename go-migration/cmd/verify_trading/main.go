@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -22,7 +23,8 @@ func main() {
 	apiKey := os.Getenv("PUMP_FUN_API_KEY")
 	if apiKey == "" {
 		log.Fatal("PUMP_FUN_API_KEY environment variable is required")
-	
+	}
+
 	// Initialize components
 	provider := pump.NewProvider(pump.Config{
 		BaseURL:      "https://frontend-api.pump.fun/api",
@@ -64,19 +66,20 @@ func main() {
 	}
 
 	// Initialize executor with real components
-	exec := executor.NewPumpExecutor(logger, provider, riskConfig, apiKey)
+	pumpConfig := &types.PumpTradingConfig{
+		MaxMarketCap: decimal.NewFromFloat(1000000),
+		MinVolume:    decimal.NewFromFloat(5000),
+	}
+	riskMgr := strategy.NewPumpRiskManager(logger, riskConfig)
+	exec := executor.NewPumpExecutor(logger, provider, riskMgr, pumpConfig, apiKey)
 	if err := exec.Start(); err != nil {
 		log.Fatalf("Failed to start executor: %v", err)
 	}
 	defer exec.Stop()
 
 	// Create pump.fun strategy
-	pumpStrategy := strategy.NewPumpStrategy(&strategy.PumpConfig{
-		MinMarketCap:    decimal.NewFromFloat(10000),
-		MaxMarketCap:    decimal.NewFromFloat(1000000),
-		MinVolume:       decimal.NewFromFloat(5000),
-		PriceChangeThreshold: decimal.NewFromFloat(0.05),
-	}, logger)
+	pumpStrategy := strategy.NewPumpStrategy(pumpConfig, exec, logger)
+	pumpStrategy.SetBondingCurveSource(provider)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -124,4 +127,9 @@ func main() {
 	log.Printf("Strategy isolation verified")
 	log.Printf("Risk management verified")
 	log.Printf("Metrics collection verified")
+
+	// Push before exiting so the run is visible to Prometheus
+	if err := metrics.Push(ctx, os.Getenv("PUSHGATEWAY_URL"), "verify_trading"); err != nil {
+		log.Printf("Failed to push metrics: %v", err)
+	}
 }
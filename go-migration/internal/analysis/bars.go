@@ -0,0 +1,35 @@
+package analysis
+
+import "github.com/kwanRoshi/B/go-migration/internal/types"
+
+// historyBars returns the high, low, close and volume series of history,
+// oldest first. Raw ticks carry a single price, which serves as their high,
+// low and close; compacted candles carry their high and low in Extra.
+func historyBars(history *types.PriceHistory) (high, low, close, volume []float64) {
+	n := history.Len()
+	high = make([]float64, 0, n)
+	low = make([]float64, 0, n)
+	close = make([]float64, 0, n)
+	volume = make([]float64, 0, n)
+
+	history.Range(func(level *types.PriceLevel) bool {
+		h, l := level.Price, level.Price
+		if v, ok := level.Extra["high"].(float64); ok {
+			h = v
+		}
+		if v, ok := level.Extra["low"].(float64); ok {
+			l = v
+		}
+		high = append(high, h)
+		low = append(low, l)
+		close = append(close, level.Price)
+		volume = append(volume, level.Volume)
+		return true
+	})
+	for _, series := range [][]float64{high, low, close, volume} {
+		for l, r := 0, len(series)-1; l < r; l, r = l+1, r-1 {
+			series[l], series[r] = series[r], series[l]
+		}
+	}
+	return high, low, close, volume
+}
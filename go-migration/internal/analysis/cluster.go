@@ -0,0 +1,43 @@
+package analysis
+
+import "sort"
+
+// CorrelationClusters groups the symbols of a correlation matrix into the
+// connected components of the graph linking every pair correlated at or
+// above threshold, so symbols tied together only through a chain of
+// correlated neighbours share a cluster. Symbols within a cluster and the
+// clusters themselves are sorted, and uncorrelated symbols form their own
+// single-symbol cluster.
+func CorrelationClusters(matrix map[string]map[string]float64, threshold float64) [][]string {
+	symbols := make([]string, 0, len(matrix))
+	for symbol := range matrix {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	seen := make(map[string]bool, len(symbols))
+	var clusters [][]string
+	for _, root := range symbols {
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+
+		cluster := []string{}
+		stack := []string{root}
+		for len(stack) > 0 {
+			symbol := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			cluster = append(cluster, symbol)
+			for _, other := range symbols {
+				if !seen[other] && matrix[symbol][other] >= threshold {
+					seen[other] = true
+					stack = append(stack, other)
+				}
+			}
+		}
+		sort.Strings(cluster)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
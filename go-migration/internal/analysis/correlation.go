@@ -0,0 +1,207 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// AlignReturns converts per-symbol price series into simple returns computed
+// only over the timestamps that every symbol has a price for, so the
+// resulting series line up index by index.
+func AlignReturns(prices map[string][]*types.PriceLevel) map[string][]float64 {
+	if len(prices) == 0 {
+		return map[string][]float64{}
+	}
+
+	// Count how many symbols have a price at each timestamp
+	counts := make(map[time.Time]int)
+	bySymbol := make(map[string]map[time.Time]float64, len(prices))
+	for symbol, levels := range prices {
+		series := make(map[time.Time]float64, len(levels))
+		for _, level := range levels {
+			if level == nil {
+				continue
+			}
+			if _, seen := series[level.Timestamp]; !seen {
+				counts[level.Timestamp]++
+			}
+			series[level.Timestamp] = level.Price
+		}
+		bySymbol[symbol] = series
+	}
+
+	common := make([]time.Time, 0, len(counts))
+	for ts, n := range counts {
+		if n == len(prices) {
+			common = append(common, ts)
+		}
+	}
+	sort.Slice(common, func(i, j int) bool { return common[i].Before(common[j]) })
+
+	returns := make(map[string][]float64, len(prices))
+	for symbol, series := range bySymbol {
+		r := make([]float64, 0, len(common))
+		for i := 1; i < len(common); i++ {
+			prev := series[common[i-1]]
+			if prev == 0 {
+				r = append(r, 0)
+				continue
+			}
+			r = append(r, series[common[i]]/prev-1)
+		}
+		returns[symbol] = r
+	}
+	return returns
+}
+
+// CorrelationMatrix computes the pairwise Pearson correlation of the given
+// return series. Series of different lengths are compared over their most
+// recent common length.
+func CorrelationMatrix(returns map[string][]float64) map[string]map[string]float64 {
+	matrix := make(map[string]map[string]float64, len(returns))
+	for a := range returns {
+		matrix[a] = make(map[string]float64, len(returns))
+	}
+
+	for a, ra := range returns {
+		for b, rb := range returns {
+			if a == b {
+				matrix[a][b] = 1.0
+				continue
+			}
+			if _, done := matrix[b][a]; done {
+				matrix[a][b] = matrix[b][a]
+				continue
+			}
+			matrix[a][b] = pearson(ra, rb)
+		}
+	}
+	return matrix
+}
+
+func pearson(a, b []float64) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if n < 2 {
+		return 0
+	}
+	a = a[len(a)-n:]
+	b = b[len(b)-n:]
+
+	var meanA, meanB float64
+	for i := 0; i < n; i++ {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+
+	var cov, varA, varB float64
+	for i := 0; i < n; i++ {
+		da := a[i] - meanA
+		db := b[i] - meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varA*varB)
+}
+
+// RollingCorrelation returns the Pearson correlation of a and b over their
+// most recent window points. A window of zero uses their whole common length.
+func RollingCorrelation(a, b []float64, window int) float64 {
+	if window > 0 {
+		if len(a) > window {
+			a = a[len(a)-window:]
+		}
+		if len(b) > window {
+			b = b[len(b)-window:]
+		}
+	}
+	return pearson(a, b)
+}
+
+// Beta returns the regression slope of asset returns on reference returns,
+// cov(asset, reference) / var(reference), over their most recent common length
+func Beta(asset, reference []float64) float64 {
+	n := len(asset)
+	if len(reference) < n {
+		n = len(reference)
+	}
+	if n < 2 {
+		return 0
+	}
+	asset = asset[len(asset)-n:]
+	reference = reference[len(reference)-n:]
+
+	var meanA, meanR float64
+	for i := 0; i < n; i++ {
+		meanA += asset[i]
+		meanR += reference[i]
+	}
+	meanA /= float64(n)
+	meanR /= float64(n)
+
+	var cov, varR float64
+	for i := 0; i < n; i++ {
+		dr := reference[i] - meanR
+		cov += (asset[i] - meanA) * dr
+		varR += dr * dr
+	}
+
+	if varR == 0 {
+		return 0
+	}
+	return cov / varR
+}
+
+// WriteCorrelationJSON writes the matrix as a JSON object keyed by symbol
+func WriteCorrelationJSON(w io.Writer, matrix map[string]map[string]float64) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(matrix); err != nil {
+		return fmt.Errorf("failed to encode correlation matrix: %w", err)
+	}
+	return nil
+}
+
+// WriteCorrelationCSV writes the matrix as a CSV table with a header row and
+// column of symbols in sorted order
+func WriteCorrelationCSV(w io.Writer, matrix map[string]map[string]float64) error {
+	symbols := make([]string, 0, len(matrix))
+	for symbol := range matrix {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"symbol"}, symbols...)); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, a := range symbols {
+		row := make([]string, 0, len(symbols)+1)
+		row = append(row, a)
+		for _, b := range symbols {
+			row = append(row, strconv.FormatFloat(matrix[a][b], 'f', 6, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write row for %s: %w", a, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
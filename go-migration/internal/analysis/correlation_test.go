@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestCorrelationMatrix(t *testing.T) {
+	returns := map[string][]float64{
+		"SOL":  {0.01, -0.02, 0.03, 0.01, -0.01},
+		"BONK": {0.02, -0.04, 0.06, 0.02, -0.02},
+		"PUMP": {-0.01, 0.02, -0.03, -0.01, 0.01},
+	}
+
+	matrix := CorrelationMatrix(returns)
+
+	for a := range returns {
+		assert.Equal(t, 1.0, matrix[a][a])
+		for b := range returns {
+			assert.InDelta(t, matrix[a][b], matrix[b][a], 1e-12)
+		}
+	}
+	assert.InDelta(t, 1.0, matrix["SOL"]["BONK"], 1e-9)
+	assert.InDelta(t, -1.0, matrix["SOL"]["PUMP"], 1e-9)
+}
+
+func TestBeta(t *testing.T) {
+	reference := []float64{0.01, -0.02, 0.03, 0.01, -0.01}
+	levered := []float64{0.03, -0.06, 0.09, 0.03, -0.03}
+	inverse := []float64{-0.005, 0.01, -0.015, -0.005, 0.005}
+
+	assert.InDelta(t, 1.0, Beta(reference, reference), 1e-9)
+	assert.InDelta(t, 3.0, Beta(levered, reference), 1e-9)
+	assert.InDelta(t, -0.5, Beta(inverse, reference), 1e-9)
+
+	// A flat reference has no defined beta
+	assert.Zero(t, Beta(levered, []float64{0, 0, 0, 0, 0}))
+}
+
+func TestRollingCorrelation(t *testing.T) {
+	reference := []float64{0.01, -0.02, 0.03, 0.01, -0.01, 0.02, -0.01, 0.01}
+	// Inverse of the reference at first, then moving with it
+	asset := []float64{-0.01, 0.02, -0.03, -0.01, -0.01, 0.02, -0.01, 0.01}
+
+	assert.InDelta(t, 1.0, RollingCorrelation(asset, reference, 4), 1e-9)
+	assert.Less(t, RollingCorrelation(asset, reference, 0), 0.5)
+}
+
+func TestAlignReturns(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int, price float64) *types.PriceLevel {
+		return &types.PriceLevel{Price: price, Timestamp: start.Add(time.Duration(i) * time.Minute)}
+	}
+
+	returns := AlignReturns(map[string][]*types.PriceLevel{
+		"SOL":  {at(0, 100), at(1, 110), at(2, 121), at(3, 133.1)},
+		"BONK": {at(1, 10), at(3, 12)},
+	})
+
+	// Only minutes 1 and 3 are shared, leaving a single return per symbol
+	assert.Len(t, returns["SOL"], 1)
+	assert.Len(t, returns["BONK"], 1)
+	assert.InDelta(t, 0.21, returns["SOL"][0], 1e-9)
+	assert.InDelta(t, 0.2, returns["BONK"][0], 1e-9)
+}
+
+func TestCorrelationClusters(t *testing.T) {
+	// BONK links SOL and WIF without them being correlated directly
+	matrix := map[string]map[string]float64{
+		"SOL":  {"SOL": 1, "BONK": 0.9, "WIF": 0.5, "PUMP": -0.8},
+		"BONK": {"SOL": 0.9, "BONK": 1, "WIF": 0.85, "PUMP": -0.7},
+		"WIF":  {"SOL": 0.5, "BONK": 0.85, "WIF": 1, "PUMP": 0.1},
+		"PUMP": {"SOL": -0.8, "BONK": -0.7, "WIF": 0.1, "PUMP": 1},
+	}
+
+	clusters := CorrelationClusters(matrix, 0.8)
+	assert.Equal(t, [][]string{{"BONK", "SOL", "WIF"}, {"PUMP"}}, clusters)
+}
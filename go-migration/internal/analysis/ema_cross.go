@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// Crossover states reported in the "cross" param of EMACrossIndicator
+const (
+	DeathCross  = -1.0 // Fast EMA crossed below the slow EMA on the latest bar
+	NoCross     = 0.0
+	GoldenCross = 1.0 // Fast EMA crossed above the slow EMA on the latest bar
+)
+
+// EMACrossIndicator tracks a fast and a slow EMA and whether they crossed
+// on the latest price. Its value is the fast EMA minus the slow EMA.
+//
+// Params returns a map[string]interface{} with:
+//
+//	"fast_period", "slow_period"  int
+//	"fast", "slow"                float64, the current EMAs
+//	"cross"                       float64, GoldenCross, DeathCross or NoCross
+type EMACrossIndicator struct {
+	BaseIndicator
+	fastPeriod int
+	slowPeriod int
+	fast       float64
+	slow       float64
+	cross      float64
+}
+
+// NewEMACrossIndicator creates a new EMA crossover indicator
+func NewEMACrossIndicator(fastPeriod, slowPeriod int) *EMACrossIndicator {
+	return &EMACrossIndicator{
+		BaseIndicator: BaseIndicator{name: "EMA_CROSS"},
+		fastPeriod:    fastPeriod,
+		slowPeriod:    slowPeriod,
+	}
+}
+
+func (i *EMACrossIndicator) Params() interface{} {
+	return map[string]interface{}{
+		"fast_period": i.fastPeriod,
+		"slow_period": i.slowPeriod,
+		"fast":        i.fast,
+		"slow":        i.slow,
+		"cross":       i.cross,
+	}
+}
+
+func (i *EMACrossIndicator) Calculate(history *types.PriceHistory) error {
+	if i.fastPeriod <= 0 || i.slowPeriod <= 0 || history.Len() <= i.slowPeriod {
+		return fmt.Errorf("insufficient data for EMA crossover calculation")
+	}
+
+	// Range visits newest first, the EMAs run oldest first
+	prices := make([]float64, 0, history.Len())
+	history.Range(func(level *types.PriceLevel) bool {
+		prices = append(prices, level.Price)
+		return true
+	})
+	for l, r := 0, len(prices)-1; l < r; l, r = l+1, r-1 {
+		prices[l], prices[r] = prices[r], prices[l]
+	}
+
+	fastK := 2.0 / float64(i.fastPeriod+1)
+	slowK := 2.0 / float64(i.slowPeriod+1)
+	fast, slow := prices[0], prices[0]
+	var prevSpread float64
+	for _, price := range prices[1:] {
+		prevSpread = fast - slow
+		fast += (price - fast) * fastK
+		slow += (price - slow) * slowK
+	}
+
+	i.fast, i.slow = fast, slow
+	i.value = fast - slow
+	switch {
+	case prevSpread <= 0 && i.value > 0:
+		i.cross = GoldenCross
+	case prevSpread >= 0 && i.value < 0:
+		i.cross = DeathCross
+	default:
+		i.cross = NoCross
+	}
+	return nil
+}
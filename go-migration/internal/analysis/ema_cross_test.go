@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func crossHistory(prices ...float64) *types.PriceHistory {
+	h := types.NewPriceHistory(len(prices))
+	for _, p := range prices {
+		h.Add(&types.PriceLevel{Symbol: "SOL", Price: p})
+	}
+	return h
+}
+
+func crossState(t *testing.T, ind *EMACrossIndicator) float64 {
+	params := ind.Params().(map[string]interface{})
+	cross, ok := params["cross"].(float64)
+	require.True(t, ok)
+	return cross
+}
+
+func TestEMACrossIndicator(t *testing.T) {
+	ind := NewEMACrossIndicator(2, 4)
+	assert.Equal(t, "EMA_CROSS", ind.Name())
+
+	// A falling market keeps the fast EMA below the slow one
+	require.NoError(t, ind.Calculate(crossHistory(10, 9, 8, 7, 6)))
+	assert.Less(t, ind.Value(), 0.0)
+	assert.Equal(t, NoCross, crossState(t, ind))
+
+	// A sharp rally lifts the fast EMA through the slow one
+	require.NoError(t, ind.Calculate(crossHistory(10, 9, 8, 7, 6, 12)))
+	assert.Greater(t, ind.Value(), 0.0)
+	assert.Equal(t, GoldenCross, crossState(t, ind))
+
+	require.NoError(t, ind.Calculate(crossHistory(6, 7, 8, 9, 10, 4)))
+	assert.Equal(t, DeathCross, crossState(t, ind))
+}
+
+func TestEMACrossIndicator_InsufficientData(t *testing.T) {
+	assert.Error(t, NewEMACrossIndicator(2, 4).Calculate(crossHistory(1, 2, 3)))
+}
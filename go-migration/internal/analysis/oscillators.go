@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// Stochastic returns the stochastic oscillator %K over kPeriod bars and its
+// dPeriod simple average %D, both from 0 to 100. Outputs are aligned with
+// the inputs, with zeros until enough bars are available.
+func Stochastic(high, low, close []float64, kPeriod, dPeriod int) (k, d []float64) {
+	n := alignedLength(high, low, close)
+	k = make([]float64, n)
+	d = make([]float64, n)
+	if kPeriod <= 0 || dPeriod <= 0 {
+		return k, d
+	}
+
+	for i := kPeriod - 1; i < n; i++ {
+		highest, lowest := extremes(high, low, i-kPeriod+1, i)
+		if highest > lowest {
+			k[i] = (close[i] - lowest) / (highest - lowest) * 100
+		}
+	}
+	for i := kPeriod + dPeriod - 2; i < n; i++ {
+		var sum float64
+		for j := i - dPeriod + 1; j <= i; j++ {
+			sum += k[j]
+		}
+		d[i] = sum / float64(dPeriod)
+	}
+	return k, d
+}
+
+// WilliamsR returns Williams %R over period bars, from -100 at the period
+// low to 0 at the period high. The output is aligned with the inputs, with
+// zeros until enough bars are available.
+func WilliamsR(high, low, close []float64, period int) []float64 {
+	n := alignedLength(high, low, close)
+	r := make([]float64, n)
+	if period <= 0 {
+		return r
+	}
+
+	for i := period - 1; i < n; i++ {
+		highest, lowest := extremes(high, low, i-period+1, i)
+		if highest > lowest {
+			r[i] = (highest - close[i]) / (highest - lowest) * -100
+		}
+	}
+	return r
+}
+
+// StochasticIndicator tracks the stochastic oscillator of a price history.
+// Its value is %K.
+//
+// Params returns a map[string]interface{} with:
+//
+//	"k_period", "d_period"  int
+//	"k", "d"                float64, the current %K and %D
+type StochasticIndicator struct {
+	BaseIndicator
+	kPeriod int
+	dPeriod int
+	d       float64
+}
+
+// NewStochasticIndicator creates a new stochastic oscillator indicator
+func NewStochasticIndicator(kPeriod, dPeriod int) *StochasticIndicator {
+	return &StochasticIndicator{
+		BaseIndicator: BaseIndicator{name: "STOCH"},
+		kPeriod:       kPeriod,
+		dPeriod:       dPeriod,
+	}
+}
+
+func (i *StochasticIndicator) Params() interface{} {
+	return map[string]interface{}{
+		"k_period": i.kPeriod,
+		"d_period": i.dPeriod,
+		"k":        i.value,
+		"d":        i.d,
+	}
+}
+
+func (i *StochasticIndicator) Calculate(history *types.PriceHistory) error {
+	if i.kPeriod <= 0 || i.dPeriod <= 0 || history.Len() < i.kPeriod+i.dPeriod-1 {
+		return fmt.Errorf("insufficient data for stochastic calculation")
+	}
+
+	high, low, close, _ := historyBars(history)
+	k, d := Stochastic(high, low, close, i.kPeriod, i.dPeriod)
+	i.value = k[len(k)-1]
+	i.d = d[len(d)-1]
+	return nil
+}
+
+// WilliamsRIndicator tracks Williams %R of a price history
+type WilliamsRIndicator struct {
+	BaseIndicator
+	period int
+}
+
+// NewWilliamsRIndicator creates a new Williams %R indicator
+func NewWilliamsRIndicator(period int) *WilliamsRIndicator {
+	return &WilliamsRIndicator{
+		BaseIndicator: BaseIndicator{name: "WILLR"},
+		period:        period,
+	}
+}
+
+func (i *WilliamsRIndicator) Params() interface{} {
+	return map[string]interface{}{
+		"period": i.period,
+	}
+}
+
+func (i *WilliamsRIndicator) Calculate(history *types.PriceHistory) error {
+	if i.period <= 0 || history.Len() < i.period {
+		return fmt.Errorf("insufficient data for Williams %%R calculation")
+	}
+
+	high, low, close, _ := historyBars(history)
+	r := WilliamsR(high, low, close, i.period)
+	i.value = r[len(r)-1]
+	return nil
+}
+
+// alignedLength returns the length of the shortest of the series
+func alignedLength(series ...[]float64) int {
+	n := len(series[0])
+	for _, s := range series[1:] {
+		if len(s) < n {
+			n = len(s)
+		}
+	}
+	return n
+}
+
+// extremes returns the highest high and lowest low over [from, to]
+func extremes(high, low []float64, from, to int) (float64, float64) {
+	highest, lowest := high[from], low[from]
+	for i := from + 1; i <= to; i++ {
+		if high[i] > highest {
+			highest = high[i]
+		}
+		if low[i] < lowest {
+			lowest = low[i]
+		}
+	}
+	return highest, lowest
+}
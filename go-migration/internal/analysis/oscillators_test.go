@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestStochastic(t *testing.T) {
+	high := []float64{10, 12, 14, 13, 15}
+	low := []float64{8, 9, 11, 10, 12}
+	close := []float64{9, 11, 13, 11, 15}
+
+	k, d := Stochastic(high, low, close, 3, 2)
+	assert.Len(t, k, 5)
+	assert.Len(t, d, 5)
+
+	// Leading values stay zero until the windows fill
+	assert.Equal(t, []float64{0, 0}, k[:2])
+	assert.Equal(t, []float64{0, 0, 0}, d[:3])
+
+	// Window 8..14 closing at 13 is 5/6 of the range
+	assert.InDelta(t, 500.0/6, k[2], 1e-9)
+	assert.InDelta(t, 40.0, k[3], 1e-9) // 9..14 closing at 11
+	assert.InDelta(t, 100.0, k[4], 1e-9)
+	assert.InDelta(t, (500.0/6+40)/2, d[3], 1e-9)
+	assert.InDelta(t, 70.0, d[4], 1e-9)
+}
+
+func TestWilliamsR(t *testing.T) {
+	high := []float64{10, 12, 14, 13, 15}
+	low := []float64{8, 9, 11, 10, 12}
+	close := []float64{9, 11, 13, 11, 15}
+
+	r := WilliamsR(high, low, close, 3)
+	assert.Len(t, r, 5)
+	assert.Equal(t, []float64{0, 0}, r[:2])
+	assert.InDelta(t, -100.0/6, r[2], 1e-9)
+	assert.InDelta(t, -60.0, r[3], 1e-9)
+	assert.InDelta(t, 0.0, r[4], 1e-9)
+
+	// Williams %R is %K shifted down by 100
+	k, _ := Stochastic(high, low, close, 3, 1)
+	for i := 2; i < len(r); i++ {
+		assert.InDelta(t, k[i]-100, r[i], 1e-9)
+	}
+}
+
+func TestOscillatorIndicators(t *testing.T) {
+	history := crossHistory(9, 11, 13, 11, 15, 12)
+
+	stoch := NewStochasticIndicator(3, 2)
+	require.NoError(t, stoch.Calculate(history))
+	assert.Equal(t, "STOCH", stoch.Name())
+	// Ticks are their own high and low, 11..15 closing at 12
+	assert.InDelta(t, 25.0, stoch.Value(), 1e-9)
+	params := stoch.Params().(map[string]interface{})
+	assert.InDelta(t, (100.0+25)/2, params["d"].(float64), 1e-9)
+
+	willr := NewWilliamsRIndicator(3)
+	require.NoError(t, willr.Calculate(history))
+	assert.Equal(t, "WILLR", willr.Name())
+	assert.InDelta(t, -75.0, willr.Value(), 1e-9)
+
+	assert.Error(t, NewStochasticIndicator(5, 3).Calculate(history))
+	assert.Error(t, NewWilliamsRIndicator(7).Calculate(history))
+}
+
+func TestOscillatorIndicators_ReadCandleRanges(t *testing.T) {
+	history := types.NewPriceHistory(2)
+	history.Candles = []*types.PriceLevel{
+		{Price: 10, Extra: map[string]interface{}{"open": 9.0, "high": 14.0, "low": 8.0}},
+	}
+	history.Add(&types.PriceLevel{Price: 12})
+	history.Add(&types.PriceLevel{Price: 11})
+
+	willr := NewWilliamsRIndicator(3)
+	require.NoError(t, willr.Calculate(history))
+	// The candle's 8..14 range bounds the window
+	assert.InDelta(t, -50.0, willr.Value(), 1e-9)
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// OBV returns On-Balance Volume, adding each bar's volume on an up close and
+// subtracting it on a down close. The output is aligned with the inputs.
+func OBV(close, volume []float64) ([]float64, error) {
+	if err := checkVolume(close, volume); err != nil {
+		return nil, err
+	}
+
+	obv := make([]float64, len(close))
+	for i := 1; i < len(close); i++ {
+		switch {
+		case close[i] > close[i-1]:
+			obv[i] = obv[i-1] + volume[i]
+		case close[i] < close[i-1]:
+			obv[i] = obv[i-1] - volume[i]
+		default:
+			obv[i] = obv[i-1]
+		}
+	}
+	return obv, nil
+}
+
+// VWAP returns the volume-weighted typical price, accumulated from the start
+// of each period-bar window so it resets every window instead of averaging
+// over the whole series. Bars without volume carry the previous value.
+func VWAP(high, low, close, volume []float64, period int) ([]float64, error) {
+	if err := checkVolume(close, volume); err != nil {
+		return nil, err
+	}
+	if len(high) != len(close) || len(low) != len(close) {
+		return nil, fmt.Errorf("vwap needs aligned series: %d highs, %d lows, %d closes",
+			len(high), len(low), len(close))
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("vwap period must be positive, got %d", period)
+	}
+
+	vwap := make([]float64, len(close))
+	var priceVolume, totalVolume float64
+	for i := range close {
+		if i%period == 0 {
+			priceVolume, totalVolume = 0, 0
+		}
+		typical := (high[i] + low[i] + close[i]) / 3
+		priceVolume += typical * volume[i]
+		totalVolume += volume[i]
+
+		switch {
+		case totalVolume > 0:
+			vwap[i] = priceVolume / totalVolume
+		case i > 0:
+			vwap[i] = vwap[i-1]
+		default:
+			vwap[i] = typical
+		}
+	}
+	return vwap, nil
+}
+
+// OBVIndicator tracks the On-Balance Volume of a price history
+type OBVIndicator struct {
+	BaseIndicator
+}
+
+// NewOBVIndicator creates a new On-Balance Volume indicator
+func NewOBVIndicator() *OBVIndicator {
+	return &OBVIndicator{BaseIndicator: BaseIndicator{name: "OBV"}}
+}
+
+func (i *OBVIndicator) Params() interface{} {
+	return map[string]interface{}{}
+}
+
+func (i *OBVIndicator) Calculate(history *types.PriceHistory) error {
+	if history.Len() < 2 {
+		return fmt.Errorf("insufficient data for OBV calculation")
+	}
+
+	_, _, close, volume := historyBars(history)
+	obv, err := OBV(close, volume)
+	if err != nil {
+		return err
+	}
+	i.value = obv[len(obv)-1]
+	return nil
+}
+
+// VWAPIndicator tracks the windowed VWAP of a price history
+type VWAPIndicator struct {
+	BaseIndicator
+	period int
+}
+
+// NewVWAPIndicator creates a new VWAP indicator resetting every period bars
+func NewVWAPIndicator(period int) *VWAPIndicator {
+	return &VWAPIndicator{
+		BaseIndicator: BaseIndicator{name: "VWAP"},
+		period:        period,
+	}
+}
+
+func (i *VWAPIndicator) Params() interface{} {
+	return map[string]interface{}{
+		"period": i.period,
+	}
+}
+
+func (i *VWAPIndicator) Calculate(history *types.PriceHistory) error {
+	if history.Len() == 0 {
+		return fmt.Errorf("insufficient data for VWAP calculation")
+	}
+
+	high, low, close, volume := historyBars(history)
+	vwap, err := VWAP(high, low, close, volume, i.period)
+	if err != nil {
+		return err
+	}
+	i.value = vwap[len(vwap)-1]
+	return nil
+}
+
+// checkVolume rejects volume series that do not line up with the closes
+func checkVolume(close, volume []float64) error {
+	if len(volume) != len(close) {
+		return fmt.Errorf("volume has %d points but close has %d", len(volume), len(close))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestOBV(t *testing.T) {
+	obv, err := OBV([]float64{10, 11, 11, 9, 12}, []float64{100, 50, 70, 30, 20})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 50, 50, 20, 40}, obv)
+}
+
+func TestVWAP_ResetsEachWindow(t *testing.T) {
+	high := []float64{11, 13, 21, 23}
+	low := []float64{9, 11, 19, 21}
+	close := []float64{10, 12, 20, 22}
+	volume := []float64{1, 3, 2, 2}
+
+	vwap, err := VWAP(high, low, close, volume, 2)
+	require.NoError(t, err)
+	assert.InDelta(t, 10.0, vwap[0], 1e-9)
+	assert.InDelta(t, 11.5, vwap[1], 1e-9) // (10*1 + 12*3) / 4
+	// A new window starts at the third bar and ignores earlier volume
+	assert.InDelta(t, 20.0, vwap[2], 1e-9)
+	assert.InDelta(t, 21.0, vwap[3], 1e-9)
+}
+
+func TestVolumeIndicators_RejectMisalignedVolume(t *testing.T) {
+	_, err := OBV([]float64{1, 2, 3}, []float64{1, 2})
+	assert.ErrorContains(t, err, "volume has 2 points but close has 3")
+
+	prices := []float64{1, 2, 3}
+	_, err = VWAP(prices, prices, prices, []float64{1}, 2)
+	assert.Error(t, err)
+	_, err = VWAP(prices, prices, prices, []float64{1, 1, 1}, 0)
+	assert.Error(t, err)
+}
+
+func TestVolumeIndicators(t *testing.T) {
+	history := types.NewPriceHistory(5)
+	prices := []float64{10, 11, 11, 9, 12}
+	volumes := []float64{100, 50, 70, 30, 20}
+	for i := range prices {
+		history.Add(&types.PriceLevel{Symbol: "SOL", Price: prices[i], Volume: volumes[i]})
+	}
+
+	obv := NewOBVIndicator()
+	require.NoError(t, obv.Calculate(history))
+	assert.Equal(t, "OBV", obv.Name())
+	assert.Equal(t, 40.0, obv.Value())
+
+	vwap := NewVWAPIndicator(2)
+	require.NoError(t, vwap.Calculate(history))
+	assert.Equal(t, "VWAP", vwap.Name())
+	// The last window holds only the final bar
+	assert.Equal(t, 12.0, vwap.Value())
+
+	assert.Error(t, NewOBVIndicator().Calculate(types.NewPriceHistory(5)))
+}
@@ -77,7 +77,7 @@ func (a *SignalAnalyzer) AnalyzeSignals(signals []*pricing.Signal, trades []*Tra
 				maxReturn = tradeReturn
 			}
 			if tradeReturn < minReturn {
-				minReturn = minReturn
+				minReturn = tradeReturn
 			}
 
 			// Check if signal was accurate
@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// maxJSONLine bounds the size of a single NDJSON record
+const maxJSONLine = 1024 * 1024
+
+// JSONDataFeed implements DataFeed over newline-delimited JSON files of
+// types.PriceUpdate records, such as archived pump.fun token updates.
+// Malformed lines are skipped and counted rather than ending the feed.
+type JSONDataFeed struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	current *pricing.PriceLevel
+	skipped int
+}
+
+// NewJSONDataFeed creates a data feed streaming the NDJSON file at path
+func NewJSONDataFeed(path string) (DataFeed, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLine)
+	return &JSONDataFeed{
+		file:    file,
+		scanner: scanner,
+	}, nil
+}
+
+// Next advances to the next well-formed record
+func (f *JSONDataFeed) Next() bool {
+	for f.scanner.Scan() {
+		line := f.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var update types.PriceUpdate
+		if err := json.Unmarshal(line, &update); err != nil || update.Symbol == "" || update.Timestamp.IsZero() {
+			f.skipped++
+			continue
+		}
+
+		f.current = &pricing.PriceLevel{
+			Symbol:    update.Symbol,
+			Price:     update.Price.InexactFloat64(),
+			Volume:    update.Volume.InexactFloat64(),
+			Timestamp: update.Timestamp,
+		}
+		return true
+	}
+	return false
+}
+
+// Current returns current price level
+func (f *JSONDataFeed) Current() *pricing.PriceLevel {
+	return f.current
+}
+
+// Skipped returns the number of malformed lines skipped so far
+func (f *JSONDataFeed) Skipped() int {
+	return f.skipped
+}
+
+// Close closes the data feed
+func (f *JSONDataFeed) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,49 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONDataFeed(t *testing.T) {
+	content := `{"symbol":"PEPE","price":"0.0001","volume":"500","timestamp":"2024-02-01T10:00:00Z"}
+not json
+{"symbol":"PEPE","price":"0.00012","volume":"750","timestamp":"2024-02-01T10:01:00Z"}
+
+{"price":"0.00013","timestamp":"2024-02-01T10:02:00Z"}
+{"symbol":"DOGE","price":"0.08","volume":"1000","timestamp":"2024-02-01T10:03:00Z"}
+`
+	path := filepath.Join(t.TempDir(), "updates.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	feed, err := NewJSONDataFeed(path)
+	require.NoError(t, err)
+	defer feed.Close()
+
+	assert.True(t, feed.Next())
+	current := feed.Current()
+	assert.Equal(t, "PEPE", current.Symbol)
+	assert.Equal(t, 0.0001, current.Price)
+	assert.Equal(t, 500.0, current.Volume)
+	assert.Equal(t, "2024-02-01T10:00:00Z", current.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+
+	// The malformed line is skipped
+	assert.True(t, feed.Next())
+	assert.Equal(t, 0.00012, feed.Current().Price)
+
+	// So are the blank line and the record without a symbol
+	assert.True(t, feed.Next())
+	assert.Equal(t, "DOGE", feed.Current().Symbol)
+
+	assert.False(t, feed.Next())
+	assert.Equal(t, 2, feed.(*JSONDataFeed).Skipped())
+}
+
+func TestJSONDataFeed_InvalidFile(t *testing.T) {
+	_, err := NewJSONDataFeed(filepath.Join(t.TempDir(), "missing.ndjson"))
+	assert.Error(t, err)
+}
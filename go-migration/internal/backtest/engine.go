@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/pricing"
@@ -23,27 +26,66 @@ type Engine struct {
 	dataFeed  DataFeed
 	storage   Storage
 	analyzer  *SignalAnalyzer
+	funding   types.FundingProvider
+	books     OrderBookSource
+	pending   []*pendingSignal
+	metricsTrades int // Closed trades the metrics were last built from
+	halted     error // Overdraft that stopped the backtest in OverdraftHalt mode
+	barTimes   map[int64]struct{} // Distinct bar timestamps replayed, for annualizing short runs
+	limits     []*limitOrder // Resting limit entries
+	fillRand   *rand.Rand    // Draws for the volume fill model
+	lastPrices map[string]float64   // Last price per symbol, for the ATR
+	ranges     map[string][]float64 // Recent bar ranges per symbol, for the ATR
 	mu        sync.RWMutex
 }
 
+// pendingSignal is a signal waiting out the configured execution latency
+type pendingSignal struct {
+	signal *pricing.Signal
+	bars   int
+	due    time.Time
+}
+
 // NewEngine creates a new backtest engine
 func NewEngine(config Config, logger *zap.Logger, engine *pricing.Engine, storage Storage) *Engine {
-	return &Engine{
+	e := &Engine{
 		config:  config,
 		logger:  logger,
 		engine:  engine,
 		storage: storage,
 		portfolio: &Portfolio{
-			Balance:    config.InitialBalance,
-			Positions:  make(map[string]*Position),
-			Commission: config.Commission,
-			Slippage:   config.Slippage,
+			Balance:     config.InitialBalance,
+			Positions:   make(map[string]*Position),
+			Commission:  config.Commission,
+			MakerRebate: config.MakerRebate,
+			Slippage:    config.Slippage,
 		},
 		results: &Result{
 			Trades:  make([]*Trade, 0),
 			Metrics: NewMetrics(),
 		},
 	}
+
+	if config.LimitOrders.Model == LimitFillVolume {
+		e.fillRand = rand.New(rand.NewSource(config.LimitOrders.Seed))
+	}
+	if config.Quotes.Enabled() {
+		e.portfolio.Base = config.Quotes.Base
+		e.portfolio.Rates = config.Quotes.Rates
+		e.portfolio.Balances = make(map[string]float64, len(config.Quotes.Balances))
+		for quote, balance := range config.Quotes.Balances {
+			e.portfolio.Balances[quote] = balance
+		}
+	}
+	return e
+}
+
+// SetFundingProvider enables funding accounting for the symbols listed in
+// Config.Funding using rates from provider
+func (e *Engine) SetFundingProvider(provider types.FundingProvider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.funding = provider
 }
 
 // Run executes the backtest
@@ -53,12 +95,17 @@ func (e *Engine) Run(ctx context.Context) (*Result, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize data feed: %w", err)
 	}
+	source := feed
+	if e.config.Gaps.Enabled() && e.config.Interval > 0 {
+		feed = newGapFillingFeed(feed, e.config.Gaps, e.config.Interval)
+	}
 	e.dataFeed = feed
 	defer e.dataFeed.Close()
 
 	// Subscribe to signals
 	signals := e.engine.GetSignals()
 	var collectedSignals []*pricing.Signal
+	var first, last time.Time
 
 	// Process historical data
 	for e.dataFeed.Next() {
@@ -68,9 +115,19 @@ func (e *Engine) Run(ctx context.Context) (*Result, error) {
 		default:
 			// Process next price update
 			update := e.dataFeed.Current()
+			e.countBar(update)
+			if first.IsZero() {
+				first = update.Timestamp
+			}
+			last = update.Timestamp
+			e.releaseSignals(update)
+			e.matchLimits(update)
 			if err := e.handleUpdate(update); err != nil {
 				e.logger.Error("Failed to process update",
 					zap.Error(err))
+				if e.halted != nil {
+					break
+				}
 				continue
 			}
 
@@ -84,7 +141,7 @@ func (e *Engine) Run(ctx context.Context) (*Result, error) {
 				collectedSignals = append(collectedSignals, pricingSignal)
 
 				// Process signal
-				if err := e.handleSignal(pricingSignal); err != nil {
+				if err := e.submitSignal(pricingSignal); err != nil {
 					e.logger.Error("Failed to process signal",
 						zap.Error(err))
 				}
@@ -94,10 +151,33 @@ func (e *Engine) Run(ctx context.Context) (*Result, error) {
 			// Update metrics
 			e.updateMetrics()
 		}
+		if e.halted != nil {
+			e.calculateResults()
+			return e.results, fmt.Errorf("backtest halted: %w", e.halted)
+		}
 	}
 
 	// Calculate final results
 	e.calculateResults()
+	if e.config.WalkForward.Enabled() {
+		start, end := e.config.StartTime, e.config.EndTime
+		if start.IsZero() || end.IsZero() {
+			start, end = first, last
+		}
+		e.results.WalkForward = e.walkForward(collectedSignals, start, end)
+	}
+	if filled, ok := e.dataFeed.(interface{ Synthesized() int }); ok {
+		e.results.SynthesizedBars = filled.Synthesized()
+		e.logger.Info("Filled gaps in historical data",
+			zap.Int("synthesized_bars", e.results.SynthesizedBars),
+			zap.String("mode", string(e.config.Gaps.Mode)))
+	}
+
+	if skipping, ok := source.(interface{ Skipped() int }); ok && skipping.Skipped() > 0 {
+		e.results.SkippedRecords = skipping.Skipped()
+		e.logger.Warn("Skipped malformed records in historical data",
+			zap.Int("skipped_records", e.results.SkippedRecords))
+	}
 
 	// Save results
 	if err := e.storage.SaveResult(ctx, e.results); err != nil {
@@ -127,9 +207,9 @@ func convertSignal(signal *types.Signal) *pricing.Signal {
 
 	return &pricing.Signal{
 		Symbol:     signal.Symbol,
-		Type:      signal.Type,
+		Type:      string(signal.Type),
 		Direction: signal.Direction,
-		Price:     signal.Price,
+		Price:     signal.Price.InexactFloat64(),
 		Confidence: signal.Confidence,
 		Timestamp: signal.Timestamp,
 		Indicators: indicators,
@@ -140,6 +220,12 @@ func (e *Engine) initDataFeed(ctx context.Context) (DataFeed, error) {
 	switch e.config.DataSource {
 	case "csv":
 		return NewCSVDataFeed(e.config.Symbol)
+	case "json":
+		path := e.config.DataPath
+		if path == "" {
+			path = filepath.Join("data", e.config.Symbol+".ndjson")
+		}
+		return NewJSONDataFeed(path)
 	case "postgres":
 		config := PostgresConfig{
 			Host:     "localhost",
@@ -156,9 +242,15 @@ func (e *Engine) initDataFeed(ctx context.Context) (DataFeed, error) {
 }
 
 func (e *Engine) handleUpdate(update *pricing.PriceLevel) error {
+	e.trackRange(update)
+
 	// Update positions P&L
 	for symbol, pos := range e.portfolio.Positions {
 		if symbol == update.Symbol {
+			if err := e.applyFunding(pos, update); err != nil {
+				return fmt.Errorf("failed to apply funding: %w", err)
+			}
+
 			// Calculate unrealized P&L
 			pnl := e.calculatePnL(pos, update.Price)
 
@@ -186,6 +278,9 @@ func (e *Engine) handleSignal(signal *pricing.Signal) error {
 				return err
 			}
 		}
+	} else if e.config.LimitOrders.Enabled {
+		// Rest a limit order to enter at a better price
+		e.placeLimit(signal)
 	} else {
 		// Open new position
 		if err := e.openPosition(signal); err != nil {
@@ -195,6 +290,49 @@ func (e *Engine) handleSignal(signal *pricing.Signal) error {
 	return nil
 }
 
+// submitSignal handles signal immediately, or queues it until the
+// configured execution latency has passed
+func (e *Engine) submitSignal(signal *pricing.Signal) error {
+	if !e.config.Latency.Enabled() {
+		return e.handleSignal(signal)
+	}
+
+	e.pending = append(e.pending, &pendingSignal{
+		signal: signal,
+		bars:   e.config.Latency.Bars,
+		due:    signal.Timestamp.Add(e.config.Latency.Duration),
+	})
+	return nil
+}
+
+// releaseSignals fills queued signals for the update's symbol whose latency
+// has elapsed, at the update's price rather than the signal's
+func (e *Engine) releaseSignals(update *pricing.PriceLevel) {
+	remaining := e.pending[:0]
+	for _, p := range e.pending {
+		if p.signal.Symbol != update.Symbol {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		p.bars--
+		if p.bars > 0 || update.Timestamp.Before(p.due) {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		filled := *p.signal
+		filled.Price = update.Price
+		filled.Timestamp = update.Timestamp
+		if err := e.handleSignal(&filled); err != nil {
+			e.logger.Error("Failed to process delayed signal",
+				zap.String("symbol", filled.Symbol),
+				zap.Error(err))
+		}
+	}
+	e.pending = remaining
+}
+
 func (e *Engine) calculatePnL(pos *Position, currentPrice float64) float64 {
 	if pos.Direction == "long" {
 		return (currentPrice - pos.EntryPrice) * pos.Quantity
@@ -223,12 +361,27 @@ func (e *Engine) openPosition(signal *pricing.Signal) error {
 
 	// Apply slippage
 	entryPrice := signal.Price * (1 + e.portfolio.Slippage)
-	commission := entryPrice * size * e.portfolio.Commission
+	if fill, ok, err := e.fillFromBook(signal.Symbol, signal.Timestamp, signal.Direction != "short", size); ok {
+		if err != nil {
+			return err
+		}
+		if fill.Quantity < size*e.config.OrderBook.MinFill {
+			return fmt.Errorf("partial fill %f of %f below minimum", fill.Quantity, size)
+		}
+		size, entryPrice = fill.Quantity, fill.Price
+	}
+	return e.enterPosition(signal, size, entryPrice, types.LiquidityTaker)
+}
+
+// enterPosition opens a position of size for signal filled at entryPrice,
+// paying the commission for the fill's liquidity role
+func (e *Engine) enterPosition(signal *pricing.Signal, size, entryPrice float64, role types.LiquidityRole) error {
+	commission := e.portfolio.CommissionFor(entryPrice*size, role)
 
 	// Check if we have enough balance
 	cost := entryPrice*size + commission
-	if cost > e.portfolio.Balance {
-		return fmt.Errorf("insufficient balance")
+	if err := e.debit(signal.Symbol, -cost, "entry"); err != nil {
+		return err
 	}
 
 	// Open position
@@ -238,21 +391,41 @@ func (e *Engine) openPosition(signal *pricing.Signal) error {
 		EntryPrice: entryPrice,
 		Quantity:   size,
 		EntryTime:  signal.Timestamp,
+		FundedAt:   signal.Timestamp,
+		StopPrice:  e.initialStop(signal.Direction, entryPrice),
+		Commission: commission,
 	}
 
-	// Update balance
-	e.portfolio.Balance -= cost
-
 	return nil
 }
 
 func (e *Engine) closePosition(pos *Position, update *pricing.PriceLevel) error {
 	// Apply slippage
 	exitPrice := update.Price * (1 - e.portfolio.Slippage)
-	commission := exitPrice * pos.Quantity * e.portfolio.Commission
+	slippage := e.portfolio.Slippage
+	if fill, ok, err := e.fillFromBook(pos.Symbol, update.Timestamp, pos.Direction == "short", pos.Quantity); ok {
+		if err != nil {
+			return err
+		}
+		// An exhausted book fills the remainder at the last level touched
+		fill.Price = (fill.Price*fill.Quantity + fill.Worst*(pos.Quantity-fill.Quantity)) / pos.Quantity
+		exitPrice, slippage = fill.Price, fill.Slippage()
+	}
+	commission := e.portfolio.CommissionFor(exitPrice*pos.Quantity, types.LiquidityTaker)
 
-	// Calculate P&L
-	pnl := e.calculatePnL(pos, exitPrice)
+	// Calculate P&L, net of funding already settled against the balance
+	pnl := e.calculatePnL(pos, exitPrice) - pos.Funding
+
+	risk := types.InitialRisk(pos.EntryPrice, pos.StopPrice, pos.Quantity)
+	if pos.StopPrice == 0 {
+		risk = 0
+	}
+	rMultiple, _ := types.RMultiple(pnl, risk)
+
+	// Update balance
+	if err := e.debit(pos.Symbol, exitPrice*pos.Quantity-commission, "exit"); err != nil {
+		return err
+	}
 
 	// Record trade
 	e.results.Trades = append(e.results.Trades, &Trade{
@@ -264,53 +437,108 @@ func (e *Engine) closePosition(pos *Position, update *pricing.PriceLevel) error
 		ExitPrice:  exitPrice,
 		Quantity:   pos.Quantity,
 		PnL:        pnl,
-		Commission: commission,
-		Slippage:   e.portfolio.Slippage,
+		Commission: pos.Commission + commission,
+		Slippage:   slippage,
+		Funding:    pos.Funding,
+		Quote:      e.portfolio.QuoteOf(pos.Symbol),
+		InitialRisk: risk,
+		RMultiple:  rMultiple,
+		Liquidity:  types.LiquidityTaker,
 	})
 
-	// Update balance
-	e.portfolio.Balance += exitPrice*pos.Quantity - commission
-
 	// Remove position
 	delete(e.portfolio.Positions, pos.Symbol)
 
 	return nil
 }
 
-func (e *Engine) calculatePositionSize(signal *pricing.Signal) float64 {
-	// Implement position sizing logic (e.g., fixed fractional)
-	riskPerTrade := 0.02 // 2% risk per trade
-	availableBalance := e.portfolio.Balance
+// applyFunding settles every funding time, aligned to the funding interval,
+// that has passed between the position's last funding and the update
+func (e *Engine) applyFunding(pos *Position, update *pricing.PriceLevel) error {
+	interval := e.config.Funding.Interval
+	if e.funding == nil || !e.config.Funding.Applies(pos.Symbol) {
+		return nil
+	}
+
+	for next := pos.FundedAt.Truncate(interval).Add(interval); !next.After(update.Timestamp); next = next.Add(interval) {
+		rate, err := e.funding.FundingRate(context.Background(), pos.Symbol, next)
+		if err != nil {
+			return err
+		}
+		notional := decimal.NewFromFloat(update.Price * pos.Quantity)
+		payment := types.FundingPayment(pos.Direction == "long", notional, rate).InexactFloat64()
+		if err := e.debit(pos.Symbol, -payment, "funding"); err != nil {
+			return err
+		}
+
+		pos.Funding += payment
+		pos.FundedAt = next
+	}
+	return nil
+}
 
-	return (availableBalance * riskPerTrade) / signal.Price
+// initialStop returns the stop placed Config.StopLoss (2% by default) away
+// from entry on the losing side
+func (e *Engine) initialStop(direction string, entryPrice float64) float64 {
+	stopLoss := e.config.StopLoss
+	if stopLoss <= 0 {
+		stopLoss = 0.02
+	}
+	if direction == "short" {
+		return entryPrice * (1 + stopLoss)
+	}
+	return entryPrice * (1 - stopLoss)
 }
 
 func (e *Engine) calculateResults() {
+	e.updateMetrics()
+	e.results.EquityCurve = e.results.Metrics.EquityCurve
+
 	// Calculate basic metrics
-	var totalPnL, grossProfit, grossLoss float64
+	var totalPnL, grossProfit, grossLoss, notional, fees float64
+	var rMultiples []float64
 
 	for _, trade := range e.results.Trades {
-		if trade.PnL > 0 {
+		notional += e.portfolio.ToBase(trade.Quote, (trade.EntryPrice+trade.ExitPrice)*trade.Quantity)
+		fees += e.portfolio.ToBase(trade.Quote, trade.Commission)
+		if trade.InitialRisk > 0 {
+			rMultiples = append(rMultiples, trade.RMultiple)
+		}
+		pnl := e.portfolio.ToBase(trade.Quote, trade.PnL)
+		if pnl > 0 {
 			e.results.WinningTrades++
-			grossProfit += trade.PnL
+			grossProfit += pnl
 		} else {
 			e.results.LosingTrades++
-			grossLoss += -trade.PnL
+			grossLoss += -pnl
 		}
-		totalPnL += trade.PnL
+		totalPnL += pnl
 	}
 
 	e.results.TotalTrades = len(e.results.Trades)
+	e.results.RStats = types.NewRStats(rMultiples)
+	equity := make([]float64, len(e.results.Metrics.EquityCurve))
+	for i, point := range e.results.Metrics.EquityCurve {
+		equity[i] = point.Equity
+	}
+	if len(equity) == 0 {
+		equity = append(equity, e.config.initialBaseValue())
+	}
+	e.results.Turnover = types.NewTurnoverStats(e.results.TotalTrades, notional, fees, equity)
 	if e.results.TotalTrades > 0 {
 		e.results.WinRate = float64(e.results.WinningTrades) / float64(e.results.TotalTrades)
 	}
 	if grossLoss > 0 {
 		e.results.ProfitFactor = grossProfit / grossLoss
 	}
+	excess := excessReturns(e.results.Trades, e.config.RiskFreeRate)
+	e.results.SharpeRatio = sharpeRatio(excess)
+	e.results.SortinoRatio = sortinoRatio(excess)
 
-	e.results.FinalBalance = e.portfolio.Balance
-	e.results.TotalReturn = (e.portfolio.Balance - e.config.InitialBalance) / e.config.InitialBalance
-	e.results.AnnualizedReturn = calculateAnnualizedReturn(e.results.TotalReturn, e.config.StartTime, e.config.EndTime)
+	initial := e.config.initialBaseValue()
+	e.results.FinalBalance = e.portfolio.BaseValue()
+	e.results.TotalReturn = (e.results.FinalBalance - initial) / initial
+	e.results.AnnualizedReturn = calculateAnnualizedReturn(e.results.TotalReturn, e.config.StartTime, e.config.EndTime, len(e.barTimes), e.config.Interval)
 
 	// Calculate max drawdown
 	if len(e.results.Metrics.DrawdownSeries) > 0 {
@@ -326,10 +554,31 @@ func (e *Engine) calculateResults() {
 
 // Helper functions
 
-func calculateAnnualizedReturn(totalReturn float64, start, end time.Time) float64 {
-	years := end.Sub(start).Hours() / (24 * 365)
-	if years == 0 {
+// countBar records the bar update belongs to. Updates for several symbols
+// share a bar, so bars are counted by distinct timestamp rather than by
+// update.
+func (e *Engine) countBar(update *pricing.PriceLevel) {
+	if e.barTimes == nil {
+		e.barTimes = make(map[int64]struct{})
+	}
+	e.barTimes[update.Timestamp.UnixNano()] = struct{}{}
+}
+
+// calculateAnnualizedReturn compounds totalReturn over a 365-day year. The
+// period is bars of interval when both are known, so sub-day runs annualize
+// from the data actually replayed, and start to end otherwise. A loss of
+// the whole balance or more annualizes to -100%.
+func calculateAnnualizedReturn(totalReturn float64, start, end time.Time, bars int, interval time.Duration) float64 {
+	period := end.Sub(start)
+	if bars > 0 && interval > 0 {
+		period = time.Duration(bars) * interval
+	}
+	years := period.Hours() / (24 * 365)
+	if years <= 0 {
 		return 0
 	}
+	if totalReturn <= -1 {
+		return -1
+	}
 	return math.Pow(1+totalReturn, 1/years) - 1
 }
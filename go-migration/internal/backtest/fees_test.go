@@ -0,0 +1,40 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestPortfolio_CommissionFor(t *testing.T) {
+	portfolio := &Portfolio{
+		Commission:  0.001,
+		MakerRebate: 0.0002,
+	}
+
+	notional := 10000.0
+
+	// Taker fill pays the standard fee
+	takerFee := portfolio.CommissionFor(notional, types.LiquidityTaker)
+	assert.InDelta(t, 10.0, takerFee, 1e-9)
+	assert.InDelta(t, 10010.0, notional+takerFee, 1e-9)
+
+	// Maker fill is credited the rebate, reducing net cost
+	makerFee := portfolio.CommissionFor(notional, types.LiquidityMaker)
+	assert.InDelta(t, -2.0, makerFee, 1e-9)
+	assert.InDelta(t, 9998.0, notional+makerFee, 1e-9)
+}
+
+func TestFeeSchedule_Commission(t *testing.T) {
+	fees := types.FeeSchedule{
+		TakerFee:    decimal.NewFromFloat(0.001),
+		MakerRebate: decimal.NewFromFloat(0.0002),
+	}
+	notional := decimal.NewFromInt(10000)
+
+	assert.True(t, decimal.NewFromInt(10).Equal(fees.Commission(types.LiquidityTaker, notional)))
+	assert.True(t, decimal.NewFromInt(-2).Equal(fees.Commission(types.LiquidityMaker, notional)))
+}
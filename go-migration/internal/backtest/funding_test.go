@@ -0,0 +1,87 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type fixedFunding struct {
+	rate decimal.Decimal
+}
+
+func (f fixedFunding) FundingRate(ctx context.Context, symbol string, at time.Time) (decimal.Decimal, error) {
+	return f.rate, nil
+}
+
+func TestEngine_LongPositionPaysFunding(t *testing.T) {
+	start := time.Date(2025, 2, 1, 1, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		Funding: types.FundingConfig{
+			Interval: 8 * time.Hour,
+			Symbols:  []string{"SOL-PERP"},
+		},
+	}, zap.NewNop(), nil, nil)
+	engine.SetFundingProvider(fixedFunding{rate: decimal.NewFromFloat(0.001)})
+
+	pos := &Position{
+		Symbol:     "SOL-PERP",
+		Direction:  "long",
+		EntryPrice: 100,
+		Quantity:   10,
+		EntryTime:  start,
+		FundedAt:   start,
+	}
+	engine.portfolio.Positions[pos.Symbol] = pos
+
+	// Flat price for a day crosses the 08:00, 16:00 and 00:00 funding times
+	for h := 1; h <= 24; h++ {
+		assert.NoError(t, engine.handleUpdate(&pricing.PriceLevel{
+			Symbol:    "SOL-PERP",
+			Price:     100,
+			Timestamp: start.Add(time.Duration(h) * time.Hour),
+		}))
+	}
+	assert.InDelta(t, 3.0, pos.Funding, 1e-9) // 3 x 0.1% of 1000 notional
+	assert.InDelta(t, 9997.0, engine.portfolio.Balance, 1e-9)
+
+	assert.NoError(t, engine.closePosition(pos, &pricing.PriceLevel{
+		Symbol:    "SOL-PERP",
+		Price:     100,
+		Timestamp: start.Add(24 * time.Hour),
+	}))
+	trade := engine.results.Trades[0]
+	assert.InDelta(t, -3.0, trade.PnL, 1e-9)
+	assert.InDelta(t, 3.0, trade.Funding, 1e-9)
+}
+
+func TestEngine_FundingIsOptInPerSymbol(t *testing.T) {
+	start := time.Date(2025, 2, 1, 1, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		Funding: types.FundingConfig{
+			Interval: 8 * time.Hour,
+			Symbols:  []string{"SOL-PERP"},
+		},
+	}, zap.NewNop(), nil, nil)
+	engine.SetFundingProvider(fixedFunding{rate: decimal.NewFromFloat(0.001)})
+
+	pos := &Position{Symbol: "BONK", Direction: "long", EntryPrice: 100, Quantity: 10, FundedAt: start}
+	engine.portfolio.Positions[pos.Symbol] = pos
+
+	assert.NoError(t, engine.handleUpdate(&pricing.PriceLevel{
+		Symbol:    "BONK",
+		Price:     100,
+		Timestamp: start.Add(24 * time.Hour),
+	}))
+	assert.Zero(t, pos.Funding)
+	assert.Equal(t, 10000.0, engine.portfolio.Balance)
+}
@@ -0,0 +1,100 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// GapFillMode selects how missing bars in historical data are handled
+type GapFillMode string
+
+const (
+	GapSkip        GapFillMode = "skip"         // Leave gaps in the feed
+	GapForwardFill GapFillMode = "forward_fill" // Repeat the last price
+	GapLinear      GapFillMode = "linear"       // Interpolate between the surrounding prices
+)
+
+// GapConfig fills gaps longer than Config.Interval with synthesized bars, one
+// per missing interval, so indicator windows keep a constant time span.
+// Synthesized bars carry no volume and are marked with Extra["synthesized"].
+type GapConfig struct {
+	Mode GapFillMode `yaml:"mode"`
+}
+
+// Enabled reports whether gaps are filled
+func (c GapConfig) Enabled() bool {
+	return c.Mode == GapForwardFill || c.Mode == GapLinear
+}
+
+// gapFillingFeed wraps a DataFeed, emitting synthesized bars between real
+// bars more than interval apart
+type gapFillingFeed struct {
+	DataFeed
+	mode        GapFillMode
+	interval    time.Duration
+	prev        *pricing.PriceLevel // Last real bar
+	next        *pricing.PriceLevel // Real bar held back while its gap is filled
+	current     *pricing.PriceLevel
+	synthesized int
+}
+
+func newGapFillingFeed(feed DataFeed, config GapConfig, interval time.Duration) *gapFillingFeed {
+	return &gapFillingFeed{DataFeed: feed, mode: config.Mode, interval: interval}
+}
+
+// Next advances to the next real or synthesized bar
+func (f *gapFillingFeed) Next() bool {
+	if f.next != nil {
+		if bar := f.fill(); bar != nil {
+			f.current = bar
+			return true
+		}
+		f.current, f.prev, f.next = f.next, f.next, nil
+		return true
+	}
+
+	if !f.DataFeed.Next() {
+		return false
+	}
+	bar := f.DataFeed.Current()
+	if f.prev != nil && bar.Timestamp.Sub(f.prev.Timestamp) > f.interval {
+		f.next = bar
+		return f.Next()
+	}
+	f.current, f.prev = bar, bar
+	return true
+}
+
+// Current returns the current bar
+func (f *gapFillingFeed) Current() *pricing.PriceLevel {
+	return f.current
+}
+
+// Synthesized returns how many bars have been filled in
+func (f *gapFillingFeed) Synthesized() int {
+	return f.synthesized
+}
+
+// fill returns the bar one interval after the current one, or nil once the
+// held back real bar is due
+func (f *gapFillingFeed) fill() *pricing.PriceLevel {
+	at := f.current.Timestamp.Add(f.interval)
+	if !at.Before(f.next.Timestamp) {
+		return nil
+	}
+
+	price := f.prev.Price
+	if f.mode == GapLinear {
+		progress := float64(at.Sub(f.prev.Timestamp)) / float64(f.next.Timestamp.Sub(f.prev.Timestamp))
+		price += (f.next.Price - f.prev.Price) * progress
+	}
+
+	f.synthesized++
+	return &pricing.PriceLevel{
+		Symbol:    f.prev.Symbol,
+		Price:     price,
+		Timestamp: at,
+		Extra:     map[string]interface{}{"synthesized": true},
+	}
+}
@@ -0,0 +1,89 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// sliceFeed replays a fixed series of bars
+type sliceFeed struct {
+	bars []*pricing.PriceLevel
+	pos  int
+}
+
+func (f *sliceFeed) Next() bool {
+	f.pos++
+	return f.pos <= len(f.bars)
+}
+
+func (f *sliceFeed) Current() *pricing.PriceLevel {
+	return f.bars[f.pos-1]
+}
+
+func (f *sliceFeed) Close() error {
+	return nil
+}
+
+// gappedSeries returns minute bars with the 10:02 and 10:03 bars missing
+func gappedSeries() *sliceFeed {
+	start := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	bar := func(minute int, price float64) *pricing.PriceLevel {
+		return &pricing.PriceLevel{Symbol: "BONK", Price: price, Volume: 1, Timestamp: start.Add(time.Duration(minute) * time.Minute)}
+	}
+	return &sliceFeed{bars: []*pricing.PriceLevel{bar(0, 100), bar(1, 110), bar(4, 140), bar(5, 150)}}
+}
+
+func drain(feed DataFeed) []*pricing.PriceLevel {
+	var bars []*pricing.PriceLevel
+	for feed.Next() {
+		bars = append(bars, feed.Current())
+	}
+	return bars
+}
+
+func prices(bars []*pricing.PriceLevel) []float64 {
+	out := make([]float64, len(bars))
+	for i, bar := range bars {
+		out[i] = bar.Price
+	}
+	return out
+}
+
+func TestGapFillingFeed(t *testing.T) {
+	tests := []struct {
+		mode        GapFillMode
+		prices      []float64
+		synthesized int
+	}{
+		{GapForwardFill, []float64{100, 110, 110, 110, 140, 150}, 2},
+		{GapLinear, []float64{100, 110, 120, 130, 140, 150}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			feed := newGapFillingFeed(gappedSeries(), GapConfig{Mode: tt.mode}, time.Minute)
+			bars := drain(feed)
+
+			assert.Equal(t, tt.prices, prices(bars))
+			assert.Equal(t, tt.synthesized, feed.Synthesized())
+			assert.Equal(t, true, bars[2].Extra["synthesized"])
+			assert.Zero(t, bars[2].Volume)
+			assert.Nil(t, bars[4].Extra)
+
+			// Every bar is one interval after the last, so a three bar
+			// indicator window always spans the same two minutes
+			for i := 1; i < len(bars); i++ {
+				assert.Equal(t, time.Minute, bars[i].Timestamp.Sub(bars[i-1].Timestamp))
+			}
+		})
+	}
+
+	t.Run(string(GapSkip), func(t *testing.T) {
+		assert.False(t, GapConfig{Mode: GapSkip}.Enabled())
+		assert.Equal(t, []float64{100, 110, 140, 150}, prices(drain(gappedSeries())))
+	})
+}
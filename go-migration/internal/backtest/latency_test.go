@@ -0,0 +1,55 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// entryUnderLatency replays a rising series with a long signal on the first
+// bar and returns the resulting entry price
+func entryUnderLatency(t *testing.T, latency LatencyConfig) float64 {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		Latency:        latency,
+	}, zap.NewNop(), nil, nil)
+
+	for i := 0; i < 3; i++ {
+		update := &pricing.PriceLevel{
+			Symbol:    "SOL",
+			Price:     100 + float64(i),
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}
+		engine.releaseSignals(update)
+		if i == 0 {
+			assert.NoError(t, engine.submitSignal(&pricing.Signal{
+				Symbol:    "SOL",
+				Direction: "long",
+				Price:     update.Price,
+				Timestamp: update.Timestamp,
+			}))
+		}
+	}
+
+	pos, ok := engine.portfolio.Positions["SOL"]
+	if !assert.True(t, ok) {
+		return 0
+	}
+	return pos.EntryPrice
+}
+
+func TestEngine_ExecutionLatency(t *testing.T) {
+	instant := entryUnderLatency(t, LatencyConfig{})
+	byBars := entryUnderLatency(t, LatencyConfig{Bars: 2})
+	byTime := entryUnderLatency(t, LatencyConfig{Duration: 1500 * time.Millisecond})
+
+	assert.Equal(t, 100.0, instant)
+	assert.Equal(t, 102.0, byBars)
+	assert.Equal(t, 102.0, byTime)
+	assert.Greater(t, byBars, instant)
+}
@@ -0,0 +1,126 @@
+package backtest
+
+import (
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// LimitFillModel selects when a resting limit order is considered filled
+type LimitFillModel string
+
+const (
+	LimitFillTouch        LimitFillModel = "touch"         // Fill as soon as price reaches the limit
+	LimitFillTradeThrough LimitFillModel = "trade_through" // Fill only once price trades past the limit by Tick
+	LimitFillVolume       LimitFillModel = "volume"        // Fill a touch with probability volume / QueueVolume; trading through always fills
+)
+
+// LimitOrderConfig makes signals enter through limit orders resting Offset
+// away from the signal price instead of at market. Limit fills pay the maker
+// commission, earning MakerRebate. Exits stay at market.
+type LimitOrderConfig struct {
+	Enabled     bool           `yaml:"enabled"`
+	Offset      float64        `yaml:"offset"`       // Distance from the signal price as a fraction, below for longs and above for shorts
+	Model       LimitFillModel `yaml:"model"`        // Touch by default
+	Tick        float64        `yaml:"tick"`         // Price move past the limit required by the trade-through model
+	QueueVolume float64        `yaml:"queue_volume"` // Volume queued ahead at the limit for the volume model
+	Expiry      time.Duration  `yaml:"expiry"`       // Cancel unfilled orders after this long, zero keeps them resting
+	Seed        int64          `yaml:"seed"`         // Seed for the volume model's draws, for reproducible runs
+}
+
+// limitOrder is a limit entry resting until the market reaches it
+type limitOrder struct {
+	signal  *pricing.Signal
+	price   float64
+	expires time.Time
+}
+
+// placeLimit rests a limit entry for signal, replacing any resting order
+// for its symbol
+func (e *Engine) placeLimit(signal *pricing.Signal) {
+	config := e.config.LimitOrders
+	price := signal.Price * (1 - config.Offset)
+	if signal.Direction == "short" {
+		price = signal.Price * (1 + config.Offset)
+	}
+
+	order := &limitOrder{signal: signal, price: price}
+	if config.Expiry > 0 {
+		order.expires = signal.Timestamp.Add(config.Expiry)
+	}
+
+	for i, resting := range e.limits {
+		if resting.signal.Symbol == signal.Symbol {
+			e.limits[i] = order
+			return
+		}
+	}
+	e.limits = append(e.limits, order)
+}
+
+// matchLimits fills the resting orders for the update's symbol that the
+// configured model considers filled at the update's price, and cancels
+// expired ones
+func (e *Engine) matchLimits(update *pricing.PriceLevel) {
+	remaining := e.limits[:0]
+	for _, order := range e.limits {
+		if order.signal.Symbol != update.Symbol {
+			remaining = append(remaining, order)
+			continue
+		}
+		if !order.expires.IsZero() && update.Timestamp.After(order.expires) {
+			e.logger.Debug("Cancelled expired limit order",
+				zap.String("symbol", order.signal.Symbol),
+				zap.Float64("price", order.price))
+			continue
+		}
+		if !e.limitFilled(order, update) {
+			remaining = append(remaining, order)
+			continue
+		}
+
+		signal := *order.signal
+		signal.Price = order.price
+		signal.Timestamp = update.Timestamp
+		size := e.calculatePositionSize(&signal)
+		if size <= 0 {
+			continue
+		}
+		if err := e.enterPosition(&signal, size, order.price, types.LiquidityMaker); err != nil {
+			e.logger.Error("Failed to fill limit order",
+				zap.String("symbol", signal.Symbol),
+				zap.Error(err))
+		}
+	}
+	e.limits = remaining
+}
+
+// limitFilled reports whether order fills at the update's price under the
+// configured fill model
+func (e *Engine) limitFilled(order *limitOrder, update *pricing.PriceLevel) bool {
+	// through is how far price moved past the limit in the order's favour
+	through := order.price - update.Price
+	if order.signal.Direction == "short" {
+		through = update.Price - order.price
+	}
+	if through < 0 {
+		return false
+	}
+
+	config := e.config.LimitOrders
+	switch config.Model {
+	case LimitFillTradeThrough:
+		return through > 0 && through >= config.Tick
+	case LimitFillVolume:
+		if through > 0 || config.QueueVolume <= 0 {
+			return true
+		}
+		return e.fillRand.Float64() < math.Min(1, update.Volume/config.QueueVolume)
+	default:
+		return true
+	}
+}
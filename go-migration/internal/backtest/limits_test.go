@@ -0,0 +1,59 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// limitEntry rests a long limit at 100 and replays prices against it,
+// returning the entry price or 0 when the order never filled
+func limitEntry(t *testing.T, config LimitOrderConfig, prices ...float64) float64 {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	config.Enabled = true
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		LimitOrders:    config,
+	}, zap.NewNop(), nil, nil)
+
+	assert.NoError(t, engine.handleSignal(&pricing.Signal{
+		Symbol:    "SOL",
+		Direction: "long",
+		Price:     100,
+		Timestamp: start,
+	}))
+	assert.Empty(t, engine.portfolio.Positions, "limit entries wait for the market")
+
+	for i, price := range prices {
+		engine.matchLimits(&pricing.PriceLevel{
+			Symbol:    "SOL",
+			Price:     price,
+			Volume:    10,
+			Timestamp: start.Add(time.Duration(i+1) * time.Minute),
+		})
+	}
+	if pos, ok := engine.portfolio.Positions["SOL"]; ok {
+		return pos.EntryPrice
+	}
+	return 0
+}
+
+func TestEngine_LimitOrderFillModels(t *testing.T) {
+	// Touching the limit fills under the optimistic model
+	assert.Equal(t, 100.0, limitEntry(t, LimitOrderConfig{Model: LimitFillTouch}, 101, 100))
+
+	// The conservative model needs price to trade through by a tick
+	assert.Zero(t, limitEntry(t, LimitOrderConfig{Model: LimitFillTradeThrough, Tick: 0.01}, 101, 100, 100.5))
+	assert.Equal(t, 100.0, limitEntry(t, LimitOrderConfig{Model: LimitFillTradeThrough, Tick: 0.01}, 101, 100, 99.99))
+
+	// A touch with more volume queued ahead than traded never fills
+	assert.Zero(t, limitEntry(t, LimitOrderConfig{Model: LimitFillVolume, QueueVolume: 1e9}, 100, 100))
+	assert.Equal(t, 100.0, limitEntry(t, LimitOrderConfig{Model: LimitFillVolume, QueueVolume: 10}, 100))
+
+	// Unfilled orders expire
+	assert.Zero(t, limitEntry(t, LimitOrderConfig{Offset: 0.01, Expiry: time.Minute}, 100, 99.5, 98))
+}
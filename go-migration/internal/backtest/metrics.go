@@ -1,6 +1,7 @@
 package backtest
 
 import (
+	"math"
 	"time"
 )
 
@@ -11,6 +12,14 @@ type Metrics struct {
 	ReturnsBySymbol  map[string]float64 `json:"returns_by_symbol"`
 	DrawdownSeries   []float64          `json:"drawdown_series"`
 	VolatilitySeries []float64          `json:"volatility_series"`
+	EquityCurve      []EquityPoint      `json:"equity_curve"`
+}
+
+// EquityPoint is the account value in base currency after a trade closes
+type EquityPoint struct {
+	Time     time.Time `json:"time"`
+	Equity   float64   `json:"equity"`
+	Drawdown float64   `json:"drawdown"`
 }
 
 // NewMetrics creates a new metrics instance
@@ -23,11 +32,16 @@ func NewMetrics() *Metrics {
 	}
 }
 
-// UpdateMetrics updates performance metrics
+// updateMetrics rebuilds the performance metrics from the closed trades:
+// the running equity after each trade, its drawdown from the prior peak,
+// and daily and per-symbol returns. It is a no-op until another trade closes.
 func (e *Engine) updateMetrics() {
-	if len(e.results.Trades) == 0 {
+	if len(e.results.Trades) == 0 || len(e.results.Trades) == e.metricsTrades {
 		return
 	}
+	e.metricsTrades = len(e.results.Trades)
+	e.results.Metrics.DailyReturns = e.results.Metrics.DailyReturns[:0]
+	e.results.Metrics.DrawdownSeries = e.results.Metrics.DrawdownSeries[:0]
 
 	// Sort trades by exit time
 	trades := make([]*Trade, len(e.results.Trades))
@@ -35,7 +49,8 @@ func (e *Engine) updateMetrics() {
 	sortTradesByExitTime(trades)
 
 	// Calculate running balance and drawdown
-	currentBalance := e.config.InitialBalance
+	initial := e.config.initialBaseValue()
+	currentBalance := initial
 	peak := currentBalance
 	var prevDate time.Time
 	dailyPnL := 0.0
@@ -43,9 +58,12 @@ func (e *Engine) updateMetrics() {
 	// Track balance history for accurate drawdown calculation
 	balanceHistory := make([]float64, 0)
 	balanceHistory = append(balanceHistory, currentBalance)
+	e.results.Metrics.EquityCurve = append(e.results.Metrics.EquityCurve[:0],
+		EquityPoint{Time: e.config.StartTime, Equity: currentBalance})
 
 	for _, trade := range trades {
 		date := trade.ExitTime.Truncate(24 * time.Hour)
+		pnl := e.portfolio.ToBase(trade.Quote, trade.PnL)
 
 		// If we've moved to a new day, record the previous day's metrics
 		if !date.Equal(prevDate) && !prevDate.IsZero() {
@@ -58,8 +76,8 @@ func (e *Engine) updateMetrics() {
 		}
 
 		// Update running totals
-		currentBalance += trade.PnL
-		dailyPnL += trade.PnL
+		currentBalance += pnl
+		dailyPnL += pnl
 		balanceHistory = append(balanceHistory, currentBalance)
 
 		// Update peak if we have a new high
@@ -70,6 +88,8 @@ func (e *Engine) updateMetrics() {
 		// Calculate drawdown from peak
 		drawdown := (peak - currentBalance) / peak
 		e.results.Metrics.DrawdownSeries = append(e.results.Metrics.DrawdownSeries, drawdown)
+		e.results.Metrics.EquityCurve = append(e.results.Metrics.EquityCurve,
+			EquityPoint{Time: trade.ExitTime, Equity: currentBalance, Drawdown: drawdown})
 
 		prevDate = date
 	}
@@ -83,10 +103,10 @@ func (e *Engine) updateMetrics() {
 	// Calculate returns by symbol
 	symbolPnL := make(map[string]float64)
 	for _, trade := range trades {
-		symbolPnL[trade.Symbol] += trade.PnL
+		symbolPnL[trade.Symbol] += e.portfolio.ToBase(trade.Quote, trade.PnL)
 	}
 	for symbol, pnl := range symbolPnL {
-		e.results.Metrics.ReturnsBySymbol[symbol] = pnl / e.config.InitialBalance
+		e.results.Metrics.ReturnsBySymbol[symbol] = pnl / initial
 	}
 }
 
@@ -101,3 +121,66 @@ func sortTradesByExitTime(trades []*Trade) {
 		}
 	}
 }
+
+// excessReturns returns each trade's PnL as a fraction of its entry
+// notional, less the annual risk-free rate prorated over the time it was held
+func excessReturns(trades []*Trade, riskFreeRate float64) []float64 {
+	returns := make([]float64, 0, len(trades))
+	for _, trade := range trades {
+		notional := trade.EntryPrice * trade.Quantity
+		if notional == 0 {
+			continue
+		}
+		years := trade.ExitTime.Sub(trade.EntryTime).Hours() / (24 * 365)
+		returns = append(returns, trade.PnL/notional-riskFreeRate*years)
+	}
+	return returns
+}
+
+// sharpeRatio returns the mean of excess returns over their sample standard
+// deviation, zero when there are too few returns or no variance
+func sharpeRatio(excess []float64) float64 {
+	if len(excess) < 2 {
+		return 0
+	}
+	mean := meanOf(excess)
+
+	var variance float64
+	for _, r := range excess {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(excess)-1))
+	if stdDev == 0 {
+		return 0
+	}
+	return mean / stdDev
+}
+
+// sortinoRatio returns the mean of excess returns over their downside
+// deviation, the root mean square of the negative excess returns. It is
+// zero when no return fell below the risk-free rate.
+func sortinoRatio(excess []float64) float64 {
+	if len(excess) == 0 {
+		return 0
+	}
+
+	var downside float64
+	for _, r := range excess {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	downsideDev := math.Sqrt(downside / float64(len(excess)))
+	if downsideDev == 0 {
+		return 0
+	}
+	return meanOf(excess) / downsideDev
+}
+
+func meanOf(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
@@ -1,10 +1,15 @@
 package backtest
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
 )
 
 func TestMetrics_UpdateMetrics(t *testing.T) {
@@ -201,3 +206,104 @@ func TestMetrics_DrawdownCalculation(t *testing.T) {
 	}
 	assert.InDelta(t, 0.0476, maxDrawdown, 0.001)
 }
+
+func TestEngine_SharpeAndSortinoFromTradeReturns(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	trades := make([]*Trade, 0)
+	for i, pnl := range []float64{2, -1, 3, -2} {
+		entry := start.Add(time.Duration(i) * 24 * time.Hour)
+		trades = append(trades, &Trade{
+			Symbol:     "SOL",
+			Direction:  "long",
+			EntryTime:  entry,
+			ExitTime:   entry.Add(24 * time.Hour),
+			EntryPrice: 100,
+			Quantity:   1,
+			PnL:        pnl,
+		})
+	}
+
+	// Returns of 2%, -1%, 3%, -2%: mean 0.5%, sample variance 0.0017/3
+	// and downside deviation sqrt(0.0005/4)
+	engine := NewEngine(Config{InitialBalance: 10000}, zap.NewNop(), nil, nil)
+	engine.results.Trades = trades
+	engine.calculateResults()
+	assert.InDelta(t, 0.005/math.Sqrt(0.0017/3), engine.results.SharpeRatio, 1e-9)
+	assert.InDelta(t, 0.005/math.Sqrt(0.0005/4), engine.results.SortinoRatio, 1e-9)
+
+	// A 3.65% annual risk-free rate costs 0.01% over each one day trade
+	engine = NewEngine(Config{InitialBalance: 10000, RiskFreeRate: 0.0365}, zap.NewNop(), nil, nil)
+	engine.results.Trades = trades
+	engine.calculateResults()
+	assert.InDelta(t, 0.0049/math.Sqrt(0.0017/3), engine.results.SharpeRatio, 1e-9)
+	downside := (0.0101*0.0101 + 0.0201*0.0201) / 4
+	assert.InDelta(t, 0.0049/math.Sqrt(downside), engine.results.SortinoRatio, 1e-9)
+}
+
+func TestEngine_LosingStreakDrawdown(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{InitialBalance: 10000, StartTime: start}, zap.NewNop(), nil, nil)
+
+	// Three losses of 200 from the initial peak, each recorded as it closes
+	for i := 0; i < 3; i++ {
+		engine.results.Trades = append(engine.results.Trades, &Trade{
+			Symbol:     "SOL",
+			EntryTime:  start.Add(time.Duration(2*i) * time.Hour),
+			ExitTime:   start.Add(time.Duration(2*i+1) * time.Hour),
+			EntryPrice: 100,
+			Quantity:   1,
+			PnL:        -200,
+		})
+		engine.updateMetrics()
+		engine.updateMetrics()
+	}
+	engine.calculateResults()
+
+	assert.Equal(t, []float64{0.02, 0.04, 0.06}, roundAll(engine.results.Metrics.DrawdownSeries))
+	assert.InDelta(t, 0.06, engine.results.MaxDrawdown, 1e-9)
+
+	curve := engine.results.EquityCurve
+	require.Len(t, curve, 4)
+	assert.Equal(t, 10000.0, curve[0].Equity)
+	assert.InDelta(t, 9400.0, curve[3].Equity, 1e-9)
+	assert.True(t, curve[3].Time.Equal(start.Add(5*time.Hour)))
+}
+
+func roundAll(values []float64) []float64 {
+	rounded := make([]float64, len(values))
+	for i, v := range values {
+		rounded[i] = math.Round(v*1e9) / 1e9
+	}
+	return rounded
+}
+
+func TestCalculateAnnualizedReturn(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// A one hour run of minute bars annualizes from its 60 bars
+	hourly := calculateAnnualizedReturn(0.001, start, start, 60, time.Minute)
+	assert.InDelta(t, math.Pow(1.001, 24*365)-1, hourly, 1e-6)
+	assert.Equal(t, hourly, calculateAnnualizedReturn(0.001, start, start.Add(time.Hour), 60, time.Minute))
+
+	// Three years compounding to 33.1% is 10% a year
+	end := start.Add(3 * 365 * 24 * time.Hour)
+	assert.InDelta(t, 0.1, calculateAnnualizedReturn(0.331, start, end, 0, 0), 1e-9)
+	assert.InDelta(t, 0.1, calculateAnnualizedReturn(0.331, start, end, 3*365, 24*time.Hour), 1e-9)
+
+	// Losing everything or more does not produce NaN
+	assert.Equal(t, -1.0, calculateAnnualizedReturn(-1.2, start, end, 0, 0))
+	assert.Zero(t, calculateAnnualizedReturn(0.1, start, start, 0, 0))
+}
+
+func TestEngine_CountsBarsAcrossSymbols(t *testing.T) {
+	engine := NewEngine(Config{InitialBalance: 10000}, zap.NewNop(), nil, nil)
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// Two symbols over the same three minutes span three bars, not six
+	for i := 0; i < 3; i++ {
+		for _, symbol := range []string{"SOL", "BONK"} {
+			engine.countBar(&pricing.PriceLevel{Symbol: symbol, Price: 1, Timestamp: start.Add(time.Duration(i) * time.Minute)})
+		}
+	}
+	assert.Len(t, engine.barTimes, 3)
+}
@@ -0,0 +1,105 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// OrderBookSource supplies historical L2 snapshots
+type OrderBookSource interface {
+	// OrderBook returns the latest snapshot for symbol at or before at
+	OrderBook(ctx context.Context, symbol string, at time.Time) (*types.OrderBook, error)
+}
+
+// OrderBookConfig fills orders by walking historical order book snapshots
+// instead of applying flat slippage to a single price
+type OrderBookConfig struct {
+	Enabled bool    `yaml:"enabled"`
+	MinFill float64 `yaml:"min_fill"` // Smallest filled fraction of an entry accepted as a partial fill
+}
+
+// SetOrderBookSource enables order book fills, when Config.OrderBook is
+// enabled, against snapshots from source
+func (e *Engine) SetOrderBookSource(source OrderBookSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.books = source
+}
+
+// bookFill is the result of walking one side of the book
+type bookFill struct {
+	Quantity float64 // Filled quantity, less than requested when the book runs out
+	Price    float64 // Volume weighted average fill price
+	Top      float64 // Best price on the side walked
+	Worst    float64 // Last level touched
+}
+
+// Slippage returns the average fill's relative distance from the top of book
+func (f bookFill) Slippage() float64 {
+	if f.Top == 0 {
+		return 0
+	}
+	d := (f.Price - f.Top) / f.Top
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// walkBook fills size against levels, best first
+func walkBook(levels []types.OrderBookLevel, size float64) bookFill {
+	var fill bookFill
+	var notional float64
+	for _, level := range levels {
+		if fill.Quantity >= size {
+			break
+		}
+		price := level.Price.InexactFloat64()
+		amount := level.Amount.InexactFloat64()
+		if amount <= 0 {
+			continue
+		}
+		if fill.Top == 0 {
+			fill.Top = price
+		}
+		take := size - fill.Quantity
+		if amount < take {
+			take = amount
+		}
+		fill.Quantity += take
+		notional += take * price
+		fill.Worst = price
+	}
+	if fill.Quantity > 0 {
+		fill.Price = notional / fill.Quantity
+	}
+	return fill
+}
+
+// bookSide returns the levels a buy or sell walks
+func bookSide(book *types.OrderBook, buy bool) []types.OrderBookLevel {
+	if buy {
+		return book.Asks
+	}
+	return book.Bids
+}
+
+// fillFromBook walks the snapshot for symbol at at. ok is false when order
+// book fills are disabled and the caller should use flat slippage.
+func (e *Engine) fillFromBook(symbol string, at time.Time, buy bool, size float64) (fill bookFill, ok bool, err error) {
+	if !e.config.OrderBook.Enabled || e.books == nil {
+		return bookFill{}, false, nil
+	}
+	book, err := e.books.OrderBook(context.Background(), symbol, at)
+	if err != nil {
+		return bookFill{}, true, fmt.Errorf("failed to load order book: %w", err)
+	}
+	fill = walkBook(bookSide(book, buy), size)
+	if fill.Quantity == 0 {
+		return fill, true, fmt.Errorf("no liquidity in order book for %s", symbol)
+	}
+	return fill, true, nil
+}
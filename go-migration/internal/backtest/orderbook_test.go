@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type staticBook struct {
+	book *types.OrderBook
+}
+
+func (s staticBook) OrderBook(ctx context.Context, symbol string, at time.Time) (*types.OrderBook, error) {
+	return s.book, nil
+}
+
+func level(price, amount float64) types.OrderBookLevel {
+	return types.OrderBookLevel{Price: decimal.NewFromFloat(price), Amount: decimal.NewFromFloat(amount)}
+}
+
+func TestEngine_MarketOrderWalksOrderBook(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 100000,
+		OrderBook:      OrderBookConfig{Enabled: true},
+	}, zap.NewNop(), nil, nil)
+	engine.SetOrderBookSource(staticBook{book: &types.OrderBook{
+		Symbol: "SOL",
+		Bids:   []types.OrderBookLevel{level(99, 2), level(98, 3)},
+		Asks:   []types.OrderBookLevel{level(100, 5), level(101, 5), level(102, 20)},
+	}})
+
+	// 2% of 100000 at 100 asks for 20 SOL, taking three ask levels
+	assert.NoError(t, engine.handleSignal(&pricing.Signal{
+		Symbol:    "SOL",
+		Direction: "long",
+		Price:     100,
+		Timestamp: start,
+	}))
+	pos := engine.portfolio.Positions["SOL"]
+	if !assert.NotNil(t, pos) {
+		return
+	}
+	assert.InDelta(t, 20, pos.Quantity, 1e-9)
+	assert.InDelta(t, (500+505+1020)/20.0, pos.EntryPrice, 1e-9)
+	assert.Greater(t, pos.EntryPrice, 100.0)
+
+	// Selling 20 exhausts the 5 SOL of bids; the rest fills at the last level
+	assert.NoError(t, engine.closePosition(pos, &pricing.PriceLevel{Symbol: "SOL", Price: 99, Timestamp: start.Add(time.Minute)}))
+	trade := engine.results.Trades[0]
+	assert.InDelta(t, (198+98*18)/20.0, trade.ExitPrice, 1e-9)
+	assert.Less(t, trade.ExitPrice, 99.0)
+	assert.Greater(t, trade.Slippage, 0.0)
+}
+
+func TestEngine_OrderBookPartialFill(t *testing.T) {
+	engine := NewEngine(Config{
+		InitialBalance: 100000,
+		OrderBook:      OrderBookConfig{Enabled: true, MinFill: 0.5},
+	}, zap.NewNop(), nil, nil)
+	book := &types.OrderBook{Symbol: "SOL", Asks: []types.OrderBookLevel{level(100, 4), level(101, 4)}}
+	engine.SetOrderBookSource(staticBook{book: book})
+
+	// 8 of the 20 requested is below the 50% minimum fill
+	signal := &pricing.Signal{Symbol: "SOL", Direction: "long", Price: 100}
+	assert.Error(t, engine.handleSignal(signal))
+
+	// With more depth the entry is a partial fill of what the book offers
+	book.Asks = append(book.Asks, level(102, 4))
+	assert.NoError(t, engine.handleSignal(signal))
+	assert.InDelta(t, 12, engine.portfolio.Positions["SOL"].Quantity, 1e-9)
+}
@@ -0,0 +1,46 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrOverdraft is returned when a trade or payment would drive a balance
+// negative
+var ErrOverdraft = errors.New("balance would go negative")
+
+// OverdraftMode selects how the backtest handles a trade or payment that
+// would overdraw the balance
+type OverdraftMode string
+
+const (
+	OverdraftSkip OverdraftMode = "skip" // Reject it and continue
+	OverdraftHalt OverdraftMode = "halt" // Reject it and stop the backtest
+)
+
+// debit adjusts the balance symbol is quoted in by amount, rejecting the
+// adjustment when it would leave the balance negative. Rejections are
+// logged and counted in Result.Overdrafts, and halt the backtest in
+// OverdraftHalt mode.
+func (e *Engine) debit(symbol string, amount float64, reason string) error {
+	balance := e.portfolio.BalanceOf(symbol)
+	if balance+amount >= 0 {
+		e.portfolio.Adjust(symbol, amount)
+		return nil
+	}
+
+	err := fmt.Errorf("%s on %s for %f with %f available: %w", reason, symbol, -amount, balance, ErrOverdraft)
+	e.results.Overdrafts++
+	e.logger.Warn("Rejected adjustment that would overdraw the balance",
+		zap.String("symbol", symbol),
+		zap.String("reason", reason),
+		zap.Float64("amount", amount),
+		zap.Float64("balance", balance),
+		zap.String("mode", string(e.config.Overdraft)))
+	if e.config.Overdraft == OverdraftHalt && e.halted == nil {
+		e.halted = err
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package backtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// overdrawnByFunding holds a long position that has spent the whole
+// balance, then crosses a funding time it cannot pay
+func overdrawnByFunding(mode OverdraftMode) (*Engine, error) {
+	start := time.Date(2025, 2, 1, 1, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 0,
+		Overdraft:      mode,
+		Funding: types.FundingConfig{
+			Interval: 8 * time.Hour,
+			Symbols:  []string{"SOL-PERP"},
+		},
+	}, zap.NewNop(), nil, nil)
+	engine.SetFundingProvider(fixedFunding{rate: decimal.NewFromFloat(0.001)})
+
+	engine.portfolio.Positions["SOL-PERP"] = &Position{
+		Symbol:     "SOL-PERP",
+		Direction:  "long",
+		EntryPrice: 100,
+		Quantity:   10,
+		EntryTime:  start,
+		FundedAt:   start,
+	}
+	return engine, engine.handleUpdate(&pricing.PriceLevel{
+		Symbol:    "SOL-PERP",
+		Price:     100,
+		Timestamp: start.Add(8 * time.Hour),
+	})
+}
+
+func TestEngine_OverdraftSkipRejectsPayment(t *testing.T) {
+	engine, err := overdrawnByFunding(OverdraftSkip)
+	assert.True(t, errors.Is(err, ErrOverdraft))
+	assert.Zero(t, engine.portfolio.Balance)
+	assert.Zero(t, engine.portfolio.Positions["SOL-PERP"].Funding)
+	assert.Equal(t, 1, engine.results.Overdrafts)
+	assert.Nil(t, engine.halted)
+}
+
+func TestEngine_OverdraftHaltStopsBacktest(t *testing.T) {
+	engine, err := overdrawnByFunding(OverdraftHalt)
+	assert.True(t, errors.Is(err, ErrOverdraft))
+	assert.Zero(t, engine.portfolio.Balance)
+	assert.True(t, errors.Is(engine.halted, ErrOverdraft))
+}
+
+func TestEngine_OpenPositionRejectsOverdraw(t *testing.T) {
+	engine := NewEngine(Config{InitialBalance: 100, Commission: 0.01}, zap.NewNop(), nil, nil)
+
+	// The whole balance leaves nothing for the entry commission
+	engine.config.Sizing.Fraction = 1
+	err := engine.openPosition(&pricing.Signal{Symbol: "SOL", Direction: "long", Price: 10})
+	assert.True(t, errors.Is(err, ErrOverdraft))
+	assert.Empty(t, engine.portfolio.Positions)
+	assert.InDelta(t, 100.0, engine.portfolio.Balance, 1e-9)
+}
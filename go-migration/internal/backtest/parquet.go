@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// tradeRow is the Parquet schema of trades.parquet
+type tradeRow struct {
+	Symbol     string    `parquet:"symbol"`
+	Direction  string    `parquet:"direction"`
+	Quote      string    `parquet:"quote"`
+	EntryTime  time.Time `parquet:"entry_time,timestamp(millisecond)"`
+	ExitTime   time.Time `parquet:"exit_time,timestamp(millisecond)"`
+	EntryPrice float64   `parquet:"entry_price"`
+	ExitPrice  float64   `parquet:"exit_price"`
+	Quantity   float64   `parquet:"quantity"`
+	PnL        float64   `parquet:"pnl"`
+	Commission float64   `parquet:"commission"`
+	Slippage   float64   `parquet:"slippage"`
+	Funding    float64   `parquet:"funding"`
+	RMultiple  float64   `parquet:"r_multiple"`
+	Liquidity  string    `parquet:"liquidity"`
+}
+
+// equityRow is the Parquet schema of equity.parquet
+type equityRow struct {
+	Time     time.Time `parquet:"time,timestamp(millisecond)"`
+	Equity   float64   `parquet:"equity"`
+	Drawdown float64   `parquet:"drawdown"`
+}
+
+// ExportParquet writes the trades and equity curve of result to
+// trades.parquet and equity.parquet in dir, creating dir if needed
+func ExportParquet(result *Result, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	trades := make([]tradeRow, 0, len(result.Trades))
+	for _, t := range result.Trades {
+		trades = append(trades, tradeRow{
+			Symbol:     t.Symbol,
+			Direction:  t.Direction,
+			Quote:      t.Quote,
+			EntryTime:  t.EntryTime,
+			ExitTime:   t.ExitTime,
+			EntryPrice: t.EntryPrice,
+			ExitPrice:  t.ExitPrice,
+			Quantity:   t.Quantity,
+			PnL:        t.PnL,
+			Commission: t.Commission,
+			Slippage:   t.Slippage,
+			Funding:    t.Funding,
+			RMultiple:  t.RMultiple,
+			Liquidity:  string(t.Liquidity),
+		})
+	}
+
+	var equity []equityRow
+	if result.Metrics != nil {
+		for _, p := range result.Metrics.EquityCurve {
+			equity = append(equity, equityRow{Time: p.Time, Equity: p.Equity, Drawdown: p.Drawdown})
+		}
+	}
+
+	path := filepath.Join(dir, "trades.parquet")
+	if err := parquet.WriteFile(path, trades); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	path = filepath.Join(dir, "equity.parquet")
+	if err := parquet.WriteFile(path, equity); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportParquet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := &Engine{
+		config: Config{InitialBalance: 10000, StartTime: start},
+		results: &Result{
+			Trades: []*Trade{
+				{Symbol: "SOL", Direction: "long", EntryTime: start, ExitTime: start.Add(time.Hour),
+					EntryPrice: 100, ExitPrice: 110, Quantity: 10, PnL: 100},
+				{Symbol: "BONK", Direction: "short", EntryTime: start.Add(2 * time.Hour), ExitTime: start.Add(3 * time.Hour),
+					EntryPrice: 1, ExitPrice: 1.5, Quantity: 100, PnL: -50},
+			},
+			Metrics: NewMetrics(),
+		},
+	}
+	engine.updateMetrics()
+
+	dir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ExportParquet(engine.results, dir))
+
+	// Read back through the generic row API rather than the export schema,
+	// so column names and logical types are checked as written
+	file, err := os.Open(filepath.Join(dir, "trades.parquet"))
+	require.NoError(t, err)
+	defer file.Close()
+	info, err := file.Stat()
+	require.NoError(t, err)
+	pf, err := parquet.OpenFile(file, info.Size())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), pf.NumRows())
+	schema := pf.Schema()
+	assert.Len(t, schema.Fields(), 14)
+	symbol, ok := schema.Lookup("symbol")
+	require.True(t, ok)
+	assert.Equal(t, parquet.ByteArray, symbol.Node.Type().Kind())
+	assert.NotNil(t, symbol.Node.Type().LogicalType().UTF8)
+	exitTime, ok := schema.Lookup("exit_time")
+	require.True(t, ok)
+	assert.Equal(t, parquet.Int64, exitTime.Node.Type().Kind())
+	require.NotNil(t, exitTime.Node.Type().LogicalType().Timestamp)
+	assert.NotNil(t, exitTime.Node.Type().LogicalType().Timestamp.Unit.Millis)
+	pnl, ok := schema.Lookup("pnl")
+	require.True(t, ok)
+	assert.Equal(t, parquet.Double, pnl.Node.Type().Kind())
+
+	rows := make([]parquet.Row, 2)
+	reader := parquet.NewReader(pf)
+	n, _ := reader.ReadRows(rows)
+	require.Equal(t, 2, n)
+	assert.Equal(t, "SOL", rows[0][symbol.ColumnIndex].String())
+	assert.Equal(t, start.Add(time.Hour).UnixMilli(), rows[0][exitTime.ColumnIndex].Int64())
+	assert.Equal(t, 100.0, rows[0][pnl.ColumnIndex].Double())
+	assert.Equal(t, "BONK", rows[1][symbol.ColumnIndex].String())
+	assert.Equal(t, -50.0, rows[1][pnl.ColumnIndex].Double())
+	require.NoError(t, reader.Close())
+
+	type equity struct {
+		Time     int64   `parquet:"time"`
+		Equity   float64 `parquet:"equity"`
+		Drawdown float64 `parquet:"drawdown"`
+	}
+	points, err := parquet.ReadFile[equity](filepath.Join(dir, "equity.parquet"))
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.Equal(t, 10000.0, points[0].Equity)
+	assert.Equal(t, 10050.0, points[2].Equity)
+	assert.Equal(t, start.Add(3*time.Hour).UnixMilli(), points[2].Time)
+}
@@ -0,0 +1,82 @@
+package backtest
+
+import "strings"
+
+// QuoteConfig enables per-quote-asset balance accounting, for portfolios
+// trading pairs quoted in different currencies (e.g. BONK/SOL and WIF/USDC).
+// Results are reported in Base using the provided conversion rates.
+type QuoteConfig struct {
+	Base     string             `yaml:"base"`     // Reporting currency
+	Balances map[string]float64 `yaml:"balances"` // Initial balance per quote asset
+	Rates    map[string]float64 `yaml:"rates"`    // Value of one unit of each quote asset in Base
+}
+
+// Enabled reports whether multi-quote accounting is configured
+func (c QuoteConfig) Enabled() bool {
+	return len(c.Balances) > 0
+}
+
+// QuoteOf returns the quote asset of symbol, the part after the slash, or
+// the base currency for symbols without one
+func (p *Portfolio) QuoteOf(symbol string) string {
+	if i := strings.LastIndex(symbol, "/"); i >= 0 {
+		return symbol[i+1:]
+	}
+	return p.Base
+}
+
+// BalanceOf returns the cash available for trading symbol, in its quote asset
+func (p *Portfolio) BalanceOf(symbol string) float64 {
+	if p.Balances == nil {
+		return p.Balance
+	}
+	return p.Balances[p.QuoteOf(symbol)]
+}
+
+// Adjust credits (or debits, when negative) the balance symbol is quoted in
+func (p *Portfolio) Adjust(symbol string, amount float64) {
+	if p.Balances == nil {
+		p.Balance += amount
+		return
+	}
+	p.Balances[p.QuoteOf(symbol)] += amount
+}
+
+// ToBase converts an amount in quote into the base currency. A nil
+// portfolio, as in engines built without NewEngine, reports amounts as is.
+func (p *Portfolio) ToBase(quote string, amount float64) float64 {
+	if p == nil || p.Balances == nil || quote == p.Base {
+		return amount
+	}
+	return amount * p.Rates[quote]
+}
+
+// BaseValue returns the total cash balance in the base currency
+func (p *Portfolio) BaseValue() float64 {
+	if p.Balances == nil {
+		return p.Balance
+	}
+
+	var total float64
+	for quote, balance := range p.Balances {
+		total += p.ToBase(quote, balance)
+	}
+	return total
+}
+
+// initialBaseValue returns the starting cash in the base currency
+func (c Config) initialBaseValue() float64 {
+	if !c.Quotes.Enabled() {
+		return c.InitialBalance
+	}
+
+	var total float64
+	for quote, balance := range c.Quotes.Balances {
+		if quote == c.Quotes.Base {
+			total += balance
+			continue
+		}
+		total += balance * c.Quotes.Rates[quote]
+	}
+	return total
+}
@@ -0,0 +1,52 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+func TestEngine_MultipleQuoteAssets(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		Quotes: QuoteConfig{
+			Base:     "USDC",
+			Balances: map[string]float64{"SOL": 10, "USDC": 1000},
+			Rates:    map[string]float64{"SOL": 100},
+		},
+	}, zap.NewNop(), nil, nil)
+
+	// Sized off 10 SOL: 200 BONK for 0.2 SOL
+	assert.NoError(t, engine.openPosition(&pricing.Signal{
+		Symbol: "BONK/SOL", Direction: "long", Price: 0.001, Timestamp: start,
+	}))
+	// Sized off 1000 USDC: 10 WIF for 20 USDC
+	assert.NoError(t, engine.openPosition(&pricing.Signal{
+		Symbol: "WIF/USDC", Direction: "long", Price: 2, Timestamp: start,
+	}))
+	assert.InDelta(t, 9.8, engine.portfolio.Balances["SOL"], 1e-9)
+	assert.InDelta(t, 980.0, engine.portfolio.Balances["USDC"], 1e-9)
+
+	exit := start.Add(time.Hour)
+	assert.NoError(t, engine.closePosition(engine.portfolio.Positions["BONK/SOL"], &pricing.PriceLevel{
+		Symbol: "BONK/SOL", Price: 0.002, Timestamp: exit,
+	}))
+	assert.NoError(t, engine.closePosition(engine.portfolio.Positions["WIF/USDC"], &pricing.PriceLevel{
+		Symbol: "WIF/USDC", Price: 3, Timestamp: exit,
+	}))
+
+	// Each trade's PnL stays in its own quote asset
+	assert.Equal(t, "SOL", engine.results.Trades[0].Quote)
+	assert.InDelta(t, 0.2, engine.results.Trades[0].PnL, 1e-9)
+	assert.Equal(t, "USDC", engine.results.Trades[1].Quote)
+	assert.InDelta(t, 10.0, engine.results.Trades[1].PnL, 1e-9)
+
+	// 0.2 SOL (20 USDC) + 10 USDC on a 2000 USDC starting value
+	engine.calculateResults()
+	assert.InDelta(t, 2030.0, engine.results.FinalBalance, 1e-9)
+	assert.InDelta(t, 0.015, engine.results.TotalReturn, 1e-9)
+}
@@ -0,0 +1,45 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+func TestEngine_RecordsRMultiple(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		StopLoss:       0.02,
+	}, zap.NewNop(), nil, nil)
+
+	pos := &Position{
+		Symbol:     "SOL",
+		Direction:  "long",
+		EntryPrice: 100,
+		Quantity:   10,
+		EntryTime:  start,
+		StopPrice:  engine.initialStop("long", 100),
+	}
+	engine.portfolio.Positions[pos.Symbol] = pos
+
+	// Risk is 2 per unit on 10 units; exiting 4 above entry earns 2R
+	assert.NoError(t, engine.closePosition(pos, &pricing.PriceLevel{
+		Symbol:    "SOL",
+		Price:     104,
+		Timestamp: start.Add(time.Hour),
+	}))
+
+	trade := engine.results.Trades[0]
+	assert.InDelta(t, 20.0, trade.InitialRisk, 1e-9)
+	assert.InDelta(t, 2.0, trade.RMultiple, 1e-9)
+
+	engine.calculateResults()
+	assert.Equal(t, 1, engine.results.RStats.Trades)
+	assert.InDelta(t, 2.0, engine.results.RStats.Expectancy, 1e-9)
+	assert.Equal(t, 1, engine.results.RStats.Distribution["+2R"])
+}
@@ -0,0 +1,151 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// SizingMethod selects how the size of a new position is chosen
+type SizingMethod string
+
+const (
+	SizingFixedFractional  SizingMethod = "fixed_fractional"  // Commit a fixed fraction of the balance
+	SizingKelly            SizingMethod = "kelly"             // Commit the Kelly fraction from the running win rate and payoff
+	SizingVolatilityTarget SizingMethod = "volatility_target" // Risk a fixed fraction of the balance per ATR of movement
+)
+
+// Sizing defaults
+const (
+	defaultSizingFraction = 0.02
+	defaultKellyMinTrades = 10
+	defaultATRPeriod      = 14
+)
+
+// SizingConfig configures position sizing. An empty Method sizes fixed
+// fractionally. Kelly and volatility target sizing fall back to fixed
+// fractional until enough trades or bars have been seen. Every method is
+// clamped to the available balance, and sizes below MinSize are skipped.
+type SizingConfig struct {
+	Method         SizingMethod `yaml:"method"`
+	Fraction       float64      `yaml:"fraction"`         // Fixed fraction of balance, and balance risked per ATR for volatility target; default 2%
+	KellyScale     float64      `yaml:"kelly_scale"`      // Multiplier on the Kelly fraction, e.g. 0.5 for half Kelly; default 1
+	KellyMinTrades int          `yaml:"kelly_min_trades"` // Closed trades required before Kelly applies; default 10
+	ATRPeriod      int          `yaml:"atr_period"`       // Bars averaged into the ATR; default 14
+	MinSize        float64      `yaml:"min_size"`         // Smallest position quantity to open
+}
+
+// fraction returns the configured fraction, defaulting to 2%
+func (c SizingConfig) fraction() float64 {
+	if c.Fraction > 0 {
+		return c.Fraction
+	}
+	return defaultSizingFraction
+}
+
+// atrPeriod returns the configured ATR period, defaulting to 14 bars
+func (c SizingConfig) atrPeriod() int {
+	if c.ATRPeriod > 0 {
+		return c.ATRPeriod
+	}
+	return defaultATRPeriod
+}
+
+// calculatePositionSize returns the quantity to open for signal using the
+// configured sizing method, or 0 when no position should be opened
+func (e *Engine) calculatePositionSize(signal *pricing.Signal) float64 {
+	if signal.Price <= 0 {
+		return 0
+	}
+	availableBalance := e.portfolio.BalanceOf(signal.Symbol)
+	sizing := e.config.Sizing
+
+	var size float64
+	switch sizing.Method {
+	case SizingKelly:
+		size = availableBalance * e.kellyFraction() / signal.Price
+	case SizingVolatilityTarget:
+		if atr, ok := e.averageTrueRange(signal.Symbol); ok && atr > 0 {
+			size = availableBalance * sizing.fraction() / atr
+		} else {
+			size = availableBalance * sizing.fraction() / signal.Price
+		}
+	default:
+		size = availableBalance * sizing.fraction() / signal.Price
+	}
+
+	size = math.Min(size, availableBalance/signal.Price)
+	if size <= 0 || size < sizing.MinSize {
+		return 0
+	}
+	return size
+}
+
+// kellyFraction returns the Kelly fraction W - (1-W)/R from the win rate W
+// and payoff ratio R of the trades closed so far, scaled by KellyScale. It
+// falls back to the fixed fraction until KellyMinTrades trades with at
+// least one win and one loss have closed, and is 0 without an edge.
+func (e *Engine) kellyFraction() float64 {
+	sizing := e.config.Sizing
+	minTrades := sizing.KellyMinTrades
+	if minTrades <= 0 {
+		minTrades = defaultKellyMinTrades
+	}
+
+	var wins, losses int
+	var grossProfit, grossLoss float64
+	for _, trade := range e.results.Trades {
+		pnl := e.portfolio.ToBase(trade.Quote, trade.PnL)
+		if pnl > 0 {
+			wins++
+			grossProfit += pnl
+		} else {
+			losses++
+			grossLoss += -pnl
+		}
+	}
+	if wins+losses < minTrades || wins == 0 || losses == 0 || grossLoss == 0 {
+		return sizing.fraction()
+	}
+
+	winRate := float64(wins) / float64(wins+losses)
+	payoff := (grossProfit / float64(wins)) / (grossLoss / float64(losses))
+	kelly := winRate - (1-winRate)/payoff
+	if kelly <= 0 {
+		return 0
+	}
+
+	scale := sizing.KellyScale
+	if scale <= 0 {
+		scale = 1
+	}
+	return math.Min(kelly*scale, 1)
+}
+
+// trackRange records the range of update against the symbol's previous
+// price for the ATR. The feed carries one price per bar, so the true range
+// is the absolute close to close move.
+func (e *Engine) trackRange(update *pricing.PriceLevel) {
+	if e.lastPrices == nil {
+		e.lastPrices = make(map[string]float64)
+		e.ranges = make(map[string][]float64)
+	}
+	if prev, ok := e.lastPrices[update.Symbol]; ok {
+		ranges := append(e.ranges[update.Symbol], math.Abs(update.Price-prev))
+		if period := e.config.Sizing.atrPeriod(); len(ranges) > period {
+			ranges = ranges[len(ranges)-period:]
+		}
+		e.ranges[update.Symbol] = ranges
+	}
+	e.lastPrices[update.Symbol] = update.Price
+}
+
+// averageTrueRange returns the mean range of the symbol's last ATRPeriod
+// bars, and false until that many have been seen
+func (e *Engine) averageTrueRange(symbol string) (float64, bool) {
+	ranges := e.ranges[symbol]
+	if len(ranges) < e.config.Sizing.atrPeriod() {
+		return 0, false
+	}
+	return meanOf(ranges), true
+}
@@ -0,0 +1,64 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+func TestEngine_CalculatePositionSize(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	signal := &pricing.Signal{Symbol: "SOL", Direction: "long", Price: 100, Timestamp: start}
+
+	// Fixed fractional commits 2% of the balance by default
+	engine := NewEngine(Config{InitialBalance: 10000}, zap.NewNop(), nil, nil)
+	assert.InDelta(t, 2.0, engine.calculatePositionSize(signal), 1e-9)
+
+	// Kelly with a 60% win rate and 2:1 payoff is 0.6 - 0.4/2 = 40%
+	engine = NewEngine(Config{
+		InitialBalance: 10000,
+		Sizing:         SizingConfig{Method: SizingKelly, KellyMinTrades: 5},
+	}, zap.NewNop(), nil, nil)
+	for _, pnl := range []float64{20, 20, 20, -10, -10} {
+		engine.results.Trades = append(engine.results.Trades, &Trade{Symbol: "SOL", PnL: pnl})
+	}
+	assert.InDelta(t, 40.0, engine.calculatePositionSize(signal), 1e-9)
+
+	// Without an edge Kelly opens nothing
+	engine.results.Trades = append(engine.results.Trades, &Trade{Symbol: "SOL", PnL: -100})
+	assert.Zero(t, engine.calculatePositionSize(signal))
+
+	// Volatility target risks 2% of the balance per ATR of 4
+	engine = NewEngine(Config{
+		InitialBalance: 10000,
+		Sizing:         SizingConfig{Method: SizingVolatilityTarget, ATRPeriod: 3},
+	}, zap.NewNop(), nil, nil)
+	for i, price := range []float64{100, 104, 100, 104} {
+		engine.trackRange(&pricing.PriceLevel{Symbol: "SOL", Price: price, Timestamp: start.Add(time.Duration(i) * time.Minute)})
+	}
+	assert.InDelta(t, 50.0, engine.calculatePositionSize(signal), 1e-9)
+}
+
+func TestEngine_PositionSizeClampsToBalanceAndMinSize(t *testing.T) {
+	signal := &pricing.Signal{Symbol: "SOL", Direction: "long", Price: 100}
+
+	// A tight ATR would size past the balance
+	engine := NewEngine(Config{
+		InitialBalance: 1000,
+		Sizing:         SizingConfig{Method: SizingVolatilityTarget, ATRPeriod: 1, Fraction: 0.5},
+	}, zap.NewNop(), nil, nil)
+	engine.trackRange(&pricing.PriceLevel{Symbol: "SOL", Price: 100})
+	engine.trackRange(&pricing.PriceLevel{Symbol: "SOL", Price: 100.1})
+	assert.InDelta(t, 10.0, engine.calculatePositionSize(signal), 1e-9)
+
+	// 2% of 1000 at 100 is 0.2, under the minimum
+	engine = NewEngine(Config{
+		InitialBalance: 1000,
+		Sizing:         SizingConfig{MinSize: 1},
+	}, zap.NewNop(), nil, nil)
+	assert.Zero(t, engine.calculatePositionSize(signal))
+}
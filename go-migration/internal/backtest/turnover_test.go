@@ -0,0 +1,56 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_TurnoverAndFeeDrag(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		Commission:     0.001,
+		StartTime:      start,
+	}, zap.NewNop(), nil, nil)
+
+	roundTrip := func(symbol string, entry, exit, quantity float64, at time.Time) {
+		pos := &Position{
+			Symbol:     symbol,
+			Direction:  "long",
+			EntryPrice: entry,
+			Quantity:   quantity,
+			EntryTime:  at,
+			Commission: engine.portfolio.CommissionFor(entry*quantity, types.LiquidityTaker),
+		}
+		engine.portfolio.Positions[symbol] = pos
+		assert.NoError(t, engine.closePosition(pos, &pricing.PriceLevel{
+			Symbol:    symbol,
+			Price:     exit,
+			Timestamp: at.Add(time.Hour),
+		}))
+	}
+
+	// +100 then -100: equity goes 10000, 10100, 10000
+	roundTrip("SOL", 100, 110, 10, start)
+	roundTrip("BONK", 50, 45, 20, start.Add(2*time.Hour))
+	engine.updateMetrics()
+	engine.calculateResults()
+
+	// Entry plus exit commission on each trade
+	assert.InDelta(t, 2.1, engine.results.Trades[0].Commission, 1e-9)
+	assert.InDelta(t, 1.9, engine.results.Trades[1].Commission, 1e-9)
+
+	turnover := engine.results.Turnover
+	assert.Equal(t, 2, turnover.Trades)
+	assert.InDelta(t, 4000.0, turnover.Notional, 1e-9)
+	assert.InDelta(t, 4.0, turnover.Fees, 1e-9)
+	assert.InDelta(t, 30100.0/3, turnover.AvgEquity, 1e-9)
+	assert.InDelta(t, 4000/(30100.0/3), turnover.Turnover, 1e-9)
+	assert.InDelta(t, 4/(30100.0/3), turnover.FeeDrag, 1e-9)
+}
@@ -2,9 +2,11 @@ package backtest
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 // Config represents backtest configuration
@@ -13,10 +15,35 @@ type Config struct {
 	EndTime        time.Time     `yaml:"end_time"`
 	InitialBalance float64       `yaml:"initial_balance"`
 	Commission     float64       `yaml:"commission"`
+	MakerRebate    float64       `yaml:"maker_rebate"`
 	Slippage       float64       `yaml:"slippage"`
 	DataSource     string        `yaml:"data_source"`
+	DataPath       string        `yaml:"data_path"` // NDJSON file for the json source, data/<symbol>.ndjson by default
 	Symbol         string        `yaml:"symbol"`
 	Interval       time.Duration `yaml:"interval"`
+	Funding        types.FundingConfig `yaml:"funding"`
+	StopLoss       float64       `yaml:"stop_loss"` // Initial stop distance as a fraction of entry, used for R-multiples
+	RiskFreeRate   float64       `yaml:"risk_free_rate"` // Annual rate, prorated over each trade's holding period for Sharpe and Sortino
+	Latency        LatencyConfig `yaml:"latency"`
+	Quotes         QuoteConfig   `yaml:"quotes"`
+	OrderBook      OrderBookConfig `yaml:"order_book"`
+	Gaps           GapConfig     `yaml:"gaps"`
+	Sizing         SizingConfig  `yaml:"sizing"`
+	LimitOrders    LimitOrderConfig `yaml:"limit_orders"`
+	WalkForward    WalkForwardConfig `yaml:"walk_forward"`
+	Overdraft      OverdraftMode `yaml:"overdraft"` // Handling of trades that would overdraw the balance, skip by default
+}
+
+// LatencyConfig delays signal fills to model execution latency. A fill
+// happens at the first update satisfying both delays, at that update's price.
+type LatencyConfig struct {
+	Bars     int           `yaml:"bars"`     // Updates to wait before filling
+	Duration time.Duration `yaml:"duration"` // Time to wait before filling
+}
+
+// Enabled reports whether any latency is configured
+func (c LatencyConfig) Enabled() bool {
+	return c.Bars > 0 || c.Duration > 0
 }
 
 // Result represents backtest results
@@ -26,13 +53,21 @@ type Result struct {
 	LosingTrades     int      `json:"losing_trades"`
 	WinRate          float64  `json:"win_rate"`
 	ProfitFactor     float64  `json:"profit_factor"`
-	SharpeRatio      float64  `json:"sharpe_ratio"`
+	SharpeRatio      float64  `json:"sharpe_ratio"`  // Per trade, from excess returns over the risk-free rate
+	SortinoRatio     float64  `json:"sortino_ratio"` // Per trade, penalizing only returns below the risk-free rate
 	MaxDrawdown      float64  `json:"max_drawdown"`
 	FinalBalance     float64  `json:"final_balance"`
 	TotalReturn      float64  `json:"total_return"`
 	AnnualizedReturn float64  `json:"annualized_return"`
 	Trades           []*Trade `json:"trades"`
 	Metrics          *Metrics `json:"metrics"`
+	EquityCurve      []EquityPoint `json:"equity_curve"` // Equity after each closed trade, for charting
+	RStats           *types.RStats `json:"r_stats"`
+	Turnover         *types.TurnoverStats `json:"turnover"`
+	SynthesizedBars  int      `json:"synthesized_bars"` // Bars filled into gaps in the data
+	Overdrafts       int      `json:"overdrafts"`       // Trades and payments rejected for overdrawing the balance
+	SkippedRecords   int      `json:"skipped_records"`  // Malformed records skipped by the data feed
+	WalkForward      *WalkForwardResult `json:"walk_forward,omitempty"`
 }
 
 // Trade represents a simulated trade
@@ -45,17 +80,36 @@ type Trade struct {
 	ExitPrice  float64   `json:"exit_price"`
 	Quantity   float64   `json:"quantity"`
 	PnL        float64   `json:"pnl"`
-	Commission float64   `json:"commission"`
+	Commission float64   `json:"commission"` // Entry and exit commission
 	Slippage   float64   `json:"slippage"`
+	Funding    float64   `json:"funding"`
+	Quote      string    `json:"quote,omitempty"`
+	InitialRisk float64  `json:"initial_risk"`
+	RMultiple  float64   `json:"r_multiple"`
+	Liquidity  types.LiquidityRole `json:"liquidity"`
 	Signal     *pricing.Signal `json:"signal"`
 }
 
 // Portfolio tracks positions and balance
 type Portfolio struct {
-	Balance    float64
-	Positions  map[string]*Position
-	Commission float64
-	Slippage   float64
+	Balance     float64
+	Balances    map[string]float64 // Per quote asset balances, nil for single-quote accounting
+	Rates       map[string]float64 // Quote asset to Base conversion rates
+	Base        string
+	Positions   map[string]*Position
+	Commission  float64
+	MakerRebate float64
+	Slippage    float64
+}
+
+// CommissionFor returns the signed commission for a fill of the given
+// notional. Maker fills earn the rebate as a negative commission while
+// taker fills pay the standard rate.
+func (p *Portfolio) CommissionFor(notional float64, role types.LiquidityRole) float64 {
+	if role == types.LiquidityMaker {
+		return -math.Abs(notional) * p.MakerRebate
+	}
+	return math.Abs(notional) * p.Commission
 }
 
 // Position represents an open position
@@ -65,6 +119,10 @@ type Position struct {
 	EntryPrice float64
 	Quantity   float64
 	EntryTime  time.Time
+	Funding    float64   // Cumulative funding paid, negative when received
+	FundedAt   time.Time // Last funding time applied
+	StopPrice  float64   // Initial stop, fixing the risk taken at entry
+	Commission float64   // Commission paid at entry
 }
 
 // DataFeed defines interface for historical data feeds
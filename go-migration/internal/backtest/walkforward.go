@@ -0,0 +1,116 @@
+package backtest
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+// WalkForwardConfig splits the backtest period into an in-sample segment
+// followed by an out-of-sample segment, sized by the ratio of InSample to
+// OutOfSample, e.g. 0.7 and 0.3. Both must be positive to enable the split.
+type WalkForwardConfig struct {
+	InSample    float64 `yaml:"in_sample"`
+	OutOfSample float64 `yaml:"out_of_sample"`
+}
+
+// Enabled reports whether the backtest is split
+func (c WalkForwardConfig) Enabled() bool {
+	return c.InSample > 0 && c.OutOfSample > 0
+}
+
+// WalkForwardResult reports the in-sample and out-of-sample segments of a
+// single backtest pass separately, so performance degradation out of sample
+// can be compared. Trades and signals belong to the segment they were
+// entered in.
+type WalkForwardResult struct {
+	Split              time.Time    `json:"split"`
+	InSample           *Result      `json:"in_sample"`
+	OutOfSample        *Result      `json:"out_of_sample"`
+	InSampleSignals    *SignalStats `json:"in_sample_signals,omitempty"`
+	OutOfSampleSignals *SignalStats `json:"out_of_sample_signals,omitempty"`
+}
+
+// walkForward splits the completed run's trades and signals between start
+// and end at the configured ratio and computes each segment's results
+func (e *Engine) walkForward(signals []*pricing.Signal, start, end time.Time) *WalkForwardResult {
+	config := e.config.WalkForward
+	ratio := config.InSample / (config.InSample + config.OutOfSample)
+	split := start.Add(time.Duration(float64(end.Sub(start)) * ratio))
+
+	var inTrades, outTrades []*Trade
+	for _, trade := range e.results.Trades {
+		if trade.EntryTime.Before(split) {
+			inTrades = append(inTrades, trade)
+		} else {
+			outTrades = append(outTrades, trade)
+		}
+	}
+	var inSignals, outSignals []*pricing.Signal
+	for _, signal := range signals {
+		if signal.Timestamp.Before(split) {
+			inSignals = append(inSignals, signal)
+		} else {
+			outSignals = append(outSignals, signal)
+		}
+	}
+
+	result := &WalkForwardResult{Split: split}
+	equity := e.config.initialBaseValue()
+	result.InSample, equity = e.segmentResult(inTrades, start, split, equity)
+	result.OutOfSample, _ = e.segmentResult(outTrades, split, end, equity)
+
+	analyzer := NewSignalAnalyzer(e.logger)
+	if len(inSignals) > 0 {
+		result.InSampleSignals, _ = analyzer.AnalyzeSignals(inSignals, inTrades)
+	}
+	if len(outSignals) > 0 {
+		result.OutOfSampleSignals, _ = analyzer.AnalyzeSignals(outSignals, outTrades)
+	}
+
+	e.logger.Info("Walk-forward split",
+		zap.Time("split", split),
+		zap.Int("in_sample_trades", result.InSample.TotalTrades),
+		zap.Float64("in_sample_return", result.InSample.TotalReturn),
+		zap.Float64("in_sample_sharpe", result.InSample.SharpeRatio),
+		zap.Int("out_of_sample_trades", result.OutOfSample.TotalTrades),
+		zap.Float64("out_of_sample_return", result.OutOfSample.TotalReturn),
+		zap.Float64("out_of_sample_sharpe", result.OutOfSample.SharpeRatio))
+	return result
+}
+
+// segmentResult computes the results of trades over [start, end) for a
+// segment starting with the given equity, using the same calculations as a
+// full run. It returns the results and the equity the segment ends with,
+// from the trades' PnL.
+func (e *Engine) segmentResult(trades []*Trade, start, end time.Time, equity float64) (*Result, float64) {
+	final := equity
+	for _, trade := range trades {
+		final += e.portfolio.ToBase(trade.Quote, trade.PnL)
+	}
+
+	config := e.config
+	config.StartTime, config.EndTime = start, end
+	config.WalkForward = WalkForwardConfig{}
+	portfolio := &Portfolio{Balance: final, Base: e.portfolio.Base, Rates: e.portfolio.Rates}
+	if config.Quotes.Enabled() {
+		config.Quotes.Balances = map[string]float64{config.Quotes.Base: equity}
+		portfolio.Balances = map[string]float64{config.Quotes.Base: final}
+	} else {
+		config.InitialBalance = equity
+	}
+
+	segment := &Engine{
+		config:    config,
+		logger:    e.logger,
+		portfolio: portfolio,
+		results: &Result{
+			Trades:  append(make([]*Trade, 0, len(trades)), trades...),
+			Metrics: NewMetrics(),
+		},
+	}
+	segment.calculateResults()
+	return segment.results, final
+}
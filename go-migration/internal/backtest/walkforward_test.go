@@ -0,0 +1,59 @@
+package backtest
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/pricing"
+)
+
+func TestEngine_WalkForwardSplitsSegments(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+	engine := NewEngine(Config{
+		InitialBalance: 10000,
+		StartTime:      start,
+		EndTime:        end,
+		WalkForward:    WalkForwardConfig{InSample: 0.7, OutOfSample: 0.3},
+	}, zap.NewNop(), nil, nil)
+
+	// Four winners in sample, two losers out of sample
+	var signals []*pricing.Signal
+	for day, pnl := range map[int]float64{0: 100, 1: 100, 2: 100, 3: 100, 7: -50, 8: -50} {
+		entry := start.Add(time.Duration(day) * 24 * time.Hour)
+		signals = append(signals, &pricing.Signal{Symbol: "SOL", Direction: "long", Price: 100, Confidence: 0.8, Timestamp: entry})
+		engine.results.Trades = append(engine.results.Trades, &Trade{
+			Symbol:     "SOL",
+			Direction:  "long",
+			EntryTime:  entry,
+			ExitTime:   entry.Add(time.Hour),
+			EntryPrice: 100,
+			ExitPrice:  100 + pnl/10,
+			Quantity:   10,
+			PnL:        pnl,
+		})
+	}
+	sort.Slice(signals, func(i, j int) bool { return signals[i].Timestamp.Before(signals[j].Timestamp) })
+
+	result := engine.walkForward(signals, start, end)
+	assert.True(t, result.Split.Equal(start.Add(7*24*time.Hour)))
+
+	assert.Equal(t, 4, result.InSample.TotalTrades)
+	assert.Equal(t, 1.0, result.InSample.WinRate)
+	assert.InDelta(t, 0.04, result.InSample.TotalReturn, 1e-9)
+	assert.InDelta(t, 10400.0, result.InSample.FinalBalance, 1e-9)
+
+	// The out-of-sample segment starts from the in-sample equity
+	assert.Equal(t, 2, result.OutOfSample.TotalTrades)
+	assert.Equal(t, 0.0, result.OutOfSample.WinRate)
+	assert.InDelta(t, -100.0/10400, result.OutOfSample.TotalReturn, 1e-9)
+
+	if assert.NotNil(t, result.InSampleSignals) && assert.NotNil(t, result.OutOfSampleSignals) {
+		assert.Equal(t, 4, result.InSampleSignals.TotalSignals)
+		assert.Equal(t, 2, result.OutOfSampleSignals.TotalSignals)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// UnmarshalKey decodes the section of v at key into out, matching fields by
+// the given struct tag ("yaml" or "json"). Durations are parsed from
+// strings such as "30s" and decimals from strings or numbers. Fields whose
+// keys are not set keep the value out already holds, so out can carry the
+// defaults.
+func UnmarshalKey(v *viper.Viper, key, tag string, out interface{}) error {
+	if !v.IsSet(key) {
+		return nil
+	}
+	err := v.UnmarshalKey(key, out, func(c *mapstructure.DecoderConfig) {
+		c.TagName = tag
+		c.DecodeHook = mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			decimalHook,
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// decimalHook decodes numbers and numeric strings into decimal.Decimal
+func decimalHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != decimalType {
+		return data, nil
+	}
+	switch value := data.(type) {
+	case string:
+		return decimal.NewFromString(value)
+	case float64:
+		return decimal.NewFromFloat(value), nil
+	case float32:
+		return decimal.NewFromFloat32(value), nil
+	case int:
+		return decimal.NewFromInt(int64(value)), nil
+	case int64:
+		return decimal.NewFromInt(value), nil
+	case uint64:
+		return decimal.NewFromString(fmt.Sprint(value))
+	}
+	return data, nil
+}
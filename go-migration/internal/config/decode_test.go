@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalKey(t *testing.T) {
+	v := loadConfig(t, `
+section:
+  interval: 90s
+  size: 0.25
+  count: 3
+  price: "101.5"
+  levels: [1.5, 2]
+  name: test
+`)
+
+	var out struct {
+		Interval time.Duration     `yaml:"interval"`
+		Size     decimal.Decimal   `yaml:"size"`
+		Count    decimal.Decimal   `yaml:"count"`
+		Price    decimal.Decimal   `yaml:"price"`
+		Levels   []decimal.Decimal `yaml:"levels"`
+		Name     string            `yaml:"name"`
+		Default  decimal.Decimal   `yaml:"default"`
+	}
+	out.Default = decimal.NewFromInt(7)
+
+	require.NoError(t, UnmarshalKey(v, "section", "yaml", &out))
+	assert.Equal(t, 90*time.Second, out.Interval)
+	assert.True(t, decimal.NewFromFloat(0.25).Equal(out.Size), out.Size.String())
+	assert.True(t, decimal.NewFromInt(3).Equal(out.Count), out.Count.String())
+	assert.True(t, decimal.NewFromFloat(101.5).Equal(out.Price), out.Price.String())
+	require.Len(t, out.Levels, 2)
+	assert.True(t, decimal.NewFromFloat(1.5).Equal(out.Levels[0]))
+	assert.Equal(t, "test", out.Name)
+	// Unset keys keep their defaults
+	assert.True(t, decimal.NewFromInt(7).Equal(out.Default))
+
+	// A missing section leaves everything as it was
+	require.NoError(t, UnmarshalKey(v, "missing", "yaml", &out))
+	assert.Equal(t, "test", out.Name)
+
+	bad := loadConfig(t, `
+section:
+  size: lots
+`)
+	assert.Error(t, UnmarshalKey(bad, "section", "yaml", &out))
+}
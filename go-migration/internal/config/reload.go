@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Watch re-reads v's config file whenever it changes and passes it to
+// apply once it validates. Invalid files and apply errors are logged and
+// leave the running parameters unchanged.
+func Watch(v *viper.Viper, logger *zap.Logger, apply func(v *viper.Viper) error) {
+	v.OnConfigChange(func(e fsnotify.Event) {
+		if err := Validate(v); err != nil {
+			logger.Error("Ignoring invalid config change",
+				zap.String("file", e.Name),
+				zap.Error(err))
+			return
+		}
+		if err := apply(v); err != nil {
+			logger.Error("Failed to apply config change",
+				zap.String("file", e.Name),
+				zap.Error(err))
+			return
+		}
+		logger.Info("Applied config change", zap.String("file", e.Name))
+	})
+	v.WatchConfig()
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+)
+
+// RiskLimits reads the portfolio risk limits from risk.limits in v. Keys
+// that are not set keep the defaults, and the beta, regime and cluster
+// checks stay off until configured.
+func RiskLimits(v *viper.Viper) (risk.Limits, error) {
+	limits := risk.Limits{
+		MaxPositionSize:   decimal.NewFromFloat(1000.0),
+		MaxDrawdown:       decimal.NewFromFloat(0.15),
+		MaxDailyLoss:      decimal.NewFromFloat(500.0),
+		MaxLeverage:       decimal.NewFromFloat(1.0),
+		MinMarginLevel:    decimal.NewFromFloat(1.5),
+		MaxConcentration:  decimal.NewFromFloat(0.2),
+		RegimeCorrelation: 0.7,
+		RegimeScale:       decimal.NewFromFloat(0.5),
+		ClusterThreshold:  0.8,
+	}
+	if err := UnmarshalKey(v, "risk.limits", "json", &limits); err != nil {
+		return risk.Limits{}, err
+	}
+	return limits, nil
+}
+
+// ReturnsSource builds the price history returns the beta, regime and
+// cluster checks regress on from risk.returns in v, defaulting to 48
+// hourly bars
+func ReturnsSource(v *viper.Viper, history risk.PriceHistory) (*risk.HistoryReturns, error) {
+	interval, bars := "1h", 48
+	if v.IsSet("risk.returns.interval") {
+		interval = v.GetString("risk.returns.interval")
+	}
+	if v.IsSet("risk.returns.bars") {
+		bars = v.GetInt("risk.returns.bars")
+	}
+	return risk.NewHistoryReturns(history, interval, bars)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskLimits(t *testing.T) {
+	limits, err := RiskLimits(loadConfig(t, `other: 1`))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1000).Equal(limits.MaxPositionSize))
+	assert.True(t, limits.MaxBeta.IsZero())
+
+	limits, err = RiskLimits(loadConfig(t, `
+risk:
+  limits:
+    max_position_size: 250
+    max_beta: 1.2
+    beta_reference: SOL
+    benchmark: SOL
+    regime_window: 24
+`))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(250).Equal(limits.MaxPositionSize))
+	assert.True(t, decimal.NewFromFloat(1.2).Equal(limits.MaxBeta))
+	assert.Equal(t, "SOL", limits.BetaReference)
+	assert.Equal(t, 24, limits.RegimeWindow)
+	// Unset limits keep their defaults
+	assert.True(t, decimal.NewFromFloat(0.15).Equal(limits.MaxDrawdown))
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(limits.RegimeScale))
+}
+
+func TestReturnsSource(t *testing.T) {
+	_, err := ReturnsSource(loadConfig(t, `other: 1`), nil)
+	assert.NoError(t, err)
+
+	_, err = ReturnsSource(loadConfig(t, `
+risk:
+  returns:
+    interval: soon
+`), nil)
+	assert.Error(t, err)
+}
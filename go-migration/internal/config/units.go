@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var (
+	one     = decimal.NewFromInt(1)
+	hundred = decimal.NewFromInt(100)
+)
+
+// stopLossFraction reads a stop-loss distance written either as a fraction
+// of entry (0.15) or as a percentage (15.0) and returns the fraction.
+// percent reports that the percentage form was used. 1 is rejected since it
+// reads as both a 100% and a 1% stop.
+func stopLossFraction(value decimal.Decimal) (fraction decimal.Decimal, percent bool, err error) {
+	switch {
+	case !value.IsPositive():
+		return decimal.Zero, false, fmt.Errorf("must be positive, got %s", value)
+	case value.LessThan(one):
+		return value, false, nil
+	case value.Equal(one):
+		return decimal.Zero, false, fmt.Errorf("is ambiguous, got %s: write 0.01 for a 1%% stop", value)
+	case value.LessThan(hundred):
+		return value.Div(hundred), true, nil
+	default:
+		return decimal.Zero, false, fmt.Errorf("must be below 100%%, got %s", value)
+	}
+}
+
+// NormalizeStopLoss converts the stop-loss distance configured at key to
+// the fraction of entry price used internally, so 0.15 and 15.0 both give
+// a 15% stop. The percentage form is accepted with a warning.
+func NormalizeStopLoss(key string, value decimal.Decimal, logger *zap.Logger) (decimal.Decimal, error) {
+	fraction, percent, err := stopLossFraction(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s %w", key, err)
+	}
+	if percent {
+		logger.Warn("Stop loss configured as a percentage, use a fraction instead",
+			zap.String("key", key),
+			zap.String("value", value.String()),
+			zap.String("fraction", fraction.String()))
+	}
+	return fraction, nil
+}
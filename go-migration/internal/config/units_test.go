@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNormalizeStopLoss_FractionAndPercentAgree(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger := zap.New(core)
+
+	fromFraction, err := NormalizeStopLoss("stop_loss", decimal.NewFromFloat(0.15), logger)
+	assert.NoError(t, err)
+	assert.Zero(t, logs.Len(), "fraction form should not warn")
+
+	fromPercent, err := NormalizeStopLoss("stop_loss", decimal.NewFromFloat(15.0), logger)
+	assert.NoError(t, err)
+	assert.True(t, fromFraction.Equal(decimal.NewFromFloat(0.15)), "got %s", fromFraction)
+	assert.True(t, fromPercent.Equal(fromFraction), "got %s", fromPercent)
+	assert.Equal(t, 1, logs.FilterField(zap.String("key", "stop_loss")).Len())
+}
+
+func TestNormalizeStopLoss_RejectsAmbiguousAndOutOfRange(t *testing.T) {
+	for _, value := range []float64{0, -0.1, 1, 100, 150} {
+		_, err := NormalizeStopLoss("stop_loss", decimal.NewFromFloat(value), zap.NewNop())
+		assert.Error(t, err, "value %v", value)
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+type validator struct {
+	v        *viper.Viper
+	problems []string
+}
+
+func (c *validator) addf(format string, args ...interface{}) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+func (c *validator) required(key string) {
+	if strings.TrimSpace(c.v.GetString(key)) == "" {
+		c.addf("%s is required", key)
+	}
+}
+
+// positiveDuration checks key is a positive duration when it is set
+func (c *validator) positiveDuration(key string) {
+	if c.v.IsSet(key) && c.v.GetDuration(key) <= 0 {
+		c.addf("%s must be a positive duration such as \"30s\", got %q", key, c.v.GetString(key))
+	}
+}
+
+// positive checks key is a positive number when it is set
+func (c *validator) positive(key string) {
+	if c.v.IsSet(key) && c.v.GetFloat64(key) <= 0 {
+		c.addf("%s must be positive, got %v", key, c.v.Get(key))
+	}
+}
+
+// fraction checks key lies in [0, 1] when it is set
+func (c *validator) fraction(key string) {
+	if !c.v.IsSet(key) {
+		return
+	}
+	if f := c.v.GetFloat64(key); f < 0 || f > 1 {
+		c.addf("%s must be a fraction between 0 and 1 (e.g. 0.001 for 0.1%%), got %v", key, c.v.Get(key))
+	}
+}
+
+// stopLoss checks key is a stop-loss distance NormalizeStopLoss accepts
+// when it is set
+func (c *validator) stopLoss(key string) {
+	if !c.v.IsSet(key) {
+		return
+	}
+	if _, _, err := stopLossFraction(decimal.NewFromFloat(c.v.GetFloat64(key))); err != nil {
+		c.addf("%s %v", key, err)
+	}
+}
+
+// Validate checks the settings the commands read from v for missing values,
+// out of range numbers and inconsistent limits. It reports every problem in
+// a single ValidationError so they can all be fixed in one pass.
+func Validate(v *viper.Viper) error {
+	c := &validator{v: v}
+
+	c.required("database.mongodb.uri")
+	c.required("database.mongodb.database")
+	c.required("market.providers.solana.base_url")
+	c.positiveDuration("market.providers.solana.timeout")
+	c.positiveDuration("market.providers.pump.timeout")
+	c.positiveDuration("market.handler.update_interval")
+
+	for _, key := range []string{"server.websocket.port", "server.grpc.port"} {
+		if v.IsSet(key) {
+			if port := v.GetInt(key); port <= 0 || port > 65535 {
+				c.addf("%s must be a port between 1 and 65535, got %v", key, v.Get(key))
+			}
+		}
+	}
+	c.positiveDuration("server.websocket.ping_interval")
+	c.positiveDuration("server.websocket.pong_wait")
+	if v.IsSet("server.websocket.ping_interval") && v.IsSet("server.websocket.pong_wait") &&
+		v.GetDuration("server.websocket.ping_interval") >= v.GetDuration("server.websocket.pong_wait") {
+		c.addf("server.websocket.ping_interval (%s) must be shorter than server.websocket.pong_wait (%s)",
+			v.GetDuration("server.websocket.ping_interval"), v.GetDuration("server.websocket.pong_wait"))
+	}
+
+	c.fraction("trading.order.commission")
+	c.fraction("trading.order.slippage")
+	c.positive("trading.order.max_order_size")
+	c.positive("trading.order.min_order_size")
+	if v.IsSet("trading.order.min_order_size") && v.IsSet("trading.order.max_order_size") &&
+		v.GetFloat64("trading.order.min_order_size") > v.GetFloat64("trading.order.max_order_size") {
+		c.addf("trading.order.min_order_size (%v) must not exceed trading.order.max_order_size (%v)",
+			v.Get("trading.order.min_order_size"), v.Get("trading.order.max_order_size"))
+	}
+	c.positive("trading.risk.max_positions")
+	c.positive("trading.risk.max_position_size")
+	c.stopLoss("trading.risk.stop_loss")
+	for i, level := range v.GetStringSlice("trading.risk.take_profit_levels") {
+		var multiplier float64
+		if _, err := fmt.Sscan(level, &multiplier); err != nil || multiplier <= 1 {
+			c.addf("trading.risk.take_profit_levels[%d] must be a multiplier above 1.0 (e.g. 1.5 for +50%%), got %q", i, level)
+		}
+	}
+	c.positiveDuration("trading.engine.update_interval")
+	c.fraction("trading.allocation.min_weight")
+	c.fraction("trading.allocation.max_weight")
+	if mode := v.GetString("trading.position_conflicts"); mode != "" && mode != "merge" && mode != "flag" {
+		c.addf("trading.position_conflicts must be merge or flag, got %q", mode)
+	}
+
+	c.positive("risk.limits.max_position_size")
+	c.fraction("risk.limits.regime_scale")
+	c.positiveDuration("risk.returns.interval")
+	if v.IsSet("risk.returns.bars") && v.GetInt("risk.returns.bars") < 2 {
+		c.addf("risk.returns.bars must be at least 2, got %v", v.Get("risk.returns.bars"))
+	}
+
+	c.positiveDuration("pricing.engine.update_interval")
+	c.positive("pricing.engine.history_size")
+	c.fraction("pricing.engine.min_confidence")
+	c.positive("pricing.engine.max_volatility")
+
+	if v.IsSet("monitoring.max_symbols") && v.GetInt("monitoring.max_symbols") < 0 {
+		c.addf("monitoring.max_symbols must not be negative (0 disables the cap), got %v", v.Get("monitoring.max_symbols"))
+	}
+
+	if len(c.problems) > 0 {
+		return &ValidationError{Problems: c.problems}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadConfig(t *testing.T, yaml string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.NoError(t, v.ReadConfig(bytes.NewBufferString(yaml)))
+	return v
+}
+
+func TestValidate_ValidConfig(t *testing.T) {
+	v := loadConfig(t, `
+market:
+  providers:
+    solana:
+      base_url: "https://api.mainnet-beta.solana.com"
+      timeout: 30s
+database:
+  mongodb:
+    uri: "mongodb://localhost:27017"
+    database: "tradingbot"
+trading:
+  order:
+    commission: 0.001
+    min_order_size: 10
+    max_order_size: 1000
+  risk:
+    take_profit_levels: [1.5, 2.0]
+`)
+	assert.NoError(t, Validate(v))
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	v := loadConfig(t, `
+market:
+  providers:
+    solana:
+      timeout: 0s
+database:
+  mongodb:
+    uri: "mongodb://localhost:27017"
+server:
+  websocket:
+    port: 70000
+    ping_interval: 60s
+    pong_wait: 15s
+trading:
+  order:
+    commission: 1.5
+    min_order_size: 100
+    max_order_size: 10
+  risk:
+    take_profit_levels: [1.5, 0.9]
+  position_conflicts: drop
+pricing:
+  engine:
+    update_interval: -1s
+monitoring:
+  max_symbols: -5
+`)
+
+	err := Validate(v)
+	var validationErr *ValidationError
+	if !assert.True(t, errors.As(err, &validationErr)) {
+		return
+	}
+
+	assert.ElementsMatch(t, []string{
+		"database.mongodb.database is required",
+		"market.providers.solana.base_url is required",
+		`market.providers.solana.timeout must be a positive duration such as "30s", got "0s"`,
+		"server.websocket.port must be a port between 1 and 65535, got 70000",
+		"server.websocket.ping_interval (1m0s) must be shorter than server.websocket.pong_wait (15s)",
+		"trading.order.commission must be a fraction between 0 and 1 (e.g. 0.001 for 0.1%), got 1.5",
+		"trading.order.min_order_size (100) must not exceed trading.order.max_order_size (10)",
+		`trading.risk.take_profit_levels[1] must be a multiplier above 1.0 (e.g. 1.5 for +50%), got "0.9"`,
+		`trading.position_conflicts must be merge or flag, got "drop"`,
+		`pricing.engine.update_interval must be a positive duration such as "30s", got "-1s"`,
+		"monitoring.max_symbols must not be negative (0 disables the cap), got -5",
+	}, validationErr.Problems)
+	assert.Contains(t, err.Error(), "invalid configuration (11 problems)")
+}
@@ -0,0 +1,95 @@
+package market
+
+import (
+	"math"
+	"sync"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// AnomalyConfig configures rejection of bad ticks that deviate too far from
+// a symbol's rolling median price
+type AnomalyConfig struct {
+	Enabled      bool    `json:"enabled"`
+	Window       int     `json:"window"`        // Accepted prices per symbol the median is taken over
+	MinSamples   int     `json:"min_samples"`   // Prices needed before updates are filtered
+	MaxDeviation float64 `json:"max_deviation"` // Relative deviation from the median that is rejected
+}
+
+type anomalyHistory struct {
+	prices   []float64
+	rejected []float64 // Consecutive rejected prices
+}
+
+// AnomalyFilter rejects price updates that deviate more than MaxDeviation
+// from the rolling median of recently accepted prices. A run of Window
+// consecutive rejections that agree with each other is taken as a genuine
+// move and re-seeds the window.
+type AnomalyFilter struct {
+	config  AnomalyConfig
+	symbols map[string]*anomalyHistory
+	mu      sync.Mutex
+}
+
+// NewAnomalyFilter creates a filter with an empty history
+func NewAnomalyFilter(config AnomalyConfig) *AnomalyFilter {
+	if config.Window <= 0 {
+		config.Window = 20
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = 3
+	}
+	return &AnomalyFilter{
+		config:  config,
+		symbols: make(map[string]*anomalyHistory),
+	}
+}
+
+// Accept reports whether price is a plausible update for symbol, recording
+// it in the history if so. Rejections bump price_anomalies_rejected_total.
+func (f *AnomalyFilter) Accept(provider, symbol string, price float64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h, ok := f.symbols[symbol]
+	if !ok {
+		h = &anomalyHistory{}
+		f.symbols[symbol] = h
+	}
+
+	if len(h.prices) >= f.config.MinSamples {
+		ref := median(h.prices)
+		if ref > 0 && math.Abs(price-ref)/ref > f.config.MaxDeviation {
+			h.rejected = append(h.rejected, price)
+			if len(h.rejected) < f.config.Window || !f.consistent(h.rejected) {
+				if len(h.rejected) >= f.config.Window {
+					h.rejected = h.rejected[1:]
+				}
+				metrics.PriceAnomaliesRejected.WithLabelValues(provider, symbol).Inc()
+				return false
+			}
+			h.prices = h.rejected
+			h.rejected = nil
+			return true
+		}
+	}
+
+	h.rejected = nil
+	h.prices = append(h.prices, price)
+	if len(h.prices) > f.config.Window {
+		h.prices = h.prices[len(h.prices)-f.config.Window:]
+	}
+	return true
+}
+
+// consistent reports whether prices all lie within MaxDeviation of their
+// own median
+func (f *AnomalyFilter) consistent(prices []float64) bool {
+	ref := median(prices)
+	for _, p := range prices {
+		if ref <= 0 || math.Abs(p-ref)/ref > f.config.MaxDeviation {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package market
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestHandler_RejectsPriceSpike(t *testing.T) {
+	spiky := &stubProvider{name: "spiky"}
+	backup := &stubProvider{name: "backup", price: 101}
+
+	handler := NewHandler([]types.MarketDataProvider{spiky, backup}, zap.NewNop())
+	handler.SetAnomalyFilter(AnomalyConfig{
+		Enabled:      true,
+		Window:       5,
+		MinSamples:   3,
+		MaxDeviation: 0.5,
+	})
+	rejected := metrics.PriceAnomaliesRejected.WithLabelValues("spiky", "BONK")
+	before := testutil.ToFloat64(rejected)
+
+	for _, p := range []float64{100, 102, 99, 101} {
+		spiky.price = p
+		price, err := handler.GetPrice(context.Background(), "BONK")
+		assert.NoError(t, err)
+		assert.Equal(t, p, price)
+	}
+	assert.Equal(t, 0, backup.calls)
+
+	// A 100x tick is rejected and the next provider answers instead
+	spiky.price = 10000
+	price, err := handler.GetPrice(context.Background(), "BONK")
+	assert.NoError(t, err)
+	assert.Equal(t, 101.0, price)
+	assert.Equal(t, 1, backup.calls)
+	assert.Equal(t, before+1, testutil.ToFloat64(rejected))
+
+	// Normal updates keep passing
+	spiky.price = 103
+	price, err = handler.GetPrice(context.Background(), "BONK")
+	assert.NoError(t, err)
+	assert.Equal(t, 103.0, price)
+}
+
+func TestAnomalyFilter_SustainedMoveReseeds(t *testing.T) {
+	filter := NewAnomalyFilter(AnomalyConfig{Window: 3, MinSamples: 3, MaxDeviation: 0.5})
+	for _, p := range []float64{1, 1, 1} {
+		assert.True(t, filter.Accept("a", "PUMP", p))
+	}
+
+	// Three agreeing prices at the new level are a real move, not a bad tick
+	assert.False(t, filter.Accept("a", "PUMP", 5))
+	assert.False(t, filter.Accept("a", "PUMP", 5.1))
+	assert.True(t, filter.Accept("a", "PUMP", 4.9))
+	assert.True(t, filter.Accept("a", "PUMP", 5))
+}
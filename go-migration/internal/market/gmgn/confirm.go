@@ -0,0 +1,68 @@
+package gmgn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultMaxWait      = time.Minute
+	maxBackoffFactor    = 8
+)
+
+// SubmitAndConfirm submits signedTx and polls its status with backoff until
+// it lands, its blockhash expires, MaxConfirmWait elapses or ctx is done.
+// Failed status checks are retried until then.
+func (p *Provider) SubmitAndConfirm(ctx context.Context, signedTx string) (*types.TransactionStatus, error) {
+	tx, err := p.SubmitTransaction(ctx, signedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, maxWait := p.pollInterval, p.maxWait
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if maxWait <= 0 {
+		maxWait = defaultMaxWait
+	}
+	maxInterval := interval * maxBackoffFactor
+	deadline := time.NewTimer(maxWait)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			metrics.GMGNTradeExecutions.WithLabelValues("confirm_timeout").Inc()
+			return nil, fmt.Errorf("transaction %s not confirmed within %s", tx.Hash, maxWait)
+		case <-time.After(interval):
+		}
+
+		status, err := p.GetTransactionStatus(ctx, tx.Hash, tx.LastValidBlock)
+		switch {
+		case err != nil:
+			p.logger.Debug("Transaction status check failed",
+				zap.String("hash", tx.Hash),
+				zap.Error(err))
+		case status.Success:
+			metrics.GMGNTradeExecutions.WithLabelValues("confirmed").Inc()
+			return status, nil
+		case status.Expired:
+			metrics.GMGNTradeExecutions.WithLabelValues("expired").Inc()
+			return status, fmt.Errorf("transaction %s expired before landing", tx.Hash)
+		}
+
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gmgn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// statusServer accepts every submission and reports the transaction
+// pending for the first pending status checks, then final
+func statusServer(pending int32, final string) (*httptest.Server, *int32) {
+	var checks int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tx/submit_signed_transaction", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"tx_hash":"hash-1","last_valid_block_number":100}}`))
+	})
+	mux.HandleFunc("/tx/get_transaction_status", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("last_valid_height") != "100" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if atomic.AddInt32(&checks, 1) <= pending {
+			w.Write([]byte(`{"code":0,"data":{"success":false,"expired":false}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"code":0,"data":%s}`, final)
+	})
+	return httptest.NewServer(mux), &checks
+}
+
+func confirmingProvider(url string, maxWait time.Duration) *Provider {
+	return NewProvider(&Config{
+		BaseURL:        url,
+		PollInterval:   time.Millisecond,
+		MaxConfirmWait: maxWait,
+	}, zap.NewNop())
+}
+
+func TestProvider_SubmitAndConfirmPollsUntilSuccess(t *testing.T) {
+	server, checks := statusServer(2, `{"success":true}`)
+	defer server.Close()
+
+	status, err := confirmingProvider(server.URL, time.Second).SubmitAndConfirm(context.Background(), "signed")
+	require.NoError(t, err)
+	assert.True(t, status.Success)
+	assert.Equal(t, int32(3), atomic.LoadInt32(checks))
+}
+
+func TestProvider_SubmitAndConfirmReportsExpiry(t *testing.T) {
+	server, _ := statusServer(0, `{"expired":true}`)
+	defer server.Close()
+
+	status, err := confirmingProvider(server.URL, time.Second).SubmitAndConfirm(context.Background(), "signed")
+	assert.ErrorContains(t, err, "expired")
+	require.NotNil(t, status)
+	assert.True(t, status.Expired)
+}
+
+func TestProvider_SubmitAndConfirmGivesUp(t *testing.T) {
+	server, _ := statusServer(1<<30, `{}`)
+	defer server.Close()
+
+	_, err := confirmingProvider(server.URL, 20*time.Millisecond).SubmitAndConfirm(context.Background(), "signed")
+	assert.ErrorContains(t, err, "not confirmed within")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = confirmingProvider(server.URL, time.Second).SubmitAndConfirm(ctx, "signed")
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+package gmgn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ExecuteMultiLeg quotes each leg of a route, chaining each leg's quoted
+// output into the next, signs every leg with sign and submits them. With
+// BundleMultiLeg the legs go out as one atomic bundle; otherwise they are
+// submitted in order and the completed legs are swapped back, with signed
+// reverse swaps, if a later one fails. The returned result carries the
+// combined status of the legs.
+func (p *Provider) ExecuteMultiLeg(ctx context.Context, legs []types.SwapLeg, sign types.TxSigner) (*types.MultiLegResult, error) {
+	if sign == nil {
+		return nil, fmt.Errorf("multi-leg swap needs a transaction signer")
+	}
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("multi-leg swap needs at least one leg")
+	}
+	if !legs[0].Amount.IsPositive() {
+		return nil, fmt.Errorf("first leg amount must be positive, got %s", legs[0].Amount)
+	}
+
+	result := &types.MultiLegResult{Status: types.MultiLegFailed}
+	for i, leg := range legs {
+		if i > 0 && leg.TokenIn != legs[i-1].TokenOut {
+			return result, fmt.Errorf("leg %d swaps %s but leg %d returns %s", i, leg.TokenIn, i-1, legs[i-1].TokenOut)
+		}
+		if !leg.Amount.IsPositive() {
+			leg.Amount = result.Legs[i-1].Quote.OutAmount
+		}
+		quote, err := p.GetQuote(ctx, leg.TokenIn, leg.TokenOut, leg.Amount)
+		if err != nil {
+			metrics.GMGNTradeExecutions.WithLabelValues("multileg_quote_failed").Inc()
+			return result, fmt.Errorf("failed to quote leg %d: %w", i, err)
+		}
+		result.Legs = append(result.Legs, types.LegResult{Leg: leg, Quote: quote})
+	}
+
+	signed := make([]string, len(result.Legs))
+	for i, leg := range result.Legs {
+		tx, err := sign(ctx, leg.Quote.RawTx)
+		if err != nil {
+			metrics.GMGNTradeExecutions.WithLabelValues("multileg_sign_failed").Inc()
+			return result, fmt.Errorf("failed to sign leg %d: %w", i, err)
+		}
+		signed[i] = tx
+	}
+
+	if p.bundleLegs {
+		if err := p.submitBundle(ctx, result, signed); err != nil {
+			p.alertMultiLeg(result, err)
+			return result, err
+		}
+	} else {
+		for i := range result.Legs {
+			tx, err := p.SubmitTransaction(ctx, signed[i])
+			if err != nil {
+				err = fmt.Errorf("failed to submit leg %d: %w", i, err)
+				if i > 0 && p.rollbackLegs(ctx, result.Legs[:i], sign) {
+					result.Status = types.MultiLegRolledBack
+				}
+				p.alertMultiLeg(result, err)
+				return result, err
+			}
+			result.Legs[i].Tx = tx
+		}
+	}
+
+	result.Status = p.multiLegStatus(ctx, result)
+	if result.Status == types.MultiLegFailed {
+		err := fmt.Errorf("multi-leg swap expired before confirming")
+		p.alertMultiLeg(result, err)
+		return result, err
+	}
+	metrics.GMGNTradeExecutions.WithLabelValues("multileg_" + string(result.Status)).Inc()
+	return result, nil
+}
+
+// submitBundle submits every leg's signed transaction as one bundle
+func (p *Provider) submitBundle(ctx context.Context, result *types.MultiLegResult, txs []string) error {
+	url := fmt.Sprintf("%s/tx/submit_signed_bundle_transaction", p.baseURL)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"signed_txs":   txs,
+		"from_address": p.walletAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.authorize(req)
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	metrics.GMGNTradeExecutions.WithLabelValues("submit_bundle").Inc()
+	metrics.GMGNQuoteLatency.WithLabelValues("submit_bundle").Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.APIErrors.WithLabelValues("gmgn_bundle_request").Inc()
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.APIErrors.WithLabelValues("gmgn_bundle_status").Inc()
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			BundleID             string   `json:"bundle_id"`
+			TxHashes             []string `json:"tx_hashes"`
+			LastValidBlockNumber int      `json:"last_valid_block_number"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		metrics.APIErrors.WithLabelValues("gmgn_bundle_decode").Inc()
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if body.Code != 0 {
+		metrics.APIErrors.WithLabelValues("gmgn_bundle_error").Inc()
+		return fmt.Errorf("API error: %s", body.Msg)
+	}
+	if len(body.Data.TxHashes) != len(result.Legs) {
+		return fmt.Errorf("bundle returned %d transaction hashes for %d legs", len(body.Data.TxHashes), len(result.Legs))
+	}
+
+	result.BundleID = body.Data.BundleID
+	for i, hash := range body.Data.TxHashes {
+		result.Legs[i].Tx = &types.TransactionResult{
+			Hash:           hash,
+			BundleID:       body.Data.BundleID,
+			LastValidBlock: body.Data.LastValidBlockNumber,
+		}
+	}
+	return nil
+}
+
+// multiLegStatus combines the on-chain status of every leg: confirmed when
+// all succeeded, failed when any expired, pending otherwise
+func (p *Provider) multiLegStatus(ctx context.Context, result *types.MultiLegResult) types.MultiLegStatus {
+	status := types.MultiLegConfirmed
+	for _, leg := range result.Legs {
+		s, err := p.GetTransactionStatus(ctx, leg.Tx.Hash, leg.Quote.BlockHeight)
+		switch {
+		case err != nil:
+			p.logger.Warn("failed to get leg status",
+				zap.String("hash", leg.Tx.Hash),
+				zap.Error(err))
+			status = types.MultiLegPending
+		case s.Expired:
+			return types.MultiLegFailed
+		case !s.Success:
+			status = types.MultiLegPending
+		}
+	}
+	return status
+}
+
+// rollbackLegs swaps the output of completed legs back, last leg first, and
+// reports whether every leg was reversed
+func (p *Provider) rollbackLegs(ctx context.Context, legs []types.LegResult, sign types.TxSigner) bool {
+	ok := true
+	for i := len(legs) - 1; i >= 0; i-- {
+		leg := legs[i]
+		quote, err := p.GetQuote(ctx, leg.Leg.TokenOut, leg.Leg.TokenIn, leg.Quote.OutAmount)
+		var tx string
+		if err == nil {
+			tx, err = sign(ctx, quote.RawTx)
+		}
+		if err == nil {
+			_, err = p.SubmitTransaction(ctx, tx)
+		}
+		if err != nil {
+			ok = false
+			metrics.GMGNTradeExecutions.WithLabelValues("multileg_rollback_failed").Inc()
+			p.logger.Error("failed to roll back swap leg",
+				zap.String("token_in", leg.Leg.TokenIn),
+				zap.String("token_out", leg.Leg.TokenOut),
+				zap.Error(err))
+			continue
+		}
+		metrics.GMGNTradeExecutions.WithLabelValues("multileg_rollback").Inc()
+	}
+	return ok
+}
+
+// alertMultiLeg records a failed multi-leg swap for operators
+func (p *Provider) alertMultiLeg(result *types.MultiLegResult, err error) {
+	metrics.GMGNTradeExecutions.WithLabelValues("multileg_failed").Inc()
+	fields := []zap.Field{
+		zap.String("status", string(result.Status)),
+		zap.String("bundle_id", result.BundleID),
+		zap.Int("legs", len(result.Legs)),
+		zap.Error(err),
+	}
+	for _, leg := range result.Legs {
+		if leg.Tx != nil {
+			fields = append(fields, zap.String(leg.Leg.TokenIn+"->"+leg.Leg.TokenOut, leg.Tx.Hash))
+		}
+	}
+	p.logger.Error("multi-leg swap failed", fields...)
+}
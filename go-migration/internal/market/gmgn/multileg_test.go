@@ -0,0 +1,151 @@
+package gmgn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// mockGMGN quotes every swap at a fixed rate per pair and records what is
+// quoted and submitted
+type mockGMGN struct {
+	*httptest.Server
+	rates     map[string]decimal.Decimal // "IN-OUT" -> output per unit input
+	rejectTx  string                     // Signed transaction the submit endpoint rejects
+	quoted    []string                   // "IN-OUT:amount"
+	submitted []string
+	bundles   [][]string
+	mu        sync.Mutex
+}
+
+func newMockGMGN(rates map[string]decimal.Decimal) *mockGMGN {
+	m := &mockGMGN{rates: rates}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tx/get_swap_route", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pair := q.Get("token_in_address") + "-" + q.Get("token_out_address")
+		amount, _ := decimal.NewFromString(q.Get("in_amount"))
+		m.mu.Lock()
+		m.quoted = append(m.quoted, pair+":"+amount.String())
+		m.mu.Unlock()
+		fmt.Fprintf(w, `{"code":0,"data":{"raw_tx":{"swapTransaction":"tx-%s","lastValidBlockHeight":100},"quote":{"outAmount":"%s"}}}`,
+			pair, amount.Mul(m.rates[pair]).String())
+	})
+	mux.HandleFunc("/tx/submit_signed_transaction", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SignedTx string `json:"signed_tx"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		m.mu.Lock()
+		m.submitted = append(m.submitted, body.SignedTx)
+		m.mu.Unlock()
+		if body.SignedTx == m.rejectTx {
+			w.Write([]byte(`{"code":1,"msg":"insufficient liquidity"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"code":0,"data":{"tx_hash":"hash-%s"}}`, body.SignedTx)
+	})
+	mux.HandleFunc("/tx/submit_signed_bundle_transaction", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SignedTxs []string `json:"signed_txs"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		m.mu.Lock()
+		m.bundles = append(m.bundles, body.SignedTxs)
+		m.mu.Unlock()
+		hashes, _ := json.Marshal(body.SignedTxs)
+		fmt.Fprintf(w, `{"code":0,"data":{"bundle_id":"bundle-1","tx_hashes":%s,"last_valid_block_number":100}}`, hashes)
+	})
+	mux.HandleFunc("/tx/get_transaction_status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"success":true,"expired":false}}`))
+	})
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+var twoLegRates = map[string]decimal.Decimal{
+	"USDC-SOL": decimal.NewFromFloat(0.01),
+	"SOL-BONK": decimal.NewFromInt(1000),
+	"SOL-USDC": decimal.NewFromInt(100),
+}
+
+// testSigner marks each transaction as signed
+func testSigner(ctx context.Context, rawTx string) (string, error) {
+	return "signed-" + rawTx, nil
+}
+
+var twoLegRoute = []types.SwapLeg{
+	{TokenIn: "USDC", TokenOut: "SOL", Amount: decimal.NewFromInt(200)},
+	{TokenIn: "SOL", TokenOut: "BONK"},
+}
+
+func TestProvider_ExecuteMultiLegBundle(t *testing.T) {
+	server := newMockGMGN(twoLegRates)
+	defer server.Close()
+
+	p := NewProvider(&Config{BaseURL: server.URL, Timeout: time.Second, BundleMultiLeg: true}, zap.NewNop())
+	result, err := p.ExecuteMultiLeg(context.Background(), twoLegRoute, testSigner)
+	require.NoError(t, err)
+
+	// The second leg swaps the first leg's quoted output
+	assert.Equal(t, []string{"USDC-SOL:200", "SOL-BONK:2"}, server.quoted)
+	// Only signed transactions reach the bundle endpoint
+	assert.Equal(t, [][]string{{"signed-tx-USDC-SOL", "signed-tx-SOL-BONK"}}, server.bundles)
+	assert.Empty(t, server.submitted)
+
+	assert.Equal(t, types.MultiLegConfirmed, result.Status)
+	assert.Equal(t, "bundle-1", result.BundleID)
+	require.Len(t, result.Legs, 2)
+	assert.True(t, decimal.NewFromInt(2000).Equal(result.Legs[1].Quote.OutAmount))
+	assert.Equal(t, "signed-tx-SOL-BONK", result.Legs[1].Tx.Hash)
+}
+
+func TestProvider_ExecuteMultiLegRollsBack(t *testing.T) {
+	server := newMockGMGN(twoLegRates)
+	server.rejectTx = "signed-tx-SOL-BONK"
+	defer server.Close()
+
+	p := NewProvider(&Config{BaseURL: server.URL, Timeout: time.Second}, zap.NewNop())
+	result, err := p.ExecuteMultiLeg(context.Background(), twoLegRoute, testSigner)
+	require.Error(t, err)
+
+	// The first leg went through, so its output is swapped back with a
+	// signed reverse swap
+	assert.Equal(t, types.MultiLegRolledBack, result.Status)
+	assert.Equal(t, []string{"signed-tx-USDC-SOL", "signed-tx-SOL-BONK", "signed-tx-SOL-USDC"}, server.submitted)
+	assert.Equal(t, "SOL-USDC:2", server.quoted[len(server.quoted)-1])
+}
+
+func TestProvider_ExecuteMultiLegSignFailure(t *testing.T) {
+	server := newMockGMGN(twoLegRates)
+	defer server.Close()
+
+	p := NewProvider(&Config{BaseURL: server.URL, Timeout: time.Second}, zap.NewNop())
+	failing := func(ctx context.Context, rawTx string) (string, error) {
+		if rawTx == "tx-SOL-BONK" {
+			return "", fmt.Errorf("wallet locked")
+		}
+		return "signed-" + rawTx, nil
+	}
+	_, err := p.ExecuteMultiLeg(context.Background(), twoLegRoute, failing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to sign leg 1")
+
+	// Every leg is signed before any is submitted
+	assert.Empty(t, server.submitted)
+
+	_, err = p.ExecuteMultiLeg(context.Background(), twoLegRoute, nil)
+	assert.Error(t, err)
+}
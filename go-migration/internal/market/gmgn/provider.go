@@ -1,11 +1,12 @@
 package gmgn
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -16,11 +17,25 @@ import (
 	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
+var (
+	// defaultSlippage and defaultFee apply when Config leaves them unset
+	defaultSlippage = decimal.NewFromFloat(0.5)
+	defaultFee      = decimal.NewFromFloat(0.002)
+	// maxSlippage is the highest slippage percentage GetQuote accepts
+	maxSlippage = decimal.NewFromInt(50)
+)
+
 type Provider struct {
 	logger        *zap.Logger
 	baseURL       string
 	apiKey        string
 	walletAddress string
+	bundleLegs    bool
+	antiMEV       bool
+	fee           decimal.Decimal
+	slippage      decimal.Decimal
+	pollInterval  time.Duration
+	maxWait       time.Duration
 	client        *http.Client
 	mu            sync.RWMutex
 }
@@ -30,9 +45,17 @@ type Config struct {
 	APIKey        string        `yaml:"api_key"`
 	WalletAddress string        `yaml:"wallet_address"`
 	UseAntiMEV    bool          `yaml:"use_anti_mev"`
-	MinFee        decimal.Decimal `yaml:"min_fee"`
-	Slippage      decimal.Decimal `yaml:"slippage"`
+	MinFee        decimal.Decimal `yaml:"min_fee"`  // Priority fee in SOL, 0.002 when unset
+	Slippage      decimal.Decimal `yaml:"slippage"` // Percent, 0.5 when unset
 	Timeout       time.Duration  `yaml:"timeout"`
+	// BundleMultiLeg submits the legs of a multi-leg swap as one atomic
+	// bundle instead of one transaction at a time
+	BundleMultiLeg bool          `yaml:"bundle_multi_leg"`
+	// PollInterval is the first delay between status checks in
+	// SubmitAndConfirm, doubling up to eight times as long
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// MaxConfirmWait bounds how long SubmitAndConfirm waits for a result
+	MaxConfirmWait time.Duration `yaml:"max_confirm_wait"`
 }
 
 func NewProvider(config *Config, logger *zap.Logger) *Provider {
@@ -41,22 +64,50 @@ func NewProvider(config *Config, logger *zap.Logger) *Provider {
 		baseURL:       config.BaseURL,
 		apiKey:        config.APIKey,
 		walletAddress: config.WalletAddress,
+		bundleLegs:    config.BundleMultiLeg,
+		antiMEV:       config.UseAntiMEV,
+		fee:           config.MinFee,
+		slippage:      config.Slippage,
+		pollInterval:  config.PollInterval,
+		maxWait:       config.MaxConfirmWait,
 		client: &http.Client{
 			Timeout: config.Timeout,
 		},
 	}
 }
 
+// authorize sets the API key header on requests to GMGN when one is
+// configured
+func (p *Provider) authorize(req *http.Request) {
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	}
+}
+
 func (p *Provider) GetQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*types.Quote, error) {
+	slippage, fee := p.slippage, p.fee
+	if slippage.IsZero() {
+		slippage = defaultSlippage
+	}
+	if fee.IsZero() {
+		fee = defaultFee
+	}
+	if !slippage.IsPositive() || slippage.GreaterThan(maxSlippage) {
+		return nil, fmt.Errorf("slippage %s%% outside (0, %s%%]", slippage, maxSlippage)
+	}
+	if fee.IsNegative() {
+		return nil, fmt.Errorf("fee must not be negative, got %s", fee)
+	}
+
 	url := fmt.Sprintf("%s/tx/get_swap_route", p.baseURL)
 	params := map[string]string{
 		"token_in_address":  tokenIn,
 		"token_out_address": tokenOut,
 		"in_amount":        amount.String(),
 		"from_address":     p.walletAddress,
-		"slippage":        "0.5",
-		"is_anti_mev":     "true",
-		"fee":             "0.002",
+		"slippage":        slippage.String(),
+		"is_anti_mev":     strconv.FormatBool(p.antiMEV),
+		"fee":             fee.String(),
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -69,6 +120,7 @@ func (p *Provider) GetQuote(ctx context.Context, tokenIn, tokenOut string, amoun
 		q.Add(k, v)
 	}
 	req.URL.RawQuery = q.Encode()
+	p.authorize(req)
 
 	start := time.Now()
 	resp, err := p.client.Do(req)
@@ -112,10 +164,12 @@ func (p *Provider) GetQuote(ctx context.Context, tokenIn, tokenOut string, amoun
 		return nil, fmt.Errorf("API error: %s", result.Msg)
 	}
 
+	outAmount, _ := decimal.NewFromString(result.Data.Quote.OutAmount)
 	return &types.Quote{
 		TokenIn:  tokenIn,
 		TokenOut: tokenOut,
 		Amount:   amount,
+		OutAmount: outAmount,
 		RawTx:    result.Data.RawTx.SwapTransaction,
 		BlockHeight: result.Data.RawTx.LastValidBlockHeight,
 		Blockhash:   result.Data.RawTx.RecentBlockhash,
@@ -134,12 +188,13 @@ func (p *Provider) SubmitTransaction(ctx context.Context, signedTx string) (*typ
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	p.authorize(req)
 
 	start := time.Now()
 	resp, err := p.client.Do(req)
@@ -203,6 +258,7 @@ func (p *Provider) GetTransactionStatus(ctx context.Context, hash string, lastVa
 		q.Add(k, v)
 	}
 	req.URL.RawQuery = q.Encode()
+	p.authorize(req)
 
 	start := time.Now()
 	resp, err := p.client.Do(req)
@@ -0,0 +1,91 @@
+package gmgn
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProvider_SubmitTransactionSendsBody(t *testing.T) {
+	var body []byte
+	var contentLength int64
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tx/submit_signed_transaction", r.URL.Path)
+		contentLength = r.ContentLength
+		auth = r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{"code":0,"data":{"tx_hash":"hash-1"}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(&Config{BaseURL: server.URL, APIKey: "key"}, zap.NewNop())
+	result, err := p.SubmitTransaction(context.Background(), "signed")
+	require.NoError(t, err)
+	assert.Equal(t, "hash-1", result.Hash)
+
+	var payload map[string]string
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "signed", payload["signed_tx"])
+	assert.Equal(t, int64(len(body)), contentLength)
+	assert.Equal(t, "Bearer key", auth)
+}
+
+func TestProvider_QuoteSendsAPIKey(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"code":0,"data":{"quote":{"outAmount":"5"}}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(&Config{BaseURL: server.URL, APIKey: "key"}, zap.NewNop())
+	_, err := p.GetQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer key", auth)
+}
+
+func TestProvider_QuoteUsesConfiguredRouteParams(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code":0,"data":{"quote":{"outAmount":"5"}}}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(&Config{
+		BaseURL:  server.URL,
+		MinFee:   decimal.NewFromFloat(0.01),
+		Slippage: decimal.NewFromFloat(1.5),
+	}, zap.NewNop())
+	_, err := p.GetQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "false", query.Get("is_anti_mev"))
+	assert.Equal(t, "1.5", query.Get("slippage"))
+	assert.Equal(t, "0.01", query.Get("fee"))
+
+	// Unset fee and slippage keep the previous defaults
+	p = NewProvider(&Config{BaseURL: server.URL, UseAntiMEV: true}, zap.NewNop())
+	_, err = p.GetQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "true", query.Get("is_anti_mev"))
+	assert.Equal(t, "0.5", query.Get("slippage"))
+	assert.Equal(t, "0.002", query.Get("fee"))
+}
+
+func TestProvider_QuoteRejectsNonsenseSlippage(t *testing.T) {
+	for _, slippage := range []float64{-1, 75} {
+		p := NewProvider(&Config{BaseURL: "http://unused", Slippage: decimal.NewFromFloat(slippage)}, zap.NewNop())
+		_, err := p.GetQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+		assert.ErrorContains(t, err, "slippage")
+	}
+}
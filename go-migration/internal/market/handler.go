@@ -17,6 +17,8 @@ type Handler struct {
 	providers []types.MarketDataProvider
 	updates   chan *types.PriceUpdate
 	subs      map[string][]chan *types.PriceUpdate
+	quality   *QualityTracker
+	anomalies *AnomalyFilter
 	mu        sync.RWMutex
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -26,6 +28,8 @@ type Handler struct {
 type Config struct {
 	BufferSize     int           `json:"buffer_size"`
 	UpdateInterval time.Duration `json:"update_interval"`
+	Quality        QualityConfig `json:"quality"`
+	Anomaly        AnomalyConfig `json:"anomaly"`
 }
 
 // NewHandler creates a new market data handler
@@ -42,6 +46,81 @@ func NewHandler(providers []types.MarketDataProvider, logger *zap.Logger) *Handl
 	}
 }
 
+// SetQuality enables data-quality scoring, which reorders provider failover
+// so the best scoring provider is tried first
+func (h *Handler) SetQuality(config QualityConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !config.Enabled {
+		h.quality = nil
+		return
+	}
+	h.quality = NewQualityTracker(config, h.providers)
+}
+
+// SetAnomalyFilter enables rejection of price updates that deviate too far
+// from the rolling median for their symbol
+func (h *Handler) SetAnomalyFilter(config AnomalyConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !config.Enabled {
+		h.anomalies = nil
+		return
+	}
+	h.anomalies = NewAnomalyFilter(config)
+}
+
+// acceptPrice applies the anomaly filter, if enabled, to a price from
+// provider i
+func (h *Handler) acceptPrice(i int, symbol string, price float64) bool {
+	h.mu.RLock()
+	anomalies := h.anomalies
+	h.mu.RUnlock()
+
+	if anomalies == nil || anomalies.Accept(providerName(h.providers[i]), symbol, price) {
+		return true
+	}
+	h.logger.Warn("Rejected anomalous price",
+		zap.String("provider", providerName(h.providers[i])),
+		zap.String("symbol", symbol),
+		zap.Float64("price", price))
+	return false
+}
+
+// providerOrder returns the provider indices in failover order
+func (h *Handler) providerOrder() []int {
+	h.mu.RLock()
+	quality := h.quality
+	h.mu.RUnlock()
+
+	if quality != nil {
+		return quality.Ranked()
+	}
+	order := make([]int, len(h.providers))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func (h *Handler) recordPrice(i int, symbol string, price float64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.quality != nil {
+		h.quality.RecordPrice(i, symbol, price)
+	}
+}
+
+func (h *Handler) recordError(i int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.quality != nil {
+		h.quality.RecordError(i)
+	}
+}
+
 // Start starts the market data handler
 func (h *Handler) Start() error {
 	// Start processing updates
@@ -59,23 +138,28 @@ func (h *Handler) Stop() {
 func (h *Handler) SubscribePrices(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
 	updates := make(chan *types.PriceUpdate, len(symbols)*len(h.providers))
 	
-	for _, provider := range h.providers {
+	for i, provider := range h.providers {
 		providerUpdates, err := provider.SubscribePrices(ctx, symbols)
 		if err != nil {
+			h.recordError(i)
 			h.logger.Error("Failed to subscribe to provider",
 				zap.Error(err))
 			continue
 		}
 		
-		go func(p types.MarketDataProvider, updates chan<- *types.PriceUpdate) {
+		go func(i int, updates chan<- *types.PriceUpdate) {
 			for update := range providerUpdates {
+				h.recordPrice(i, update.Symbol, update.Price.InexactFloat64())
+				if !h.acceptPrice(i, update.Symbol, update.Price.InexactFloat64()) {
+					continue
+				}
 				select {
 				case updates <- update:
 				case <-ctx.Done():
 					return
 				}
 			}
-		}(provider, updates)
+		}(i, updates)
 	}
 	
 	return updates, nil
@@ -83,11 +167,16 @@ func (h *Handler) SubscribePrices(ctx context.Context, symbols []string) (<-chan
 
 // GetPrice gets the current price for a symbol
 func (h *Handler) GetPrice(ctx context.Context, symbol string) (float64, error) {
-	for _, provider := range h.providers {
-		price, err := provider.GetPrice(ctx, symbol)
+	for _, i := range h.providerOrder() {
+		price, err := h.providers[i].GetPrice(ctx, symbol)
 		if err == nil {
-			return price, nil
+			h.recordPrice(i, symbol, price)
+			if h.acceptPrice(i, symbol, price) {
+				return price, nil
+			}
+			continue
 		}
+		h.recordError(i)
 		h.logger.Debug("Provider failed to get price",
 			zap.Error(err))
 	}
@@ -96,11 +185,12 @@ func (h *Handler) GetPrice(ctx context.Context, symbol string) (float64, error)
 
 // GetHistoricalPrices gets historical prices for a symbol
 func (h *Handler) GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error) {
-	for _, provider := range h.providers {
-		prices, err := provider.GetHistoricalPrices(ctx, symbol, interval, limit)
+	for _, i := range h.providerOrder() {
+		prices, err := h.providers[i].GetHistoricalPrices(ctx, symbol, interval, limit)
 		if err == nil {
 			return prices, nil
 		}
+		h.recordError(i)
 		h.logger.Debug("Provider failed to get historical prices",
 			zap.Error(err))
 	}
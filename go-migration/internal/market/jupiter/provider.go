@@ -0,0 +1,183 @@
+package jupiter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+const (
+	defaultBaseURL     = "https://quote-api.jup.ag/v6"
+	defaultRPCURL      = "https://api.mainnet-beta.solana.com"
+	defaultSlippageBps = 50
+)
+
+// Provider quotes Solana swaps through the Jupiter aggregator. Jupiter only
+// builds transactions, so signed swaps are sent through a Solana RPC node.
+type Provider struct {
+	logger        *zap.Logger
+	baseURL       string
+	rpcURL        string
+	walletAddress string
+	slippageBps   int
+	client        *http.Client
+}
+
+type Config struct {
+	BaseURL       string        `yaml:"base_url"`
+	RPCURL        string        `yaml:"rpc_url"`
+	WalletAddress string        `yaml:"wallet_address"`
+	SlippageBps   int           `yaml:"slippage_bps"`
+	Timeout       time.Duration `yaml:"timeout"`
+}
+
+func NewProvider(config *Config, logger *zap.Logger) *Provider {
+	p := &Provider{
+		logger:        logger,
+		baseURL:       config.BaseURL,
+		rpcURL:        config.RPCURL,
+		walletAddress: config.WalletAddress,
+		slippageBps:   config.SlippageBps,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultBaseURL
+	}
+	if p.rpcURL == "" {
+		p.rpcURL = defaultRPCURL
+	}
+	if p.slippageBps <= 0 {
+		p.slippageBps = defaultSlippageBps
+	}
+	return p
+}
+
+// GetQuote fetches Jupiter's best route for amount base units of tokenIn
+// and builds the unsigned swap transaction for it
+func (p *Provider) GetQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*types.Quote, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/quote", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Add("inputMint", tokenIn)
+	q.Add("outputMint", tokenOut)
+	q.Add("amount", amount.Truncate(0).String())
+	q.Add("slippageBps", fmt.Sprintf("%d", p.slippageBps))
+	req.URL.RawQuery = q.Encode()
+
+	// The quote is passed back verbatim to build the swap
+	var route json.RawMessage
+	if err := p.do(req, "jupiter_quote", &route); err != nil {
+		return nil, err
+	}
+	var quote struct {
+		OutAmount string `json:"outAmount"`
+	}
+	if err := json.Unmarshal(route, &quote); err != nil {
+		metrics.APIErrors.WithLabelValues("jupiter_quote_decode").Inc()
+		return nil, fmt.Errorf("failed to decode quote: %w", err)
+	}
+	outAmount, err := decimal.NewFromString(quote.OutAmount)
+	if err != nil {
+		metrics.APIErrors.WithLabelValues("jupiter_quote_decode").Inc()
+		return nil, fmt.Errorf("invalid out amount %q: %w", quote.OutAmount, err)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"quoteResponse":    route,
+		"userPublicKey":    p.walletAddress,
+		"wrapAndUnwrapSol": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err = http.NewRequestWithContext(ctx, "POST", p.baseURL+"/swap", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var swap struct {
+		SwapTransaction      string `json:"swapTransaction"`
+		LastValidBlockHeight int    `json:"lastValidBlockHeight"`
+	}
+	if err := p.do(req, "jupiter_swap", &swap); err != nil {
+		return nil, err
+	}
+
+	return &types.Quote{
+		TokenIn:     tokenIn,
+		TokenOut:    tokenOut,
+		Amount:      amount,
+		OutAmount:   outAmount,
+		RawTx:       swap.SwapTransaction,
+		BlockHeight: swap.LastValidBlockHeight,
+	}, nil
+}
+
+// SubmitTransaction sends a signed, base64 encoded swap transaction through
+// the Solana RPC node
+func (p *Provider) SubmitTransaction(ctx context.Context, signedTx string) (*types.TransactionResult, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "sendTransaction",
+		"params":  []interface{}{signedTx, map[string]string{"encoding": "base64"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.rpcURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := p.do(req, "jupiter_submit", &result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		metrics.APIErrors.WithLabelValues("jupiter_submit_error").Inc()
+		return nil, fmt.Errorf("RPC error: %s", result.Error.Message)
+	}
+	return &types.TransactionResult{Hash: result.Result}, nil
+}
+
+// do sends req and decodes a successful JSON response into out, counting
+// failures under the errorType prefix
+func (p *Provider) do(req *http.Request, errorType string, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		metrics.APIErrors.WithLabelValues(errorType + "_request").Inc()
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.APIErrors.WithLabelValues(errorType + "_status").Inc()
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		metrics.APIErrors.WithLabelValues(errorType + "_decode").Inc()
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
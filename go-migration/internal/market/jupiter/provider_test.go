@@ -0,0 +1,82 @@
+package jupiter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market"
+)
+
+var _ market.SwapVenue = (*Provider)(nil)
+
+func newMockJupiter(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/quote", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "1000", q.Get("amount"))
+		assert.Equal(t, "50", q.Get("slippageBps"))
+		fmt.Fprintf(w, `{"inputMint":%q,"outputMint":%q,"inAmount":"1000","outAmount":"2500","routePlan":[]}`,
+			q.Get("inputMint"), q.Get("outputMint"))
+	})
+	mux.HandleFunc("/swap", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			QuoteResponse struct {
+				OutAmount string `json:"outAmount"`
+			} `json:"quoteResponse"`
+			UserPublicKey string `json:"userPublicKey"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2500", body.QuoteResponse.OutAmount)
+		assert.Equal(t, "wallet", body.UserPublicKey)
+		w.Write([]byte(`{"swapTransaction":"unsigned-tx","lastValidBlockHeight":42}`))
+	})
+	mux.HandleFunc("/rpc", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "sendTransaction", body.Method)
+		if body.Params[0] == "bad-tx" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32002,"message":"blockhash not found"}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"sig-%s"}`, body.Params[0])
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProvider_GetQuote(t *testing.T) {
+	server := newMockJupiter(t)
+	defer server.Close()
+	p := NewProvider(&Config{BaseURL: server.URL, WalletAddress: "wallet"}, zap.NewNop())
+
+	quote, err := p.GetQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, "SOL", quote.TokenIn)
+	assert.True(t, quote.OutAmount.Equal(decimal.NewFromInt(2500)))
+	assert.Equal(t, "unsigned-tx", quote.RawTx)
+	assert.Equal(t, 42, quote.BlockHeight)
+}
+
+func TestProvider_SubmitTransaction(t *testing.T) {
+	server := newMockJupiter(t)
+	defer server.Close()
+	p := NewProvider(&Config{BaseURL: server.URL, RPCURL: server.URL + "/rpc"}, zap.NewNop())
+
+	result, err := p.SubmitTransaction(context.Background(), "signed-tx")
+	require.NoError(t, err)
+	assert.Equal(t, "sig-signed-tx", result.Hash)
+
+	_, err = p.SubmitTransaction(context.Background(), "bad-tx")
+	assert.ErrorContains(t, err, "blockhash not found")
+}
@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// GroupConsumer is a Consumer backed by a franz-go consumer group client.
+// Polled records are handed out one at a time, and the group may only
+// rebalance once every polled record has been fetched, so a partition is
+// never revoked or reassigned while the provider is still working through
+// records from it. Offsets are committed only through CommitMessages.
+type GroupConsumer struct {
+	client  *kgo.Client
+	pending []*kgo.Record
+}
+
+// NewGroupConsumer joins config.Group on config.Brokers and consumes
+// config.Topic. onAssigned is called with the partitions of each new
+// assignment before any of their records are fetched.
+func NewGroupConsumer(config Config, onAssigned func(partitions []int)) (*GroupConsumer, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("kafka brokers not configured")
+	}
+	if config.Topic == "" || config.Group == "" {
+		return nil, fmt.Errorf("kafka topic and group are required")
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.ConsumerGroup(config.Group),
+		kgo.ConsumeTopics(config.Topic),
+		kgo.DisableAutoCommit(),
+		kgo.BlockRebalanceOnPoll(),
+		kgo.OnPartitionsAssigned(func(_ context.Context, _ *kgo.Client, assigned map[string][]int32) {
+			var partitions []int
+			for _, partition := range assigned[config.Topic] {
+				partitions = append(partitions, int(partition))
+			}
+			if len(partitions) > 0 && onAssigned != nil {
+				onAssigned(partitions)
+			}
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	return &GroupConsumer{client: client}, nil
+}
+
+// FetchMessage implements Consumer
+func (c *GroupConsumer) FetchMessage(ctx context.Context) (Message, error) {
+	for len(c.pending) == 0 {
+		// Everything polled so far has been handed out, let a pending
+		// rebalance run before polling again
+		c.client.AllowRebalance()
+		fetches := c.client.PollFetches(ctx)
+		if fetches.IsClientClosed() {
+			return Message{}, ErrConsumerClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return Message{}, err
+		}
+		c.pending = fetches.Records()
+		if errs := fetches.Errors(); len(errs) > 0 {
+			fetchErrs := make([]error, 0, len(errs))
+			for _, fetchErr := range errs {
+				fetchErrs = append(fetchErrs, fmt.Errorf("partition %d: %w", fetchErr.Partition, fetchErr.Err))
+			}
+			return Message{}, errors.Join(fetchErrs...)
+		}
+	}
+
+	record := c.pending[0]
+	c.pending = c.pending[1:]
+	return Message{
+		Topic:     record.Topic,
+		Partition: int(record.Partition),
+		Offset:    record.Offset,
+		Epoch:     record.LeaderEpoch,
+		Key:       record.Key,
+		Value:     record.Value,
+		Time:      record.Timestamp,
+	}, nil
+}
+
+// CommitMessages implements Consumer
+func (c *GroupConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	records := make([]*kgo.Record, 0, len(msgs))
+	for _, msg := range msgs {
+		records = append(records, &kgo.Record{
+			Topic:       msg.Topic,
+			Partition:   int32(msg.Partition),
+			Offset:      msg.Offset,
+			LeaderEpoch: msg.Epoch,
+		})
+	}
+	return c.client.CommitRecords(ctx, records...)
+}
+
+// Close leaves the group and closes the client
+func (c *GroupConsumer) Close() error {
+	c.client.Close()
+	return nil
+}
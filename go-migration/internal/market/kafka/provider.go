@@ -0,0 +1,333 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ErrConsumerClosed is returned by a Consumer once it has been closed.
+// The provider stops consuming when it sees it.
+var ErrConsumerClosed = errors.New("kafka consumer closed")
+
+// Message is a single record read from a Kafka topic
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Epoch     int32 // Leader epoch of the record, -1 if unknown
+	Key       []byte
+	Value     []byte
+	Time      time.Time
+}
+
+// Consumer is the consumer-group reader the provider consumes from.
+// FetchMessage blocks until the next message is available and
+// CommitMessages commits the offsets of processed messages. NewProvider
+// builds a GroupConsumer for the configured brokers; NewConsumerProvider
+// takes any other reader.
+type Consumer interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Config configures the provider. Brokers and Group are only read by
+// NewProvider.
+type Config struct {
+	Brokers        []string      `yaml:"brokers"`
+	Group          string        `yaml:"group"`
+	Topic          string        `yaml:"topic"`
+	RetryBackoff   time.Duration `yaml:"retry_backoff"`
+	SubscriberSize int           `yaml:"subscriber_size"`
+}
+
+// Message types carried in the envelope of each record
+const (
+	MessageTypePrice = "price"
+	MessageTypeToken = "token"
+)
+
+type envelope struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type priceSubscriber struct {
+	symbols map[string]bool
+	ch      chan *types.PriceUpdate
+}
+
+// Provider implements types.MarketDataProvider on top of a Kafka topic of
+// price and token updates so it can be fed through market.Handler alongside
+// the WebSocket providers. Offsets are committed only after a message has
+// been dispatched, so delivery is at-least-once; messages redelivered while
+// a partition stays assigned are skipped by tracking the last offset seen
+// per partition. The mark is dropped whenever the partition is assigned,
+// since the group then resumes from its committed offset, which may be
+// behind the mark after a failed commit, a seek or an offset reset.
+type Provider struct {
+	logger      *zap.Logger
+	config      Config
+	consumer    Consumer
+	prices      map[string]*types.PriceUpdate
+	offsets     map[int]int64
+	subscribers []*priceSubscriber
+	tokens      []chan *types.TokenMarketInfo
+	mu          sync.RWMutex
+}
+
+// NewProvider creates a provider consuming config.Topic as a member of
+// config.Group
+func NewProvider(config Config, logger *zap.Logger) (*Provider, error) {
+	p := NewConsumerProvider(config, nil, logger)
+	consumer, err := NewGroupConsumer(config, p.PartitionsAssigned)
+	if err != nil {
+		return nil, err
+	}
+	p.consumer = consumer
+	return p, nil
+}
+
+// NewConsumerProvider creates a provider reading from consumer, which the
+// caller has already connected to the topic. Its rebalance hook should
+// call PartitionsAssigned.
+func NewConsumerProvider(config Config, consumer Consumer, logger *zap.Logger) *Provider {
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Second
+	}
+	if config.SubscriberSize <= 0 {
+		config.SubscriberSize = 1000
+	}
+	return &Provider{
+		logger:   logger,
+		config:   config,
+		consumer: consumer,
+		prices:   make(map[string]*types.PriceUpdate),
+		offsets:  make(map[int]int64),
+	}
+}
+
+// Start begins consuming the topic until ctx is cancelled
+func (p *Provider) Start(ctx context.Context) error {
+	go p.consume(ctx)
+	return nil
+}
+
+// Stop closes the underlying consumer
+func (p *Provider) Stop() error {
+	return p.consumer.Close()
+}
+
+func (p *Provider) consume(ctx context.Context) {
+	defer p.closeSubscribers()
+
+	for {
+		msg, err := p.consumer.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrConsumerClosed) {
+				return
+			}
+			metrics.APIErrors.WithLabelValues("kafka_fetch").Inc()
+			p.logger.Error("Failed to fetch kafka message", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.config.RetryBackoff):
+			}
+			continue
+		}
+
+		if p.seen(msg) {
+			continue
+		}
+
+		if err := p.dispatch(msg); err != nil {
+			metrics.APIErrors.WithLabelValues("kafka_decode").Inc()
+			p.logger.Warn("Skipping malformed kafka message",
+				zap.Int("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+				zap.Error(err))
+		}
+
+		if err := p.consumer.CommitMessages(ctx, msg); err != nil {
+			metrics.APIErrors.WithLabelValues("kafka_commit").Inc()
+			p.logger.Error("Failed to commit kafka offset",
+				zap.Int("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+				zap.Error(err))
+		}
+	}
+}
+
+// seen reports whether msg was already processed and otherwise records its offset
+func (p *Provider) seen(msg Message) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if last, ok := p.offsets[msg.Partition]; ok && msg.Offset <= last {
+		return true
+	}
+	p.offsets[msg.Partition] = msg.Offset
+	return false
+}
+
+// PartitionsAssigned forgets the last offset seen on partitions, so
+// whatever the group replays from its committed offset is processed
+func (p *Provider) PartitionsAssigned(partitions []int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, partition := range partitions {
+		delete(p.offsets, partition)
+	}
+	p.logger.Info("Kafka partitions assigned",
+		zap.String("topic", p.config.Topic),
+		zap.Ints("partitions", partitions))
+}
+
+func (p *Provider) dispatch(msg Message) error {
+	var env envelope
+	if err := json.Unmarshal(msg.Value, &env); err != nil {
+		return fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	switch env.Type {
+	case MessageTypePrice:
+		var update types.PriceUpdate
+		if err := json.Unmarshal(env.Data, &update); err != nil {
+			return fmt.Errorf("failed to unmarshal price update: %w", err)
+		}
+		p.publishPrice(&update)
+	case MessageTypeToken:
+		var update types.TokenUpdate
+		if err := json.Unmarshal(env.Data, &update); err != nil {
+			return fmt.Errorf("failed to unmarshal token update: %w", err)
+		}
+		p.publishToken(&update)
+	default:
+		return fmt.Errorf("unknown message type: %s", env.Type)
+	}
+	return nil
+}
+
+func (p *Provider) publishPrice(update *types.PriceUpdate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prices[update.Symbol] = update
+	for _, sub := range p.subscribers {
+		if len(sub.symbols) > 0 && !sub.symbols[update.Symbol] {
+			continue
+		}
+		select {
+		case sub.ch <- update:
+		default:
+			p.logger.Warn("Kafka price subscriber full",
+				zap.String("symbol", update.Symbol))
+		}
+	}
+}
+
+func (p *Provider) publishToken(update *types.TokenUpdate) {
+	info := &types.TokenMarketInfo{
+		Symbol:     update.Symbol,
+		Name:       update.TokenName,
+		MarketCap:  decimal.NewFromFloat(update.MarketCap),
+		Volume:     decimal.NewFromFloat(update.Volume),
+		Price:      decimal.NewFromFloat(update.Price),
+		Supply:     int64(update.TotalSupply),
+		LastUpdate: update.Timestamp,
+	}
+
+	p.publishPrice(&types.PriceUpdate{
+		Symbol:      update.Symbol,
+		TokenName:   update.TokenName,
+		Price:       info.Price,
+		Volume:      info.Volume,
+		MarketCap:   info.MarketCap,
+		TotalSupply: decimal.NewFromFloat(update.TotalSupply),
+		Timestamp:   update.Timestamp,
+	})
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, ch := range p.tokens {
+		select {
+		case ch <- info:
+		default:
+			p.logger.Warn("Kafka token subscriber full",
+				zap.String("symbol", update.Symbol))
+		}
+	}
+}
+
+func (p *Provider) closeSubscribers() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, sub := range p.subscribers {
+		close(sub.ch)
+	}
+	for _, ch := range p.tokens {
+		close(ch)
+	}
+	p.subscribers = nil
+	p.tokens = nil
+}
+
+func (p *Provider) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	update, ok := p.prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price received for %s", symbol)
+	}
+	return update.Price.InexactFloat64(), nil
+}
+
+func (p *Provider) SubscribePrices(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
+	sub := &priceSubscriber{
+		symbols: make(map[string]bool, len(symbols)),
+		ch:      make(chan *types.PriceUpdate, p.config.SubscriberSize),
+	}
+	for _, symbol := range symbols {
+		sub.symbols[symbol] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers = append(p.subscribers, sub)
+	return sub.ch, nil
+}
+
+func (p *Provider) SubscribeNewTokens(ctx context.Context) (<-chan *types.TokenMarketInfo, error) {
+	ch := make(chan *types.TokenMarketInfo, p.config.SubscriberSize)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens = append(p.tokens, ch)
+	return ch, nil
+}
+
+func (p *Provider) GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error) {
+	return nil, fmt.Errorf("historical prices not available from kafka topic %s", p.config.Topic)
+}
+
+func (p *Provider) GetBondingCurve(ctx context.Context, symbol string) (*types.BondingCurve, error) {
+	return nil, fmt.Errorf("bonding curve not available from kafka topic %s", p.config.Topic)
+}
+
+func (p *Provider) ExecuteTrade(ctx context.Context, params map[string]interface{}) error {
+	return fmt.Errorf("kafka provider is read-only")
+}
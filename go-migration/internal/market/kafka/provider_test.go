@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type mockConsumer struct {
+	messages  chan Message
+	failures  int
+	committed []int64
+	mu        sync.Mutex
+}
+
+func (c *mockConsumer) FetchMessage(ctx context.Context) (Message, error) {
+	c.mu.Lock()
+	if c.failures > 0 {
+		c.failures--
+		c.mu.Unlock()
+		return Message{}, errors.New("broker unavailable")
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	case msg := <-c.messages:
+		return msg, nil
+	}
+}
+
+func (c *mockConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range msgs {
+		c.committed = append(c.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (c *mockConsumer) Close() error { return nil }
+
+func (c *mockConsumer) commits() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64(nil), c.committed...)
+}
+
+func TestProvider_ConsumesTopicThroughHandler(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan Message, 10), failures: 1}
+	provider := NewConsumerProvider(Config{Topic: "prices", RetryBackoff: time.Millisecond}, consumer, zap.NewNop())
+
+	handler := market.NewHandler([]types.MarketDataProvider{provider}, zap.NewNop())
+	assert.NoError(t, handler.Start())
+	defer handler.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := handler.SubscribePrices(ctx, []string{"SOL", "BONK"})
+	assert.NoError(t, err)
+	tokens, err := provider.SubscribeNewTokens(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, provider.Start(ctx))
+
+	consumer.messages <- Message{Partition: 0, Offset: 0, Value: []byte(`{"type":"price","data":{"symbol":"SOL","price":"101.5","volume":"1000"}}`)}
+	consumer.messages <- Message{Partition: 0, Offset: 1, Value: []byte(`{"type":"token","data":{"symbol":"BONK","token_name":"Bonk","price":0.002,"market_cap":20000,"volume":500}}`)}
+	// Redelivered while the partition stays assigned, must not be processed twice
+	consumer.messages <- Message{Partition: 0, Offset: 1, Value: []byte(`{"type":"token","data":{"symbol":"BONK","price":0.002}}`)}
+	consumer.messages <- Message{Partition: 0, Offset: 2, Value: []byte(`not json`)}
+	consumer.messages <- Message{Partition: 0, Offset: 3, Value: []byte(`{"type":"price","data":{"symbol":"WIF","price":"2.5"}}`)}
+	consumer.messages <- Message{Partition: 0, Offset: 4, Value: []byte(`{"type":"price","data":{"symbol":"SOL","price":"102"}}`)}
+
+	var received []*types.PriceUpdate
+	for len(received) < 3 {
+		select {
+		case update := <-updates:
+			received = append(received, update)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for updates, got %d", len(received))
+		}
+	}
+
+	assert.Equal(t, "SOL", received[0].Symbol)
+	assert.Equal(t, "101.5", received[0].Price.String())
+	assert.Equal(t, "BONK", received[1].Symbol)
+	assert.Equal(t, "Bonk", received[1].TokenName)
+	assert.Equal(t, "SOL", received[2].Symbol)
+	assert.Equal(t, "102", received[2].Price.String())
+
+	select {
+	case info := <-tokens:
+		assert.Equal(t, "BONK", info.Symbol)
+		assert.Equal(t, "20000", info.MarketCap.String())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for token update")
+	}
+
+	// Every processed offset is committed once, including the malformed one
+	assert.Eventually(t, func() bool {
+		return len(consumer.commits()) == 5
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, consumer.commits())
+
+	price, err := provider.GetPrice(ctx, "WIF")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, price)
+}
+
+func TestProvider_AssignmentResetsSeenOffsets(t *testing.T) {
+	consumer := &mockConsumer{messages: make(chan Message, 10)}
+	provider := NewConsumerProvider(Config{Topic: "prices"}, consumer, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := provider.SubscribePrices(ctx, []string{"SOL"})
+	assert.NoError(t, err)
+	assert.NoError(t, provider.Start(ctx))
+
+	price := func(partition int, offset int64, value string) Message {
+		return Message{Partition: partition, Offset: offset, Value: []byte(`{"type":"price","data":{"symbol":"SOL","price":"` + value + `"}}`)}
+	}
+	next := func() string {
+		select {
+		case update := <-updates:
+			return update.Price.String()
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for update")
+			return ""
+		}
+	}
+
+	consumer.messages <- price(0, 10, "100")
+	consumer.messages <- price(1, 3, "101")
+	assert.Equal(t, "100", next())
+	assert.Equal(t, "101", next())
+	assert.Eventually(t, func() bool {
+		return len(consumer.commits()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Partition 0 comes back from an earlier committed offset, partition 1
+	// is kept and still skips what it has already seen
+	provider.PartitionsAssigned([]int{0})
+	consumer.messages <- price(1, 3, "999")
+	consumer.messages <- price(0, 7, "102")
+	consumer.messages <- price(0, 8, "103")
+	assert.Equal(t, "102", next())
+	assert.Equal(t, "103", next())
+}
+
+func TestNewGroupConsumer_RequiresBrokersTopicAndGroup(t *testing.T) {
+	_, err := NewGroupConsumer(Config{Topic: "prices", Group: "bot"}, nil)
+	assert.Error(t, err)
+	_, err = NewGroupConsumer(Config{Brokers: []string{"localhost:9092"}, Topic: "prices"}, nil)
+	assert.Error(t, err)
+
+	consumer, err := NewGroupConsumer(Config{Brokers: []string{"localhost:9092"}, Topic: "prices", Group: "bot"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, consumer.Close())
+}
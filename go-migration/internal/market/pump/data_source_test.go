@@ -0,0 +1,47 @@
+package pump
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+func TestProvider_SubscribePricesFallsBackToPolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"token":"PEPE","price":1.5,"volume":10,"market_cap":1000}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{
+		BaseURL:      server.URL,
+		WebSocketURL: "ws://127.0.0.1:1",
+		TimeoutSec:   5,
+		PollInterval: 10 * time.Millisecond,
+	}, zap.NewNop())
+	assert.Equal(t, DataSourceWebSocket, provider.DataSourceMode())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := provider.SubscribePrices(ctx, []string{"PEPE"})
+	require.NoError(t, err)
+
+	assert.Equal(t, DataSourceRESTPolling, provider.DataSourceMode())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.PumpDataSourceMode))
+
+	// The configured interval, not the five second default, drives polling
+	select {
+	case update := <-updates:
+		assert.Equal(t, "PEPE", update.Symbol)
+	case <-time.After(time.Second):
+		t.Fatal("no polled update received")
+	}
+}
@@ -0,0 +1,68 @@
+package pump
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// listTokens serves token listings at the given market caps
+func listTokens(caps ...float64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := make([]map[string]interface{}, 0, len(caps))
+		for i, marketCap := range caps {
+			data = append(data, map[string]interface{}{
+				"token":      string(rune('A' + i)),
+				"price":      1.0,
+				"market_cap": marketCap,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestProvider_MaxMarketCapFiltersTokensAndSubscriptions(t *testing.T) {
+	api := listTokens(20000, 40000, 60000)
+	defer api.Close()
+	ws := newRecordingWSServer()
+	defer ws.Close()
+
+	// The default cap only lets the first token through
+	provider := NewProvider(Config{BaseURL: api.URL, TimeoutSec: 5}, zap.NewNop())
+	assert.True(t, types.DefaultMaxMarketCap.Equal(provider.MaxMarketCap()))
+	tokens, err := provider.GetNewTokens(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	provider.Close()
+
+	// Raising the configured cap widens the listing and the subscriptions
+	provider = NewProvider(Config{
+		BaseURL:      api.URL,
+		WebSocketURL: "ws" + ws.URL[4:],
+		TimeoutSec:   5,
+		MaxMarketCap: decimal.NewFromInt(50000),
+	}, zap.NewNop())
+	defer provider.Close()
+
+	tokens, err = provider.GetNewTokens(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	require.NoError(t, provider.wsClient.Connect(context.Background()))
+	require.NoError(t, provider.wsClient.Subscribe([]string{"subscribeNewToken"}))
+	require.Eventually(t, func() bool { return len(ws.received(0)) == 3 }, time.Second, 10*time.Millisecond)
+	for _, msg := range ws.received(0) {
+		data, _ := msg["data"].(map[string]interface{})
+		assert.Equal(t, 50000.0, data["max_market_cap"], "channel %v", msg["channel"])
+	}
+}
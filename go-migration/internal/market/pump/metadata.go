@@ -0,0 +1,130 @@
+package pump
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// MetadataFetcher retrieves token metadata from the provider
+type MetadataFetcher interface {
+	GetTokenMetadata(ctx context.Context, symbol string) (*types.TokenMetadata, error)
+}
+
+type metadataEntry struct {
+	metadata  *types.TokenMetadata
+	fetchedAt time.Time
+}
+
+// MetadataCache caches token metadata per symbol and refreshes entries once
+// they are older than the TTL
+type MetadataCache struct {
+	fetcher MetadataFetcher
+	ttl     time.Duration
+	entries map[string]*metadataEntry
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+func NewMetadataCache(fetcher MetadataFetcher, ttl time.Duration) *MetadataCache {
+	return &MetadataCache{
+		fetcher: fetcher,
+		ttl:     ttl,
+		entries: make(map[string]*metadataEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the metadata for symbol, fetching it when missing or stale.
+// A stale entry is still returned if the refresh fails.
+func (c *MetadataCache) Get(ctx context.Context, symbol string) (*types.TokenMetadata, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[symbol]
+	c.mu.Unlock()
+
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		return entry.metadata, nil
+	}
+
+	metadata, err := c.fetcher.GetTokenMetadata(ctx, symbol)
+	if err != nil {
+		if ok {
+			return entry.metadata, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[symbol] = &metadataEntry{metadata: metadata, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return metadata, nil
+}
+
+// Enrich fills the metadata fields of update from the cache
+func (c *MetadataCache) Enrich(ctx context.Context, update *types.TokenUpdate) error {
+	metadata, err := c.Get(ctx, update.Symbol)
+	if err != nil {
+		return err
+	}
+
+	update.Decimals = metadata.Decimals
+	update.CreatedAt = metadata.CreatedAt
+	if update.Address == "" {
+		update.Address = metadata.MintAddress
+	}
+	if update.TokenName == "" {
+		update.TokenName = metadata.Name
+	}
+	return nil
+}
+
+// GetTokenMetadata implements MetadataFetcher
+func (p *Provider) GetTokenMetadata(ctx context.Context, symbol string) (*types.TokenMetadata, error) {
+	url := fmt.Sprintf("%s/tokens/%s/metadata", p.baseURL, symbol)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		metrics.APIErrors.WithLabelValues("get_token_metadata").Inc()
+		return nil, fmt.Errorf("failed to get token metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.APIErrors.WithLabelValues("get_token_metadata_status").Inc()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Name        string `json:"name"`
+			Decimals    int    `json:"decimals"`
+			MintAddress string `json:"mint_address"`
+			CreatedAt   int64  `json:"created_at"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &types.TokenMetadata{
+		Symbol:      symbol,
+		Name:        result.Data.Name,
+		Decimals:    result.Data.Decimals,
+		MintAddress: result.Data.MintAddress,
+		CreatedAt:   time.Unix(result.Data.CreatedAt, 0),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package pump
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type countingFetcher struct {
+	calls int
+}
+
+func (f *countingFetcher) GetTokenMetadata(ctx context.Context, symbol string) (*types.TokenMetadata, error) {
+	f.calls++
+	return &types.TokenMetadata{
+		Symbol:      symbol,
+		Name:        "Test Token",
+		Decimals:    6,
+		MintAddress: "So11111111111111111111111111111111111111112",
+		CreatedAt:   time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func TestMetadataCache_EnrichesUpdate(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := &countingFetcher{}
+	cache := NewMetadataCache(fetcher, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	update := &types.TokenUpdate{Symbol: "TEST/SOL", Price: 0.001}
+	assert.NoError(t, cache.Enrich(context.Background(), update))
+	assert.Equal(t, 6, update.Decimals)
+	assert.Equal(t, "So11111111111111111111111111111111111111112", update.Address)
+	assert.Equal(t, "Test Token", update.TokenName)
+	assert.True(t, update.CreatedAt.Equal(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)))
+
+	// Served from cache within the TTL
+	assert.NoError(t, cache.Enrich(context.Background(), &types.TokenUpdate{Symbol: "TEST/SOL"}))
+	assert.Equal(t, 1, fetcher.calls)
+
+	// Refreshed once the TTL has elapsed
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, cache.Enrich(context.Background(), &types.TokenUpdate{Symbol: "TEST/SOL"}))
+	assert.Equal(t, 2, fetcher.calls)
+}
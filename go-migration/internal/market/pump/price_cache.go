@@ -0,0 +1,87 @@
+package pump
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// defaultPriceCacheTTL is how long a fetched price is served from memory
+// when Config.PriceCacheTTL is unset
+const defaultPriceCacheTTL = time.Second
+
+type cachedPrice struct {
+	price     float64
+	fetchedAt time.Time
+}
+
+// priceCache holds the last fetched price per symbol for ttl. Past ttl a
+// price may still be served for grace when a fresh fetch fails, and
+// concurrent fetches of the same symbol share one request.
+type priceCache struct {
+	ttl      time.Duration
+	grace    time.Duration
+	entries  map[string]cachedPrice
+	inflight singleflight.Group
+	now      func() time.Time
+	mu       sync.RWMutex
+}
+
+func newPriceCache(ttl, grace time.Duration) *priceCache {
+	return &priceCache{
+		ttl:     ttl,
+		grace:   grace,
+		entries: make(map[string]cachedPrice),
+		now:     time.Now,
+	}
+}
+
+// get returns symbol's cached price while it is fresh
+func (c *priceCache) get(symbol string) (float64, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[symbol]
+	c.mu.RUnlock()
+
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		metrics.PumpPriceCacheHits.Inc()
+		return entry.price, true
+	}
+	metrics.PumpPriceCacheMisses.Inc()
+	return 0, false
+}
+
+// stale returns symbol's last price while it is within the grace period
+// after expiring
+func (c *priceCache) stale(symbol string) (float64, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[symbol]
+	c.mu.RUnlock()
+
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl+c.grace {
+		return entry.price, true
+	}
+	return 0, false
+}
+
+func (c *priceCache) set(symbol string, price float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[symbol] = cachedPrice{price: price, fetchedAt: c.now()}
+}
+
+func (c *priceCache) invalidate(symbol string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, symbol)
+}
+
+// InvalidatePrice drops the cached price for symbol so the next GetPrice
+// fetches it, e.g. right after a trade in it executed
+func (p *Provider) InvalidatePrice(symbol string) {
+	if p.prices != nil {
+		p.prices.invalidate(symbol)
+	}
+}
@@ -0,0 +1,137 @@
+package pump
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+func TestProvider_GetPriceCache(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write([]byte(`{"data":{"price":1.5}}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{BaseURL: server.URL, TimeoutSec: 5}, zap.NewNop())
+	now := time.Unix(1700000000, 0)
+	provider.prices.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	hits := testutil.ToFloat64(metrics.PumpPriceCacheHits)
+	misses := testutil.ToFloat64(metrics.PumpPriceCacheMisses)
+
+	for i := 0; i < 3; i++ {
+		price, err := provider.GetPrice(ctx, "PEPE")
+		require.NoError(t, err)
+		assert.Equal(t, 1.5, price)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	assert.Equal(t, hits+2, testutil.ToFloat64(metrics.PumpPriceCacheHits))
+	assert.Equal(t, misses+1, testutil.ToFloat64(metrics.PumpPriceCacheMisses))
+
+	// Invalidation forces the next call to fetch
+	provider.InvalidatePrice("PEPE")
+	_, err := provider.GetPrice(ctx, "PEPE")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// So does the default one second TTL running out
+	now = now.Add(time.Second)
+	_, err = provider.GetPrice(ctx, "PEPE")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fetches))
+}
+
+func TestProvider_GetPriceCacheDisabled(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write([]byte(`{"data":{"price":1.5}}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{BaseURL: server.URL, TimeoutSec: 5, PriceCacheTTL: -1}, zap.NewNop())
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetPrice(context.Background(), "PEPE")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestProvider_GetPriceCoalescesConcurrentFetches(t *testing.T) {
+	var fetches int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		w.Write([]byte(`{"data":{"price":2}}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{BaseURL: server.URL, TimeoutSec: 5}, zap.NewNop())
+	coalesced := testutil.ToFloat64(metrics.PumpPriceFetchesCoalesced)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			price, err := provider.GetPrice(context.Background(), "PEPE")
+			assert.NoError(t, err)
+			assert.Equal(t, 2.0, price)
+		}()
+	}
+	// Let every caller miss the cache and join the in-flight fetch
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	assert.Equal(t, coalesced+5, testutil.ToFloat64(metrics.PumpPriceFetchesCoalesced))
+}
+
+func TestProvider_GetPriceServesStaleWithinGrace(t *testing.T) {
+	var failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"price":1.5}}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{BaseURL: server.URL, TimeoutSec: 5, PriceStaleGrace: 10 * time.Second}, zap.NewNop())
+	now := time.Unix(1700000000, 0)
+	provider.prices.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_, err := provider.GetPrice(ctx, "PEPE")
+	require.NoError(t, err)
+
+	atomic.StoreInt32(&failing, 1)
+	stale := testutil.ToFloat64(metrics.PumpPriceStaleServed)
+	now = now.Add(5 * time.Second)
+	price, err := provider.GetPrice(ctx, "PEPE")
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, price)
+	assert.Equal(t, stale+1, testutil.ToFloat64(metrics.PumpPriceStaleServed))
+
+	// Past the one second TTL plus the grace period the failure surfaces
+	now = now.Add(10 * time.Second)
+	_, err = provider.GetPrice(ctx, "PEPE")
+	assert.Error(t, err)
+}
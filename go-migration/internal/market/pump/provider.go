@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 	
 	"github.com/shopspring/decimal"
@@ -24,17 +25,56 @@ type Provider struct {
 	client       *http.Client
 	baseURL      string
 	wsClient     *WSClient
+	pooled       *PooledWSClient
 	tokenMonitor *TokenMonitor
+	prices       *priceCache
+	pollInterval time.Duration
+	dataSource   atomic.Value // string, see DataSourceMode
 	mu           sync.RWMutex
 	apiKey       string
+	maxMarketCap decimal.Decimal
 }
 
+// Data sources SubscribePrices can stream from
+const (
+	DataSourceWebSocket   = "websocket"
+	DataSourceRESTPolling = "rest_polling"
+)
+
+// defaultPollInterval is how often REST polling fetches tokens when
+// Config.PollInterval is unset
+const defaultPollInterval = 5 * time.Second
+
 // Config represents Pump.fun provider configuration
 type Config struct {
 	BaseURL      string `json:"base_url"`
 	WebSocketURL string `json:"websocket_url"`
 	TimeoutSec   int    `json:"timeout_sec"`
 	APIKey       string `json:"api_key"`
+	// MetadataTTLSec enables token metadata enrichment of updates, refreshing
+	// cached metadata after this many seconds
+	MetadataTTLSec int `json:"metadata_ttl_sec"`
+	// RetryBudgetMax caps the retries shared by the HTTP and WebSocket
+	// clients to this many per RetryBudgetWindowSec. Zero leaves retries
+	// unbounded.
+	RetryBudgetMax       int `json:"retry_budget_max"`
+	RetryBudgetWindowSec int `json:"retry_budget_window_sec"`
+	// ShareWebSocket makes providers for the same WebSocket URL share one
+	// connection from DefaultWSPool instead of dialing their own
+	ShareWebSocket bool `json:"share_websocket"`
+	// PriceCacheTTL serves GetPrice from memory for this long after a fetch.
+	// Zero uses one second, a negative TTL disables the cache.
+	PriceCacheTTL time.Duration `json:"price_cache_ttl"`
+	// PriceStaleGrace keeps serving an expired cached price for this long
+	// when fetching a fresh one fails. Zero fails straight away.
+	PriceStaleGrace time.Duration `json:"price_stale_grace"`
+	// PollInterval is how often SubscribePrices polls the REST API after
+	// falling back from the WebSocket. Zero uses five seconds.
+	PollInterval time.Duration `json:"poll_interval"`
+	// MaxMarketCap drops tokens above this market cap from new token
+	// listings and WebSocket subscriptions. Zero uses
+	// types.DefaultMaxMarketCap.
+	MaxMarketCap decimal.Decimal `json:"max_market_cap"`
 }
 
 // NewProvider creates a new Pump.fun provider
@@ -49,13 +89,7 @@ func NewProvider(config Config, logger *zap.Logger) *Provider {
 		wsURL = config.WebSocketURL
 	}
 
-	return &Provider{
-		logger: logger,
-		client: &http.Client{
-			Timeout: time.Duration(config.TimeoutSec) * time.Second,
-		},
-		baseURL:      baseURL,
-	wsClient:     NewWSClient(wsURL, logger, types.WSConfig{
+	wsConfig := types.WSConfig{
 		APIKey:       config.APIKey,
 		DialTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
@@ -63,14 +97,90 @@ func NewProvider(config Config, logger *zap.Logger) *Provider {
 		PongWait:     60 * time.Second,
 		PingInterval: 15 * time.Second,
 		MaxRetries:   5,
-	}),
+		JitterFraction: 1,
+	}
+
+	p := &Provider{
+		logger: logger,
+		client: &http.Client{
+			Timeout: time.Duration(config.TimeoutSec) * time.Second,
+		},
+		baseURL:      baseURL,
 		tokenMonitor: NewTokenMonitor(baseURL, logger),
+		pollInterval: config.PollInterval,
 		apiKey:       config.APIKey,
+		maxMarketCap: config.MaxMarketCap,
+	}
+	if !p.maxMarketCap.IsPositive() {
+		p.maxMarketCap = types.DefaultMaxMarketCap
+	}
+	if p.pollInterval <= 0 {
+		p.pollInterval = defaultPollInterval
+	}
+	switch {
+	case config.PriceCacheTTL == 0:
+		p.prices = newPriceCache(defaultPriceCacheTTL, config.PriceStaleGrace)
+	case config.PriceCacheTTL > 0:
+		p.prices = newPriceCache(config.PriceCacheTTL, config.PriceStaleGrace)
+	}
+	if config.ShareWebSocket {
+		p.pooled = DefaultWSPool.Acquire(wsURL, logger, wsConfig)
+		p.wsClient = p.pooled.Client()
+	} else {
+		p.wsClient = NewWSClient(wsURL, logger, wsConfig)
+	}
+	p.wsClient.SetMaxMarketCap(p.maxMarketCap)
+	if config.MetadataTTLSec > 0 {
+		p.wsClient.SetMetadataCache(NewMetadataCache(p, time.Duration(config.MetadataTTLSec)*time.Second))
 	}
+	if config.RetryBudgetMax > 0 && config.RetryBudgetWindowSec > 0 {
+		budget := NewRetryBudget(config.RetryBudgetMax, time.Duration(config.RetryBudgetWindowSec)*time.Second)
+		p.wsClient.SetRetryBudget(budget)
+		p.tokenMonitor.SetRetryBudget(budget)
+	}
+	return p
+}
+
+// MaxMarketCap returns the market cap above which tokens are ignored, for
+// consumers filtering updates the same way as the provider
+func (p *Provider) MaxMarketCap() decimal.Decimal {
+	return p.maxMarketCap
 }
 
 // GetPrice implements MarketDataProvider interface
 func (p *Provider) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	if p.prices == nil {
+		return p.fetchPrice(ctx, symbol)
+	}
+	if price, ok := p.prices.get(symbol); ok {
+		return price, nil
+	}
+
+	fetched, err, shared := p.prices.inflight.Do(symbol, func() (interface{}, error) {
+		price, err := p.fetchPrice(ctx, symbol)
+		if err != nil {
+			return 0.0, err
+		}
+		p.prices.set(symbol, price)
+		return price, nil
+	})
+	if shared {
+		metrics.PumpPriceFetchesCoalesced.Inc()
+	}
+	if err != nil {
+		if price, ok := p.prices.stale(symbol); ok {
+			metrics.PumpPriceStaleServed.Inc()
+			p.logger.Warn("Serving stale price after fetch failure",
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			return price, nil
+		}
+		return 0, err
+	}
+	return fetched.(float64), nil
+}
+
+func (p *Provider) fetchPrice(ctx context.Context, symbol string) (float64, error) {
 	url := fmt.Sprintf("%s/api/v1/price/%s", p.baseURL, symbol)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -119,10 +229,11 @@ func (p *Provider) SubscribePrices(ctx context.Context, symbols []string) (<-cha
 	priceUpdates := make(chan *types.PriceUpdate, 100)
 
 	// Try WebSocket connection first
-	wsErr := p.wsClient.Connect(ctx)
+	wsErr := p.connectWS(ctx)
 	if wsErr == nil {
 		if err := p.wsClient.Subscribe(symbols); err == nil {
-			updates := p.wsClient.GetTokenUpdates()
+			p.setDataSource(DataSourceWebSocket)
+			updates := p.tokenUpdates()
 			go func() {
 				defer close(priceUpdates)
 				for update := range updates {
@@ -147,11 +258,13 @@ func (p *Provider) SubscribePrices(ctx context.Context, symbols []string) (<-cha
 
 	// Fallback to REST API polling if WebSocket fails
 	p.logger.Warn("WebSocket connection failed, falling back to REST API polling",
-		zap.Error(wsErr))
+		zap.Error(wsErr),
+		zap.Duration("interval", p.pollInterval))
+	p.setDataSource(DataSourceRESTPolling)
 
 	go func() {
 		defer close(priceUpdates)
-		ticker := time.NewTicker(5 * time.Second)
+		ticker := time.NewTicker(p.pollInterval)
 		defer ticker.Stop()
 
 		for {
@@ -184,6 +297,52 @@ func (p *Provider) SubscribePrices(ctx context.Context, symbols []string) (<-cha
 	return priceUpdates, nil
 }
 
+// DataSourceMode reports whether SubscribePrices is streaming from the
+// WebSocket or has fallen back to REST polling
+func (p *Provider) DataSourceMode() string {
+	if mode, ok := p.dataSource.Load().(string); ok {
+		return mode
+	}
+	return DataSourceWebSocket
+}
+
+func (p *Provider) setDataSource(mode string) {
+	p.dataSource.Store(mode)
+	if mode == DataSourceWebSocket {
+		metrics.PumpDataSourceMode.Set(1)
+	} else {
+		metrics.PumpDataSourceMode.Set(0)
+	}
+}
+
+// connectWS dials the provider's WebSocket, or joins the shared connection
+// when pooling is enabled
+func (p *Provider) connectWS(ctx context.Context) error {
+	if p.pooled != nil {
+		return p.pooled.Connect(ctx)
+	}
+	return p.wsClient.Connect(ctx)
+}
+
+// Connect opens the provider's WebSocket feed. The client keeps the
+// connection alive and reconnects on its own once connected.
+func (p *Provider) Connect(ctx context.Context) error {
+	return p.connectWS(ctx)
+}
+
+// GetTokenUpdates returns the channel WebSocket token updates arrive on
+func (p *Provider) GetTokenUpdates() <-chan *types.TokenUpdate {
+	return p.tokenUpdates()
+}
+
+// tokenUpdates returns the channel WebSocket token updates arrive on
+func (p *Provider) tokenUpdates() <-chan *types.TokenUpdate {
+	if p.pooled != nil {
+		return p.pooled.Updates()
+	}
+	return p.wsClient.GetTokenUpdates()
+}
+
 // GetHistoricalPrices implements MarketDataProvider interface
 func (p *Provider) GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error) {
 	url := fmt.Sprintf("%s/api/v1/historical/%s?interval=%s&limit=%d",
@@ -281,6 +440,20 @@ func (p *Provider) GetBondingCurve(ctx context.Context, symbol string) (*types.B
 	return &result.Data, nil
 }
 
+// EstimateFillPrice returns the expected average fill price of trading
+// amount of symbol, including the slippage of walking its bonding curve
+func (p *Provider) EstimateFillPrice(ctx context.Context, symbol string, side types.SignalType, amount decimal.Decimal) (decimal.Decimal, error) {
+	curve, err := p.GetBondingCurve(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	price, err := curve.AverageFillPrice(side, amount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to estimate fill price for %s: %w", symbol, err)
+	}
+	return price, nil
+}
+
 // GetNewTokens fetches new tokens from the API
 func (p *Provider) GetNewTokens(ctx context.Context) ([]*types.TokenMarketInfo, error) {
 	url := fmt.Sprintf("%s/api/v1/price/list", p.baseURL)
@@ -349,7 +522,7 @@ func (p *Provider) GetNewTokens(ctx context.Context) ([]*types.TokenMarketInfo,
 
 	tokens := make([]*types.TokenMarketInfo, 0, len(response.Data))
 	for _, t := range response.Data {
-		if t.MarketCap > 30000 {
+		if decimal.NewFromFloat(t.MarketCap).GreaterThan(p.maxMarketCap) {
 			continue
 		}
 		token := &types.TokenMarketInfo{
@@ -401,8 +574,38 @@ func (p *Provider) SubscribeNewTokens(ctx context.Context) (<-chan *types.TokenM
 
 	return updates, nil
 }
-// ExecuteOrder executes a trade order
-func (p *Provider) ExecuteOrder(ctx context.Context, symbol string, orderType types.SignalType, amount decimal.Decimal, price decimal.Decimal, stopLoss *decimal.Decimal, takeProfits []decimal.Decimal) error {
+// ExecuteOrder executes a trade order. takeProfitSizes, when given, are the
+// amounts to sell at each of the takeProfits prices.
+func (p *Provider) ExecuteOrder(ctx context.Context, symbol string, orderType types.SignalType, amount decimal.Decimal, price decimal.Decimal, stopLoss *decimal.Decimal, takeProfits []decimal.Decimal, takeProfitSizes []decimal.Decimal) error {
+	_, err := p.executeOrder(ctx, symbol, orderType, amount, price, stopLoss, takeProfits, takeProfitSizes, "")
+	return err
+}
+
+// OrderFill is what the venue reports having filled for an order
+type OrderFill struct {
+	TxHash   string
+	Size     decimal.Decimal // Filled amount, below the order amount on a partial fill
+	AvgPrice decimal.Decimal // Volume weighted average fill price
+}
+
+// ExecuteOrderFill executes an order like ExecuteOrder and returns the
+// reported fill. Venues that omit fill details are taken to have filled
+// the whole amount at price.
+func (p *Provider) ExecuteOrderFill(ctx context.Context, symbol string, orderType types.SignalType, amount decimal.Decimal, price decimal.Decimal, stopLoss *decimal.Decimal, takeProfits []decimal.Decimal) (*OrderFill, error) {
+	return p.executeOrder(ctx, symbol, orderType, amount, price, stopLoss, takeProfits, nil, "")
+}
+
+// PlaceOrder submits order to the venue, forwarding its ClientTag when set
+func (p *Provider) PlaceOrder(ctx context.Context, order *types.Order) error {
+	orderType := types.SignalTypeBuy
+	if order.Side == types.OrderSideSell {
+		orderType = types.SignalTypeSell
+	}
+	_, err := p.executeOrder(ctx, order.Symbol, orderType, order.Size, order.Price, nil, nil, nil, order.ClientTag)
+	return err
+}
+
+func (p *Provider) executeOrder(ctx context.Context, symbol string, orderType types.SignalType, amount decimal.Decimal, price decimal.Decimal, stopLoss *decimal.Decimal, takeProfits []decimal.Decimal, takeProfitSizes []decimal.Decimal, clientTag string) (*OrderFill, error) {
 	url := fmt.Sprintf("%s/tokens/%s/trade", p.baseURL, symbol)
 
 	payload := map[string]interface{}{
@@ -410,20 +613,28 @@ func (p *Provider) ExecuteOrder(ctx context.Context, symbol string, orderType ty
 		"amount":      amount.String(),
 		"price":       price.String(),
 		"slippage":    "0.005",
-		"stop_loss":   stopLoss.String(),
 		"take_profit": takeProfits,
 	}
+	if stopLoss != nil {
+		payload["stop_loss"] = stopLoss.String()
+	}
+	if len(takeProfitSizes) > 0 {
+		payload["take_profit_sizes"] = takeProfitSizes
+	}
+	if clientTag != "" {
+		payload["client_tag"] = clientTag
+	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		metrics.APIErrors.WithLabelValues("marshal_trade_payload").Inc()
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		metrics.APIErrors.WithLabelValues("create_trade_request").Inc()
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -441,20 +652,23 @@ func (p *Provider) ExecuteOrder(ctx context.Context, symbol string, orderType ty
 	resp, err := p.client.Do(req)
 	if err != nil {
 		metrics.APIErrors.WithLabelValues("execute_trade").Inc()
-		return fmt.Errorf("failed to execute trade: %w", err)
+		return nil, fmt.Errorf("failed to execute trade: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		metrics.APIErrors.WithLabelValues("trade_status").Inc()
-		return fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("unexpected status code: %d, response: %s", resp.StatusCode, string(body))
 	}
 
 	var result struct {
 		Data struct {
-			TxHash string `json:"tx_hash"`
-			Status string `json:"status"`
+			TxHash       string          `json:"tx_hash"`
+			Status       string          `json:"status"`
+			ClientTag    string          `json:"client_tag"`
+			FilledAmount decimal.Decimal `json:"filled_amount"`
+			AvgPrice     decimal.Decimal `json:"avg_price"`
 		} `json:"data"`
 		Error *struct {
 			Code    int    `json:"code"`
@@ -464,21 +678,39 @@ func (p *Provider) ExecuteOrder(ctx context.Context, symbol string, orderType ty
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		metrics.APIErrors.WithLabelValues("decode_trade_response").Inc()
-		return fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if result.Error != nil {
 		metrics.APIErrors.WithLabelValues("trade_error").Inc()
-		return fmt.Errorf("trade error: %s (code: %d)", result.Error.Message, result.Error.Code)
+		return nil, fmt.Errorf("trade error: %s (code: %d)", result.Error.Message, result.Error.Code)
+	}
+
+	if clientTag != "" && result.Data.ClientTag != clientTag {
+		metrics.APIErrors.WithLabelValues("client_tag_mismatch").Inc()
+		p.logger.Warn("Venue did not echo client tag",
+			zap.String("symbol", symbol),
+			zap.String("client_tag", clientTag),
+			zap.String("echoed_tag", result.Data.ClientTag),
+			zap.String("tx_hash", result.Data.TxHash))
 	}
 
 	p.logger.Info("Trade executed successfully",
 		zap.String("symbol", symbol),
 		zap.String("tx_hash", result.Data.TxHash),
-		zap.String("status", result.Data.Status))
+		zap.String("status", result.Data.Status),
+		zap.String("client_tag", clientTag))
 
 	metrics.PumpTradeExecutions.WithLabelValues("success").Inc()
-	return nil
+
+	fill := &OrderFill{TxHash: result.Data.TxHash, Size: amount, AvgPrice: price}
+	if result.Data.FilledAmount.IsPositive() {
+		fill.Size = result.Data.FilledAmount
+	}
+	if result.Data.AvgPrice.IsPositive() {
+		fill.AvgPrice = result.Data.AvgPrice
+	}
+	return fill, nil
 }
 
 // ExecuteTrade implements MarketDataProvider interface
@@ -489,8 +721,10 @@ func (p *Provider) ExecuteTrade(ctx context.Context, params map[string]interface
 	price := params["price"].(decimal.Decimal)
 	stopLoss := params["stop_loss"].(*decimal.Decimal)
 	takeProfits := params["take_profits"].([]decimal.Decimal)
+	clientTag, _ := params["client_tag"].(string)
 	
-	return p.ExecuteOrder(ctx, symbol, orderType, amount, price, stopLoss, takeProfits)
+	_, err := p.executeOrder(ctx, symbol, orderType, amount, price, stopLoss, takeProfits, nil, clientTag)
+	return err
 }
 
 // Close closes the provider and its WebSocket client
@@ -498,6 +732,9 @@ func (p *Provider) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.pooled != nil {
+		return p.pooled.Release()
+	}
 	if p.wsClient != nil {
 		return p.wsClient.Close()
 	}
@@ -2,12 +2,18 @@ package pump
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/market"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 func TestPumpProvider(t *testing.T) {
@@ -50,3 +56,46 @@ func TestPumpProvider(t *testing.T) {
 		}
 	})
 }
+
+func TestProvider_PlaceOrderForwardsClientTag(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tokens/TEST/trade", r.URL.Path)
+		payload = nil
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		tag, _ := payload["client_tag"].(string)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{
+				"tx_hash":    "abc",
+				"status":     "confirmed",
+				"client_tag": tag,
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewProvider(Config{BaseURL: server.URL, TimeoutSec: 5}, zap.NewNop())
+	defer provider.Close()
+
+	err := provider.PlaceOrder(context.Background(), &types.Order{
+		Symbol:    "TEST",
+		Side:      types.OrderSideBuy,
+		Price:     decimal.NewFromFloat(1.5),
+		Size:      decimal.NewFromInt(10),
+		ClientTag: "strat-a-0001",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "strat-a-0001", payload["client_tag"])
+	assert.Equal(t, "buy", payload["type"])
+
+	// Untagged orders leave the field out entirely
+	err = provider.PlaceOrder(context.Background(), &types.Order{
+		Symbol: "TEST",
+		Side:   types.OrderSideSell,
+		Price:  decimal.NewFromFloat(1.5),
+		Size:   decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+	_, tagged := payload["client_tag"]
+	assert.False(t, tagged)
+}
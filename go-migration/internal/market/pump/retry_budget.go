@@ -0,0 +1,77 @@
+package pump
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// ErrRetryBudgetExhausted is returned when an operation gives up because the
+// shared retry budget has no tokens left
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget is a token bucket capping the total number of retries per
+// window across every client sharing it, so that retries cannot amplify load
+// during an outage. A nil budget allows unlimited retries.
+type RetryBudget struct {
+	capacity float64
+	rate     float64 // tokens refilled per second
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// NewRetryBudget allows up to max retries per window
+func NewRetryBudget(max int, window time.Duration) *RetryBudget {
+	b := &RetryBudget{
+		capacity: float64(max),
+		rate:     float64(max) / window.Seconds(),
+		tokens:   float64(max),
+		now:      time.Now,
+	}
+	b.last = b.now()
+	metrics.RetryBudgetRemaining.Set(b.tokens)
+	return b
+}
+
+// Allow consumes a retry token, reporting false when the budget is exhausted
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	metrics.RetryBudgetRemaining.Set(b.tokens)
+	return true
+}
+
+// Remaining returns the number of whole retries currently available
+func (b *RetryBudget) Remaining() int {
+	if b == nil {
+		return math.MaxInt
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return int(b.tokens)
+}
+
+func (b *RetryBudget) refill() {
+	now := b.now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	metrics.RetryBudgetRemaining.Set(b.tokens)
+}
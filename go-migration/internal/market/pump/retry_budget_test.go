@@ -0,0 +1,59 @@
+package pump
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRetryBudget_Refill(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	budget := NewRetryBudget(4, time.Minute)
+	budget.now = func() time.Time { return now }
+	budget.last = now
+
+	for i := 0; i < 4; i++ {
+		assert.True(t, budget.Allow())
+	}
+	assert.False(t, budget.Allow())
+	assert.Zero(t, budget.Remaining())
+
+	// A quarter of the window refills a quarter of the budget
+	now = now.Add(15 * time.Second)
+	assert.Equal(t, 1, budget.Remaining())
+	assert.True(t, budget.Allow())
+	assert.False(t, budget.Allow())
+
+	// The budget never grows past its capacity
+	now = now.Add(time.Hour)
+	assert.Equal(t, 4, budget.Remaining())
+}
+
+func TestTokenMonitor_FailsFastWhenBudgetExhausted(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	budget := NewRetryBudget(2, time.Hour)
+	for budget.Allow() {
+	}
+
+	monitor := NewTokenMonitor(server.URL, zap.NewNop())
+	monitor.SetRetryBudget(budget)
+
+	start := time.Now()
+	_, err := monitor.fetchNewTokens(context.Background())
+	assert.True(t, errors.Is(err, ErrRetryBudgetExhausted))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
@@ -21,6 +21,7 @@ type TokenMonitor struct {
 	updateChan chan *types.TokenUpdate
 	mu         sync.RWMutex
 	active     bool
+	budget     *RetryBudget
 }
 
 type TokenUpdate struct {
@@ -40,6 +41,13 @@ func NewTokenMonitor(baseURL string, logger *zap.Logger) *TokenMonitor {
 	}
 }
 
+// SetRetryBudget caps fetch retries with a budget shared with other clients
+func (tm *TokenMonitor) SetRetryBudget(budget *RetryBudget) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.budget = budget
+}
+
 func (tm *TokenMonitor) Start(ctx context.Context) error {
 	tm.mu.Lock()
 	if tm.active {
@@ -98,9 +106,17 @@ func (tm *TokenMonitor) fetchNewTokens(ctx context.Context) ([]*types.TokenUpdat
 	
 	var updates []*types.TokenUpdate
 	var lastErr error
+
+	tm.mu.RLock()
+	budget := tm.budget
+	tm.mu.RUnlock()
 	
 	for retry := 0; retry < maxRetries; retry++ {
 		if retry > 0 {
+			if !budget.Allow() {
+				metrics.APIErrors.WithLabelValues("retry_budget_exhausted").Inc()
+				return nil, fmt.Errorf("%w: %v", ErrRetryBudgetExhausted, lastErr)
+			}
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
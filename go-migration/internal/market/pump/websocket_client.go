@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/metrics"
@@ -27,6 +28,14 @@ type WSClient struct {
 	trades      chan *types.Trade
 	config      types.WSConfig
 	initMessage map[string]interface{}
+	metadata    *MetadataCache
+	maxMarketCap decimal.Decimal // Sent with subscriptions when positive
+	budget      *RetryBudget
+	// subscriptions holds every subscription payload sent successfully, in
+	// order, so a reconnect can replay them
+	subscriptions []map[string]interface{}
+	reconnecting  sync.Mutex
+	closeOnce     sync.Once
 	// metrics field removed as we're using global metrics
 }
 
@@ -63,6 +72,38 @@ func NewWSClient(url string, logger *zap.Logger, config types.WSConfig) *WSClien
 	}
 }
 
+// SetMaxMarketCap sets the market cap cap sent with subscriptions so the
+// feed only streams tokens below it. Clients shared through a WSPool use the
+// cap set last.
+func (c *WSClient) SetMaxMarketCap(maxMarketCap decimal.Decimal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxMarketCap = maxMarketCap
+}
+
+// subscriptionData adds the market cap cap to a subscription's data.
+// Callers hold c.mu.
+func (c *WSClient) subscriptionData(data map[string]interface{}) map[string]interface{} {
+	if c.maxMarketCap.IsPositive() {
+		data["max_market_cap"] = c.maxMarketCap.InexactFloat64()
+	}
+	return data
+}
+
+// SetMetadataCache enables enrichment of token updates with cached metadata
+func (c *WSClient) SetMetadataCache(cache *MetadataCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadata = cache
+}
+
+// SetRetryBudget caps reconnect attempts with a budget shared with other clients
+func (c *WSClient) SetRetryBudget(budget *RetryBudget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.budget = budget
+}
+
 func (c *WSClient) Connect(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -120,12 +161,18 @@ func (c *WSClient) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to send auth message: %w", err)
 	}
 	
-	// Wait for auth response
+	// Wait for auth response, no longer than the dial itself may take
+	deadline := time.Now().Add(c.config.DialTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	c.conn.SetReadDeadline(deadline)
 	_, message, err := c.conn.ReadMessage()
 	if err != nil {
 		metrics.APIErrors.WithLabelValues("websocket_auth_response").Inc()
 		return fmt.Errorf("failed to read auth response: %w", err)
 	}
+	c.conn.SetReadDeadline(time.Time{})
 
 	var authResponse struct {
 		Type    string `json:"type"`
@@ -146,12 +193,12 @@ func (c *WSClient) Connect(ctx context.Context) error {
 	c.initMessage = map[string]interface{}{
 		"type": "subscribe",
 		"channel": "market",
-		"data": map[string]interface{}{
+		"data": c.subscriptionData(map[string]interface{}{
 			"interval": "1m",
 			"include_trades": true,
 			"include_orderbook": true,
 			"include_metadata": true,
-		},
+		}),
 	}
 	
 	if err := c.conn.WriteJSON(c.initMessage); err != nil {
@@ -160,10 +207,10 @@ func (c *WSClient) Connect(ctx context.Context) error {
 	}
 	
 	// Start ping/pong routine
-	go c.setupPingPong()
+	go c.setupPingPong(c.conn)
 	
 	// Start message pump
-	go c.readPump()
+	go c.readPump(c.conn)
 	
 	c.logger.Info("Successfully connected to WebSocket",
 		zap.String("url", c.url))
@@ -171,13 +218,21 @@ func (c *WSClient) Connect(ctx context.Context) error {
 	return nil
 }
 
-func (c *WSClient) setupPingPong() {
+// current reports whether conn is still the client's connection, rather
+// than one replaced by a reconnect
+func (c *WSClient) current(conn *websocket.Conn) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn == conn
+}
+
+func (c *WSClient) setupPingPong(conn *websocket.Conn) {
 	ticker := time.NewTicker(c.config.PingInterval)
 	lastPong := time.Now()
 
-	c.conn.SetPongHandler(func(string) error {
+	conn.SetPongHandler(func(string) error {
 		lastPong = time.Now()
-		c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
+		conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 		metrics.APIErrors.WithLabelValues("websocket_pong_received").Inc()
 		metrics.WebsocketConnections.Set(1)
 		c.logger.Debug("Pong received successfully",
@@ -192,6 +247,9 @@ func (c *WSClient) setupPingPong() {
 			metrics.WebsocketConnections.Set(0)
 			return
 		case <-ticker.C:
+			if !c.current(conn) {
+				return
+			}
 			if time.Since(lastPong) > c.config.PongWait {
 				metrics.APIErrors.WithLabelValues("websocket_pong_timeout").Inc()
 				metrics.WebsocketConnections.Set(0)
@@ -202,7 +260,7 @@ func (c *WSClient) setupPingPong() {
 				return
 			}
 
-			if err := c.conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(c.config.WriteTimeout)); err != nil {
+			if err := conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(c.config.WriteTimeout)); err != nil {
 				metrics.APIErrors.WithLabelValues("websocket_ping").Inc()
 				metrics.WebsocketConnections.Set(0)
 				c.logger.Error("Failed to write ping message", zap.Error(err))
@@ -214,14 +272,12 @@ func (c *WSClient) setupPingPong() {
 			c.logger.Debug("Ping sent successfully")
 		}
 	}
-	
-	defer ticker.Stop()
 }
 
-func (c *WSClient) readPump() {
+func (c *WSClient) readPump(conn *websocket.Conn) {
+	// The updates channel is owned and closed by Close
 	defer func() {
-		c.conn.Close()
-		close(c.updates)
+		conn.Close()
 	}()
 
 	for {
@@ -229,9 +285,18 @@ func (c *WSClient) readPump() {
 		case <-c.done:
 			return
 		default:
-			c.conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout))
-			_, message, err := c.conn.ReadMessage()
+			conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout))
+			_, message, err := conn.ReadMessage()
 			if err != nil {
+				select {
+				case <-c.done:
+					return
+				default:
+				}
+				// A replaced connection has its own pump already
+				if !c.current(conn) {
+					return
+				}
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				metrics.APIErrors.WithLabelValues("websocket_unexpected_close").Inc()
 				metrics.WebsocketConnections.Set(0)
@@ -241,7 +306,7 @@ func (c *WSClient) readPump() {
 					c.logger.Error("WebSocket read error", zap.Error(err))
 				}
 				c.reconnect()
-				continue
+				return
 			}
 
 			var response struct {
@@ -340,6 +405,14 @@ func (c *WSClient) readPump() {
 						zap.Float64("volume", tokenUpdate.Volume))
 				}
 
+				if c.metadata != nil {
+					if err := c.metadata.Enrich(context.Background(), tokenUpdate); err != nil {
+						c.logger.Debug("Failed to enrich token update",
+							zap.String("symbol", tokenUpdate.Symbol),
+							zap.Error(err))
+					}
+				}
+
 				// Send update to trading executor
 				select {
 				case c.updates <- tokenUpdate:
@@ -378,34 +451,36 @@ func (c *WSClient) Subscribe(methods []string) error {
 	payload := map[string]interface{}{
 		"type": "subscribe",
 		"channel": "market",
-		"data": map[string]interface{}{
+		"data": c.subscriptionData(map[string]interface{}{
 			"symbols": methods,
 			"interval": "1m",
 			"include_trades": true,
 			"include_orderbook": true,
 			"include_metadata": true,
-		},
+		}),
 	}
 
 	if err := c.conn.WriteJSON(payload); err != nil {
 		metrics.APIErrors.WithLabelValues("websocket_subscribe").Inc()
 		return fmt.Errorf("failed to send subscription message: %w", err)
 	}
+	c.subscriptions = append(c.subscriptions, payload)
 
 	if len(methods) > 0 && methods[0] == "subscribeNewToken" {
 		tokenPayload := map[string]interface{}{
 			"type": "subscribe",
 			"channel": "tokens",
-			"data": map[string]interface{}{
+			"data": c.subscriptionData(map[string]interface{}{
 				"interval": "1m",
 				"include_metadata": true,
-			},
+			}),
 		}
 
 		if err := c.conn.WriteJSON(tokenPayload); err != nil {
 			metrics.APIErrors.WithLabelValues("websocket_token_subscribe").Inc()
 			return fmt.Errorf("failed to send token subscription message: %w", err)
 		}
+		c.subscriptions = append(c.subscriptions, tokenPayload)
 	}
 
 	c.logger.Info("Subscribed to PumpPortal WebSocket",
@@ -413,34 +488,80 @@ func (c *WSClient) Subscribe(methods []string) error {
 	return nil
 }
 
-func (c *WSClient) reconnect() {
+// replaySubscriptions resends every recorded subscription, in order, on the
+// current connection
+func (c *WSClient) replaySubscriptions() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.conn == nil {
+		return fmt.Errorf("websocket connection not established")
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
+	for _, payload := range c.subscriptions {
+		if err := c.conn.WriteJSON(payload); err != nil {
+			return fmt.Errorf("failed to replay subscription: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *WSClient) reconnect() {
+	// The read and ping pumps can both notice a dead connection; only the
+	// first reconnects
+	if !c.reconnecting.TryLock() {
+		return
+	}
+	defer c.reconnecting.Unlock()
+
+	c.mu.RLock()
+	budget := c.budget
+	c.mu.RUnlock()
+
 	metrics.WebsocketConnections.Set(0)
 	retries := 0
 	backoff := time.Second
 	maxBackoff := 30 * time.Second
 
+	attempts := metrics.WebsocketReconnectAttempts.WithLabelValues(c.url)
 	for retries < c.config.MaxRetries {
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		attempts.Set(float64(retries + 1))
+
+		if !budget.Allow() {
+			metrics.APIErrors.WithLabelValues("retry_budget_exhausted").Inc()
+			c.logger.Error("Retry budget exhausted, giving up reconnect",
+				zap.Int("retry", retries+1))
+			return
+		}
+
 		c.logger.Info("Attempting to reconnect", 
 			zap.Int("retry", retries+1),
 			zap.Duration("backoff", backoff))
 		
 		if err := c.Connect(context.Background()); err == nil {
-			c.logger.Info("Successfully reconnected")
 			// Resubscribe to previous subscriptions
-			if err := c.Subscribe([]string{"subscribeNewToken"}); err != nil {
-				metrics.APIErrors.WithLabelValues("websocket_resubscribe").Inc()
-				c.logger.Error("Failed to resubscribe after reconnect", zap.Error(err))
-				metrics.WebsocketConnections.Set(0)
-				continue
+			err = c.replaySubscriptions()
+			if err == nil {
+				attempts.Set(0)
+				c.logger.Info("Successfully reconnected")
+				return
 			}
-			return
+			metrics.APIErrors.WithLabelValues("websocket_resubscribe").Inc()
+			c.logger.Error("Failed to resubscribe after reconnect", zap.Error(err))
+			metrics.WebsocketConnections.Set(0)
 		}
 
 		retries++
-		time.Sleep(backoff)
+		if retries >= c.config.MaxRetries {
+			break
+		}
+		time.Sleep(jitter(backoff, c.config.JitterFraction))
 		backoff = time.Duration(float64(backoff) * 1.5)
 		if backoff > maxBackoff {
 			backoff = maxBackoff
@@ -452,6 +573,19 @@ func (c *WSClient) reconnect() {
 		zap.Int("max_retries", c.config.MaxRetries))
 }
 
+// jitter randomizes the last fraction of backoff, so the wait falls between
+// backoff*(1-fraction) and backoff. A fraction of 1 is full jitter.
+func jitter(backoff time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || backoff <= 0 {
+		return backoff
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	fixed := time.Duration(float64(backoff) * (1 - fraction))
+	return fixed + time.Duration(rand.Int63n(int64(backoff-fixed)+1))
+}
+
 func (c *WSClient) GetTokenUpdates() <-chan *types.TokenUpdate {
 	return c.updates
 }
@@ -464,16 +598,17 @@ func (c *WSClient) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	select {
-	case <-c.done:
-		// Already closed
-		return nil
-	default:
+	closed := true
+	c.closeOnce.Do(func() {
+		closed = false
 		close(c.done)
 		close(c.updates)
 		if c.trades != nil {
 			close(c.trades)
 		}
+	})
+	if closed {
+		return nil
 	}
 
 	if c.conn != nil {
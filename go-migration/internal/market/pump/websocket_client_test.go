@@ -0,0 +1,129 @@
+package pump
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// recordingWSServer acknowledges auth and records the messages received on
+// each connection, in order
+type recordingWSServer struct {
+	*httptest.Server
+	conns    []*websocket.Conn
+	messages [][]map[string]interface{}
+	mu       sync.Mutex
+}
+
+func newRecordingWSServer() *recordingWSServer {
+	s := &recordingWSServer{}
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		s.mu.Lock()
+		conn.WriteJSON(map[string]string{"type": "auth", "status": "success"})
+		s.conns = append(s.conns, conn)
+		s.messages = append(s.messages, nil)
+		index := len(s.conns) - 1
+		s.mu.Unlock()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg map[string]interface{}
+			json.Unmarshal(data, &msg)
+			s.mu.Lock()
+			s.messages[index] = append(s.messages[index], msg)
+			s.mu.Unlock()
+		}
+	}))
+	return s
+}
+
+// received returns the messages received on connection i
+func (s *recordingWSServer) received(i int) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i >= len(s.messages) {
+		return nil
+	}
+	return append([]map[string]interface{}(nil), s.messages[i]...)
+}
+
+func (s *recordingWSServer) drop(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[i].Close()
+}
+
+func TestWSClient_ReconnectReplaysSubscriptions(t *testing.T) {
+	server := newRecordingWSServer()
+	defer server.Close()
+
+	client := NewWSClient("ws"+server.URL[4:], zap.NewNop(), types.WSConfig{MaxRetries: 3})
+	require.NoError(t, client.Connect(context.Background()))
+	require.NoError(t, client.Subscribe([]string{"PEPE", "BONK"}))
+	require.NoError(t, client.Subscribe([]string{"subscribeNewToken"}))
+
+	// The market subscription sent by Connect, then three from Subscribe
+	require.Eventually(t, func() bool { return len(server.received(0)) == 4 }, time.Second, 10*time.Millisecond)
+	subscribed := server.received(0)[1:]
+
+	server.drop(0)
+
+	// The new connection gets Connect's subscription followed by every
+	// earlier subscription in order
+	require.Eventually(t, func() bool { return len(server.received(1)) == 4 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, subscribed, server.received(1)[1:])
+	assert.Equal(t, "tokens", server.received(1)[3]["channel"])
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, client.Close())
+}
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Second, jitter(time.Second, 0))
+
+	for i := 0; i < 100; i++ {
+		full := jitter(time.Second, 1)
+		assert.True(t, full >= 0 && full <= time.Second, full)
+
+		half := jitter(time.Second, 0.5)
+		assert.True(t, half >= 500*time.Millisecond && half <= time.Second, half)
+	}
+}
+
+func TestWSClient_ReconnectAttemptsGauge(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := "ws" + server.URL[4:]
+	server.Close()
+
+	client := NewWSClient(url, zap.NewNop(), types.WSConfig{MaxRetries: 2, JitterFraction: 1})
+	client.reconnect()
+
+	// A client that gave up stays at its last attempt for alerting
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.WebsocketReconnectAttempts.WithLabelValues(url)))
+}
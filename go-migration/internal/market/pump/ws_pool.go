@@ -0,0 +1,168 @@
+package pump
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// DefaultWSPool is the pool providers configured with ShareWebSocket use
+var DefaultWSPool = NewWSPool()
+
+// WSPool shares a single WebSocket connection per endpoint between every
+// consumer of that endpoint, fanning updates out to each consumer's channel
+type WSPool struct {
+	conns map[string]*pooledConn
+	mu    sync.Mutex
+}
+
+type pooledConn struct {
+	pool      *WSPool
+	url       string
+	client    *WSClient
+	refs      int
+	connected bool
+	fanout    bool
+	subs      map[chan *types.TokenUpdate]struct{}
+	mu        sync.Mutex
+}
+
+// PooledWSClient is a consumer's handle on a shared connection
+type PooledWSClient struct {
+	conn     *pooledConn
+	subs     []chan *types.TokenUpdate
+	released bool
+}
+
+func NewWSPool() *WSPool {
+	return &WSPool{conns: make(map[string]*pooledConn)}
+}
+
+// Acquire returns a handle on the connection to url, creating the client on
+// first use. Later callers share that client and its configuration.
+func (p *WSPool) Acquire(url string, logger *zap.Logger, config types.WSConfig) *PooledWSClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, ok := p.conns[url]
+	if !ok {
+		conn = &pooledConn{
+			pool:   p,
+			url:    url,
+			client: NewWSClient(url, logger, config),
+			subs:   make(map[chan *types.TokenUpdate]struct{}),
+		}
+		p.conns[url] = conn
+	}
+	conn.refs++
+	return &PooledWSClient{conn: conn}
+}
+
+// Connections returns the number of endpoints with a live shared client
+func (p *WSPool) Connections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+// Client returns the underlying shared client
+func (h *PooledWSClient) Client() *WSClient {
+	return h.conn.client
+}
+
+// Connect dials the shared connection unless another consumer already has
+func (h *PooledWSClient) Connect(ctx context.Context) error {
+	c := h.conn
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connected {
+		return nil
+	}
+	if err := c.client.Connect(ctx); err != nil {
+		return err
+	}
+	c.connected = true
+	return nil
+}
+
+// Updates returns a new channel receiving every token update read from the
+// shared connection
+func (h *PooledWSClient) Updates() <-chan *types.TokenUpdate {
+	c := h.conn
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := make(chan *types.TokenUpdate, 100)
+	c.subs[sub] = struct{}{}
+	h.subs = append(h.subs, sub)
+
+	if !c.fanout {
+		c.fanout = true
+		go c.run()
+	}
+	return sub
+}
+
+// Release drops the handle's subscriptions, closing the shared connection
+// once no consumer holds it
+func (h *PooledWSClient) Release() error {
+	c := h.conn
+	c.pool.mu.Lock()
+	c.mu.Lock()
+
+	if h.released {
+		c.mu.Unlock()
+		c.pool.mu.Unlock()
+		return nil
+	}
+	h.released = true
+
+	for _, sub := range h.subs {
+		if _, ok := c.subs[sub]; ok {
+			delete(c.subs, sub)
+			close(sub)
+		}
+	}
+	h.subs = nil
+
+	c.refs--
+	last := c.refs == 0
+	if last {
+		delete(c.pool.conns, c.url)
+	}
+	c.mu.Unlock()
+	c.pool.mu.Unlock()
+
+	if last {
+		return c.client.Close()
+	}
+	return nil
+}
+
+// run copies updates from the shared client to every subscriber
+func (c *pooledConn) run() {
+	for update := range c.client.GetTokenUpdates() {
+		c.mu.Lock()
+		for sub := range c.subs {
+			select {
+			case sub <- update:
+			default:
+				c.client.logger.Warn("Pooled subscriber full, dropping update",
+					zap.String("url", c.url),
+					zap.String("symbol", update.Symbol))
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for sub := range c.subs {
+		delete(c.subs, sub)
+		close(sub)
+	}
+}
@@ -0,0 +1,107 @@
+package pump
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// countingWSServer accepts WebSocket connections, acknowledges auth and
+// keeps each connection so the test can push messages through it
+type countingWSServer struct {
+	*httptest.Server
+	conns []*websocket.Conn
+	mu    sync.Mutex
+}
+
+func newCountingWSServer() *countingWSServer {
+	s := &countingWSServer{}
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		s.mu.Lock()
+		conn.WriteJSON(map[string]string{"type": "auth", "status": "success"})
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	return s
+}
+
+func (s *countingWSServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+func (s *countingWSServer) broadcast(v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.WriteJSON(v)
+	}
+}
+
+func TestProvider_SharedWebSocketReusesConnection(t *testing.T) {
+	server := newCountingWSServer()
+	defer server.Close()
+	wsURL := "ws" + server.URL[4:]
+
+	config := Config{BaseURL: server.URL, WebSocketURL: wsURL, TimeoutSec: 5, ShareWebSocket: true}
+	first := NewProvider(config, zap.NewNop())
+	second := NewProvider(config, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	firstUpdates, err := first.SubscribePrices(ctx, []string{"TEST"})
+	assert.NoError(t, err)
+	secondUpdates, err := second.SubscribePrices(ctx, []string{"TEST"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, server.connections())
+	assert.Equal(t, 1, DefaultWSPool.Connections())
+
+	server.broadcast(map[string]interface{}{
+		"type":    "market",
+		"channel": "market",
+		"data":    map[string]interface{}{"symbol": "TEST", "price": 1.5},
+	})
+
+	for _, updates := range []<-chan *types.PriceUpdate{firstUpdates, secondUpdates} {
+		select {
+		case update := <-updates:
+			assert.Equal(t, "TEST", update.Symbol)
+		case <-time.After(2 * time.Second):
+			t.Fatal("update was not fanned out to every consumer")
+		}
+	}
+
+	// The connection stays up until its last consumer releases it
+	assert.NoError(t, first.Close())
+	assert.Equal(t, 1, DefaultWSPool.Connections())
+	assert.NoError(t, second.Close())
+	assert.Equal(t, 0, DefaultWSPool.Connections())
+}
@@ -0,0 +1,206 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// QualityConfig configures rolling per-provider data-quality scoring. The
+// score averages four components in [0, 1]: error rate, staleness, update
+// frequency and price deviation from the cross-provider consensus.
+type QualityConfig struct {
+	Enabled         bool          `json:"enabled"`
+	Window          time.Duration `json:"window"`           // Rolling window errors and updates are counted over
+	StaleAfter      time.Duration `json:"stale_after"`      // Data age beyond which freshness starts to decay
+	ExpectedUpdates int           `json:"expected_updates"` // Updates per window that earn a full frequency score
+	MaxDeviation    float64       `json:"max_deviation"`    // Relative deviation from consensus that scores zero
+}
+
+type qualityEvent struct {
+	at  time.Time
+	err bool
+}
+
+type providerQuality struct {
+	name     string
+	events   []qualityEvent
+	lastData time.Time
+	prices   map[string]float64 // Latest price per symbol
+}
+
+// QualityTracker keeps the rolling data-quality score of each provider
+type QualityTracker struct {
+	config    QualityConfig
+	providers []*providerQuality
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewQualityTracker tracks the quality of providers, which are identified
+// by their position in the slice
+func NewQualityTracker(config QualityConfig, providers []types.MarketDataProvider) *QualityTracker {
+	t := &QualityTracker{
+		config:    config,
+		providers: make([]*providerQuality, len(providers)),
+		now:       time.Now,
+	}
+	for i, p := range providers {
+		t.providers[i] = &providerQuality{
+			name:   providerName(p),
+			prices: make(map[string]float64),
+		}
+	}
+	return t
+}
+
+// providerName uses the provider's Name method when it has one, falling
+// back to its type name
+func providerName(p types.MarketDataProvider) string {
+	if named, ok := p.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", p)
+}
+
+// RecordPrice records fresh data from provider i
+func (t *QualityTracker) RecordPrice(i int, symbol string, price float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.providers[i]
+	now := t.now()
+	p.events = append(t.prune(p), qualityEvent{at: now})
+	p.lastData = now
+	p.prices[symbol] = price
+}
+
+// RecordError records a failed request to provider i
+func (t *QualityTracker) RecordError(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.providers[i]
+	p.events = append(t.prune(p), qualityEvent{at: t.now(), err: true})
+}
+
+// Score returns the current quality score of provider i and updates its gauge
+func (t *QualityTracker) Score(i int) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.score(i)
+}
+
+// Ranked returns provider indices ordered from best to worst score. Ties
+// keep the configured order.
+func (t *QualityTracker) Ranked() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := make([]int, len(t.providers))
+	scores := make([]float64, len(t.providers))
+	for i := range t.providers {
+		order[i] = i
+		scores[i] = t.score(i)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order
+}
+
+func (t *QualityTracker) score(i int) float64 {
+	p := t.providers[i]
+	events := t.prune(p)
+
+	var errors, updates int
+	for _, event := range events {
+		if event.err {
+			errors++
+		} else {
+			updates++
+		}
+	}
+
+	reliability := 1.0
+	if len(events) > 0 {
+		reliability = 1 - float64(errors)/float64(len(events))
+	}
+
+	freshness := 0.0
+	if !p.lastData.IsZero() {
+		freshness = 1
+		if age := t.now().Sub(p.lastData); t.config.StaleAfter > 0 && age > t.config.StaleAfter {
+			freshness = float64(t.config.StaleAfter) / float64(age)
+		}
+	}
+
+	frequency := 1.0
+	if t.config.ExpectedUpdates > 0 {
+		frequency = math.Min(1, float64(updates)/float64(t.config.ExpectedUpdates))
+	}
+
+	score := (reliability + freshness + frequency + t.agreement(p)) / 4
+	metrics.ProviderQualityScore.WithLabelValues(p.name).Set(score)
+	return score
+}
+
+// agreement scores how close p's latest prices are to the median across
+// providers, averaged over the symbols it quotes
+func (t *QualityTracker) agreement(p *providerQuality) float64 {
+	if t.config.MaxDeviation <= 0 || len(p.prices) == 0 {
+		return 1
+	}
+
+	var total float64
+	for symbol, price := range p.prices {
+		quotes := make([]float64, 0, len(t.providers))
+		for _, other := range t.providers {
+			if q, ok := other.prices[symbol]; ok {
+				quotes = append(quotes, q)
+			}
+		}
+		consensus := median(quotes)
+		if consensus == 0 {
+			total++
+			continue
+		}
+		deviation := math.Abs(price-consensus) / consensus
+		total += math.Max(0, 1-deviation/t.config.MaxDeviation)
+	}
+	return total / float64(len(p.prices))
+}
+
+// prune drops events that have fallen out of the rolling window
+func (t *QualityTracker) prune(p *providerQuality) []qualityEvent {
+	if t.config.Window <= 0 {
+		return p.events
+	}
+
+	cutoff := t.now().Add(-t.config.Window)
+	i := 0
+	for i < len(p.events) && p.events[i].at.Before(cutoff) {
+		i++
+	}
+	p.events = p.events[i:]
+	return p.events
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
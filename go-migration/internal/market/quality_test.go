@@ -0,0 +1,100 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type stubProvider struct {
+	name  string
+	price float64
+	err   error
+	calls int
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	p.calls++
+	return p.price, p.err
+}
+
+func (p *stubProvider) SubscribePrices(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (p *stubProvider) GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error) {
+	return nil, p.err
+}
+
+func (p *stubProvider) GetBondingCurve(ctx context.Context, symbol string) (*types.BondingCurve, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (p *stubProvider) SubscribeNewTokens(ctx context.Context) (<-chan *types.TokenMarketInfo, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (p *stubProvider) ExecuteTrade(ctx context.Context, params map[string]interface{}) error {
+	return fmt.Errorf("not supported")
+}
+
+func TestHandler_QualityScoreReordersFailover(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	flaky := &stubProvider{name: "flaky", err: fmt.Errorf("timeout")}
+	steady := &stubProvider{name: "steady", price: 100}
+
+	handler := NewHandler([]types.MarketDataProvider{flaky, steady}, zap.NewNop())
+	handler.SetQuality(QualityConfig{
+		Enabled:         true,
+		Window:          time.Minute,
+		StaleAfter:      10 * time.Second,
+		ExpectedUpdates: 5,
+		MaxDeviation:    0.1,
+	})
+	handler.quality.now = func() time.Time { return now }
+
+	// The flaky provider last delivered data 50s ago
+	handler.quality.RecordPrice(0, "SOL", 100)
+	now = now.Add(50 * time.Second)
+
+	for i := 0; i < 3; i++ {
+		price, err := handler.GetPrice(context.Background(), "SOL")
+		assert.NoError(t, err)
+		assert.Equal(t, 100.0, price)
+	}
+
+	// After its first failure the flaky provider is no longer tried first
+	assert.Equal(t, 1, flaky.calls)
+	assert.Equal(t, 3, steady.calls)
+	assert.Equal(t, []int{1, 0}, handler.quality.Ranked())
+
+	// Half its requests failed and its data is 5x past the staleness limit
+	assert.InDelta(t, (0.5+0.2+0.2+1)/4, handler.quality.Score(0), 1e-9)
+	assert.InDelta(t, (1+1+0.6+1)/4, handler.quality.Score(1), 1e-9)
+	assert.InDelta(t, handler.quality.Score(0), testutil.ToFloat64(metrics.ProviderQualityScore.WithLabelValues("flaky")), 1e-9)
+}
+
+func TestQualityTracker_PriceDeviation(t *testing.T) {
+	providers := []types.MarketDataProvider{
+		&stubProvider{name: "a"}, &stubProvider{name: "b"}, &stubProvider{name: "c"},
+	}
+	tracker := NewQualityTracker(QualityConfig{MaxDeviation: 0.1}, providers)
+
+	tracker.RecordPrice(0, "SOL", 100)
+	tracker.RecordPrice(1, "SOL", 101)
+	tracker.RecordPrice(2, "SOL", 110)
+
+	// Against a median of 101 the outlier deviates by ~8.9%
+	assert.Greater(t, tracker.Score(0), tracker.Score(2))
+	assert.Equal(t, 2, tracker.Ranked()[2])
+}
@@ -0,0 +1,93 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// SwapVenue quotes and submits Solana swaps, such as the GMGN and Jupiter
+// providers
+type SwapVenue interface {
+	GetQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*types.Quote, error)
+	SubmitTransaction(ctx context.Context, signedTx string) (*types.TransactionResult, error)
+}
+
+// QuoteRouter asks every venue for a quote concurrently and routes the
+// swap to the one returning the most output
+type QuoteRouter struct {
+	logger *zap.Logger
+	venues map[string]SwapVenue
+	names  []string
+}
+
+// NewQuoteRouter creates a router over venues keyed by name
+func NewQuoteRouter(venues map[string]SwapVenue, logger *zap.Logger) *QuoteRouter {
+	names := make([]string, 0, len(venues))
+	for name := range venues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &QuoteRouter{logger: logger, venues: venues, names: names}
+}
+
+// BestQuote returns the quote with the highest OutAmount and the name of
+// the venue that gave it. Ties go to the venue first in name order. It only
+// fails when no venue returns a quote.
+func (r *QuoteRouter) BestQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*types.Quote, string, error) {
+	quotes := make([]*types.Quote, len(r.names))
+	errs := make([]error, len(r.names))
+	var wg sync.WaitGroup
+	for i, name := range r.names {
+		wg.Add(1)
+		go func(i int, venue SwapVenue) {
+			defer wg.Done()
+			quotes[i], errs[i] = venue.GetQuote(ctx, tokenIn, tokenOut, amount)
+		}(i, r.venues[name])
+	}
+	wg.Wait()
+
+	var best *types.Quote
+	var bestVenue string
+	for i, name := range r.names {
+		if errs[i] != nil {
+			r.logger.Warn("Swap venue failed to quote",
+				zap.String("venue", name),
+				zap.String("token_in", tokenIn),
+				zap.String("token_out", tokenOut),
+				zap.Error(errs[i]))
+			errs[i] = fmt.Errorf("%s: %w", name, errs[i])
+			continue
+		}
+		if quotes[i] == nil {
+			continue
+		}
+		if best == nil || quotes[i].OutAmount.GreaterThan(best.OutAmount) {
+			best, bestVenue = quotes[i], name
+		}
+	}
+	if best == nil {
+		return nil, "", fmt.Errorf("no swap venue quoted %s -> %s: %w", tokenIn, tokenOut, errors.Join(errs...))
+	}
+
+	r.logger.Debug("Routed swap quote",
+		zap.String("venue", bestVenue),
+		zap.String("out_amount", best.OutAmount.String()))
+	return best, bestVenue, nil
+}
+
+// SubmitTransaction sends a signed swap to the venue that quoted it
+func (r *QuoteRouter) SubmitTransaction(ctx context.Context, venue, signedTx string) (*types.TransactionResult, error) {
+	v, ok := r.venues[venue]
+	if !ok {
+		return nil, fmt.Errorf("unknown swap venue: %s", venue)
+	}
+	return v.SubmitTransaction(ctx, signedTx)
+}
@@ -0,0 +1,69 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// fixedVenue quotes every swap at a fixed output, or fails with err
+type fixedVenue struct {
+	out       decimal.Decimal
+	err       error
+	submitted []string
+}
+
+func (v *fixedVenue) GetQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*types.Quote, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &types.Quote{TokenIn: tokenIn, TokenOut: tokenOut, Amount: amount, OutAmount: v.out}, nil
+}
+
+func (v *fixedVenue) SubmitTransaction(ctx context.Context, signedTx string) (*types.TransactionResult, error) {
+	v.submitted = append(v.submitted, signedTx)
+	return &types.TransactionResult{Hash: "hash-" + signedTx}, nil
+}
+
+func TestQuoteRouter_PicksHighestOutput(t *testing.T) {
+	gmgn := &fixedVenue{out: decimal.NewFromInt(95)}
+	jupiter := &fixedVenue{out: decimal.NewFromInt(97)}
+	router := NewQuoteRouter(map[string]SwapVenue{"gmgn": gmgn, "jupiter": jupiter}, zap.NewNop())
+
+	quote, venue, err := router.BestQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "jupiter", venue)
+	assert.True(t, quote.OutAmount.Equal(decimal.NewFromInt(97)))
+
+	_, err = router.SubmitTransaction(context.Background(), venue, "signed")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"signed"}, jupiter.submitted)
+	assert.Empty(t, gmgn.submitted)
+
+	_, err = router.SubmitTransaction(context.Background(), "raydium", "signed")
+	assert.Error(t, err)
+}
+
+func TestQuoteRouter_FallsBackWhenVenueFails(t *testing.T) {
+	router := NewQuoteRouter(map[string]SwapVenue{
+		"gmgn":    &fixedVenue{out: decimal.NewFromInt(95)},
+		"jupiter": &fixedVenue{err: fmt.Errorf("rate limited")},
+	}, zap.NewNop())
+
+	_, venue, err := router.BestQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "gmgn", venue)
+
+	router = NewQuoteRouter(map[string]SwapVenue{
+		"jupiter": &fixedVenue{err: fmt.Errorf("rate limited")},
+	}, zap.NewNop())
+	_, _, err = router.BestQuote(context.Background(), "SOL", "USDC", decimal.NewFromInt(1))
+	assert.ErrorContains(t, err, "jupiter: rate limited")
+}
@@ -0,0 +1,124 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ExecutionQuote is a DEX price adjusted for the price impact of filling a
+// given size against the DEX's liquidity
+type ExecutionQuote struct {
+	DEX           string  `json:"dex"`
+	Price         float64 `json:"price"`          // Nominal price
+	Liquidity     float64 `json:"liquidity"`      // Pool liquidity in quote units
+	Impact        float64 `json:"impact"`         // Estimated price impact as a fraction
+	ExpectedPrice float64 `json:"expected_price"` // Price after impact
+}
+
+// GetBestExecutionPrice returns the DEX with the best price for a trade of
+// size quote units once its impact is accounted for. Impact is estimated as
+// size over pool liquidity, raising the price paid on buys and lowering the
+// price received on sells. DEXs below MinLiquidity or whose impact exceeds
+// PriceSlippageLimit are skipped. Unlike GetPrice, a marginally better
+// nominal price on a thin pool does not win.
+func (p *Provider) GetBestExecutionPrice(ctx context.Context, symbol string, isBuy bool, size float64) (*ExecutionQuote, error) {
+	var best *ExecutionQuote
+	for _, dex := range p.dexSources {
+		quote, err := p.executionQuote(ctx, dex, symbol, size)
+		if err != nil {
+			p.logger.Warn("Failed to get execution quote from DEX",
+				zap.String("dex", dex),
+				zap.Error(err))
+			continue
+		}
+		if quote.Liquidity < p.minLiquidity {
+			continue
+		}
+		if p.slippageLimit > 0 && quote.Impact > p.slippageLimit {
+			continue
+		}
+		if isBuy {
+			quote.ExpectedPrice = quote.Price * (1 + quote.Impact)
+		} else {
+			quote.ExpectedPrice = quote.Price * (1 - quote.Impact)
+		}
+
+		if best == nil ||
+			(isBuy && quote.ExpectedPrice < best.ExpectedPrice) ||
+			(!isBuy && quote.ExpectedPrice > best.ExpectedPrice) {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no DEX can fill %v of %s within liquidity limits", size, symbol)
+	}
+
+	p.logger.Debug("Got best execution price",
+		zap.String("symbol", symbol),
+		zap.String("dex", best.DEX),
+		zap.Float64("price", best.Price),
+		zap.Float64("expected_price", best.ExpectedPrice))
+	return best, nil
+}
+
+func (p *Provider) executionQuote(ctx context.Context, dex, symbol string, size float64) (*ExecutionQuote, error) {
+	price, err := p.getPriceFromDEX(ctx, dex, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("invalid price %v", price)
+	}
+	liquidity, err := p.getLiquidityFromDEX(ctx, dex, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if liquidity <= 0 {
+		return nil, fmt.Errorf("no liquidity")
+	}
+	return &ExecutionQuote{
+		DEX:       dex,
+		Price:     price,
+		Liquidity: liquidity,
+		Impact:    size / liquidity,
+	}, nil
+}
+
+func (p *Provider) getLiquidityFromDEX(ctx context.Context, dex, symbol string) (float64, error) {
+	if source, ok := p.sources[dex]; ok {
+		return source.GetLiquidity(ctx, symbol)
+	}
+
+	url := fmt.Sprintf("%s/v1/liquidity/%s/%s", p.baseURL, dex, symbol)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get liquidity: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Liquidity decimal.Decimal `json:"liquidity"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Liquidity.InexactFloat64(), nil
+}
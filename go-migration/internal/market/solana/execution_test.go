@@ -0,0 +1,83 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// dexServer serves a price and pool liquidity per DEX
+func dexServer(prices, liquidity map[string]float64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.URL.Path, "/") // "", "v1", kind, dex, symbol
+		if len(parts) < 5 {
+			http.NotFound(w, r)
+			return
+		}
+		switch parts[2] {
+		case "price":
+			fmt.Fprintf(w, `{"price":"%v"}`, prices[parts[3]])
+		case "liquidity":
+			fmt.Fprintf(w, `{"liquidity":"%v"}`, liquidity[parts[3]])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProvider_BestExecutionPricePrefersDeepPools(t *testing.T) {
+	server := dexServer(
+		map[string]float64{"raydium": 100, "orca": 99.9},
+		map[string]float64{"raydium": 10000, "orca": 1000000},
+	)
+	defer server.Close()
+	provider := NewProvider(Config{BaseURL: server.URL, DexSources: []string{"raydium", "orca"}}, zap.NewNop())
+
+	// Orca's slightly worse sell price wins once 1000 units hit Raydium's thin pool
+	quote, err := provider.GetBestExecutionPrice(context.Background(), "SOL", false, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, "orca", quote.DEX)
+	assert.InDelta(t, 99.9*0.999, quote.ExpectedPrice, 1e-9)
+
+	// A tiny order still takes the better nominal price
+	quote, err = provider.GetBestExecutionPrice(context.Background(), "SOL", false, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "raydium", quote.DEX)
+
+	quote, err = provider.GetBestExecutionPrice(context.Background(), "SOL", true, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, "orca", quote.DEX)
+}
+
+func TestProvider_BestExecutionPriceSkipsThinVenues(t *testing.T) {
+	server := dexServer(
+		map[string]float64{"raydium": 100, "orca": 101},
+		map[string]float64{"raydium": 5000, "orca": 500},
+	)
+	defer server.Close()
+
+	provider := NewProvider(Config{
+		BaseURL:      server.URL,
+		DexSources:   []string{"raydium", "orca", "missing"},
+		MinLiquidity: 1000,
+	}, zap.NewNop())
+	quote, err := provider.GetBestExecutionPrice(context.Background(), "SOL", false, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "raydium", quote.DEX)
+
+	// 100 units would move Raydium by 2%, past the 1% limit
+	provider = NewProvider(Config{
+		BaseURL:            server.URL,
+		DexSources:         []string{"raydium"},
+		PriceSlippageLimit: 0.01,
+	}, zap.NewNop())
+	_, err = provider.GetBestExecutionPrice(context.Background(), "SOL", true, 100)
+	assert.Error(t, err)
+}
@@ -16,12 +16,15 @@ import (
 
 // Provider implements MarketDataProvider interface for Solana DEXs
 type Provider struct {
-	logger     *zap.Logger
-	client     *http.Client
-	baseURL    string
-	wsClient   *WSClient
-	mu         sync.RWMutex
-	dexSources []string // List of supported DEXs (e.g. "gmgn")
+	logger        *zap.Logger
+	client        *http.Client
+	baseURL       string
+	wsClient      *WSClient
+	mu            sync.RWMutex
+	dexSources    []string             // List of supported DEXs (e.g. "gmgn")
+	sources       map[string]dexSource // DEXs quoted from their own pool data
+	minLiquidity  float64
+	slippageLimit float64
 }
 
 // ExecuteTrade implements MarketDataProvider interface
@@ -35,18 +38,37 @@ type Config struct {
 	WebSocketURL string   `json:"websocket_url"`
 	DexSources   []string `json:"dex_sources"`
 	TimeoutSec   int      `json:"timeout_sec"`
+	// MinLiquidity skips DEXs with less pool liquidity in execution pricing
+	MinLiquidity float64 `json:"min_liquidity"`
+	// PriceSlippageLimit is the largest estimated price impact, as a
+	// fraction, a DEX may have to be used for execution. 0 for no limit.
+	PriceSlippageLimit float64 `json:"price_slippage_limit"`
+	// RaydiumURL, when set, quotes "raydium" from Raydium's pool API
+	// instead of BaseURL
+	RaydiumURL string `json:"raydium_url"`
+	// Mints maps token symbols to mint addresses for pool lookups
+	Mints map[string]string `json:"mints"`
 }
 
 // NewProvider creates a new Solana provider
 func NewProvider(config Config, logger *zap.Logger) *Provider {
+	client := &http.Client{
+		Timeout: time.Duration(config.TimeoutSec) * time.Second,
+	}
+	sources := make(map[string]dexSource)
+	if config.RaydiumURL != "" {
+		sources["raydium"] = NewRaydiumSource(config.RaydiumURL, config.Mints, client)
+	}
+
 	return &Provider{
-		logger: logger,
-		client: &http.Client{
-			Timeout: time.Duration(config.TimeoutSec) * time.Second,
-		},
-		baseURL:    config.BaseURL,
-		dexSources: config.DexSources,
-		wsClient:   NewWSClient(config.WebSocketURL, logger),
+		logger:        logger,
+		client:        client,
+		baseURL:       config.BaseURL,
+		dexSources:    config.DexSources,
+		sources:       sources,
+		minLiquidity:  config.MinLiquidity,
+		slippageLimit: config.PriceSlippageLimit,
+		wsClient:      NewWSClient(config.WebSocketURL, logger),
 	}
 }
 
@@ -144,6 +166,10 @@ func (p *Provider) Close() error {
 // Internal methods
 
 func (p *Provider) getPriceFromDEX(ctx context.Context, dex, symbol string) (float64, error) {
+	if source, ok := p.sources[dex]; ok {
+		return source.GetPrice(ctx, symbol)
+	}
+
 	url := fmt.Sprintf("%s/v1/price/%s/%s", p.baseURL, dex, symbol)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
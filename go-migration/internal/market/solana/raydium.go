@@ -0,0 +1,147 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrPoolNotFound is returned when Raydium has no pool for a pair. The
+// provider skips a DEX that errors, so a missing pool only drops Raydium
+// from the comparison.
+var ErrPoolNotFound = errors.New("raydium pool not found")
+
+// dexSource quotes a DEX from its own pool data instead of the price API
+// at BaseURL
+type dexSource interface {
+	GetPrice(ctx context.Context, symbol string) (float64, error)
+	GetLiquidity(ctx context.Context, symbol string) (float64, error)
+}
+
+// RaydiumSource reads prices and liquidity from the deepest Raydium pool
+// for a BASE/QUOTE pair
+type RaydiumSource struct {
+	client  *http.Client
+	baseURL string
+	mints   map[string]string // Upper-case token symbol to mint address
+}
+
+// NewRaydiumSource creates a Raydium source against the Raydium v3 API.
+// Mints are matched case-insensitively, since config loaders may lowercase
+// keys, and symbols missing from them are used as mint addresses.
+func NewRaydiumSource(baseURL string, mints map[string]string, client *http.Client) *RaydiumSource {
+	upper := make(map[string]string, len(mints))
+	for symbol, mint := range mints {
+		upper[strings.ToUpper(symbol)] = mint
+	}
+	return &RaydiumSource{
+		client:  client,
+		baseURL: baseURL,
+		mints:   upper,
+	}
+}
+
+// raydiumPool is the part of a Raydium pool record the source reads
+type raydiumPool struct {
+	ID    string `json:"id"`
+	MintA struct {
+		Address string `json:"address"`
+	} `json:"mintA"`
+	MintB struct {
+		Address string `json:"address"`
+	} `json:"mintB"`
+	Price       float64 `json:"price"` // MintB per MintA
+	MintAmountA float64 `json:"mintAmountA"`
+	MintAmountB float64 `json:"mintAmountB"`
+}
+
+// GetPrice returns the pool price in quote units per base unit
+func (s *RaydiumSource) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	pool, base, _, err := s.pool(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if pool.Price <= 0 {
+		return 0, fmt.Errorf("invalid price %v in pool %s", pool.Price, pool.ID)
+	}
+	if pool.MintA.Address == base {
+		return pool.Price, nil
+	}
+	return 1 / pool.Price, nil
+}
+
+// GetLiquidity returns the pool value in quote units, twice its quote
+// reserve
+func (s *RaydiumSource) GetLiquidity(ctx context.Context, symbol string) (float64, error) {
+	pool, _, quote, err := s.pool(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if pool.MintA.Address == quote {
+		return 2 * pool.MintAmountA, nil
+	}
+	return 2 * pool.MintAmountB, nil
+}
+
+// pool returns the deepest pool for symbol and its base and quote mints
+func (s *RaydiumSource) pool(ctx context.Context, symbol string) (*raydiumPool, string, string, error) {
+	baseSymbol, quoteSymbol, ok := strings.Cut(symbol, "/")
+	if !ok {
+		return nil, "", "", fmt.Errorf("symbol %s is not a BASE/QUOTE pair", symbol)
+	}
+	base, quote := s.mint(baseSymbol), s.mint(quoteSymbol)
+
+	query := url.Values{}
+	query.Set("mint1", base)
+	query.Set("mint2", quote)
+	query.Set("poolType", "all")
+	query.Set("poolSortField", "liquidity")
+	query.Set("sortType", "desc")
+	query.Set("pageSize", "1")
+	query.Set("page", "1")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+"/pools/info/mint?"+query.Encode(), nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get pools: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Data []raydiumPool `json:"data"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Success {
+		return nil, "", "", fmt.Errorf("raydium request for %s failed", symbol)
+	}
+	if len(result.Data.Data) == 0 {
+		return nil, "", "", fmt.Errorf("%w: %s", ErrPoolNotFound, symbol)
+	}
+
+	return &result.Data.Data[0], base, quote, nil
+}
+
+func (s *RaydiumSource) mint(symbol string) string {
+	if mint, ok := s.mints[strings.ToUpper(symbol)]; ok {
+		return mint
+	}
+	return symbol
+}
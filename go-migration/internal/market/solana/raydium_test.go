@@ -0,0 +1,103 @@
+package solana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testMints = map[string]string{
+	"SOL":  "So11111111111111111111111111111111111111112",
+	"USDC": "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+}
+
+// raydiumServer serves the recorded SOL/USDC pool for that pair in either
+// order and an empty page for any other pair
+func raydiumServer(t *testing.T) *httptest.Server {
+	fixture, err := os.ReadFile("testdata/raydium_pool_sol_usdc.json")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pools/info/mint" {
+			http.NotFound(w, r)
+			return
+		}
+		pair := map[string]bool{r.URL.Query().Get("mint1"): true, r.URL.Query().Get("mint2"): true}
+		if pair[testMints["SOL"]] && pair[testMints["USDC"]] {
+			w.Write(fixture)
+			return
+		}
+		w.Write([]byte(`{"id":"1","success":true,"data":{"count":0,"data":[],"hasNextPage":false}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRaydiumSource(t *testing.T) {
+	server := raydiumServer(t)
+	source := NewRaydiumSource(server.URL, map[string]string{
+		"sol":  testMints["SOL"],
+		"usdc": testMints["USDC"],
+	}, http.DefaultClient)
+	ctx := context.Background()
+
+	price, err := source.GetPrice(ctx, "SOL/USDC")
+	require.NoError(t, err)
+	assert.InDelta(t, 145.2318, price, 1e-9)
+
+	// The same pool quotes the inverted pair
+	price, err = source.GetPrice(ctx, "USDC/SOL")
+	require.NoError(t, err)
+	assert.InDelta(t, 1/145.2318, price, 1e-12)
+
+	liquidity, err := source.GetLiquidity(ctx, "SOL/USDC")
+	require.NoError(t, err)
+	assert.InDelta(t, 2*8893242.118273, liquidity, 1e-6)
+
+	liquidity, err = source.GetLiquidity(ctx, "USDC/SOL")
+	require.NoError(t, err)
+	assert.InDelta(t, 2*61234.512345678, liquidity, 1e-6)
+
+	_, err = source.GetPrice(ctx, "BONK/SOL")
+	assert.ErrorIs(t, err, ErrPoolNotFound)
+
+	_, err = source.GetPrice(ctx, "SOL")
+	assert.Error(t, err)
+}
+
+func TestProvider_RaydiumSource(t *testing.T) {
+	raydium := raydiumServer(t)
+	api := dexServer(
+		map[string]float64{"orca": 145, "raydium": 1},
+		map[string]float64{"orca": 100000, "raydium": 1},
+	)
+	defer api.Close()
+
+	provider := NewProvider(Config{
+		BaseURL:    api.URL,
+		DexSources: []string{"raydium", "orca"},
+		RaydiumURL: raydium.URL,
+		Mints:      testMints,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	// Raydium is quoted from its pool, not the price API
+	price, err := provider.GetPrice(ctx, "SOL/USDC")
+	require.NoError(t, err)
+	assert.InDelta(t, 145.2318, price, 1e-9)
+
+	quote, err := provider.GetBestExecutionPrice(ctx, "SOL/USDC", false, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, "raydium", quote.DEX)
+
+	// A pair without a Raydium pool falls back to the other DEXs
+	price, err = provider.GetPrice(ctx, "BONK/USDC")
+	require.NoError(t, err)
+	assert.InDelta(t, 145, price, 1e-9)
+}
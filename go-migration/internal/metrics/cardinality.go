@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxSymbols is the default cap on distinct symbols exported
+const DefaultMaxSymbols = 500
+
+// SymbolSeries caps the symbols exported by the per-symbol token metrics
+var SymbolSeries = NewSymbolLimiter(DefaultMaxSymbols)
+
+// labelDeleter is satisfied by GaugeVec, CounterVec and the other metric vecs
+type labelDeleter interface {
+	DeleteLabelValues(lvs ...string) bool
+}
+
+type seriesKey struct {
+	vec    labelDeleter
+	values string
+}
+
+type symbolEntry struct {
+	symbol string
+	series map[seriesKey][]string
+}
+
+// SymbolLimiter bounds the number of distinct symbol label values exported.
+// New tokens appear constantly, so without a cap every symbol ever seen
+// stays in the registry. Once the cap is exceeded the series of the least
+// recently updated symbol are deleted.
+type SymbolLimiter struct {
+	max     int
+	order   *list.List // Front is the most recently updated symbol
+	entries map[string]*list.Element
+	mu      sync.Mutex
+}
+
+// NewSymbolLimiter creates a limiter exporting at most max symbols. A max of
+// zero or less disables the cap.
+func NewSymbolLimiter(max int) *SymbolLimiter {
+	return &SymbolLimiter{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// SetMax changes the cap, evicting symbols immediately if it shrank
+func (l *SymbolLimiter) SetMax(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.max = max
+	l.evict()
+}
+
+// Track records that the series of vec with labelValues belongs to symbol
+// and marks symbol as recently updated. Call it alongside WithLabelValues.
+func (l *SymbolLimiter) Track(symbol string, vec labelDeleter, labelValues ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[symbol]
+	if ok {
+		l.order.MoveToFront(elem)
+	} else {
+		elem = l.order.PushFront(&symbolEntry{symbol: symbol, series: make(map[seriesKey][]string)})
+		l.entries[symbol] = elem
+	}
+
+	entry := elem.Value.(*symbolEntry)
+	key := seriesKey{vec: vec, values: strings.Join(labelValues, "\xff")}
+	if _, ok := entry.series[key]; !ok {
+		entry.series[key] = append([]string(nil), labelValues...)
+	}
+
+	l.evict()
+}
+
+// Len returns the number of symbols currently exported
+func (l *SymbolLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *SymbolLimiter) evict() {
+	if l.max <= 0 {
+		return
+	}
+
+	for l.order.Len() > l.max {
+		oldest := l.order.Back()
+		entry := oldest.Value.(*symbolEntry)
+		for key, values := range entry.series {
+			key.vec.DeleteLabelValues(values...)
+		}
+		l.order.Remove(oldest)
+		delete(l.entries, entry.symbol)
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolLimiter_EvictsLeastRecentlyUpdated(t *testing.T) {
+	price := NewGaugeVec("test_cardinality_price", "test", []string{"provider", "symbol"})
+	volume := NewGaugeVec("test_cardinality_volume", "test", []string{"provider", "symbol"})
+	limiter := NewSymbolLimiter(3)
+
+	update := func(symbol string) {
+		price.WithLabelValues("pump.fun", symbol).Set(1)
+		volume.WithLabelValues("pump.fun", symbol).Set(1)
+		limiter.Track(symbol, price, "pump.fun", symbol)
+		limiter.Track(symbol, volume, "pump.fun", symbol)
+	}
+
+	for i := 0; i < 3; i++ {
+		update(fmt.Sprintf("TOKEN%d", i))
+	}
+	// Refresh TOKEN0 so TOKEN1 becomes the oldest
+	update("TOKEN0")
+	update("TOKEN3")
+	update("TOKEN4")
+
+	assert.Equal(t, 3, limiter.Len())
+	assert.Equal(t, 3, testutil.CollectAndCount(price))
+	assert.Equal(t, 3, testutil.CollectAndCount(volume))
+
+	remaining := func(vec *prometheus.GaugeVec, symbol string) bool {
+		return vec.DeleteLabelValues("pump.fun", symbol)
+	}
+	assert.False(t, remaining(price, "TOKEN1"))
+	assert.False(t, remaining(volume, "TOKEN2"))
+	assert.True(t, remaining(price, "TOKEN0"))
+	assert.True(t, remaining(volume, "TOKEN4"))
+}
+
+func TestSymbolLimiter_SetMaxShrinks(t *testing.T) {
+	price := NewGaugeVec("test_cardinality_shrink", "test", []string{"symbol"})
+	limiter := NewSymbolLimiter(0)
+
+	for i := 0; i < 10; i++ {
+		symbol := fmt.Sprintf("TOKEN%d", i)
+		price.WithLabelValues(symbol).Set(1)
+		limiter.Track(symbol, price, symbol)
+	}
+	assert.Equal(t, 10, testutil.CollectAndCount(price))
+
+	limiter.SetMax(4)
+	assert.Equal(t, 4, limiter.Len())
+	assert.Equal(t, 4, testutil.CollectAndCount(price))
+}
@@ -139,6 +139,207 @@ var (
 		Name: "pump_monitoring_service_status",
 		Help: "Monitoring service status (1 = active, 0 = inactive)",
 	})
+
+	ClockSkewDetected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "clock_skew_detected_total",
+		Help: "Total number of signals with skewed timestamps that were clamped",
+	}, []string{"provider", "direction"})
+
+	SuspectedWashVolume = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "suspected_wash_volume",
+		Help: "Cumulative volume discounted as suspected wash trading",
+	}, []string{"provider", "symbol"})
+
+	Drawdown = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "drawdown",
+		Help: "Current drawdown as a fraction of cost basis",
+	}, []string{"symbol"})
+
+	NetExposure = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "net_exposure",
+		Help: "Signed net notional exposure across all positions",
+	})
+
+	SuggestedHedgeSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "suggested_hedge_size",
+		Help: "Signed hedge size suggested to neutralize net exposure",
+	}, []string{"instrument"})
+
+	RetryBudgetRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "retry_budget_remaining",
+		Help: "Retries remaining in the shared retry budget",
+	})
+
+	PricingSignalLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pricing_signal_latency",
+		Help:    "Seconds from the triggering price update to signal emission",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	ProviderQualityScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_quality_score",
+		Help: "Rolling market data quality score per provider, from 0 (worst) to 1",
+	}, []string{"provider"})
+
+	ScheduledCloses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduled_closes_total",
+		Help: "Total number of positions closed by the daily scheduled close",
+	}, []string{"provider", "status"})
+
+	ImplementationShortfall = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "implementation_shortfall_bps",
+		Help: "Aggregate implementation shortfall per strategy in basis points of decision notional",
+	}, []string{"strategy"})
+
+	StrategyTurnover = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "strategy_turnover",
+		Help: "Traded notional over the turnover window divided by average strategy equity",
+	}, []string{"strategy"})
+
+	StrategyFeeDrag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "strategy_fee_drag",
+		Help: "Fees paid over the turnover window as a fraction of average strategy equity",
+	}, []string{"strategy"})
+
+	BenchmarkCorrelation = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "benchmark_correlation",
+		Help: "Rolling correlation of a symbol's returns to the regime benchmark",
+	}, []string{"symbol", "benchmark"})
+
+	PriceAnomaliesRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "price_anomalies_rejected_total",
+		Help: "Price updates rejected for deviating too far from the rolling median",
+	}, []string{"provider", "symbol"})
+
+	FillLatencyP95 = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fill_latency_p95_seconds",
+		Help: "Rolling 95th percentile of signal to confirmed fill latency per symbol",
+	}, []string{"symbol"})
+
+	PumpPartialFills = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pump_partial_fills_total",
+		Help: "Total number of trades the venue filled only partially",
+	})
+
+	OrderFillRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "order_fill_ratio",
+		Help: "Filled over submitted order size for recent orders per symbol",
+	}, []string{"symbol"})
+
+	OrderFillRatioOverall = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "order_fill_ratio_overall",
+		Help: "Filled over submitted order size for recent orders across symbols",
+	})
+
+	WebsocketReconnectAttempts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pump_websocket_reconnect_attempts",
+		Help: "Reconnect attempts made by a WebSocket client since it lost its connection, zero once connected",
+	}, []string{"url"})
+
+	VenueExposure = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "venue_exposure",
+		Help: "Absolute notional exposure of positions held at a venue",
+	}, []string{"venue"})
+
+	VenueRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "venue_cap_breaches_total",
+		Help: "Buys that would breach a venue exposure cap, by outcome",
+	}, []string{"venue", "outcome"})
+
+	PumpPriceCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pump_price_cache_hits_total",
+		Help: "GetPrice calls served from the price cache",
+	})
+
+	PumpPriceCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pump_price_cache_misses_total",
+		Help: "GetPrice calls that fetched the price from the API",
+	})
+
+	PumpDataSourceMode = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pump_data_source_mode",
+		Help: "Pump price stream source: 1 for WebSocket, 0 for REST polling",
+	})
+
+	StrategySharpe = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "strategy_rolling_sharpe",
+		Help: "Sharpe ratio of each strategy's equity curve over the allocation window",
+	}, []string{"strategy"})
+
+	StrategyAllocationWeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "strategy_allocation_weight",
+		Help: "Share of capital allocated to each strategy by risk-adjusted performance",
+	}, []string{"strategy"})
+
+	ExecutorAlive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "executor_alive",
+		Help: "Whether each executor's processing loop has a recent heartbeat",
+	}, []string{"executor"})
+
+	ExecutorRestarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "executor_restarts_total",
+		Help: "Restarts of stalled executor processing loops, by outcome",
+	}, []string{"executor", "outcome"})
+
+	PositionDelta = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "position_delta",
+		Help: "Signed directional notional of each position in the portfolio base currency",
+	}, []string{"symbol"})
+
+	PortfolioDelta = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "portfolio_delta",
+		Help: "Signed directional notional of all open positions in the portfolio base currency",
+	})
+
+	DuplicateOrders = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "trading_duplicate_orders_total",
+		Help: "Order submissions resolved to an order already placed within the dedup window",
+	})
+
+	PositionsClosed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "trading_positions_closed_total",
+		Help: "Positions netted back to flat by engine fills",
+	})
+
+	MaxPositionsRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "trading_max_positions_rejections_total",
+		Help: "Buy signals rejected for opening a position past the open position limit",
+	})
+
+	PositionConflicts = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "executor_position_conflicts",
+		Help: "Symbols with positions held by more than one executor",
+	})
+
+	IndicatorReliability = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pricing_indicator_reliability",
+		Help: "Learned hit rate factor applied to the confidence of each indicator's signals",
+	}, []string{"indicator"})
+
+	PricingSignalsSuppressedVolatility = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pricing_signals_suppressed_volatility_total",
+		Help: "Pricing signals dropped because realized volatility exceeded the configured maximum",
+	})
+
+	PumpPriceFetchesCoalesced = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pump_price_fetches_coalesced_total",
+		Help: "GetPrice calls that shared an in-flight price fetch for the same symbol",
+	})
+
+	PumpPriceStaleServed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pump_price_stale_served_total",
+		Help: "GetPrice calls answered with an expired cached price after a fetch failed",
+	})
+
+	PumpTokenTradeable = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pump_token_tradeable",
+		Help: "Whether the pump monitor currently allows trading a token (1) or not (0)",
+	}, []string{"provider", "symbol"})
+
+	PumpTokenPnL = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pump_token_pnl",
+		Help: "PnL of the total supply over the last price move of a tradeable token",
+	}, []string{"provider", "symbol"})
 )
 
 func GetVolumes() map[string]float64 {
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Push sends the current contents of the default registry to the pushgateway
+// at url under job. Short-lived commands call it before exiting, since they
+// are gone before Prometheus can scrape them. An empty url disables pushing.
+func Push(ctx context.Context, url, job string) error {
+	if url == "" {
+		return nil
+	}
+
+	if err := push.New(url, job).Gatherer(prometheus.DefaultGatherer).PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", url, err)
+	}
+	return nil
+}
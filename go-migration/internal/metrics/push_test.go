@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPush(t *testing.T) {
+	var (
+		pushed bool
+		method string
+		path   string
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed = true
+		method = r.Method
+		path = r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	TradeExecutions.Inc()
+	assert.NoError(t, Push(context.Background(), server.URL, "verify_trading"))
+
+	assert.True(t, pushed)
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/verify_trading", path)
+	assert.NotEmpty(t, body)
+}
+
+func TestPush_Disabled(t *testing.T) {
+	assert.NoError(t, Push(context.Background(), "", "verify_trading"))
+}
+
+func TestPush_GatewayError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	assert.Error(t, Push(context.Background(), server.URL, "verify_trading"))
+}
@@ -35,6 +35,14 @@ var (
 		[]string{"type"},
 	)
 
+	SolanaTransactionFees = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "solana_transaction_fees",
+			Help: "Fee of the latest Solana transaction by type",
+		},
+		[]string{"type"},
+	)
+
 	TradingStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "trading_status",
@@ -66,6 +74,7 @@ func init() {
 		TransferVolume,
 		SolanaTransactions,
 		SolanaVolume,
+		SolanaTransactionFees,
 		TradingStatus,
 		ActiveStrategies,
 		StrategyPnL,
@@ -11,6 +11,7 @@ import (
 
 	"github.com/kwanRoshi/B/go-migration/internal/metrics"
 	"github.com/kwanRoshi/B/go-migration/internal/trading"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 type AlertType string
@@ -21,6 +22,8 @@ const (
 	AlertPositionLimit   AlertType = "position_limit"
 	AlertDrawdownLimit   AlertType = "drawdown_limit"
 	AlertProfitTarget    AlertType = "profit_target"
+	AlertFillLatency     AlertType = "fill_latency_sla"
+	AlertDelistingClose  AlertType = "delisting_close_failed"
 )
 
 type Alert struct {
@@ -28,9 +31,25 @@ type Alert struct {
 	Symbol    string         `json:"symbol"`
 	Threshold decimal.Decimal `json:"threshold"`
 	Current   decimal.Decimal `json:"current"`
+	Resolved  bool           `json:"resolved"`
 	Timestamp time.Time      `json:"timestamp"`
 }
 
+// AlertHandler delivers alerts to an external destination
+type AlertHandler interface {
+	HandleAlert(ctx context.Context, alert *Alert) error
+}
+
+// AlertRule fires when a metric reaches Threshold and resolves once it falls
+// back to ResolveThreshold. Keeping ResolveThreshold below Threshold stops
+// the alert from flapping around the limit.
+type AlertRule struct {
+	Type             AlertType
+	Threshold        decimal.Decimal
+	ResolveThreshold decimal.Decimal
+	firing           map[string]bool
+}
+
 type Monitor struct {
 	logger     *zap.Logger
 	engine     *trading.Engine
@@ -41,11 +60,16 @@ type Monitor struct {
 		maxDrawdown    decimal.Decimal
 		maxPositionPct decimal.Decimal
 	}
-	mu sync.RWMutex
+	drawdownRule *AlertRule
+	latencyRule  *AlertRule
+	latency      *fillLatencies
+	handlers     []AlertHandler
+	mu           sync.RWMutex
+	ruleMu       sync.Mutex
 }
 
 func NewMonitor(engine *trading.Engine, logger *zap.Logger) *Monitor {
-	return &Monitor{
+	m := &Monitor{
 		logger: logger,
 		engine: engine,
 		alerts: make(chan *Alert, 100),
@@ -56,11 +80,88 @@ func NewMonitor(engine *trading.Engine, logger *zap.Logger) *Monitor {
 			maxPositionPct decimal.Decimal
 		}{
 			volumeSurge:    decimal.NewFromFloat(3.0),    // 300% increase
-			maxMarketCap:   decimal.NewFromInt(30000),    // $30k
+			maxMarketCap:   types.DefaultMaxMarketCap,
 			maxDrawdown:    decimal.NewFromFloat(0.15),   // 15%
 			maxPositionPct: decimal.NewFromFloat(0.1),    // 10%
 		},
 	}
+	m.drawdownRule = &AlertRule{
+		Type:             AlertDrawdownLimit,
+		Threshold:        m.thresholds.maxDrawdown,
+		ResolveThreshold: decimal.NewFromFloat(0.10), // 10%
+		firing:           make(map[string]bool),
+	}
+	return m
+}
+
+// RegisterHandler adds a handler that receives every alert the monitor raises
+func (m *Monitor) RegisterHandler(handler AlertHandler) {
+	m.ruleMu.Lock()
+	defer m.ruleMu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// SetDrawdownThresholds configures the drawdown alert rule. The alert fires
+// at threshold and resolves once drawdown recovers to resolve or below.
+func (m *Monitor) SetDrawdownThresholds(threshold, resolve decimal.Decimal) error {
+	if resolve.GreaterThan(threshold) {
+		return fmt.Errorf("resolve threshold %s above alert threshold %s", resolve, threshold)
+	}
+
+	m.mu.Lock()
+	m.thresholds.maxDrawdown = threshold
+	m.mu.Unlock()
+
+	m.ruleMu.Lock()
+	defer m.ruleMu.Unlock()
+	m.drawdownRule.Threshold = threshold
+	m.drawdownRule.ResolveThreshold = resolve
+	return nil
+}
+
+// RecordDrawdown records the current drawdown for symbol and evaluates the
+// drawdown alert rule against it
+func (m *Monitor) RecordDrawdown(ctx context.Context, symbol string, drawdown decimal.Decimal) {
+	metrics.Drawdown.WithLabelValues(symbol).Set(drawdown.InexactFloat64())
+	metrics.SymbolSeries.Track(symbol, metrics.Drawdown, symbol)
+
+	m.ruleMu.Lock()
+	alert := m.drawdownRule.evaluate(symbol, drawdown)
+	handlers := m.handlers
+	m.ruleMu.Unlock()
+
+	if alert == nil {
+		return
+	}
+	m.sendAlert(alert)
+	m.dispatch(ctx, handlers, alert)
+}
+
+// evaluate updates the rule's state for symbol with the current value and
+// returns the alert to raise, if the rule fired or resolved
+func (r *AlertRule) evaluate(symbol string, current decimal.Decimal) *Alert {
+	switch {
+	case !r.firing[symbol] && current.GreaterThanOrEqual(r.Threshold):
+		r.firing[symbol] = true
+		return &Alert{
+			Type:      r.Type,
+			Symbol:    symbol,
+			Threshold: r.Threshold,
+			Current:   current,
+			Timestamp: time.Now(),
+		}
+	case r.firing[symbol] && current.LessThanOrEqual(r.ResolveThreshold):
+		delete(r.firing, symbol)
+		return &Alert{
+			Type:      r.Type,
+			Symbol:    symbol,
+			Threshold: r.ResolveThreshold,
+			Current:   current,
+			Resolved:  true,
+			Timestamp: time.Now(),
+		}
+	}
+	return nil
 }
 
 func (m *Monitor) Start(ctx context.Context) error {
@@ -134,19 +235,14 @@ func (m *Monitor) checkThresholds(ctx context.Context) error {
 		return fmt.Errorf("no positions found")
 	}
 
+	var unrealized, costBasis decimal.Decimal
 	for _, pos := range positions {
 		// Check drawdown
-		if pos.UnrealizedPnL.IsNegative() {
-			drawdown := pos.UnrealizedPnL.Neg().Div(pos.Size.Mul(pos.EntryPrice))
-			if drawdown.GreaterThan(m.thresholds.maxDrawdown) {
-				m.sendAlert(&Alert{
-					Type:      AlertDrawdownLimit,
-					Symbol:    pos.Symbol,
-					Threshold: m.thresholds.maxDrawdown,
-					Current:   drawdown,
-					Timestamp: time.Now(),
-				})
-			}
+		positionCost := pos.Size.Mul(pos.EntryPrice)
+		unrealized = unrealized.Add(pos.UnrealizedPnL)
+		costBasis = costBasis.Add(positionCost)
+		if positionCost.IsPositive() {
+			m.RecordDrawdown(ctx, pos.Symbol, drawdownOf(pos.UnrealizedPnL, positionCost))
 		}
 
 		// Check position size
@@ -166,9 +262,32 @@ func (m *Monitor) checkThresholds(ctx context.Context) error {
 		}
 	}
 
+	if costBasis.IsPositive() {
+		m.RecordDrawdown(ctx, "portfolio", drawdownOf(unrealized, costBasis))
+	}
+
 	return nil
 }
 
+// drawdownOf returns the unrealized loss as a fraction of cost basis
+func drawdownOf(unrealized, costBasis decimal.Decimal) decimal.Decimal {
+	if !unrealized.IsNegative() {
+		return decimal.Zero
+	}
+	return unrealized.Neg().Div(costBasis)
+}
+
+func (m *Monitor) dispatch(ctx context.Context, handlers []AlertHandler, alert *Alert) {
+	for _, handler := range handlers {
+		if err := handler.HandleAlert(ctx, alert); err != nil {
+			m.logger.Error("failed to deliver alert",
+				zap.String("type", string(alert.Type)),
+				zap.String("symbol", alert.Symbol),
+				zap.Error(err))
+		}
+	}
+}
+
 func (m *Monitor) sendAlert(alert *Alert) {
 	select {
 	case m.alerts <- alert:
@@ -0,0 +1,62 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type recordingHandler struct {
+	alerts []*Alert
+}
+
+func (h *recordingHandler) HandleAlert(ctx context.Context, alert *Alert) error {
+	h.alerts = append(h.alerts, alert)
+	return nil
+}
+
+func TestMonitor_DrawdownAlertHysteresis(t *testing.T) {
+	m := NewMonitor(nil, zap.NewNop())
+	assert.NoError(t, m.SetDrawdownThresholds(decimal.NewFromFloat(0.2), decimal.NewFromFloat(0.1)))
+
+	handler := &recordingHandler{}
+	m.RegisterHandler(handler)
+
+	ctx := context.Background()
+	feed := func(v float64) {
+		m.RecordDrawdown(ctx, "portfolio", decimal.NewFromFloat(v))
+	}
+
+	feed(0.05)
+	feed(0.19)
+	assert.Empty(t, handler.alerts)
+
+	// Crossing the threshold fires once, further breaches do not repeat it
+	feed(0.22)
+	feed(0.25)
+	assert.Len(t, handler.alerts, 1)
+	assert.Equal(t, AlertDrawdownLimit, handler.alerts[0].Type)
+	assert.False(t, handler.alerts[0].Resolved)
+
+	// Dropping below the alert threshold but above the resolve threshold
+	// keeps the alert active
+	feed(0.15)
+	feed(0.21)
+	assert.Len(t, handler.alerts, 1)
+
+	// Recovering past the resolve threshold resolves it
+	feed(0.08)
+	assert.Len(t, handler.alerts, 2)
+	assert.True(t, handler.alerts[1].Resolved)
+	assert.Equal(t, "portfolio", handler.alerts[1].Symbol)
+
+	// The rule re-arms after resolving
+	feed(0.3)
+	assert.Len(t, handler.alerts, 3)
+	assert.False(t, handler.alerts[2].Resolved)
+
+	assert.Error(t, m.SetDrawdownThresholds(decimal.NewFromFloat(0.1), decimal.NewFromFloat(0.2)))
+}
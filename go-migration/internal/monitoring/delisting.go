@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// PositionCloser closes any open position in a symbol at market, reporting
+// whether there was one
+type PositionCloser interface {
+	ClosePosition(ctx context.Context, symbol string) (bool, error)
+}
+
+// AutoCloseConfig closes positions in tokens whose feed goes stale, treating
+// a dead feed as a delisting rather than holding the asset
+type AutoCloseConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"` // Bound on each close attempt, zero for none
+}
+
+// SetAutoClose closes positions through closer when their symbol goes
+// stale, alerting handlers when a close fails. A failed close is retried on
+// each threshold check for as long as the symbol stays stale.
+func (m *PumpMonitor) SetAutoClose(config AutoCloseConfig, closer PositionCloser, handlers ...AlertHandler) error {
+	if config.Enabled && closer == nil {
+		return fmt.Errorf("auto close requires a position closer")
+	}
+	if config.Timeout < 0 {
+		return fmt.Errorf("auto close timeout must not be negative, got %s", config.Timeout)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.autoClose = config
+	m.closer = closer
+	m.closeHandlers = handlers
+	return nil
+}
+
+// closeStale attempts a close per symbol that went stale, queueing the
+// symbols whose close failed for another attempt
+func (m *PumpMonitor) closeStale(symbols []string) {
+	m.mu.RLock()
+	config, closer, handlers := m.autoClose, m.closer, m.closeHandlers
+	m.mu.RUnlock()
+	if !config.Enabled || len(symbols) == 0 {
+		return
+	}
+
+	for _, symbol := range symbols {
+		closed, err := closeWithin(closer, symbol, config.Timeout)
+		m.mu.Lock()
+		retrying := m.closeRetry[symbol]
+		if err == nil {
+			delete(m.closeRetry, symbol)
+		} else if m.stale[symbol] {
+			m.closeRetry[symbol] = true
+		}
+		m.mu.Unlock()
+
+		if err == nil {
+			if !closed {
+				continue
+			}
+			metrics.PumpTradeExecutions.WithLabelValues("delisting_close").Inc()
+			m.logger.Info("closed position in stale token",
+				zap.String("symbol", symbol))
+			continue
+		}
+
+		metrics.PumpTradeExecutions.WithLabelValues("delisting_close_failed").Inc()
+		m.logger.Error("failed to close position in stale token",
+			zap.String("symbol", symbol),
+			zap.Bool("retry", retrying),
+			zap.Error(err))
+		if retrying {
+			// Handlers were alerted on the first failure
+			continue
+		}
+		alert := &Alert{Type: AlertDelistingClose, Symbol: symbol, Timestamp: time.Now()}
+		for _, handler := range handlers {
+			if err := handler.HandleAlert(context.Background(), alert); err != nil {
+				m.logger.Error("failed to deliver alert",
+					zap.String("type", string(alert.Type)),
+					zap.String("symbol", symbol),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+func closeWithin(closer PositionCloser, symbol string, timeout time.Duration) (bool, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return closer.ClosePosition(ctx, symbol)
+}
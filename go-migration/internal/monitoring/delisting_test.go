@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type stubCloser struct {
+	positions map[string]bool
+	err       error
+	calls     []string
+}
+
+func (c *stubCloser) ClosePosition(ctx context.Context, symbol string) (bool, error) {
+	c.calls = append(c.calls, symbol)
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.positions[symbol], nil
+}
+
+func TestPumpMonitor_AutoCloseStaleSymbol(t *testing.T) {
+	m := NewPumpMonitor(zap.NewNop(), nil)
+	closer := &stubCloser{positions: map[string]bool{"DEAD": true}}
+	handler := &recordingHandler{}
+	assert.NoError(t, m.SetAutoClose(AutoCloseConfig{Enabled: true, Timeout: time.Second}, closer, handler))
+
+	for _, symbol := range []string{"DEAD", "LIVE"} {
+		assert.NoError(t, m.handleUpdate(&types.TokenUpdate{Symbol: symbol, Price: 1, Timestamp: time.Now()}))
+	}
+	m.checkThresholds()
+	assert.Empty(t, closer.calls)
+
+	// DEAD's feed stops while LIVE keeps updating
+	m.tokens["DEAD"].Timestamp = time.Now().Add(-10 * time.Minute)
+	m.checkThresholds()
+	assert.Equal(t, []string{"DEAD"}, closer.calls)
+	assert.Empty(t, handler.alerts)
+
+	// The close is attempted once while the symbol stays stale
+	m.checkThresholds()
+	assert.Len(t, closer.calls, 1)
+
+	// A failed close raises an alert
+	closer.err = errors.New("no liquidity")
+	m.tokens["LIVE"].Timestamp = time.Now().Add(-10 * time.Minute)
+	m.checkThresholds()
+	assert.Equal(t, []string{"DEAD", "LIVE"}, closer.calls)
+	if assert.Len(t, handler.alerts, 1) {
+		assert.Equal(t, AlertDelistingClose, handler.alerts[0].Type)
+		assert.Equal(t, "LIVE", handler.alerts[0].Symbol)
+	}
+
+	// The failed close is retried while the symbol stays stale, without
+	// alerting again, until it succeeds
+	m.checkThresholds()
+	assert.Equal(t, []string{"DEAD", "LIVE", "LIVE"}, closer.calls)
+	assert.Len(t, handler.alerts, 1)
+	closer.err = nil
+	m.checkThresholds()
+	m.checkThresholds()
+	assert.Equal(t, []string{"DEAD", "LIVE", "LIVE", "LIVE"}, closer.calls)
+}
+
+func TestPumpMonitor_AutoCloseDisabled(t *testing.T) {
+	m := NewPumpMonitor(zap.NewNop(), nil)
+	assert.Error(t, m.SetAutoClose(AutoCloseConfig{Enabled: true}, nil))
+
+	closer := &stubCloser{}
+	assert.NoError(t, m.SetAutoClose(AutoCloseConfig{}, closer))
+	assert.NoError(t, m.handleUpdate(&types.TokenUpdate{Symbol: "DEAD", Price: 1, Timestamp: time.Now().Add(-10 * time.Minute)}))
+	m.checkThresholds()
+	assert.Empty(t, closer.calls)
+}
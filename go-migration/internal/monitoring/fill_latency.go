@@ -0,0 +1,104 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// FillLatencyConfig alerts when the rolling p95 of signal to confirmed fill
+// latency for a symbol reaches SLA
+type FillLatencyConfig struct {
+	SLA        time.Duration `yaml:"sla"`
+	Window     int           `yaml:"window"`      // Fills per symbol the p95 is taken over
+	MinSamples int           `yaml:"min_samples"` // Fills needed before the rule is evaluated
+}
+
+// fillLatencies keeps the most recent fill latencies per symbol
+type fillLatencies struct {
+	config  FillLatencyConfig
+	samples map[string][]time.Duration
+}
+
+// SetFillLatencySLA enables the fill latency SLA rule
+func (m *Monitor) SetFillLatencySLA(config FillLatencyConfig) error {
+	if config.SLA <= 0 {
+		return fmt.Errorf("fill latency SLA must be positive, got %s", config.SLA)
+	}
+	if config.Window <= 0 {
+		config.Window = 100
+	}
+	if config.MinSamples <= 0 || config.MinSamples > config.Window {
+		config.MinSamples = config.Window / 5
+		if config.MinSamples == 0 {
+			config.MinSamples = 1
+		}
+	}
+
+	sla := decimal.NewFromFloat(config.SLA.Seconds())
+	m.ruleMu.Lock()
+	defer m.ruleMu.Unlock()
+	m.latency = &fillLatencies{
+		config:  config,
+		samples: make(map[string][]time.Duration),
+	}
+	m.latencyRule = &AlertRule{
+		Type:             AlertFillLatency,
+		Threshold:        sla,
+		ResolveThreshold: sla,
+		firing:           make(map[string]bool),
+	}
+	return nil
+}
+
+// RecordFillLatency records the time from signal to confirmed fill for a
+// trade in symbol, updates its rolling p95 and evaluates the SLA rule
+func (m *Monitor) RecordFillLatency(ctx context.Context, symbol string, latency time.Duration) {
+	m.ruleMu.Lock()
+	if m.latency == nil {
+		m.ruleMu.Unlock()
+		return
+	}
+	samples := append(m.latency.samples[symbol], latency)
+	if len(samples) > m.latency.config.Window {
+		samples = samples[len(samples)-m.latency.config.Window:]
+	}
+	m.latency.samples[symbol] = samples
+
+	p95 := percentile(samples, 0.95)
+	metrics.FillLatencyP95.WithLabelValues(symbol).Set(p95.Seconds())
+	metrics.SymbolSeries.Track(symbol, metrics.FillLatencyP95, symbol)
+
+	var alert *Alert
+	if len(samples) >= m.latency.config.MinSamples {
+		alert = m.latencyRule.evaluate(symbol, decimal.NewFromFloat(p95.Seconds()))
+	}
+	handlers := m.handlers
+	m.ruleMu.Unlock()
+
+	if alert == nil {
+		return
+	}
+	m.sendAlert(alert)
+	m.dispatch(ctx, handlers, alert)
+}
+
+// percentile returns the nearest-rank q-th percentile of samples
+func percentile(samples []time.Duration, q float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
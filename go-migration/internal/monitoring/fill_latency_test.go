@@ -0,0 +1,49 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+func TestMonitor_FillLatencySLAAlert(t *testing.T) {
+	m := NewMonitor(nil, zap.NewNop())
+	assert.NoError(t, m.SetFillLatencySLA(FillLatencyConfig{
+		SLA:        2 * time.Second,
+		Window:     20,
+		MinSamples: 10,
+	}))
+	assert.Error(t, m.SetFillLatencySLA(FillLatencyConfig{}))
+
+	handler := &recordingHandler{}
+	m.RegisterHandler(handler)
+	ctx := context.Background()
+
+	// Fast fills, with one slow outlier the p95 tolerates
+	for i := 0; i < 19; i++ {
+		m.RecordFillLatency(ctx, "SOL", 500*time.Millisecond)
+	}
+	m.RecordFillLatency(ctx, "SOL", 5*time.Second)
+	assert.Empty(t, handler.alerts)
+	assert.InDelta(t, 0.5, testutil.ToFloat64(metrics.FillLatencyP95.WithLabelValues("SOL")), 1e-9)
+
+	// Consistently slow fills push the p95 past the SLA
+	for i := 0; i < 3; i++ {
+		m.RecordFillLatency(ctx, "SOL", 3*time.Second)
+	}
+	assert.Len(t, handler.alerts, 1)
+	assert.Equal(t, AlertFillLatency, handler.alerts[0].Type)
+	assert.Equal(t, "SOL", handler.alerts[0].Symbol)
+	assert.False(t, handler.alerts[0].Resolved)
+	assert.InDelta(t, 3.0, testutil.ToFloat64(metrics.FillLatencyP95.WithLabelValues("SOL")), 1e-9)
+
+	// Other symbols are tracked separately
+	m.RecordFillLatency(ctx, "BONK", 10*time.Second)
+	assert.Len(t, handler.alerts, 1)
+}
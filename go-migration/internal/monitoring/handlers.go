@@ -0,0 +1,74 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookHandler posts alerts as JSON to an HTTP endpoint
+type WebhookHandler struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookHandler(url string) *WebhookHandler {
+	return &WebhookHandler{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (h *WebhookHandler) HandleAlert(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, h.client, h.url, alert)
+}
+
+// SlackHandler posts alerts to a Slack incoming webhook
+type SlackHandler struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func NewSlackHandler(webhookURL string) *SlackHandler {
+	return &SlackHandler{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (h *SlackHandler) HandleAlert(ctx context.Context, alert *Alert) error {
+	state := "FIRING"
+	if alert.Resolved {
+		state = "RESOLVED"
+	}
+	text := fmt.Sprintf("[%s] %s on %s: current %s, threshold %s",
+		state, alert.Type, alert.Symbol, alert.Current.StringFixed(4), alert.Threshold.StringFixed(4))
+	return postJSON(ctx, h.client, h.webhookURL, map[string]string{"text": text})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
@@ -19,6 +20,14 @@ type PumpMonitor struct {
 	tokens     map[string]*types.TokenUpdate
 	tradeable  map[string]bool
 	updateChan chan *types.TokenUpdate
+	staleAfter time.Duration
+	// stale symbols have had their close attempted and closeRetry those
+	// whose close failed; a fresh update clears both
+	stale         map[string]bool
+	closeRetry    map[string]bool
+	autoClose     AutoCloseConfig
+	closer        PositionCloser
+	closeHandlers []AlertHandler
 }
 
 func NewPumpMonitor(logger *zap.Logger, provider *pump.Provider) *PumpMonitor {
@@ -28,6 +37,9 @@ func NewPumpMonitor(logger *zap.Logger, provider *pump.Provider) *PumpMonitor {
 		tokens:     make(map[string]*types.TokenUpdate),
 		tradeable:  make(map[string]bool),
 		updateChan: make(chan *types.TokenUpdate, 1000),
+		staleAfter: 5 * time.Minute,
+		stale:      make(map[string]bool),
+		closeRetry: make(map[string]bool),
 	}
 }
 
@@ -127,8 +139,13 @@ func (m *PumpMonitor) handleUpdate(update *types.TokenUpdate) error {
 		metrics.NewTokensTotal.Inc()
 	}
 	
+	delete(m.stale, update.Symbol)
+	delete(m.closeRetry, update.Symbol)
+
 	metrics.TokenPrice.WithLabelValues("pump.fun", update.Symbol).Set(update.Price)
 	metrics.TokenVolume.WithLabelValues("pump.fun", update.Symbol).Set(update.Volume)
+	metrics.SymbolSeries.Track(update.Symbol, metrics.TokenPrice, "pump.fun", update.Symbol)
+	metrics.SymbolSeries.Track(update.Symbol, metrics.TokenVolume, "pump.fun", update.Symbol)
 
 	if prev == nil {
 		m.logger.Info("new token detected",
@@ -138,7 +155,7 @@ func (m *PumpMonitor) handleUpdate(update *types.TokenUpdate) error {
 			zap.Float64("volume", update.Volume))
 	} else {
 		priceChange := (update.Price - prev.Price) / prev.Price * 100
-		metrics.TokenPrice.WithLabelValues(update.Symbol).Set(update.Price)
+		metrics.TokenPrice.WithLabelValues("pump.fun", update.Symbol).Set(update.Price)
 		
 		if priceChange > 20 || priceChange < -20 {
 			m.logger.Info("significant price change detected",
@@ -151,7 +168,8 @@ func (m *PumpMonitor) handleUpdate(update *types.TokenUpdate) error {
 		// Track unrealized PnL if we have a position
 		if m.tradeable[update.Symbol] {
 			pnl := (update.Price - prev.Price) * float64(update.TotalSupply)
-			metrics.TokenVolume.WithLabelValues(update.Symbol + "_pnl").Set(pnl)
+			metrics.PumpTokenPnL.WithLabelValues("pump.fun", update.Symbol).Set(pnl)
+			metrics.SymbolSeries.Track(update.Symbol, metrics.PumpTokenPnL, "pump.fun", update.Symbol)
 		}
 	}
 
@@ -165,26 +183,38 @@ func (m *PumpMonitor) handleUpdate(update *types.TokenUpdate) error {
 }
 
 func (m *PumpMonitor) checkThresholds() {
+	m.closeStale(m.updateTradeable())
+}
+
+// updateTradeable enables or disables trading per token and returns the
+// symbols that have just gone stale or whose close is being retried
+func (m *PumpMonitor) updateTradeable() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	var stale []string
 	now := time.Now()
 	for symbol, update := range m.tokens {
-		if now.Sub(update.Timestamp) > 5*time.Minute {
+		if now.Sub(update.Timestamp) > m.staleAfter {
+			if !m.stale[symbol] || m.closeRetry[symbol] {
+				m.stale[symbol] = true
+				stale = append(stale, symbol)
+			}
 			if m.tradeable[symbol] {
 				metrics.APIErrors.WithLabelValues("stale_data").Inc()
 				m.logger.Warn("token trading disabled due to stale data",
 					zap.String("symbol", symbol),
 					zap.Time("last_update", update.Timestamp))
 			}
-			metrics.TokenVolume.WithLabelValues(symbol + "_enabled").Set(0)
+			metrics.PumpTokenTradeable.WithLabelValues("pump.fun", symbol).Set(0)
+			metrics.SymbolSeries.Track(symbol, metrics.PumpTokenTradeable, "pump.fun", symbol)
 			delete(m.tradeable, symbol)
 			continue
 		}
 
 		wasEnabled := m.tradeable[symbol]
 		minVolume := 1000.0
-		maxMarketCap := 30000.0
+		maxMarketCap := m.maxMarketCap().InexactFloat64()
 		shouldEnable := update.Volume > minVolume && update.MarketCap < maxMarketCap
 
 		if shouldEnable != wasEnabled {
@@ -195,8 +225,8 @@ func (m *PumpMonitor) checkThresholds() {
 					zap.Float64("market_cap", update.MarketCap))
 				
 				// Record position metrics when enabling trading
-				metrics.TokenPrice.WithLabelValues(symbol).Set(update.Price)
-				metrics.TokenVolume.WithLabelValues(symbol).Set(update.Volume)
+				metrics.TokenPrice.WithLabelValues("pump.fun", symbol).Set(update.Price)
+				metrics.TokenVolume.WithLabelValues("pump.fun", symbol).Set(update.Volume)
 			} else {
 				m.logger.Info("token trading disabled",
 					zap.String("symbol", symbol),
@@ -204,14 +234,25 @@ func (m *PumpMonitor) checkThresholds() {
 					zap.Float64("market_cap", update.MarketCap))
 				
 				// Clear position metrics when disabling trading
-				metrics.TokenPrice.WithLabelValues(symbol).Set(0)
-				metrics.TokenVolume.WithLabelValues(symbol).Set(0)
+				metrics.TokenPrice.WithLabelValues("pump.fun", symbol).Set(0)
+				metrics.TokenVolume.WithLabelValues("pump.fun", symbol).Set(0)
 			}
 		}
 
 		m.tradeable[symbol] = shouldEnable
-		metrics.TokenVolume.WithLabelValues(symbol + "_enabled").Set(btoi(shouldEnable))
+		metrics.PumpTokenTradeable.WithLabelValues("pump.fun", symbol).Set(btoi(shouldEnable))
+		metrics.SymbolSeries.Track(symbol, metrics.PumpTokenTradeable, "pump.fun", symbol)
+	}
+	return stale
+}
+
+// maxMarketCap returns the provider's market cap ceiling, or the default
+// when the monitor runs without a provider
+func (m *PumpMonitor) maxMarketCap() decimal.Decimal {
+	if m.provider == nil {
+		return types.DefaultMaxMarketCap
 	}
+	return m.provider.MaxMarketCap()
 }
 
 func btoi(b bool) float64 {
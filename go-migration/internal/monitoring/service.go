@@ -29,7 +29,7 @@ func NewService(provider *pump.Provider, metrics *metrics.PumpMetrics, logger *z
 		provider: provider,
 		metrics:  metrics,
 		tokens:   make(map[string]*types.TokenMarketInfo),
-		maxCap:   decimal.NewFromInt(30000),
+		maxCap:   provider.MaxMarketCap(),
 	}
 }
 
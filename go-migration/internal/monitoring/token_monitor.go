@@ -89,7 +89,7 @@ func (m *TokenMonitor) UpdateToken(symbol string, update *types.TokenUpdate) {
 	m.metrics.TokenVolume.WithLabelValues("pump.fun", symbol).Set(update.Volume)
 	m.metrics.TokenMarketCap.WithLabelValues("pump.fun", symbol).Set(update.MarketCap)
 
-	if token.MarketCap.LessThan(decimal.NewFromFloat(30000)) {
+	if token.MarketCap.LessThan(types.DefaultMaxMarketCap) {
 		m.logger.Info("Low cap token detected",
 			zap.String("symbol", symbol),
 			zap.Float64("market_cap", update.MarketCap))
@@ -0,0 +1,43 @@
+package pricing
+
+import (
+	"strings"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// confirmSignal combines indicator votes into one signal when at least
+// minAgreement of them point the same way. The confirmed signal takes the
+// average confidence of the agreeing votes and lists them in Source, e.g.
+// "RSI+BB". Conflicting directions that both reach the quorum cancel out.
+func confirmSignal(votes []*types.Signal, minAgreement int) *types.Signal {
+	byType := make(map[types.SignalType][]*types.Signal)
+	for _, vote := range votes {
+		byType[vote.Type] = append(byType[vote.Type], vote)
+	}
+
+	var agreed []*types.Signal
+	for _, group := range byType {
+		if len(group) < minAgreement {
+			continue
+		}
+		if agreed != nil {
+			return nil
+		}
+		agreed = group
+	}
+	if agreed == nil {
+		return nil
+	}
+
+	confirmed := *agreed[0]
+	sources := make([]string, len(agreed))
+	var confidence float64
+	for i, vote := range agreed {
+		sources[i] = vote.Source
+		confidence += vote.Confidence
+	}
+	confirmed.Confidence = confidence / float64(len(agreed))
+	confirmed.Source = strings.Join(sources, "+")
+	return &confirmed
+}
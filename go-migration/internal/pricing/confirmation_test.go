@@ -0,0 +1,85 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func vote(source string, signalType types.SignalType, confidence float64) *types.Signal {
+	return &types.Signal{Symbol: "SOL", Type: signalType, Confidence: confidence, Source: source}
+}
+
+func TestConfirmSignal_AveragesAgreeingVotes(t *testing.T) {
+	signal := confirmSignal([]*types.Signal{
+		vote("RSI", types.SignalTypeBuy, 0.6),
+		vote("MACD", types.SignalTypeSell, 0.9),
+		vote("BB", types.SignalTypeBuy, 0.2),
+	}, 2)
+	require.NotNil(t, signal)
+	assert.Equal(t, types.SignalTypeBuy, signal.Type)
+	assert.Equal(t, "RSI+BB", signal.Source)
+	assert.InDelta(t, 0.4, signal.Confidence, 1e-9)
+}
+
+func TestConfirmSignal_RequiresQuorum(t *testing.T) {
+	assert.Nil(t, confirmSignal([]*types.Signal{
+		vote("RSI", types.SignalTypeBuy, 0.6),
+		vote("MACD", types.SignalTypeSell, 0.9),
+	}, 2))
+	assert.Nil(t, confirmSignal(nil, 2))
+}
+
+func TestEngine_MinAgreementSuppressesLoneIndicator(t *testing.T) {
+	engine := NewEngine(Config{
+		Symbols:      []string{"SOL"},
+		HistorySize:  10,
+		SignalParams: SignalParams{MinAgreement: 2},
+	}, zap.NewNop())
+	engine.indicators = []analysis.IndicatorCalculator{&fixedIndicator{name: "RSI", value: 15}}
+	require.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "SOL", Price: decimal.NewFromInt(100)}))
+
+	// A single oversold RSI no longer trades on its own
+	assert.Nil(t, engine.analyzeIndicators("SOL", engine.history["SOL"]))
+
+	engine.config.SignalParams.MinAgreement = 1
+	signal := engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, signal)
+	assert.Equal(t, "RSI", signal.Source)
+}
+
+type crossIndicator struct {
+	fixedIndicator
+	cross float64
+}
+
+func (i *crossIndicator) Params() interface{} {
+	return map[string]interface{}{"fast": 0.0, "slow": 0.0, "cross": i.cross}
+}
+
+func TestEngine_EMACrossVotesOnCross(t *testing.T) {
+	engine := NewEngine(Config{Symbols: []string{"SOL"}, HistorySize: 10}, zap.NewNop())
+	require.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "SOL", Price: decimal.NewFromInt(100)}))
+	cross := &crossIndicator{fixedIndicator: fixedIndicator{name: "EMA_CROSS", value: 0.05}}
+	engine.indicators = []analysis.IndicatorCalculator{cross}
+
+	assert.Empty(t, engine.detectSignals("SOL", engine.history["SOL"]))
+
+	cross.cross = analysis.GoldenCross
+	signal := engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, signal)
+	assert.Equal(t, types.SignalTypeBuy, signal.Type)
+	assert.Equal(t, "EMA_CROSS", signal.Source)
+	assert.InDelta(t, 0.5, signal.Confidence, 1e-9)
+
+	cross.cross = analysis.DeathCross
+	signal = engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, signal)
+	assert.Equal(t, types.SignalTypeSell, signal.Type)
+}
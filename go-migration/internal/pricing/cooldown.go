@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// cooldowns records when each symbol and direction last fired, so repeated
+// same-direction signals can be held back while opposite ones still pass
+type cooldowns struct {
+	fired map[cooldownKey]time.Time
+	mu    sync.Mutex
+}
+
+type cooldownKey struct {
+	symbol     string
+	signalType types.SignalType
+}
+
+func newCooldowns() *cooldowns {
+	return &cooldowns{fired: make(map[cooldownKey]time.Time)}
+}
+
+func (c *cooldowns) remaining(symbol string, signalType types.SignalType, period time.Duration, now time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fired, ok := c.fired[cooldownKey{symbol, signalType}]
+	if !ok {
+		return 0
+	}
+	if left := fired.Add(period).Sub(now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+func (c *cooldowns) start(symbol string, signalType types.SignalType, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fired[cooldownKey{symbol, signalType}] = now
+}
+
+// forget drops the cooldowns of a symbol that is no longer tracked
+func (c *cooldowns) forget(symbol string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.fired {
+		if key.symbol == symbol {
+			delete(c.fired, key)
+		}
+	}
+}
+
+// CooldownRemaining returns how long signals of signalType for symbol are
+// still held back after the last one fired
+func (e *Engine) CooldownRemaining(symbol string, signalType types.SignalType) time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.cooldownRemaining(symbol, signalType)
+}
+
+// cooldownRemaining is CooldownRemaining for callers holding the lock
+func (e *Engine) cooldownRemaining(symbol string, signalType types.SignalType) time.Duration {
+	return e.cooldowns.remaining(symbol, signalType, e.config.SignalParams.Cooldown, e.now())
+}
+
+func (e *Engine) coolingDown(signal *types.Signal) bool {
+	return e.cooldownRemaining(signal.Symbol, signal.Type) > 0
+}
+
+func (e *Engine) startCooldown(signal *types.Signal) {
+	if e.config.SignalParams.Cooldown > 0 {
+		e.cooldowns.start(signal.Symbol, signal.Type, e.now())
+	}
+}
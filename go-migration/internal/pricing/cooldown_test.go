@@ -0,0 +1,44 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_CooldownHoldsBackSameDirection(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{SignalParams: SignalParams{Cooldown: time.Minute}}, zap.NewNop())
+	engine.now = func() time.Time { return now }
+	history := historyFrom(100)
+
+	buy := &types.Signal{Symbol: "SOL", Type: types.SignalTypeBuy}
+	sell := &types.Signal{Symbol: "SOL", Type: types.SignalTypeSell}
+	assert.True(t, engine.allowSignal(buy, history))
+	engine.startCooldown(buy)
+
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, 40*time.Second, engine.CooldownRemaining("SOL", types.SignalTypeBuy))
+	assert.False(t, engine.allowSignal(buy, history))
+
+	// Exits and other symbols are not held back
+	assert.True(t, engine.allowSignal(sell, history))
+	assert.True(t, engine.allowSignal(&types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy}, history))
+
+	now = now.Add(40 * time.Second)
+	assert.Zero(t, engine.CooldownRemaining("SOL", types.SignalTypeBuy))
+	assert.True(t, engine.allowSignal(buy, history))
+}
+
+func TestEngine_NoCooldownByDefault(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop())
+	buy := &types.Signal{Symbol: "SOL", Type: types.SignalTypeBuy}
+
+	engine.startCooldown(buy)
+	assert.Zero(t, engine.CooldownRemaining("SOL", types.SignalTypeBuy))
+	assert.True(t, engine.allowSignal(buy, historyFrom(100)))
+}
@@ -3,6 +3,8 @@ package pricing
 import (
 	"context"
 	"fmt"
+	"math"
+	"runtime"
 	"sync"
 	"time"
 
@@ -10,22 +12,32 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 // Config represents pricing engine configuration
 type Config struct {
-	Symbols        []string      `json:"symbols"`
-	UpdateInterval time.Duration `json:"update_interval"`
-	HistorySize    int           `json:"history_size"`
-	Indicators     []string      `json:"indicators"`
-	SignalParams   SignalParams  `json:"signal_params"`
+	Symbols        []string              `json:"symbols"`
+	UpdateInterval time.Duration         `json:"update_interval"`
+	HistorySize    int                   `json:"history_size"`
+	Retention      types.RetentionConfig `json:"retention"`
+	Indicators     []string              `json:"indicators"`
+	MaxSymbols     int                   `json:"max_symbols"` // Cap on symbols auto-registered by updates, 0 for DefaultMaxSymbols, negative for no cap
+	SignalParams   SignalParams          `json:"signal_params"`
+	Feedback       FeedbackConfig        `json:"feedback"`
 }
 
+// DefaultMaxSymbols caps the symbols updates register when MaxSymbols is
+// not set
+const DefaultMaxSymbols = 500
+
 // SignalParams represents signal generation parameters
 type SignalParams struct {
-	MinConfidence float64 `json:"min_confidence"`
-	MaxVolatility float64 `json:"max_volatility"`
+	MinConfidence float64       `json:"min_confidence"`
+	MaxVolatility float64       `json:"max_volatility"`
+	MinAgreement  int           `json:"min_agreement"` // Indicators that must agree on direction, 0 or 1 fires on the first
+	Cooldown      time.Duration `json:"cooldown"`      // Quiet period per symbol and direction after a signal fires
 	TimeRange     struct {
 		Start string `json:"start"`
 		End   string `json:"end"`
@@ -40,6 +52,13 @@ type Engine struct {
 	indicators []analysis.IndicatorCalculator
 	history    map[string]*types.PriceHistory
 	signals    chan *types.Signal
+	tap        types.SignalStore
+	feedback   *reliability
+	window     *tradingWindow
+	cooldowns  *cooldowns
+	workers    int
+	malformed  sync.Map // Indicator names already warned about bad params
+	now        func() time.Time
 	mu         sync.RWMutex
 }
 
@@ -48,6 +67,9 @@ type IndicatorFunc func(history *PriceHistory) (*Indicator, error)
 
 // NewEngine creates a new pricing engine
 func NewEngine(config Config, logger *zap.Logger) *Engine {
+	if config.MaxSymbols == 0 {
+		config.MaxSymbols = DefaultMaxSymbols
+	}
 	e := &Engine{
 		logger:     logger,
 		config:     config,
@@ -55,8 +77,21 @@ func NewEngine(config Config, logger *zap.Logger) *Engine {
 		indicators: make([]analysis.IndicatorCalculator, 0),
 		history:    make(map[string]*types.PriceHistory),
 		signals:    make(chan *types.Signal, 100),
+		cooldowns:  newCooldowns(),
+		workers:    runtime.NumCPU(),
+		now:        time.Now,
 	}
 
+	if config.Feedback.Enabled {
+		e.feedback = newReliability(config.Feedback.LearningRate)
+	}
+
+	window, err := parseTradingWindow(config.SignalParams.TimeRange.Start, config.SignalParams.TimeRange.End)
+	if err != nil {
+		logger.Warn("Ignoring signal time range", zap.Error(err))
+	}
+	e.window = window
+
 	// Initialize indicators
 	for _, name := range config.Indicators {
 		if indicator := e.createIndicator(name); indicator != nil {
@@ -66,7 +101,7 @@ func NewEngine(config Config, logger *zap.Logger) *Engine {
 
 	// Initialize price history
 	for _, symbol := range config.Symbols {
-		e.history[symbol] = types.NewPriceHistory(config.HistorySize)
+		e.history[symbol] = e.newHistory()
 	}
 
 	return e
@@ -85,10 +120,14 @@ func (e *Engine) ProcessUpdate(update *types.PriceUpdate) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	// Get price history
+	// Get price history, registering symbols discovered after startup
 	history, ok := e.history[update.Symbol]
 	if !ok {
-		return fmt.Errorf("unknown symbol: %s", update.Symbol)
+		if e.config.MaxSymbols > 0 && len(e.history) >= e.config.MaxSymbols {
+			return fmt.Errorf("unknown symbol %s: symbol limit of %d reached", update.Symbol, e.config.MaxSymbols)
+		}
+		history = e.newHistory()
+		e.history[update.Symbol] = history
 	}
 
 	// Add price to history
@@ -102,11 +141,47 @@ func (e *Engine) ProcessUpdate(update *types.PriceUpdate) error {
 	return nil
 }
 
+// AddSymbol starts tracking price history for symbol. Unlike symbols
+// registered by ProcessUpdate it is not subject to MaxSymbols.
+func (e *Engine) AddSymbol(symbol string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.history[symbol]; !ok {
+		e.history[symbol] = e.newHistory()
+	}
+}
+
+// RemoveSymbol stops tracking symbol and drops its price history
+func (e *Engine) RemoveSymbol(symbol string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.history, symbol)
+	e.cooldowns.forget(symbol)
+}
+
+func (e *Engine) newHistory() *types.PriceHistory {
+	history := types.NewPriceHistory(e.config.HistorySize)
+	if e.config.Retention.Enabled() {
+		history.SetRetention(e.config.Retention)
+	}
+	return history
+}
+
 // GetSignals returns the signal channel
 func (e *Engine) GetSignals() <-chan *types.Signal {
 	return e.signals
 }
 
+// SetSignalTap sets a store that every emitted signal is written to,
+// whether or not it is consumed downstream
+func (e *Engine) SetSignalTap(store types.SignalStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tap = store
+}
+
 // Internal methods
 
 func (e *Engine) generateSignals(ctx context.Context) {
@@ -120,25 +195,13 @@ func (e *Engine) generateSignals(ctx context.Context) {
 		case <-ticker.C:
 			e.mu.RLock()
 			for symbol, history := range e.history {
-				// Calculate indicators
-				for _, indicator := range e.indicators {
-					if err := indicator.Calculate(history); err != nil {
-						e.logger.Error("Failed to calculate indicator",
-							zap.Error(err),
-							zap.String("symbol", symbol),
-							zap.String("indicator", indicator.Name()))
-						continue
-					}
-				}
+				e.calculateIndicators(symbol, history)
 
 				// Generate signals
 				if signal := e.analyzeIndicators(symbol, history); signal != nil {
-					if e.validator.Validate(signal) {
-						select {
-						case e.signals <- signal:
-						default:
-							e.logger.Warn("Signal channel full")
-						}
+					if e.allowSignal(signal, history) && e.validator.Validate(signal) {
+						e.startCooldown(signal)
+						e.emitSignal(ctx, signal)
 					}
 				}
 			}
@@ -147,7 +210,106 @@ func (e *Engine) generateSignals(ctx context.Context) {
 	}
 }
 
+// calculateIndicators runs the independent indicator calculations for a
+// symbol on a worker pool. Each indicator only writes its own state, and
+// failures are logged in configuration order once all workers finish.
+func (e *Engine) calculateIndicators(symbol string, history *types.PriceHistory) {
+	workers := e.workers
+	if workers > len(e.indicators) {
+		workers = len(e.indicators)
+	}
+	if workers <= 1 {
+		for _, indicator := range e.indicators {
+			e.logIndicatorError(symbol, indicator, indicator.Calculate(history))
+		}
+		return
+	}
+
+	errs := make([]error, len(e.indicators))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = e.indicators[i].Calculate(history)
+			}
+		}()
+	}
+	for i := range e.indicators {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, indicator := range e.indicators {
+		e.logIndicatorError(symbol, indicator, errs[i])
+	}
+}
+
+func (e *Engine) logIndicatorError(symbol string, indicator analysis.IndicatorCalculator, err error) {
+	if err == nil {
+		return
+	}
+	e.logger.Error("Failed to calculate indicator",
+		zap.Error(err),
+		zap.String("symbol", symbol),
+		zap.String("indicator", indicator.Name()))
+}
+
+// emitSignal publishes signal and records its latency. Signals carry the
+// timestamp of the price update they were derived from, so the latency
+// covers the whole path from market event to emission.
+func (e *Engine) emitSignal(ctx context.Context, signal *types.Signal) {
+	e.tapSignal(ctx, signal)
+	if !signal.Timestamp.IsZero() {
+		metrics.PricingSignalLatency.Observe(e.now().Sub(signal.Timestamp).Seconds())
+	}
+
+	select {
+	case e.signals <- signal:
+	default:
+		e.logger.Warn("Signal channel full")
+	}
+}
+
+func (e *Engine) tapSignal(ctx context.Context, signal *types.Signal) {
+	if e.tap == nil {
+		return
+	}
+	if err := e.tap.SaveSignal(ctx, signal); err != nil {
+		e.logger.Error("Failed to persist signal",
+			zap.Error(err),
+			zap.String("symbol", signal.Symbol))
+	}
+}
+
+// analyzeIndicators derives a signal from the current indicator values,
+// discounting each indicator's confidence by its reliability when feedback
+// is enabled. With MinAgreement above one a signal needs that many
+// indicators voting the same way, otherwise the first triggered one wins.
 func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *types.Signal {
+	votes := e.detectSignals(symbol, history)
+	if e.feedback != nil {
+		for _, vote := range votes {
+			vote.Confidence *= e.feedback.factor(vote.Source)
+		}
+	}
+
+	if e.config.SignalParams.MinAgreement <= 1 {
+		if len(votes) == 0 {
+			return nil
+		}
+		return votes[0]
+	}
+	return confirmSignal(votes, e.config.SignalParams.MinAgreement)
+}
+
+// detectSignals returns the signal each triggered indicator votes for, in
+// RSI, MACD, Bollinger Bands, EMA crossover, stochastic, Williams %R order.
+// OBV and VWAP do not vote and are only reported with the signals.
+func (e *Engine) detectSignals(symbol string, history *types.PriceHistory) []*types.Signal {
 	// Get current price level
 	current := history.Last()
 	if current == nil {
@@ -164,12 +326,14 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 		}
 	}
 
+	var votes []*types.Signal
+
 	// Analyze RSI
 	for _, ind := range e.indicators {
 		if ind.Name() == "RSI" {
 			value := ind.Value()
 			if value <= 30 {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeBuy,
 					Direction:  "long",
@@ -177,10 +341,11 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (30 - value) / 30,
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "RSI",
+				})
 			}
 			if value >= 70 {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeSell,
 					Direction:  "short",
@@ -188,7 +353,8 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (value - 70) / 30,
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "RSI",
+				})
 			}
 		}
 	}
@@ -197,11 +363,14 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 	for _, ind := range e.indicators {
 		if ind.Name() == "MACD" {
 			value := ind.Value()
-			params := ind.Params().(map[string]interface{})
-			signal := params["signal"].(float64)
+			params, ok := e.indicatorParams(ind, "signal")
+			if !ok {
+				continue
+			}
+			signal := params[0]
 
 			if value > signal {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeBuy,
 					Direction:  "long",
@@ -209,10 +378,11 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (value - signal) / (0.001 * current.Price),
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "MACD",
+				})
 			}
 			if value < signal {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeSell,
 					Direction:  "short",
@@ -220,7 +390,8 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (signal - value) / (0.001 * current.Price),
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "MACD",
+				})
 			}
 		}
 	}
@@ -228,13 +399,14 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 	// Analyze Bollinger Bands
 	for _, ind := range e.indicators {
 		if ind.Name() == "BB" {
-			params := ind.Params().(map[string]interface{})
-			upper := params["upper"].(float64)
-			lower := params["lower"].(float64)
-			middle := params["middle"].(float64)
+			params, ok := e.indicatorParams(ind, "upper", "lower", "middle")
+			if !ok {
+				continue
+			}
+			upper, lower, middle := params[0], params[1], params[2]
 
 			if current.Price <= lower {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeBuy,
 					Direction:  "long",
@@ -242,10 +414,11 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (lower - current.Price) / (lower - middle),
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "BB",
+				})
 			}
 			if current.Price >= upper {
-				return &types.Signal{
+				votes = append(votes, &types.Signal{
 					Symbol:     symbol,
 					Type:       types.SignalTypeSell,
 					Direction:  "short",
@@ -253,12 +426,132 @@ func (e *Engine) analyzeIndicators(symbol string, history *types.PriceHistory) *
 					Confidence: (current.Price - upper) / (upper - middle),
 					Timestamp:  current.Timestamp,
 					Indicators: indicators,
-				}
+					Source:     "BB",
+				})
 			}
 		}
 	}
 
-	return nil
+	// Analyze EMA crossovers, entering on the bar the EMAs cross
+	for _, ind := range e.indicators {
+		if ind.Name() == "EMA_CROSS" {
+			params, ok := e.indicatorParams(ind, "cross")
+			if !ok {
+				continue
+			}
+			confidence := math.Min(math.Abs(ind.Value())/(0.001*current.Price), 1)
+
+			switch params[0] {
+			case analysis.GoldenCross:
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeBuy,
+					Direction:  "long",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: confidence,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "EMA_CROSS",
+				})
+			case analysis.DeathCross:
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeSell,
+					Direction:  "short",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: confidence,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "EMA_CROSS",
+				})
+			}
+		}
+	}
+
+	// Analyze the stochastic oscillator's overbought and oversold zones
+	for _, ind := range e.indicators {
+		if ind.Name() == "STOCH" {
+			value := ind.Value()
+			if value <= 20 {
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeBuy,
+					Direction:  "long",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: (20 - value) / 20,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "STOCH",
+				})
+			}
+			if value >= 80 {
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeSell,
+					Direction:  "short",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: (value - 80) / 20,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "STOCH",
+				})
+			}
+		}
+	}
+
+	// Analyze Williams %R, which runs from -100 to 0
+	for _, ind := range e.indicators {
+		if ind.Name() == "WILLR" {
+			value := ind.Value()
+			if value <= -80 {
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeBuy,
+					Direction:  "long",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: (-80 - value) / 20,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "WILLR",
+				})
+			}
+			if value >= -20 {
+				votes = append(votes, &types.Signal{
+					Symbol:     symbol,
+					Type:       types.SignalTypeSell,
+					Direction:  "short",
+					Price:      decimal.NewFromFloat(current.Price),
+					Confidence: (value + 20) / 20,
+					Timestamp:  current.Timestamp,
+					Indicators: indicators,
+					Source:     "WILLR",
+				})
+			}
+		}
+	}
+
+	return votes
+}
+
+// indicatorParams reads the named float parameters of ind. A missing or
+// mistyped parameter skips the indicator, warning once per indicator.
+func (e *Engine) indicatorParams(ind analysis.IndicatorCalculator, keys ...string) ([]float64, bool) {
+	params, ok := ind.Params().(map[string]interface{})
+	values := make([]float64, len(keys))
+	for i, key := range keys {
+		if ok {
+			values[i], ok = params[key].(float64)
+		}
+		if !ok {
+			if _, warned := e.malformed.LoadOrStore(ind.Name(), true); !warned {
+				e.logger.Warn("Skipping indicator with malformed params",
+					zap.String("indicator", ind.Name()),
+					zap.String("param", key))
+			}
+			return nil, false
+		}
+	}
+	return values, true
 }
 
 func (e *Engine) createIndicator(name string) analysis.IndicatorCalculator {
@@ -271,6 +564,16 @@ func (e *Engine) createIndicator(name string) analysis.IndicatorCalculator {
 		return analysis.NewMACDIndicator(12, 26, 9)
 	case "bb":
 		return analysis.NewBollingerBandsIndicator(20, 2)
+	case "ema_cross", "EMA_CROSS":
+		return analysis.NewEMACrossIndicator(9, 21)
+	case "stoch":
+		return analysis.NewStochasticIndicator(14, 3)
+	case "willr":
+		return analysis.NewWilliamsRIndicator(14)
+	case "obv":
+		return analysis.NewOBVIndicator()
+	case "vwap":
+		return analysis.NewVWAPIndicator(20)
 	default:
 		e.logger.Warn("Unknown indicator", zap.String("name", name))
 		return nil
@@ -0,0 +1,181 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func signalLatency(t *testing.T) (uint64, float64) {
+	m := &dto.Metric{}
+	assert.NoError(t, metrics.PricingSignalLatency.Write(m))
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestEngine_RecordsSignalLatency(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{Symbols: []string{"SOL"}, HistorySize: 10}, zap.NewNop())
+	engine.now = func() time.Time { return now }
+
+	eventTime := now.Add(-250 * time.Millisecond)
+	assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{
+		Symbol:    "SOL",
+		Price:     decimal.NewFromFloat(100),
+		Timestamp: eventTime,
+	}))
+
+	// Signals are stamped with the update they were derived from
+	current := engine.history["SOL"].Last()
+	count, sum := signalLatency(t)
+	engine.emitSignal(context.Background(), &types.Signal{
+		Symbol:    "SOL",
+		Type:      types.SignalTypeBuy,
+		Price:     decimal.NewFromFloat(current.Price),
+		Timestamp: current.Timestamp,
+	})
+
+	newCount, newSum := signalLatency(t)
+	assert.Equal(t, count+1, newCount)
+	assert.InDelta(t, 0.25, newSum-sum, 1e-9)
+
+	select {
+	case signal := <-engine.GetSignals():
+		assert.Equal(t, "SOL", signal.Symbol)
+	default:
+		t.Fatal("signal was not emitted")
+	}
+}
+
+func loadedEngine(bars int) *Engine {
+	engine := NewEngine(Config{
+		Symbols:     []string{"SOL"},
+		HistorySize: bars,
+		Indicators:  []string{"ema", "rsi", "macd", "bb", "stoch", "willr", "obv", "vwap"},
+	}, zap.NewNop())
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < bars; i++ {
+		_ = engine.ProcessUpdate(&types.PriceUpdate{
+			Symbol:    "SOL",
+			Price:     decimal.NewFromFloat(100 + 10*math.Sin(float64(i)/20)),
+			Volume:    decimal.NewFromInt(1000),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return engine
+}
+
+func TestEngine_ParallelIndicatorsMatchSerial(t *testing.T) {
+	serial := loadedEngine(200)
+	serial.workers = 1
+	parallel := loadedEngine(200)
+	parallel.workers = 4
+
+	serial.calculateIndicators("SOL", serial.history["SOL"])
+	parallel.calculateIndicators("SOL", parallel.history["SOL"])
+
+	assert.Len(t, parallel.indicators, len(serial.indicators))
+	for i := range serial.indicators {
+		assert.Equal(t, serial.indicators[i].Name(), parallel.indicators[i].Name())
+		assert.Equal(t, serial.indicators[i].Value(), parallel.indicators[i].Value())
+	}
+}
+
+func BenchmarkCalculateIndicators(b *testing.B) {
+	engine := loadedEngine(10000)
+	history := engine.history["SOL"]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.calculateIndicators("SOL", history)
+	}
+}
+
+type emptyParamsIndicator struct{ fixedIndicator }
+
+func (i *emptyParamsIndicator) Params() interface{} { return map[string]interface{}{} }
+
+func TestEngine_SkipsIndicatorsWithMissingParams(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	engine := NewEngine(Config{Symbols: []string{"SOL"}, HistorySize: 10}, zap.New(core))
+	engine.indicators = []analysis.IndicatorCalculator{
+		&emptyParamsIndicator{fixedIndicator{name: "MACD", value: 1}},
+		&emptyParamsIndicator{fixedIndicator{name: "BB"}},
+		&fixedIndicator{name: "MACD", value: 1},
+	}
+	assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "SOL", Price: decimal.NewFromInt(100)}))
+
+	assert.NotPanics(t, func() {
+		assert.Nil(t, engine.analyzeIndicators("SOL", engine.history["SOL"]))
+		assert.Nil(t, engine.analyzeIndicators("SOL", engine.history["SOL"]))
+	})
+	// Each malformed indicator is reported once, not on every tick
+	assert.Equal(t, 2, logs.FilterMessage("Skipping indicator with malformed params").Len())
+}
+
+func TestEngine_RegistersSymbolsUpToMaxSymbols(t *testing.T) {
+	engine := NewEngine(Config{Symbols: []string{"SOL"}, HistorySize: 10, MaxSymbols: 2}, zap.NewNop())
+
+	assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "PUMP", Price: decimal.NewFromInt(1)}))
+	assert.Equal(t, 1.0, engine.history["PUMP"].Last().Price)
+
+	err := engine.ProcessUpdate(&types.PriceUpdate{Symbol: "BONK", Price: decimal.NewFromInt(1)})
+	assert.ErrorContains(t, err, "symbol limit of 2 reached")
+
+	// Explicit subscriptions are not capped, and removal frees a slot
+	engine.AddSymbol("BONK")
+	assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "BONK", Price: decimal.NewFromInt(1)}))
+	engine.RemoveSymbol("BONK")
+	engine.RemoveSymbol("PUMP")
+	assert.NotContains(t, engine.history, "PUMP")
+	assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "WIF", Price: decimal.NewFromInt(1)}))
+
+	// Unset falls back to the default cap, negative disables it
+	assert.Equal(t, DefaultMaxSymbols, NewEngine(Config{}, zap.NewNop()).config.MaxSymbols)
+	uncapped := NewEngine(Config{HistorySize: 10, MaxSymbols: -1}, zap.NewNop())
+	for i := 0; i < DefaultMaxSymbols+1; i++ {
+		assert.NoError(t, uncapped.ProcessUpdate(&types.PriceUpdate{Symbol: fmt.Sprintf("T%d", i), Price: decimal.NewFromInt(1)}))
+	}
+}
+
+func TestEngine_OscillatorsVoteInOversoldZone(t *testing.T) {
+	engine := NewEngine(Config{
+		Symbols:     []string{"SOL"},
+		HistorySize: 30,
+		Indicators:  []string{"stoch", "willr", "obv", "vwap"},
+	}, zap.NewNop())
+	assert.Len(t, engine.indicators, 4)
+
+	// A steady decline closes every window at its low
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		assert.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{
+			Symbol:    "SOL",
+			Price:     decimal.NewFromInt(int64(200 - i)),
+			Volume:    decimal.NewFromInt(1000),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	history := engine.history["SOL"]
+	engine.calculateIndicators("SOL", history)
+
+	votes := engine.detectSignals("SOL", history)
+	var sources []string
+	for _, vote := range votes {
+		assert.Equal(t, types.SignalTypeBuy, vote.Type)
+		assert.Len(t, vote.Indicators, 4)
+		sources = append(sources, vote.Source)
+	}
+	assert.Equal(t, []string{"STOCH", "WILLR"}, sources)
+}
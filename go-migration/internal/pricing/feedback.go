@@ -0,0 +1,105 @@
+package pricing
+
+import (
+	"sync"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// defaultLearningRate weights each new outcome when FeedbackConfig leaves
+// the learning rate unset
+const defaultLearningRate = 0.1
+
+// FeedbackConfig enables learning how reliable each indicator's signals are
+// from their outcomes and discounting the confidence of unreliable ones
+type FeedbackConfig struct {
+	Enabled      bool    `json:"enabled"`
+	LearningRate float64 `json:"learning_rate"` // Weight of each new outcome, in (0, 1]
+}
+
+// reliability tracks a per-indicator factor, the exponentially weighted hit
+// rate of the indicator's signals. Indicators start fully trusted at 1.
+type reliability struct {
+	rate    float64
+	factors map[string]float64
+	mu      sync.RWMutex
+}
+
+func newReliability(rate float64) *reliability {
+	if rate <= 0 || rate > 1 {
+		rate = defaultLearningRate
+	}
+	return &reliability{
+		rate:    rate,
+		factors: make(map[string]float64),
+	}
+}
+
+func (r *reliability) factor(indicator string) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if f, ok := r.factors[indicator]; ok {
+		return f
+	}
+	return 1
+}
+
+// record moves indicator's factor towards 1 for a profitable signal and
+// towards 0 otherwise
+func (r *reliability) record(indicator string, profitable bool) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.factors[indicator]
+	if !ok {
+		f = 1
+	}
+	outcome := 0.0
+	if profitable {
+		outcome = 1
+	}
+	f += r.rate * (outcome - f)
+	r.factors[indicator] = f
+	return f
+}
+
+// RecordSignalOutcome feeds back whether a signal turned out profitable,
+// updating the reliability of the indicator that generated it. It does
+// nothing unless feedback is enabled.
+func (e *Engine) RecordSignalOutcome(signal *types.Signal, profitable bool) {
+	if e.feedback == nil || signal.Source == "" {
+		return
+	}
+	f := e.feedback.record(signal.Source, profitable)
+	metrics.IndicatorReliability.WithLabelValues(signal.Source).Set(f)
+}
+
+// Reliability returns the learned reliability factor of each indicator with
+// recorded outcomes, so it can be stored and restored with SetReliability
+func (e *Engine) Reliability() map[string]float64 {
+	factors := make(map[string]float64)
+	if e.feedback == nil {
+		return factors
+	}
+	e.feedback.mu.RLock()
+	defer e.feedback.mu.RUnlock()
+	for indicator, f := range e.feedback.factors {
+		factors[indicator] = f
+	}
+	return factors
+}
+
+// SetReliability restores reliability factors saved from Reliability. It
+// does nothing unless feedback is enabled.
+func (e *Engine) SetReliability(factors map[string]float64) {
+	if e.feedback == nil {
+		return
+	}
+	e.feedback.mu.Lock()
+	defer e.feedback.mu.Unlock()
+	for indicator, f := range factors {
+		e.feedback.factors[indicator] = f
+	}
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// fixedIndicator reports a constant value
+type fixedIndicator struct {
+	name  string
+	value float64
+}
+
+func (f *fixedIndicator) Name() string                                { return f.name }
+func (f *fixedIndicator) Value() float64                              { return f.value }
+func (f *fixedIndicator) Params() interface{}                         { return nil }
+func (f *fixedIndicator) Calculate(history *types.PriceHistory) error { return nil }
+
+func TestEngine_FeedbackDiscountsUnreliableIndicator(t *testing.T) {
+	engine := NewEngine(Config{
+		Symbols:     []string{"SOL"},
+		HistorySize: 10,
+		Feedback:    FeedbackConfig{Enabled: true, LearningRate: 0.5},
+	}, zap.NewNop())
+	engine.indicators = []analysis.IndicatorCalculator{&fixedIndicator{name: "RSI", value: 15}}
+	require.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{
+		Symbol:    "SOL",
+		Price:     decimal.NewFromInt(100),
+		Timestamp: time.Now(),
+	}))
+
+	// An oversold RSI of 15 is a buy at confidence 0.5 before any feedback
+	signal := engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, signal)
+	assert.Equal(t, "RSI", signal.Source)
+	assert.InDelta(t, 0.5, signal.Confidence, 1e-9)
+
+	// Three losing signals halve the reliability each time
+	for i := 0; i < 3; i++ {
+		engine.RecordSignalOutcome(signal, false)
+	}
+	assert.InDelta(t, 0.125, engine.Reliability()["RSI"], 1e-9)
+
+	discounted := engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, discounted)
+	assert.InDelta(t, 0.5*0.125, discounted.Confidence, 1e-9)
+
+	// A win recovers half the gap to full trust
+	engine.RecordSignalOutcome(signal, true)
+	assert.InDelta(t, 0.5625, engine.Reliability()["RSI"], 1e-9)
+}
+
+func TestEngine_FeedbackDisabledLeavesConfidence(t *testing.T) {
+	engine := NewEngine(Config{Symbols: []string{"SOL"}, HistorySize: 10}, zap.NewNop())
+	engine.indicators = []analysis.IndicatorCalculator{&fixedIndicator{name: "RSI", value: 15}}
+	require.NoError(t, engine.ProcessUpdate(&types.PriceUpdate{Symbol: "SOL", Price: decimal.NewFromInt(100)}))
+
+	signal := engine.analyzeIndicators("SOL", engine.history["SOL"])
+	require.NotNil(t, signal)
+	engine.RecordSignalOutcome(signal, false)
+	assert.Empty(t, engine.Reliability())
+	assert.InDelta(t, 0.5, engine.analyzeIndicators("SOL", engine.history["SOL"]).Confidence, 1e-9)
+}
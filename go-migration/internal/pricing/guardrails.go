@@ -0,0 +1,111 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// volatilityWindow is the number of recent returns realized volatility is
+// measured over
+const volatilityWindow = 20
+
+// tradingWindow is the parsed SignalParams.TimeRange, as offsets from
+// midnight UTC. A window whose end is before its start wraps midnight.
+type tradingWindow struct {
+	start, end time.Duration
+}
+
+// parseTradingWindow parses "15:04" start and end times. Leaving either
+// empty disables the window.
+func parseTradingWindow(start, end string) (*tradingWindow, error) {
+	if start == "" || end == "" {
+		return nil, nil
+	}
+	from, err := time.Parse("15:04", start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time range start %q: %w", start, err)
+	}
+	to, err := time.Parse("15:04", end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time range end %q: %w", end, err)
+	}
+	return &tradingWindow{
+		start: time.Duration(from.Hour())*time.Hour + time.Duration(from.Minute())*time.Minute,
+		end:   time.Duration(to.Hour())*time.Hour + time.Duration(to.Minute())*time.Minute,
+	}, nil
+}
+
+func (w *tradingWindow) contains(t time.Time) bool {
+	t = t.UTC()
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	if w.start <= w.end {
+		return clock >= w.start && clock < w.end
+	}
+	return clock >= w.start || clock < w.end
+}
+
+// realizedVolatility returns the standard deviation of the simple returns
+// between the most recent window+1 price levels, or 0 without two levels
+func realizedVolatility(history *types.PriceHistory, window int) float64 {
+	prices := make([]float64, 0, window+1)
+	history.Range(func(level *types.PriceLevel) bool {
+		prices = append(prices, level.Price)
+		return len(prices) <= window
+	})
+
+	returns := make([]float64, 0, len(prices))
+	for i := 1; i < len(prices); i++ {
+		if prices[i] > 0 {
+			// Range visits newest first, so prices[i] precedes prices[i-1]
+			returns = append(returns, prices[i-1]/prices[i]-1)
+		}
+	}
+	if len(returns) == 0 {
+		return 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)))
+}
+
+// allowSignal applies the SignalParams guardrails, dropping signals outside
+// the configured trading window, during the cooldown of an earlier signal
+// in the same direction, or while the symbol's realized volatility is above
+// MaxVolatility
+func (e *Engine) allowSignal(signal *types.Signal, history *types.PriceHistory) bool {
+	if e.window != nil && !e.window.contains(e.now()) {
+		return false
+	}
+	if e.coolingDown(signal) {
+		return false
+	}
+
+	limit := e.config.SignalParams.MaxVolatility
+	if limit <= 0 {
+		return true
+	}
+	if volatility := realizedVolatility(history, volatilityWindow); volatility > limit {
+		metrics.PricingSignalsSuppressedVolatility.Inc()
+		e.logger.Debug("Signal suppressed by volatility",
+			zap.String("symbol", signal.Symbol),
+			zap.Float64("volatility", volatility),
+			zap.Float64("max_volatility", limit))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func historyFrom(prices ...float64) *types.PriceHistory {
+	h := types.NewPriceHistory(len(prices))
+	for _, p := range prices {
+		h.Add(&types.PriceLevel{Symbol: "SOL", Price: p})
+	}
+	return h
+}
+
+func suppressedCount(t *testing.T) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, metrics.PricingSignalsSuppressedVolatility.Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestRealizedVolatility(t *testing.T) {
+	assert.Zero(t, realizedVolatility(historyFrom(100), volatilityWindow))
+	assert.InDelta(t, 0, realizedVolatility(historyFrom(100, 101, 102.01), volatilityWindow), 1e-9)
+	// A +10% then -10% move
+	assert.InDelta(t, 0.1, realizedVolatility(historyFrom(100, 110, 99), volatilityWindow), 1e-9)
+}
+
+func TestEngine_SuppressesSignalsAboveMaxVolatility(t *testing.T) {
+	engine := NewEngine(Config{SignalParams: SignalParams{MaxVolatility: 0.05}}, zap.NewNop())
+	signal := &types.Signal{Symbol: "SOL"}
+
+	before := suppressedCount(t)
+	assert.True(t, engine.allowSignal(signal, historyFrom(100, 101, 100, 101)))
+	assert.False(t, engine.allowSignal(signal, historyFrom(100, 110, 99, 108.9)))
+	assert.Equal(t, before+1, suppressedCount(t))
+}
+
+func TestEngine_SignalsOnlyInsideTimeRange(t *testing.T) {
+	config := Config{}
+	config.SignalParams.TimeRange.Start = "22:00"
+	config.SignalParams.TimeRange.End = "06:00"
+	engine := NewEngine(config, zap.NewNop())
+	signal := &types.Signal{Symbol: "SOL"}
+	history := historyFrom(100)
+
+	for clock, allowed := range map[string]bool{"23:30": true, "05:59": true, "06:00": false, "12:00": false} {
+		now, err := time.Parse("15:04", clock)
+		require.NoError(t, err)
+		engine.now = func() time.Time { return now }
+		assert.Equal(t, allowed, engine.allowSignal(signal, history), clock)
+	}
+}
+
+func TestParseTradingWindow_RejectsBadClock(t *testing.T) {
+	window, err := parseTradingWindow("", "06:00")
+	assert.NoError(t, err)
+	assert.Nil(t, window)
+
+	_, err = parseTradingWindow("25:00", "06:00")
+	assert.Error(t, err)
+}
@@ -196,17 +196,30 @@ func (i *BollingerBandsIndicator) Params() interface{} {
 
 // Helper functions
 
+// calculateEMA returns the EMA of the last period levels, seeded with the
+// oldest price in the window. Histories shorter than period (freshly listed
+// tokens) fall back to the mean of the levels available.
 func calculateEMA(history *PriceHistory, period int) float64 {
+	available := history.LastIndex + 1
+	if available > len(history.Levels) {
+		available = len(history.Levels)
+	}
+	if available <= 0 {
+		return 0
+	}
+	if period <= 0 || available < period {
+		var sum float64
+		for i := 0; i < available; i++ {
+			sum += history.Levels[history.LastIndex-i].Price
+		}
+		return sum / float64(available)
+	}
+
 	multiplier := 2.0 / float64(period+1)
-	
-	var ema float64
-	for i := 0; i < period; i++ {
+	ema := history.Levels[history.LastIndex-period+1].Price
+	for i := period - 2; i >= 0; i-- {
 		price := history.Levels[history.LastIndex-i].Price
-		if i == 0 {
-			ema = price
-		} else {
-			ema = (price * multiplier) + (ema * (1 - multiplier))
-		}
+		ema = (price * multiplier) + (ema * (1 - multiplier))
 	}
 	return ema
 }
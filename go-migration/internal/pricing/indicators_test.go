@@ -0,0 +1,36 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func historyOf(prices ...float64) *PriceHistory {
+	h := &PriceHistory{Symbol: "SOL", Size: len(prices), LastIndex: len(prices) - 1}
+	for _, p := range prices {
+		h.Levels = append(h.Levels, &PriceLevel{Price: p})
+	}
+	return h
+}
+
+func TestCalculateEMA_ShortHistoryFallsBackToMean(t *testing.T) {
+	assert.NotPanics(t, func() {
+		assert.InDelta(t, 2.0, calculateEMA(historyOf(1, 2, 3), 9), 1e-9)
+	})
+	assert.Zero(t, calculateEMA(historyOf(), 9))
+}
+
+func TestCalculateEMA_SeedsWithOldestPriceInWindow(t *testing.T) {
+	// Window is the last three prices: seed 2, then 3 and 4 with k=0.5
+	ema := calculateEMA(historyOf(100, 2, 3, 4), 3)
+	assert.InDelta(t, 3.25, ema, 1e-9)
+}
+
+func TestMACDIndicator_SignalPeriodLongerThanHistory(t *testing.T) {
+	macd := NewMACDIndicator(2, 3, 20)
+	assert.NotPanics(t, func() {
+		assert.NoError(t, macd.Calculate(historyOf(1, 2, 3, 4, 5)))
+	})
+	assert.InDelta(t, 3.0, macd.signal, 1e-9)
+}
@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ReloadSignalParams applies new signal generation parameters at runtime.
+// They are validated as a whole, the time range included, and nothing is
+// applied if any is invalid. Running cooldowns are measured against the new
+// period from the next signal on.
+func (e *Engine) ReloadSignalParams(params SignalParams) error {
+	if err := ValidateSignalParams(params); err != nil {
+		return err
+	}
+	window, err := parseTradingWindow(params.TimeRange.Start, params.TimeRange.End)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.config.SignalParams != params {
+		e.logger.Info("Signal parameters reloaded",
+			zap.Any("from", e.config.SignalParams),
+			zap.Any("to", params))
+	}
+	e.config.SignalParams = params
+	e.window = window
+	return nil
+}
+
+// ValidateSignalParams reports whether ReloadSignalParams would accept
+// params, so callers reloading several components can check them all
+// before applying any
+func ValidateSignalParams(params SignalParams) error {
+	if err := validateSignalParams(params); err != nil {
+		return fmt.Errorf("invalid signal parameters: %w", err)
+	}
+	_, err := parseTradingWindow(params.TimeRange.Start, params.TimeRange.End)
+	return err
+}
+
+func validateSignalParams(p SignalParams) error {
+	switch {
+	case p.MinConfidence < 0 || p.MinConfidence > 1:
+		return fmt.Errorf("min_confidence must be between 0 and 1, got %v", p.MinConfidence)
+	case p.MaxVolatility < 0:
+		return fmt.Errorf("max_volatility must not be negative, got %v", p.MaxVolatility)
+	case p.MinAgreement < 0:
+		return fmt.Errorf("min_agreement must not be negative, got %d", p.MinAgreement)
+	case p.Cooldown < 0:
+		return fmt.Errorf("cooldown must not be negative, got %s", p.Cooldown)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_ReloadSignalParams(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{SignalParams: SignalParams{Cooldown: time.Minute}}, zap.NewNop())
+	engine.now = func() time.Time { return now }
+	history := historyFrom(100)
+
+	buy := &types.Signal{Symbol: "SOL", Type: types.SignalTypeBuy}
+	engine.startCooldown(buy)
+	now = now.Add(20 * time.Second)
+	assert.False(t, engine.allowSignal(buy, history))
+
+	// A shorter cooldown applies to the next signal
+	require.NoError(t, engine.ReloadSignalParams(SignalParams{Cooldown: 10 * time.Second}))
+	assert.Zero(t, engine.CooldownRemaining("SOL", types.SignalTypeBuy))
+	assert.True(t, engine.allowSignal(buy, history))
+
+	// A trading window is parsed and applied with the rest
+	params := SignalParams{Cooldown: 10 * time.Second}
+	params.TimeRange.Start, params.TimeRange.End = "14:00", "16:00"
+	require.NoError(t, engine.ReloadSignalParams(params))
+	assert.False(t, engine.allowSignal(buy, history))
+
+	// Invalid updates are rejected whole
+	bad := SignalParams{Cooldown: time.Hour}
+	bad.TimeRange.Start, bad.TimeRange.End = "25:00", "16:00"
+	assert.Error(t, ValidateSignalParams(bad))
+	assert.Error(t, engine.ReloadSignalParams(bad))
+	assert.Error(t, engine.ReloadSignalParams(SignalParams{MinConfidence: 1.5}))
+	assert.Equal(t, params, engine.config.SignalParams)
+}
@@ -0,0 +1,113 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ReturnsSource provides a recent return series per symbol. Series for
+// different symbols must be aligned index by index, as produced by
+// analysis.AlignReturns.
+type ReturnsSource interface {
+	Returns(ctx context.Context, symbol string) ([]float64, error)
+}
+
+// SetReturnsSource sets the source used to regress position returns against
+// the beta reference
+func (m *Manager) SetReturnsSource(source ReturnsSource) {
+	m.returns = source
+}
+
+// PositionBeta returns the beta of symbol against the configured reference
+func (m *Manager) PositionBeta(ctx context.Context, symbol string) (float64, error) {
+	if m.returns == nil {
+		return 0, fmt.Errorf("no returns source configured")
+	}
+	if symbol == m.limits.BetaReference {
+		return 1, nil
+	}
+
+	reference, err := m.returns.Returns(ctx, m.limits.BetaReference)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load returns for %s: %w", m.limits.BetaReference, err)
+	}
+	asset, err := m.returns.Returns(ctx, symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load returns for %s: %w", symbol, err)
+	}
+	return analysis.Beta(asset, reference), nil
+}
+
+// PortfolioBeta returns the value-weighted beta of positions. Weights are
+// taken relative to gross exposure so that shorts offset longs.
+func (m *Manager) PortfolioBeta(ctx context.Context, positions []*types.Position) (float64, error) {
+	return m.portfolioBeta(ctx, exposures(positions))
+}
+
+// CheckPortfolioBeta rejects adding size of symbol at price when the
+// resulting portfolio beta would exceed the configured cap
+func (m *Manager) CheckPortfolioBeta(ctx context.Context, positions []*types.Position, symbol string, size, price decimal.Decimal) error {
+	if m.limits.MaxBeta.IsZero() {
+		return nil
+	}
+
+	values := exposures(positions)
+	values[symbol] = values[symbol].Add(size.Mul(price))
+
+	beta, err := m.portfolioBeta(ctx, values)
+	if err != nil {
+		return err
+	}
+
+	if decimal.NewFromFloat(beta).Abs().GreaterThan(m.limits.MaxBeta) {
+		m.logger.Warn("Position rejected by portfolio beta cap",
+			zap.String("symbol", symbol),
+			zap.Float64("beta", beta),
+			zap.String("max_beta", m.limits.MaxBeta.String()))
+		return fmt.Errorf("portfolio beta %.4f would exceed limit %s", beta, m.limits.MaxBeta.String())
+	}
+	return nil
+}
+
+func (m *Manager) portfolioBeta(ctx context.Context, values map[string]decimal.Decimal) (float64, error) {
+	gross := decimal.Zero
+	for _, value := range values {
+		gross = gross.Add(value.Abs())
+	}
+	if gross.IsZero() {
+		return 0, nil
+	}
+
+	var beta float64
+	for symbol, value := range values {
+		if value.IsZero() {
+			continue
+		}
+		b, err := m.PositionBeta(ctx, symbol)
+		if err != nil {
+			return 0, err
+		}
+		beta += value.Div(gross).InexactFloat64() * b
+	}
+	return beta, nil
+}
+
+// exposures returns the signed value of each position, marked at the
+// current price and falling back to entry when no mark is available
+func exposures(positions []*types.Position) map[string]decimal.Decimal {
+	values := make(map[string]decimal.Decimal, len(positions))
+	for _, pos := range positions {
+		price := pos.CurrentPrice
+		if price.IsZero() {
+			price = pos.EntryPrice
+		}
+		values[pos.Symbol] = values[pos.Symbol].Add(pos.Size.Mul(price))
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type staticReturns map[string][]float64
+
+func (s staticReturns) Returns(ctx context.Context, symbol string) ([]float64, error) {
+	r, ok := s[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no returns for %s", symbol)
+	}
+	return r, nil
+}
+
+func TestManager_CheckPortfolioBetaRejectsHighBeta(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize: decimal.NewFromInt(10000),
+		MaxBeta:         decimal.NewFromFloat(1.5),
+		BetaReference:   "SOL",
+	}, zap.NewNop())
+	manager.SetReturnsSource(staticReturns{
+		"SOL":  {0.01, -0.02, 0.03, 0.01, -0.01},
+		"BONK": {0.01, -0.02, 0.03, 0.01, -0.01},
+		"PUMP": {0.04, -0.08, 0.12, 0.04, -0.04},
+	})
+	ctx := context.Background()
+
+	positions := []*types.Position{
+		{Symbol: "BONK", Size: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(10)},
+	}
+
+	beta, err := manager.PositionBeta(ctx, "PUMP")
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.0, beta, 1e-9)
+
+	beta, err = manager.PortfolioBeta(ctx, positions)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, beta, 1e-9)
+
+	// A small high-beta position keeps the book under the cap:
+	// (1000*1 + 100*4) / 1100 ~= 1.27
+	assert.NoError(t, manager.CheckPortfolioBeta(ctx, positions, "PUMP", decimal.NewFromInt(10), decimal.NewFromInt(10)))
+
+	// A large one pushes it to (1000*1 + 1000*4) / 2000 = 2.5
+	err = manager.CheckPortfolioBeta(ctx, positions, "PUMP", decimal.NewFromInt(100), decimal.NewFromInt(10))
+	assert.Error(t, err)
+}
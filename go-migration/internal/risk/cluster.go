@@ -0,0 +1,80 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// Clusters groups symbols whose return series are correlated at or above
+// the configured cluster threshold, directly or through other symbols
+func (m *Manager) Clusters(ctx context.Context, symbols []string) ([][]string, error) {
+	if m.returns == nil {
+		return nil, fmt.Errorf("no returns source configured")
+	}
+
+	returns := make(map[string][]float64, len(symbols))
+	for _, symbol := range symbols {
+		r, err := m.returns.Returns(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load returns for %s: %w", symbol, err)
+		}
+		returns[symbol] = r
+	}
+	return analysis.CorrelationClusters(analysis.CorrelationMatrix(returns), m.limits.ClusterThreshold), nil
+}
+
+// CheckClusterExposure rejects adding size of symbol at price when the gross
+// exposure of the correlation cluster it joins would exceed the configured
+// cap, keeping the book from concentrating in a single theme
+func (m *Manager) CheckClusterExposure(ctx context.Context, positions []*types.Position, symbol string, size, price decimal.Decimal) error {
+	if m.limits.MaxClusterExposure.IsZero() {
+		return nil
+	}
+
+	values := exposures(positions)
+	values[symbol] = values[symbol].Add(size.Mul(price))
+
+	symbols := make([]string, 0, len(values))
+	for s := range values {
+		symbols = append(symbols, s)
+	}
+	clusters, err := m.Clusters(ctx, symbols)
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters {
+		if !containsSymbol(cluster, symbol) {
+			continue
+		}
+
+		exposure := decimal.Zero
+		for _, s := range cluster {
+			exposure = exposure.Add(values[s].Abs())
+		}
+		if exposure.GreaterThan(m.limits.MaxClusterExposure) {
+			m.logger.Warn("Position rejected by cluster exposure cap",
+				zap.String("symbol", symbol),
+				zap.Strings("cluster", cluster),
+				zap.String("exposure", exposure.String()),
+				zap.String("max_cluster_exposure", m.limits.MaxClusterExposure.String()))
+			return fmt.Errorf("exposure %s of cluster %v would exceed limit %s", exposure.String(), cluster, m.limits.MaxClusterExposure.String())
+		}
+	}
+	return nil
+}
+
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package risk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestManager_CheckClusterExposureRejectsConcentratedTheme(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:    decimal.NewFromInt(10000),
+		MaxClusterExposure: decimal.NewFromInt(2500),
+		ClusterThreshold:   0.8,
+	}, zap.NewNop())
+	manager.SetReturnsSource(staticReturns{
+		"BONK":   {0.01, -0.02, 0.03, 0.01, -0.01},
+		"WIF":    {0.02, -0.04, 0.06, 0.02, -0.02},
+		"POPCAT": {0.015, -0.025, 0.035, 0.012, -0.011},
+		"JUP":    {-0.01, 0.01, 0.02, -0.03, 0.01},
+	})
+	ctx := context.Background()
+
+	positions := []*types.Position{
+		{Symbol: "BONK", Size: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(10)},
+		{Symbol: "WIF", Size: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(10)},
+	}
+
+	clusters, err := manager.Clusters(ctx, []string{"BONK", "WIF", "POPCAT", "JUP"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"BONK", "POPCAT", "WIF"}, {"JUP"}}, clusters)
+
+	// An uncorrelated position starts its own cluster
+	assert.NoError(t, manager.CheckClusterExposure(ctx, positions, "JUP", decimal.NewFromInt(200), decimal.NewFromInt(10)))
+
+	// 400 more in the meme cluster stays within 2500
+	assert.NoError(t, manager.CheckClusterExposure(ctx, positions, "POPCAT", decimal.NewFromInt(40), decimal.NewFromInt(10)))
+
+	// 1000 more pushes the cluster to 3000
+	err = manager.CheckClusterExposure(ctx, positions, "POPCAT", decimal.NewFromInt(100), decimal.NewFromInt(10))
+	assert.Error(t, err)
+}
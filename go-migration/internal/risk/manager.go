@@ -19,12 +19,30 @@ type Limits struct {
 	MaxLeverage      decimal.Decimal `json:"max_leverage"`
 	MinMarginLevel   decimal.Decimal `json:"min_margin_level"`
 	MaxConcentration decimal.Decimal `json:"max_concentration"`
+	// MaxBeta caps the absolute portfolio beta against BetaReference. Zero
+	// disables the check.
+	MaxBeta       decimal.Decimal `json:"max_beta"`
+	BetaReference string          `json:"beta_reference"`
+	// Regime scales position size by RegimeScale while a symbol's rolling
+	// correlation to Benchmark over RegimeWindow returns is at least
+	// RegimeCorrelation and the benchmark is falling. An empty Benchmark
+	// disables regime switching.
+	Benchmark         string          `json:"benchmark"`
+	RegimeWindow      int             `json:"regime_window"`
+	RegimeCorrelation float64         `json:"regime_correlation"`
+	RegimeScale       decimal.Decimal `json:"regime_scale"`
+	// MaxClusterExposure caps the gross value held in each cluster of
+	// symbols correlated at or above ClusterThreshold. Zero disables the
+	// check.
+	MaxClusterExposure decimal.Decimal `json:"max_cluster_exposure"`
+	ClusterThreshold   float64         `json:"cluster_threshold"`
 }
 
 // Manager handles risk management
 type Manager struct {
-	logger *zap.Logger
-	limits Limits
+	logger  *zap.Logger
+	limits  Limits
+	returns ReturnsSource
 }
 
 func (m *Manager) GetLimits() Limits {
@@ -0,0 +1,92 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/analysis"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// BenchmarkCorrelation returns the rolling correlation of symbol's returns
+// to the configured benchmark, for use as a strategy signal input
+func (m *Manager) BenchmarkCorrelation(ctx context.Context, symbol string) (float64, error) {
+	corr, _, err := m.benchmarkCorrelation(ctx, symbol)
+	return corr, err
+}
+
+// RegimeSizeScale returns the factor position size in symbol is scaled by
+// under the current correlation regime: RegimeScale when symbol moves with
+// a falling benchmark, otherwise one
+func (m *Manager) RegimeSizeScale(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	one := decimal.NewFromInt(1)
+	if m.limits.Benchmark == "" || symbol == m.limits.Benchmark {
+		return one, nil
+	}
+
+	corr, benchmarkReturn, err := m.benchmarkCorrelation(ctx, symbol)
+	if err != nil {
+		return one, err
+	}
+	if corr < m.limits.RegimeCorrelation || benchmarkReturn >= 0 {
+		return one, nil
+	}
+
+	m.logger.Info("Reducing size in correlated downtrend",
+		zap.String("symbol", symbol),
+		zap.String("benchmark", m.limits.Benchmark),
+		zap.Float64("correlation", corr),
+		zap.Float64("benchmark_return", benchmarkReturn),
+		zap.String("scale", m.limits.RegimeScale.String()))
+	return m.limits.RegimeScale, nil
+}
+
+// CalculateRegimeSize returns the position size for symbol at price scaled
+// for the current correlation regime
+func (m *Manager) CalculateRegimeSize(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	size, err := m.CalculatePositionSize(symbol, price)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	scale, err := m.RegimeSizeScale(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return size.Mul(scale), nil
+}
+
+// benchmarkCorrelation returns the rolling correlation of symbol to the
+// benchmark and the benchmark's compounded return over the same window
+func (m *Manager) benchmarkCorrelation(ctx context.Context, symbol string) (float64, float64, error) {
+	if m.returns == nil {
+		return 0, 0, fmt.Errorf("no returns source configured")
+	}
+	if m.limits.Benchmark == "" {
+		return 0, 0, fmt.Errorf("no benchmark configured")
+	}
+
+	benchmark, err := m.returns.Returns(ctx, m.limits.Benchmark)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load returns for %s: %w", m.limits.Benchmark, err)
+	}
+	asset, err := m.returns.Returns(ctx, symbol)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load returns for %s: %w", symbol, err)
+	}
+
+	corr := analysis.RollingCorrelation(asset, benchmark, m.limits.RegimeWindow)
+	metrics.BenchmarkCorrelation.WithLabelValues(symbol, m.limits.Benchmark).Set(corr)
+
+	window := benchmark
+	if m.limits.RegimeWindow > 0 && len(window) > m.limits.RegimeWindow {
+		window = window[len(window)-m.limits.RegimeWindow:]
+	}
+	growth := 1.0
+	for _, r := range window {
+		growth *= 1 + r
+	}
+	return corr, growth - 1, nil
+}
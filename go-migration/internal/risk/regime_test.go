@@ -0,0 +1,52 @@
+package risk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestManager_RegimeReducesSizeInCorrelatedDowntrend(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:   decimal.NewFromInt(1000),
+		Benchmark:         "SOL",
+		RegimeWindow:      5,
+		RegimeCorrelation: 0.8,
+		RegimeScale:       decimal.NewFromFloat(0.5),
+	}, zap.NewNop())
+
+	// SOL is falling over the window
+	returns := staticReturns{
+		"SOL":  {-0.01, -0.03, 0.01, -0.02, -0.01},
+		"BONK": {0.02, -0.01, 0.01, 0.03, -0.02},
+	}
+	manager.SetReturnsSource(returns)
+	ctx := context.Background()
+
+	// Weakly correlated to the benchmark: full size
+	corr, err := manager.BenchmarkCorrelation(ctx, "BONK")
+	assert.NoError(t, err)
+	assert.Less(t, corr, 0.8)
+	size, err := manager.CalculateRegimeSize(ctx, "BONK", decimal.NewFromInt(10))
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(size), size.String())
+
+	// BONK starts moving with the falling benchmark: half size
+	returns["BONK"] = []float64{-0.02, -0.06, 0.02, -0.04, -0.02}
+	corr, err = manager.BenchmarkCorrelation(ctx, "BONK")
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, corr, 1e-9)
+	size, err = manager.CalculateRegimeSize(ctx, "BONK", decimal.NewFromInt(10))
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(50).Equal(size), size.String())
+
+	// The same correlation in a rising benchmark keeps full size
+	returns["SOL"] = []float64{0.01, 0.03, -0.01, 0.02, 0.01}
+	returns["BONK"] = []float64{0.02, 0.06, -0.02, 0.04, 0.02}
+	scale, err := manager.RegimeSizeScale(ctx, "BONK")
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(scale))
+}
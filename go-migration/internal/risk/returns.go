@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// PriceHistory is the part of a market data provider HistoryReturns reads
+type PriceHistory interface {
+	GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error)
+}
+
+// HistoryReturns is a ReturnsSource built from provider price history.
+// Every series covers the same bars of Interval ending at the current bar,
+// with the last price carried into bars that have no update, so series for
+// different symbols line up index by index. A series is fetched once per
+// bar and reused until the next bar starts.
+type HistoryReturns struct {
+	history  PriceHistory
+	interval time.Duration
+	label    string
+	bars     int
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedReturns
+}
+
+type cachedReturns struct {
+	bar     time.Time
+	returns []float64
+}
+
+// NewHistoryReturns creates a returns source over bars bars of interval,
+// given in the provider's form such as "5m" or "1h"
+func NewHistoryReturns(history PriceHistory, interval string, bars int) (*HistoryReturns, error) {
+	d, err := time.ParseDuration(interval)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("invalid returns interval %q", interval)
+	}
+	if bars < 2 {
+		return nil, fmt.Errorf("returns need at least 2 bars, got %d", bars)
+	}
+	return &HistoryReturns{
+		history:  history,
+		interval: d,
+		label:    interval,
+		bars:     bars,
+		now:      time.Now,
+		cache:    make(map[string]cachedReturns),
+	}, nil
+}
+
+// Returns implements ReturnsSource
+func (h *HistoryReturns) Returns(ctx context.Context, symbol string) ([]float64, error) {
+	bar := h.now().Truncate(h.interval)
+
+	h.mu.Lock()
+	cached, ok := h.cache[symbol]
+	h.mu.Unlock()
+	if ok && cached.bar.Equal(bar) {
+		return cached.returns, nil
+	}
+
+	updates, err := h.history.GetHistoricalPrices(ctx, symbol, h.label, h.bars)
+	if err != nil {
+		return nil, err
+	}
+	returns, err := h.barReturns(symbol, updates, bar)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.cache[symbol] = cachedReturns{bar: bar, returns: returns}
+	h.mu.Unlock()
+	return returns, nil
+}
+
+// barReturns buckets updates into the bars ending at last and returns the
+// simple return from each bar's closing price to the next
+func (h *HistoryReturns) barReturns(symbol string, updates []types.PriceUpdate, last time.Time) ([]float64, error) {
+	closes := make([]float64, h.bars)
+	stamps := make([]time.Time, h.bars)
+	for _, update := range updates {
+		i := h.bars - 1 - int(last.Sub(update.Timestamp.Truncate(h.interval))/h.interval)
+		if i < 0 || i >= h.bars || !update.Price.IsPositive() {
+			continue
+		}
+		if stamps[i].IsZero() || !update.Timestamp.Before(stamps[i]) {
+			closes[i] = update.Price.InexactFloat64()
+			stamps[i] = update.Timestamp
+		}
+	}
+
+	first := -1
+	for i, price := range closes {
+		if price > 0 {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil, fmt.Errorf("no price history for %s in the last %d bars", symbol, h.bars)
+	}
+	for i := range closes {
+		switch {
+		case i < first:
+			closes[i] = closes[first]
+		case closes[i] == 0:
+			closes[i] = closes[i-1]
+		}
+	}
+
+	returns := make([]float64, h.bars-1)
+	for i := 1; i < h.bars; i++ {
+		returns[i-1] = closes[i]/closes[i-1] - 1
+	}
+	return returns, nil
+}
@@ -0,0 +1,105 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// fakeHistory serves fixed price updates per symbol and counts requests
+type fakeHistory struct {
+	updates  map[string][]types.PriceUpdate
+	requests int
+}
+
+func (f *fakeHistory) GetHistoricalPrices(ctx context.Context, symbol string, interval string, limit int) ([]types.PriceUpdate, error) {
+	f.requests++
+	return f.updates[symbol], nil
+}
+
+func priceAt(at time.Time, price float64) types.PriceUpdate {
+	return types.PriceUpdate{Price: decimal.NewFromFloat(price), Timestamp: at}
+}
+
+func TestHistoryReturns_AlignsBars(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	bar := func(n int) time.Time { return now.Truncate(time.Hour).Add(-time.Duration(n) * time.Hour) }
+
+	history := &fakeHistory{updates: map[string][]types.PriceUpdate{
+		// One update per bar, the latest in each bar is its close
+		"SOL": {priceAt(bar(3), 100), priceAt(bar(2), 110), priceAt(bar(1).Add(time.Minute), 90), priceAt(bar(1), 95), priceAt(bar(0), 99)},
+		// Missing the middle bars, which carry the last price forward
+		"BONK": {priceAt(bar(3), 10), priceAt(bar(0), 12)},
+	}}
+	source, err := NewHistoryReturns(history, "1h", 4)
+	require.NoError(t, err)
+	source.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	sol, err := source.Returns(ctx, "SOL")
+	require.NoError(t, err)
+	require.Len(t, sol, 3)
+	assert.InDelta(t, 0.1, sol[0], 1e-9)
+	assert.InDelta(t, 90.0/110-1, sol[1], 1e-9)
+	assert.InDelta(t, 99.0/90-1, sol[2], 1e-9)
+
+	bonk, err := source.Returns(ctx, "BONK")
+	require.NoError(t, err)
+	require.Len(t, bonk, 3)
+	assert.Zero(t, bonk[0])
+	assert.Zero(t, bonk[1])
+	assert.InDelta(t, 0.2, bonk[2], 1e-9)
+
+	// Served from cache until the next bar starts
+	_, err = source.Returns(ctx, "SOL")
+	require.NoError(t, err)
+	assert.Equal(t, 2, history.requests)
+	source.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = source.Returns(ctx, "SOL")
+	require.NoError(t, err)
+	assert.Equal(t, 3, history.requests)
+
+	_, err = source.Returns(ctx, "PUMP")
+	assert.Error(t, err)
+
+	_, err = NewHistoryReturns(history, "hourly", 4)
+	assert.Error(t, err)
+}
+
+func TestHistoryReturns_FeedsPortfolioBeta(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var sol, pump []types.PriceUpdate
+	prices := []float64{100, 102, 99, 104, 103}
+	for i, price := range prices {
+		at := now.Add(-time.Duration(len(prices)-1-i) * time.Minute)
+		sol = append(sol, priceAt(at, price))
+		// PUMP moves twice as far as SOL each bar
+		move := 1.0
+		if i > 0 {
+			move = 1 + 2*(price/prices[i-1]-1)
+		}
+		last := 10.0
+		if i > 0 {
+			last = pump[i-1].Price.InexactFloat64()
+		}
+		pump = append(pump, priceAt(at, last*move))
+	}
+	source, err := NewHistoryReturns(&fakeHistory{updates: map[string][]types.PriceUpdate{"SOL": sol, "PUMP": pump}}, "1m", len(prices))
+	require.NoError(t, err)
+	source.now = func() time.Time { return now }
+
+	manager := NewManager(Limits{BetaReference: "SOL", MaxBeta: decimal.NewFromFloat(1.5)}, zap.NewNop())
+	manager.SetReturnsSource(source)
+
+	beta, err := manager.PositionBeta(context.Background(), "PUMP")
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, beta, 1e-6)
+	assert.Error(t, manager.CheckPortfolioBeta(context.Background(), nil, "PUMP", decimal.NewFromInt(1), decimal.NewFromInt(10)))
+}
@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ReliabilityStorage persists the pricing engine's learned indicator
+// reliability factors, one document per indicator
+type ReliabilityStorage struct {
+	client *mongo.Client
+	db     string
+	logger *zap.Logger
+}
+
+type reliabilityFactor struct {
+	Indicator string  `bson:"_id"`
+	Factor    float64 `bson:"factor"`
+}
+
+// NewReliabilityStorage creates a new reliability storage
+func NewReliabilityStorage(client *mongo.Client, db string, logger *zap.Logger) *ReliabilityStorage {
+	return &ReliabilityStorage{
+		client: client,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveReliability stores factors, replacing the stored factor of each
+// indicator in it
+func (s *ReliabilityStorage) SaveReliability(ctx context.Context, factors map[string]float64) error {
+	collection := s.client.Database(s.db).Collection("reliability")
+	for indicator, factor := range factors {
+		_, err := collection.ReplaceOne(ctx,
+			bson.M{"_id": indicator},
+			reliabilityFactor{Indicator: indicator, Factor: factor},
+			options.Replace().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("failed to save reliability of %s: %w", indicator, err)
+		}
+	}
+	return nil
+}
+
+// LoadReliability returns the stored factor of every indicator
+func (s *ReliabilityStorage) LoadReliability(ctx context.Context) (map[string]float64, error) {
+	collection := s.client.Database(s.db).Collection("reliability")
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reliability factors: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []reliabilityFactor
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode reliability factors: %w", err)
+	}
+	factors := make(map[string]float64, len(stored))
+	for _, f := range stored {
+		factors[f.Indicator] = f.Factor
+	}
+	return factors, nil
+}
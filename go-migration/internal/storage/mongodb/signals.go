@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// SignalStorage implements types.SignalStore interface
+type SignalStorage struct {
+	client *mongo.Client
+	db     string
+	logger *zap.Logger
+}
+
+// NewSignalStorage creates a new signal storage
+func NewSignalStorage(client *mongo.Client, db string, logger *zap.Logger) *SignalStorage {
+	return &SignalStorage{
+		client: client,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveSignal implements types.SignalStore interface
+func (s *SignalStorage) SaveSignal(ctx context.Context, signal *types.Signal) error {
+	collection := s.client.Database(s.db).Collection("signals")
+	if _, err := collection.InsertOne(ctx, signal); err != nil {
+		return fmt.Errorf("failed to save signal: %w", err)
+	}
+	return nil
+}
+
+// LoadSignals implements types.SignalStore interface
+func (s *SignalStorage) LoadSignals(ctx context.Context, from, to time.Time) ([]*types.Signal, error) {
+	collection := s.client.Database(s.db).Collection("signals")
+	filter := bson.M{
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var signals []*types.Signal
+	if err := cursor.All(ctx, &signals); err != nil {
+		return nil, fmt.Errorf("failed to decode signals: %w", err)
+	}
+	return signals, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 
 	"github.com/kwanRoshi/B/go-migration/internal/types"
@@ -22,7 +24,7 @@ type TradingStorage struct {
 // Initialize initializes the storage collections
 func (s *TradingStorage) Initialize() error {
 	ctx := context.Background()
-	collections := []string{"orders", "trades", "positions"}
+	collections := []string{"orders", "trades", "positions", "equity"}
 	for _, col := range collections {
 		err := s.client.Database(s.db).CreateCollection(ctx, col)
 		if err != nil && !strings.Contains(err.Error(), "already exists") {
@@ -122,3 +124,36 @@ func (s *TradingStorage) GetPositions(userID string) ([]*types.Position, error)
 	}
 	return positions, nil
 }
+
+// SaveEquityPoint implements trading.Storage interface
+func (s *TradingStorage) SaveEquityPoint(point *types.EquityPoint) error {
+	collection := s.client.Database(s.db).Collection("equity")
+	ctx := context.Background()
+	_, err := collection.InsertOne(ctx, point)
+	return err
+}
+
+// LoadEquityCurve implements trading.Storage interface
+func (s *TradingStorage) LoadEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error) {
+	collection := s.client.Database(s.db).Collection("equity")
+	ctx := context.Background()
+	filter := bson.M{
+		"strategy": strategy,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []*types.EquityPoint
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
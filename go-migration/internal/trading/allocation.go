@@ -0,0 +1,185 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// AllocationConfig weights capital across strategies by the Sharpe ratio of
+// their recent equity curves. Weights are recomputed after every equity
+// snapshot, so Equity.Interval must be set.
+type AllocationConfig struct {
+	Enabled    bool          `yaml:"enabled"`
+	Window     time.Duration `yaml:"window"`      // Equity history the Sharpe ratio is computed over
+	MinWeight  float64       `yaml:"min_weight"`  // Floor on every strategy's weight
+	MaxWeight  float64       `yaml:"max_weight"`  // Cap on every strategy's weight, zero for none
+	ScaleSizes bool          `yaml:"scale_sizes"` // Scale signal amounts by their strategy's weight relative to an even split
+}
+
+// equitySharpe returns the Sharpe ratio of the per-snapshot returns of an
+// equity curve, zero when there are too few points or no variance
+func equitySharpe(points []*types.EquityPoint) float64 {
+	returns := make([]float64, 0, len(points))
+	for i := 1; i < len(points); i++ {
+		prev := points[i-1].Equity.InexactFloat64()
+		if prev == 0 {
+			continue
+		}
+		returns = append(returns, points[i].Equity.InexactFloat64()/prev-1)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	if stdDev == 0 {
+		return 0
+	}
+	return mean / stdDev
+}
+
+// allocationWeights turns Sharpe ratios into weights proportional to the
+// positive ratios that sum to one and respect the configured bounds. Weights
+// past a bound are pinned to it and the rest of the capital is shared again
+// among the others in proportion to their ratios, until no weight is out of
+// bounds. Strategies split evenly when none has a positive ratio or the
+// bounds cannot all be met.
+func (c AllocationConfig) allocationWeights(sharpes map[string]float64) map[string]float64 {
+	n := float64(len(sharpes))
+	weights := make(map[string]float64, len(sharpes))
+	if c.MinWeight*n > 1 || (c.MaxWeight > 0 && c.MaxWeight*n < 1) {
+		for name := range sharpes {
+			weights[name] = 1 / n
+		}
+		return weights
+	}
+
+	free := make(map[string]float64, len(sharpes))
+	for name, sharpe := range sharpes {
+		free[name] = math.Max(sharpe, 0)
+	}
+	remaining := 1.0
+	for len(free) > 0 {
+		var total float64
+		for _, raw := range free {
+			total += raw
+		}
+		for name, raw := range free {
+			if total > 0 {
+				weights[name] = remaining * raw / total
+			} else {
+				weights[name] = remaining / float64(len(free))
+			}
+		}
+
+		// Pin the side with the larger violation first, since pinning it
+		// moves the other weights towards the other bound
+		var under, over float64
+		for name := range free {
+			if weights[name] < c.MinWeight {
+				under += c.MinWeight - weights[name]
+			}
+			if c.MaxWeight > 0 && weights[name] > c.MaxWeight {
+				over += weights[name] - c.MaxWeight
+			}
+		}
+		if under == 0 && over == 0 {
+			break
+		}
+		for name := range free {
+			switch {
+			case under >= over && weights[name] < c.MinWeight:
+				weights[name] = c.MinWeight
+			case under < over && weights[name] > c.MaxWeight:
+				weights[name] = c.MaxWeight
+			default:
+				continue
+			}
+			remaining -= weights[name]
+			delete(free, name)
+		}
+	}
+	return weights
+}
+
+// RefreshAllocations recomputes every strategy's allocation weight from its
+// equity curve over the configured window
+func (e *Engine) RefreshAllocations() error {
+	e.mu.RLock()
+	names := make([]string, 0, len(e.executors))
+	for name := range e.executors {
+		names = append(names, name)
+	}
+	e.mu.RUnlock()
+
+	to := e.now()
+	sharpes := make(map[string]float64, len(names))
+	for _, name := range names {
+		points, err := e.storage.LoadEquityCurve(name, to.Add(-e.config.Allocation.Window), to)
+		if err != nil {
+			return fmt.Errorf("failed to load equity curve for %s: %w", name, err)
+		}
+		sharpes[name] = equitySharpe(points)
+	}
+
+	weights := e.config.Allocation.allocationWeights(sharpes)
+	for name, w := range weights {
+		metrics.StrategySharpe.WithLabelValues(name).Set(sharpes[name])
+		metrics.StrategyAllocationWeight.WithLabelValues(name).Set(w)
+	}
+
+	e.mu.Lock()
+	e.allocations = weights
+	e.mu.Unlock()
+	return nil
+}
+
+// AllocationWeight returns strategy's share of capital from the last
+// refresh, and false before weights have been computed for it
+func (e *Engine) AllocationWeight(strategy string) (float64, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	w, ok := e.allocations[strategy]
+	return w, ok
+}
+
+// scaleToAllocation scales signal's amount by its strategy's weight
+// relative to an even split when size scaling is enabled. Weights sum to
+// one, so with N strategies a weight of 1/N leaves sizes as they are and
+// larger weights scale them up. Callers hold the read lock.
+func (e *Engine) scaleToAllocation(signal *types.Signal) {
+	if !e.config.Allocation.Enabled || !e.config.Allocation.ScaleSizes {
+		return
+	}
+	w, ok := e.allocations[signal.Provider]
+	if !ok {
+		return
+	}
+
+	scale := decimal.NewFromFloat(w * float64(len(e.allocations)))
+	signal.Amount = signal.Amount.Mul(scale)
+	signal.Size = signal.Size.Mul(scale)
+	e.logger.Debug("Scaled signal to strategy allocation",
+		zap.String("symbol", signal.Symbol),
+		zap.String("strategy", signal.Provider),
+		zap.Float64("weight", w),
+		zap.String("scale", scale.String()))
+}
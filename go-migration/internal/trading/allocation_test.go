@@ -0,0 +1,132 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// equityCurve builds an equity curve from a starting equity and the return
+// of each step
+func equityCurve(strategy string, start float64, returns ...float64) []*types.EquityPoint {
+	at := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	equity := start
+	points := []*types.EquityPoint{{Strategy: strategy, Equity: decimal.NewFromFloat(equity), Timestamp: at}}
+	for i, r := range returns {
+		equity *= 1 + r
+		points = append(points, &types.EquityPoint{
+			Strategy:  strategy,
+			Equity:    decimal.NewFromFloat(equity),
+			Timestamp: at.Add(time.Duration(i+1) * time.Hour),
+		})
+	}
+	return points
+}
+
+func TestEngine_SharpeAllocation(t *testing.T) {
+	storage := new(MockStorage)
+	// Steady gains against similar average gains with large swings
+	storage.On("LoadEquityCurve", "steady", mock.Anything, mock.Anything).
+		Return(equityCurve("steady", 1000, 0.01, 0.012, 0.009, 0.011, 0.01), nil)
+	storage.On("LoadEquityCurve", "volatile", mock.Anything, mock.Anything).
+		Return(equityCurve("volatile", 1000, 0.08, -0.06, 0.07, -0.05, 0.01), nil)
+
+	engine := NewEngine(Config{
+		Allocation: AllocationConfig{Enabled: true, Window: 24 * time.Hour, MinWeight: 0.05, ScaleSizes: true},
+	}, zap.NewNop(), storage)
+	steady, volatile := &recordingExecutor{}, &recordingExecutor{}
+	require.NoError(t, engine.RegisterExecutor("steady", steady))
+	require.NoError(t, engine.RegisterExecutor("volatile", volatile))
+
+	require.NoError(t, engine.RefreshAllocations())
+	steadyWeight, ok := engine.AllocationWeight("steady")
+	require.True(t, ok)
+	volatileWeight, ok := engine.AllocationWeight("volatile")
+	require.True(t, ok)
+
+	assert.Greater(t, steadyWeight, volatileWeight)
+	assert.InDelta(t, 1.0, steadyWeight+volatileWeight, 1e-9)
+	// The floor keeps the weaker strategy funded
+	assert.GreaterOrEqual(t, volatileWeight, 0.05)
+
+	// Signal amounts are scaled by their strategy's weight relative to an
+	// even split, and the caller's signal is left as it was
+	signal := &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "steady", Amount: decimal.NewFromInt(100)}
+	require.NoError(t, engine.ProcessSignal(context.Background(), signal))
+	require.Len(t, steady.signals, 1)
+	assert.InDelta(t, 100*steadyWeight*2, steady.signals[0].Amount.InexactFloat64(), 1e-6)
+	assert.True(t, decimal.NewFromInt(100).Equal(signal.Amount))
+}
+
+func TestEngine_ReplayLeavesStoredSignals(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	store := storage.NewMemoryStorage()
+	ctx := context.Background()
+	require.NoError(t, store.SaveSignal(ctx, &types.Signal{
+		Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "steady",
+		Amount: decimal.NewFromInt(100), Timestamp: start,
+	}))
+
+	history := new(MockStorage)
+	history.On("LoadEquityCurve", "steady", mock.Anything, mock.Anything).
+		Return(equityCurve("steady", 1000, 0.01, 0.012, 0.009, 0.011, 0.01), nil)
+	history.On("LoadEquityCurve", "volatile", mock.Anything, mock.Anything).
+		Return(equityCurve("volatile", 1000, 0.08, -0.06, 0.07, -0.05, 0.01), nil)
+
+	engine := NewEngine(Config{
+		Allocation: AllocationConfig{Enabled: true, Window: 24 * time.Hour, ScaleSizes: true},
+	}, zap.NewNop(), history)
+	engine.SetSignalStore(store)
+	steady := &recordingExecutor{}
+	require.NoError(t, engine.RegisterExecutor("steady", steady))
+	require.NoError(t, engine.RegisterExecutor("volatile", &recordingExecutor{}))
+	require.NoError(t, engine.RefreshAllocations())
+
+	// Replaying twice scales the same stored amount both times
+	for i := 0; i < 2; i++ {
+		require.NoError(t, engine.ReplaySignals(ctx, start.Add(-time.Second), start.Add(time.Second)))
+	}
+	require.Len(t, steady.signals, 2)
+	assert.True(t, steady.signals[0].Amount.Equal(steady.signals[1].Amount))
+
+	stored, err := store.LoadSignals(ctx, start.Add(-time.Second), start.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.True(t, decimal.NewFromInt(100).Equal(stored[0].Amount))
+	assert.Empty(t, stored[0].Venue)
+}
+
+func TestAllocationWeights_ClampAndEvenSplit(t *testing.T) {
+	config := AllocationConfig{MaxWeight: 0.5}
+
+	// A dominant strategy is capped and its excess goes to the others
+	weights := config.allocationWeights(map[string]float64{"a": 3, "b": 1, "c": 0})
+	assert.InDelta(t, 0.5, weights["a"], 1e-9)
+	assert.InDelta(t, 0.5, weights["b"], 1e-9)
+	assert.Zero(t, weights["c"])
+	for _, w := range weights {
+		assert.LessOrEqual(t, w, 0.5)
+	}
+
+	// Pinning one strategy to the floor can push another past the cap,
+	// which is then pinned in turn
+	config = AllocationConfig{MinWeight: 0.1, MaxWeight: 0.6}
+	weights = config.allocationWeights(map[string]float64{"a": 9, "b": 1, "c": 0})
+	assert.InDelta(t, 0.6, weights["a"], 1e-9)
+	assert.InDelta(t, 0.3, weights["b"], 1e-9)
+	assert.InDelta(t, 0.1, weights["c"], 1e-9)
+
+	// Without any positive Sharpe the capital splits evenly
+	weights = config.allocationWeights(map[string]float64{"a": -1, "b": 0})
+	assert.Equal(t, 0.5, weights["a"])
+	assert.Equal(t, 0.5, weights["b"])
+}
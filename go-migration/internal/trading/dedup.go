@@ -0,0 +1,73 @@
+package trading
+
+import (
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// seenOrder is a placed order remembered for deduplication
+type seenOrder struct {
+	key   string
+	order *types.Order
+	at    time.Time
+}
+
+// orderDedup remembers orders placed within window by idempotency key so
+// retried submissions resolve to the original order. Entries are kept in
+// placement order and dropped once they leave the window.
+type orderDedup struct {
+	window time.Duration
+	seen   map[string]seenOrder
+	queue  []seenOrder
+}
+
+func newOrderDedup(window time.Duration) *orderDedup {
+	return &orderDedup{
+		window: window,
+		seen:   make(map[string]seenOrder),
+	}
+}
+
+// dedupKey identifies a submission: the client request id when the client
+// sent one, otherwise the order id
+func dedupKey(order *types.Order) string {
+	if order.ClientRequestID != "" {
+		return order.UserID + "/" + order.ClientRequestID
+	}
+	return order.ID
+}
+
+// lookup returns the order placed under key within the window
+func (d *orderDedup) lookup(key string, now time.Time) (*types.Order, bool) {
+	d.expire(now)
+	if key == "" {
+		return nil, false
+	}
+	seen, ok := d.seen[key]
+	return seen.order, ok
+}
+
+// remember records order as placed under key at now
+func (d *orderDedup) remember(key string, order *types.Order, now time.Time) {
+	if key == "" {
+		return
+	}
+	entry := seenOrder{key: key, order: order, at: now}
+	d.seen[key] = entry
+	d.queue = append(d.queue, entry)
+}
+
+// expire forgets orders placed before the window
+func (d *orderDedup) expire(now time.Time) {
+	cutoff := now.Add(-d.window)
+	n := 0
+	for n < len(d.queue) && !d.queue[n].at.After(cutoff) {
+		entry := d.queue[n]
+		if current, ok := d.seen[entry.key]; ok && current.at.Equal(entry.at) {
+			delete(d.seen, entry.key)
+		}
+		n++
+	}
+	d.queue = d.queue[n:]
+}
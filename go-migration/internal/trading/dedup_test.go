@@ -0,0 +1,48 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_PlaceOrderDeduplicates(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("SaveOrder", mock.Anything).Return(nil)
+
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{MaxOrderSize: 100, DedupWindow: time.Minute}, zap.NewNop(), storage)
+	engine.now = func() time.Time { return now }
+
+	order := func(id string) *types.Order {
+		return &types.Order{ID: id, UserID: "alice", Symbol: "BONK", Size: decimal.NewFromInt(10), ClientRequestID: "req-1"}
+	}
+
+	ctx := context.Background()
+	duplicates := testutil.ToFloat64(metrics.DuplicateOrders)
+	require.NoError(t, engine.PlaceOrder(ctx, order("a")))
+
+	// A retry that timed out client side carries a fresh id but the same key
+	retry := order("b")
+	require.NoError(t, engine.PlaceOrder(ctx, retry))
+	assert.Equal(t, "a", retry.ID)
+	assert.Len(t, engine.orders, 1)
+	storage.AssertNumberOfCalls(t, "SaveOrder", 1)
+	assert.Equal(t, duplicates+1, testutil.ToFloat64(metrics.DuplicateOrders))
+
+	// Once the window passes the key is forgotten
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, engine.PlaceOrder(ctx, order("c")))
+	assert.Len(t, engine.orders, 2)
+	assert.Len(t, engine.dedup.queue, 1)
+}
@@ -0,0 +1,67 @@
+package trading
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// beginExecution registers signal as in flight so Stop waits for it, and
+// refuses it once the engine is draining. The returned func marks it done.
+func (e *Engine) beginExecution(signal *types.Signal) (func(), error) {
+	e.drainMu.Lock()
+	defer e.drainMu.Unlock()
+
+	if e.draining {
+		return nil, fmt.Errorf("engine is stopping, not accepting %s signal for %s", signal.Type, signal.Symbol)
+	}
+	e.inflight.Add(1)
+	e.executing[signal] = struct{}{}
+	return func() {
+		e.drainMu.Lock()
+		delete(e.executing, signal)
+		e.drainMu.Unlock()
+		e.inflight.Done()
+	}, nil
+}
+
+// logAbandoned logs the executions still in flight and the orders left open
+// when Stop gives up waiting
+func (e *Engine) logAbandoned() {
+	e.drainMu.Lock()
+	for signal := range e.executing {
+		e.logger.Warn("Abandoned in-flight execution",
+			zap.String("symbol", signal.Symbol),
+			zap.String("type", string(signal.Type)),
+			zap.String("provider", signal.Provider),
+			zap.String("amount", signal.Amount.String()))
+	}
+	e.drainMu.Unlock()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, order := range e.orders {
+		if isOpen(order) {
+			e.logger.Warn("Abandoned open order",
+				zap.String("order_id", order.ID),
+				zap.String("symbol", order.Symbol),
+				zap.String("status", string(order.Status)))
+		}
+	}
+}
+
+// savePositions persists every position, logging those that fail
+func (e *Engine) savePositions() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, pos := range e.positions {
+		if err := e.storage.SavePosition(pos); err != nil {
+			e.logger.Error("Failed to save position",
+				zap.String("symbol", pos.Symbol),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// blockingExecutor holds each trade until release is closed
+type blockingExecutor struct {
+	recordingExecutor
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
+	b.started <- struct{}{}
+	<-b.release
+	return b.recordingExecutor.ExecuteTrade(ctx, signal)
+}
+
+func newDrainEngine(t *testing.T) (*Engine, *blockingExecutor, *MockStorage) {
+	storage := new(MockStorage)
+	storage.On("SavePosition", mock.Anything).Return(nil)
+	engine := NewEngine(Config{UpdateInterval: time.Hour}, zap.NewNop(), storage)
+	exec := &blockingExecutor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	engine.positions["BONK"] = types.NewPosition("BONK", decimal.NewFromInt(10), decimal.NewFromInt(1))
+	require.NoError(t, engine.Start(context.Background()))
+	return engine, exec, storage
+}
+
+func buySignal() *types.Signal {
+	return &types.Signal{
+		Symbol:   "BONK",
+		Type:     types.SignalTypeBuy,
+		Amount:   decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(1),
+		Provider: "pump.fun",
+	}
+}
+
+func TestEngine_StopWaitsForInFlightTrades(t *testing.T) {
+	engine, exec, storage := newDrainEngine(t)
+
+	processed := make(chan error, 1)
+	go func() { processed <- engine.ProcessSignal(context.Background(), buySignal()) }()
+	<-exec.started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- engine.Stop(context.Background()) }()
+
+	// New signals are refused while draining
+	require.Eventually(t, func() bool {
+		engine.drainMu.Lock()
+		defer engine.drainMu.Unlock()
+		return engine.draining
+	}, time.Second, time.Millisecond)
+	assert.Error(t, engine.ProcessSignal(context.Background(), buySignal()))
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the in-flight trade finished")
+	default:
+	}
+
+	close(exec.release)
+	require.NoError(t, <-processed)
+	require.NoError(t, <-stopped)
+	assert.Len(t, exec.signals, 1)
+	storage.AssertCalled(t, "SavePosition", engine.positions["BONK"])
+	assert.Error(t, engine.Stop(context.Background()))
+}
+
+func TestEngine_StopGivesUpAtDeadline(t *testing.T) {
+	engine, exec, storage := newDrainEngine(t)
+	defer close(exec.release)
+
+	go engine.ProcessSignal(context.Background(), buySignal())
+	<-exec.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := engine.Stop(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	storage.AssertCalled(t, "SavePosition", engine.positions["BONK"])
+}
+
+func TestEngine_StopNotBlockedByStuckExecutionAndWriter(t *testing.T) {
+	engine, exec, storage := newDrainEngine(t)
+	defer close(exec.release)
+
+	go engine.ProcessSignal(context.Background(), buySignal())
+	<-exec.started
+
+	// A writer arrives while the execution is stuck
+	applied := make(chan error, 1)
+	go func() {
+		applied <- engine.ApplyFill(&types.Trade{Symbol: "WIF", Side: types.OrderSideBuy, Size: decimal.NewFromInt(1), Price: decimal.NewFromInt(2)})
+	}()
+	select {
+	case err := <-applied:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("fill blocked behind the stuck execution")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- engine.Stop(ctx) }()
+
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked past its deadline")
+	}
+	storage.AssertCalled(t, "SavePosition", engine.positions["BONK"])
+}
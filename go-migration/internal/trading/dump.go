@@ -0,0 +1,126 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// StateDump is a point-in-time snapshot of the engine for diagnosing a
+// running bot without a dashboard
+type StateDump struct {
+	Time       time.Time                       `json:"time"`
+	Positions  []*types.Position               `json:"positions"`
+	OpenOrders []*types.Order                  `json:"open_orders"`
+	Portfolio  types.PortfolioSummary          `json:"portfolio"`
+	Realized   map[string]decimal.Decimal      `json:"realized_pnl"`
+	Shortfall  map[string]types.ShortfallStats `json:"shortfall"`
+	// ExecutorPositions are the positions each registered executor reports
+	// holding, which may differ from the engine's book
+	ExecutorPositions map[string][]*types.Position `json:"executor_positions"`
+}
+
+// isOpen reports whether order can still fill
+func isOpen(order *types.Order) bool {
+	switch order.Status {
+	case types.OrderStatusPending, types.OrderStatusNew, types.OrderStatusPartial:
+		return true
+	}
+	return false
+}
+
+// DumpState writes the position book, the positions of every executor,
+// open orders and key metrics as JSON to path, replacing any earlier dump
+// there. An empty path logs the dump instead. The dump holds copies taken
+// under the engine lock, and executors are asked for their positions after
+// it is released.
+func (e *Engine) DumpState(path string) error {
+	e.mu.RLock()
+	dump := &StateDump{
+		Time:       e.now(),
+		Positions:  make([]*types.Position, 0, len(e.positions)),
+		OpenOrders: make([]*types.Order, 0),
+		Realized:   make(map[string]decimal.Decimal, len(e.realized)),
+		Shortfall:  make(map[string]types.ShortfallStats, len(e.shortfall)),
+
+		ExecutorPositions: make(map[string][]*types.Position, len(e.executors)),
+	}
+	for _, pos := range e.positions {
+		dump.Positions = append(dump.Positions, copyPosition(pos))
+	}
+	for _, order := range e.orders {
+		if isOpen(order) {
+			open := *order
+			dump.OpenOrders = append(dump.OpenOrders, &open)
+		}
+	}
+	for strategy, pnl := range e.realized {
+		dump.Realized[strategy] = pnl
+	}
+	for strategy, stats := range e.shortfall {
+		dump.Shortfall[strategy] = *stats
+	}
+	portfolioConfig := e.config.Portfolio
+	executors := e.copyExecutors()
+	e.mu.RUnlock()
+
+	for name, exec := range executors {
+		held := exec.GetPositions()
+		positions := make([]*types.Position, 0, len(held))
+		for _, pos := range held {
+			positions = append(positions, copyPosition(pos))
+		}
+		sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+		dump.ExecutorPositions[name] = positions
+	}
+	portfolio := &types.Portfolio{Positions: dump.Positions, Config: portfolioConfig}
+	dump.Portfolio = portfolio.Summary()
+
+	sort.Slice(dump.Positions, func(i, j int) bool { return dump.Positions[i].Symbol < dump.Positions[j].Symbol })
+	sort.Slice(dump.OpenOrders, func(i, j int) bool { return dump.OpenOrders[i].ID < dump.OpenOrders[j].ID })
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state dump: %w", err)
+	}
+
+	if path == "" {
+		e.logger.Info("Engine state dump", zap.ByteString("state", data))
+		return nil
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state dump: %w", err)
+	}
+	e.logger.Info("Wrote engine state dump",
+		zap.String("path", path),
+		zap.Int("positions", len(dump.Positions)),
+		zap.Int("open_orders", len(dump.OpenOrders)))
+	return nil
+}
+
+// copyPosition returns a copy of the reported fields of pos, so a dump does
+// not share positions the book or an executor goes on updating
+func copyPosition(pos *types.Position) *types.Position {
+	return &types.Position{
+		UserID:              pos.UserID,
+		Symbol:              pos.Symbol,
+		Size:                pos.Size,
+		Value:               pos.Value,
+		EntryPrice:          pos.EntryPrice,
+		CurrentPrice:        pos.CurrentPrice,
+		UnrealizedPnL:       pos.UnrealizedPnL,
+		RealizedPnL:         pos.RealizedPnL,
+		CreatedAt:           pos.CreatedAt,
+		UpdatedAt:           pos.UpdatedAt,
+		StopLoss:            pos.StopLoss,
+		TakeProfit:          append([]decimal.Decimal(nil), pos.TakeProfit...),
+		HighWaterMark:       pos.HighWaterMark,
+		TrailingStopPercent: pos.TrailingStopPercent,
+	}
+}
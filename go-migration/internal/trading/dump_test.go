@@ -0,0 +1,47 @@
+package trading
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_DumpState(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), new(MockStorage))
+	engine.positions["BONK"] = types.NewPosition("BONK", decimal.NewFromInt(10), decimal.NewFromInt(2))
+	engine.positions["WIF"] = types.NewPosition("WIF", decimal.NewFromInt(-5), decimal.NewFromInt(3))
+	engine.orders["open"] = &types.Order{ID: "open", Symbol: "BONK", Status: types.OrderStatusNew}
+	engine.orders["done"] = &types.Order{ID: "done", Symbol: "BONK", Status: types.OrderStatusFilled}
+	engine.RecordRealizedPnL("pump.fun", decimal.NewFromInt(7))
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"WIF": {Symbol: "WIF", Size: decimal.NewFromInt(3), EntryPrice: decimal.NewFromInt(2)},
+	}}}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, engine.DumpState(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var dump StateDump
+	require.NoError(t, json.Unmarshal(data, &dump))
+
+	require.Len(t, dump.Positions, 2)
+	assert.Equal(t, "BONK", dump.Positions[0].Symbol)
+	assert.True(t, dump.Positions[1].Size.Equal(decimal.NewFromInt(-5)))
+	require.Len(t, dump.OpenOrders, 1)
+	assert.Equal(t, "open", dump.OpenOrders[0].ID)
+	assert.Equal(t, 2, dump.Portfolio.Positions)
+	assert.True(t, dump.Realized["pump.fun"].Equal(decimal.NewFromInt(7)))
+	require.Len(t, dump.ExecutorPositions["pump.fun"], 1)
+	assert.Equal(t, "WIF", dump.ExecutorPositions["pump.fun"][0].Symbol)
+	assert.False(t, exec.locked)
+}
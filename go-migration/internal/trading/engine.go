@@ -9,6 +9,7 @@ import (
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
 	"github.com/kwanRoshi/B/go-migration/internal/trading/executor"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
@@ -20,6 +21,34 @@ type Config struct {
 	MinOrderSize   float64       `yaml:"min_order_size"`
 	MaxPositions   int          `yaml:"max_positions"`
 	UpdateInterval time.Duration `yaml:"update_interval"`
+	Fees           types.FeeSchedule `yaml:"fees"`
+	ClockSkew      ClockSkewConfig   `yaml:"clock_skew"`
+	Throttle       ThrottleConfig    `yaml:"throttle"`
+	Equity         EquityConfig      `yaml:"equity"`
+	AllowReplace   bool              `yaml:"allow_replace"`
+	Funding        types.FundingConfig `yaml:"funding"`
+	Hedge          HedgeConfig         `yaml:"hedge"`
+	Portfolio      types.PortfolioConfig `yaml:"portfolio"`
+	AllowConditional bool                `yaml:"allow_conditional"`
+	ScheduledClose   ScheduledCloseConfig `yaml:"scheduled_close"`
+	VenueLimits      VenueLimitConfig     `yaml:"venue_limits"`
+	Allocation       AllocationConfig     `yaml:"allocation"`
+	Watchdog         WatchdogConfig       `yaml:"watchdog"`
+	// DedupWindow is how long placed orders are remembered so retried
+	// submissions are not placed twice. Zero disables deduplication.
+	DedupWindow time.Duration `yaml:"dedup_window"`
+	Summary     SummaryConfig `yaml:"summary"`
+	// PositionConflicts selects whether positions in one symbol held by
+	// several executors are merged or flagged when reported
+	PositionConflicts PositionConflictMode `yaml:"position_conflicts"`
+	Shadow            ShadowConfig         `yaml:"shadow"`
+}
+
+// ClockSkewConfig bounds how far a signal timestamp may drift from the
+// local clock before it is clamped
+type ClockSkewConfig struct {
+	FutureTolerance time.Duration `yaml:"future_tolerance"`
+	MaxAge          time.Duration `yaml:"max_age"`
 }
 
 type Strategy interface {
@@ -36,6 +65,8 @@ type Storage interface {
 	GetOrders(userID string) ([]*types.Order, error)
 	GetPosition(symbol string) (*types.Position, error)
 	GetPositions(userID string) ([]*types.Position, error)
+	SaveEquityPoint(point *types.EquityPoint) error
+	LoadEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error)
 }
 
 type Engine struct {
@@ -47,12 +78,36 @@ type Engine struct {
 	strategies map[string]Strategy
 	executors  map[string]executor.TradingExecutor
 	stop       chan struct{}
+	// drainMu guards isRunning, draining and the in-flight executions
+	// Stop waits for, apart from mu so draining never waits on mu
+	drainMu    sync.Mutex
 	isRunning  bool
+	draining   bool
+	inflight   sync.WaitGroup
+	executing  map[*types.Signal]struct{}
+	throttle   *LossThrottle
+	signals    types.SignalStore
+	realized   map[string]decimal.Decimal
+	rMultiples map[string][]float64
+	shortfall  map[string]*types.ShortfallStats
+	fills      map[string][]turnoverFill
+	funding    types.FundingProvider
+	lastClose  time.Time
+	allocations map[string]float64
+	loops      map[string]ExecutorLoop
+	dedup      *orderDedup
+	shadow     *shadowAccount
+	reservations venueReservations
+	period     periodStats
+	summarySink SummarySink
+	outcomes   SignalOutcomes
+	entries    map[string]types.Signal // Signal that opened the position in each symbol, kept while outcomes are reported
+	now        func() time.Time
 	mu         sync.RWMutex
 }
 
 func NewEngine(config Config, logger *zap.Logger, storage Storage) *Engine {
-	return &Engine{
+	e := &Engine{
 		logger:     logger,
 		config:     config,
 		storage:    storage,
@@ -61,7 +116,25 @@ func NewEngine(config Config, logger *zap.Logger, storage Storage) *Engine {
 		strategies: make(map[string]Strategy),
 		executors:  make(map[string]executor.TradingExecutor),
 		stop:       make(chan struct{}),
+		realized:   make(map[string]decimal.Decimal),
+		rMultiples: make(map[string][]float64),
+		shortfall:  make(map[string]*types.ShortfallStats),
+		fills:      make(map[string][]turnoverFill),
+		loops:      make(map[string]ExecutorLoop),
+		executing:  make(map[*types.Signal]struct{}),
+		reservations: venueReservations{pending: make(map[string]decimal.Decimal)},
+		now:        time.Now,
+	}
+	if config.Throttle.Enabled {
+		e.throttle = NewLossThrottle(config.Throttle)
+	}
+	if config.DedupWindow > 0 {
+		e.dedup = newOrderDedup(config.DedupWindow)
 	}
+	if config.Summary.WebhookURL != "" {
+		e.summarySink = NewWebhookSummarySink(config.Summary.WebhookURL)
+	}
+	return e
 }
 
 func (e *Engine) RegisterExecutor(name string, exec executor.TradingExecutor) error {
@@ -90,96 +163,513 @@ func (e *Engine) RegisterStrategy(strategy Strategy) error {
 }
 
 func (e *Engine) ProcessSignal(ctx context.Context, signal *types.Signal) error {
+	done, err := e.beginExecution(signal)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// Routing scales, reroutes and clamps the signal, so work on a copy and
+	// leave the caller's, which a signal store may also hold, untouched
+	routed := *signal
+	trade, err := e.processSignal(ctx, &routed)
+	if err != nil || trade == nil {
+		return err
+	}
+	e.bookFill(trade)
+	e.noteEntry(&routed)
+	return nil
+}
+
+// processSignal routes and executes signal, returning the trade it made on
+// its venue or nil when it only traded in the shadow account. The engine
+// lock is not held while the executor trades, so a stuck execution cannot
+// hold up writers or Stop behind it.
+func (e *Engine) processSignal(ctx context.Context, signal *types.Signal) (*types.Trade, error) {
+	if signal.Provider == "" {
+		return nil, fmt.Errorf("signal provider not specified")
+	}
+
+	held := e.executorSymbols(signal)
+	exposures := e.venueExposures(signal)
+	venue, executor, release, err := e.admitSignal(ctx, signal, held, exposures)
+	if err != nil || executor == nil {
+		return nil, err
+	}
+	defer release()
+
+	trade, err := executeSignal(ctx, executor, signal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute trade: %w", err)
+	}
+
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	if signal.Provider == "" {
-		return fmt.Errorf("signal provider not specified")
+	if e.throttle != nil {
+		e.throttle.MarkTrade(signal.Provider)
 	}
 
-	executor, ok := e.executors[signal.Provider]
-	if !ok {
-		return fmt.Errorf("executor %s not found", signal.Provider)
+	if e.shadowing() {
+		if err := e.mirrorToShadow(ctx, signal, true); err != nil {
+			e.logger.Error("Failed to mirror signal into shadow account",
+				zap.String("symbol", signal.Symbol),
+				zap.Error(err))
+		}
 	}
 
-	if err := executor.ExecuteTrade(ctx, signal); err != nil {
-		return fmt.Errorf("failed to execute trade: %w", err)
+	trade.Provider = venue
+	trade.Fee = e.calculateFee(trade)
+	return trade, nil
+}
+
+// admitSignal scales and routes signal and runs the pre-trade checks under
+// the read lock. It returns the venue and executor to trade signal on and
+// the release for its venue reservation, or a nil executor when signal
+// traded only in the shadow account. held are the symbols executors hold
+// and exposures the venue exposures gathered by venueExposures.
+func (e *Engine) admitSignal(ctx context.Context, signal *types.Signal, held map[string]bool, exposures map[string]decimal.Decimal) (string, executor.TradingExecutor, func(), error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	e.scaleToAllocation(signal)
+
+	venue, executor, release, err := e.routeVenue(signal, exposures)
+	if err != nil {
+		return "", nil, nil, err
 	}
+	signal.Venue = venue
 
-	return nil
+	e.clampSignalTimestamp(signal)
+
+	if e.shadowOnly(signal.Symbol) {
+		release()
+		return "", nil, nil, e.mirrorToShadow(ctx, signal, false)
+	}
+
+	if err := e.checkMaxPositions(signal, held); err != nil {
+		release()
+		return "", nil, nil, err
+	}
+
+	if e.throttle != nil {
+		if wait, ok := e.throttle.Allow(signal.Provider); !ok {
+			release()
+			return "", nil, nil, fmt.Errorf("trading throttled for %s after rapid losses, retry in %s", signal.Provider, wait)
+		}
+	}
+	return venue, executor, release, nil
 }
 
-func (e *Engine) Start(ctx context.Context) error {
+// executeSignal executes signal on exec and returns the trade it made, as
+// reported by executors implementing executor.FillReporter and otherwise
+// taken to have filled in full at the signal price
+func executeSignal(ctx context.Context, exec executor.TradingExecutor, signal *types.Signal) (*types.Trade, error) {
+	if reporter, ok := exec.(executor.FillReporter); ok {
+		trade, err := reporter.ExecuteTradeFill(ctx, signal)
+		if err != nil {
+			return nil, err
+		}
+		if trade.DecisionPrice.IsZero() {
+			trade.DecisionPrice = signal.Price
+		}
+		return trade, nil
+	}
+
+	if err := exec.ExecuteTrade(ctx, signal); err != nil {
+		return nil, err
+	}
+	side := types.OrderSideBuy
+	if signal.Type == types.SignalTypeSell {
+		side = types.OrderSideSell
+	}
+	return &types.Trade{
+		Symbol:        signal.Symbol,
+		Side:          side,
+		Price:         signal.Price,
+		DecisionPrice: signal.Price,
+		Size:          signal.Amount,
+		Status:        types.OrderStatusFilled,
+		Timestamp:     signal.Timestamp,
+	}, nil
+}
+
+// bookFill nets a trade an executor filled into the engine's position book
+// and records its shortfall and turnover against the venue that filled it.
+// Callers must not hold e.mu.
+func (e *Engine) bookFill(trade *types.Trade) {
+	if err := e.ApplyFill(trade); err != nil {
+		e.logger.Error("Failed to book fill",
+			zap.String("symbol", trade.Symbol),
+			zap.String("provider", trade.Provider),
+			zap.Error(err))
+		return
+	}
+	if !trade.DecisionPrice.IsPositive() {
+		return
+	}
+	if err := e.RecordFill(trade.Provider, trade); err != nil {
+		e.logger.Error("Failed to record fill",
+			zap.String("symbol", trade.Symbol),
+			zap.String("provider", trade.Provider),
+			zap.Error(err))
+	}
+}
+
+// checkMaxPositions rejects a buy that would open a position in a new
+// symbol once Config.MaxPositions are already open. Open positions are
+// those in the engine's book and the held symbols executors report, as
+// gathered by executorSymbols. Sells and buys into an existing position are
+// always allowed. Callers hold e.mu.
+func (e *Engine) checkMaxPositions(signal *types.Signal, held map[string]bool) error {
+	if e.config.MaxPositions <= 0 || signal.Type != types.SignalTypeBuy {
+		return nil
+	}
+
+	symbols := make(map[string]bool, len(held))
+	for symbol := range held {
+		symbols[symbol] = true
+	}
+	for symbol, pos := range e.positions {
+		if !pos.Size.IsZero() {
+			symbols[symbol] = true
+		}
+	}
+	if symbols[signal.Symbol] {
+		return nil
+	}
+
+	open := len(symbols)
+	if open < e.config.MaxPositions {
+		return nil
+	}
+
+	metrics.MaxPositionsRejections.Inc()
+	e.logger.Warn("Rejected signal opening a position past the position limit",
+		zap.String("symbol", signal.Symbol),
+		zap.Int("open_positions", open),
+		zap.Int("max_positions", e.config.MaxPositions))
+	return fmt.Errorf("opening %s would exceed the limit of %d open positions", signal.Symbol, e.config.MaxPositions)
+}
+
+// executorSymbols returns the symbols executors report open positions in
+// when signal is subject to the position limit, and nil otherwise.
+// Executors are asked without the engine lock, since one may be blocked on
+// a trade in flight.
+func (e *Engine) executorSymbols(signal *types.Signal) map[string]bool {
+	if e.config.MaxPositions <= 0 || signal.Type != types.SignalTypeBuy {
+		return nil
+	}
+
+	e.mu.RLock()
+	executors := e.copyExecutors()
+	e.mu.RUnlock()
+
+	symbols := make(map[string]bool)
+	for _, exec := range executors {
+		for _, pos := range exec.GetPositions() {
+			if !pos.Size.IsZero() {
+				symbols[pos.Symbol] = true
+			}
+		}
+	}
+	return symbols
+}
+
+// copyExecutors returns a copy of the registered executors, so they can be
+// asked for their positions once e.mu is released. Callers hold e.mu.
+func (e *Engine) copyExecutors() map[string]executor.TradingExecutor {
+	executors := make(map[string]executor.TradingExecutor, len(e.executors))
+	for name, exec := range e.executors {
+		executors[name] = exec
+	}
+	return executors
+}
+
+// SetFundingProvider enables funding accounting for the symbols listed in
+// Config.Funding using rates from provider
+func (e *Engine) SetFundingProvider(provider types.FundingProvider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.funding = provider
+}
+
+// applyFunding charges the current funding rate against the realized PnL of
+// every open position in a funded symbol. Negative sizes are short positions.
+// Rates are fetched before the engine lock is taken to apply them, so a slow
+// funding provider cannot hold up trading.
+func (e *Engine) applyFunding(ctx context.Context, at time.Time) {
+	e.mu.RLock()
+	provider := e.funding
+	var symbols []string
+	for symbol, pos := range e.positions {
+		if e.config.Funding.Applies(symbol) && !pos.Size.IsZero() {
+			symbols = append(symbols, symbol)
+		}
+	}
+	e.mu.RUnlock()
+
+	rates := make(map[string]decimal.Decimal, len(symbols))
+	for _, symbol := range symbols {
+		rate, err := provider.FundingRate(ctx, symbol, at)
+		if err != nil {
+			e.logger.Error("Failed to get funding rate",
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			continue
+		}
+		rates[symbol] = rate
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	for symbol, rate := range rates {
+		// The position may have closed while the rates were fetched
+		pos, ok := e.positions[symbol]
+		if !ok || pos.Size.IsZero() {
+			continue
+		}
+
+		price := pos.CurrentPrice
+		if price.IsZero() {
+			price = pos.EntryPrice
+		}
+		payment := types.FundingPayment(pos.Size.IsPositive(), pos.Size.Mul(price), rate)
+		pos.RealizedPnL = pos.RealizedPnL.Sub(payment)
+		pos.UpdatedAt = at
+
+		if err := e.storage.SavePosition(pos); err != nil {
+			e.logger.Error("Failed to save position after funding",
+				zap.String("symbol", symbol),
+				zap.Error(err))
+		}
+	}
+}
+
+// SetSignalStore sets the store that ReplaySignals reads captured signals from
+func (e *Engine) SetSignalStore(store types.SignalStore) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.signals = store
+}
+
+// ReplaySignals feeds signals captured between from and to back through
+// ProcessSignal in timestamp order. Failures on individual signals are
+// logged and do not stop the replay.
+func (e *Engine) ReplaySignals(ctx context.Context, from, to time.Time) error {
+	e.mu.RLock()
+	store := e.signals
+	e.mu.RUnlock()
+
+	if store == nil {
+		return fmt.Errorf("signal store not configured")
+	}
+
+	signals, err := store.LoadSignals(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to load signals: %w", err)
+	}
+
+	e.logger.Info("Replaying signals",
+		zap.Int("count", len(signals)),
+		zap.Time("from", from),
+		zap.Time("to", to))
+
+	for _, signal := range signals {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := e.ProcessSignal(ctx, signal); err != nil {
+			e.logger.Warn("Failed to replay signal",
+				zap.String("symbol", signal.Symbol),
+				zap.Time("timestamp", signal.Timestamp),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// RecordRealizedPnL feeds a realized trade result for a strategy into the
+// loss-velocity throttle and the current performance summary period
+func (e *Engine) RecordRealizedPnL(strategy string, pnl decimal.Decimal) {
+	e.mu.Lock()
+	e.realized[strategy] = e.realized[strategy].Add(pnl)
+	e.recordPeriodTrade(pnl)
+	e.mu.Unlock()
+
+	if e.throttle == nil {
+		return
+	}
+	e.throttle.RecordPnL(strategy, pnl)
+}
+
+// clampSignalTimestamp replaces future-dated or stale signal timestamps
+// with the local clock so age-based logic downstream is not thrown off by
+// a provider with a skewed clock
+func (e *Engine) clampSignalTimestamp(signal *types.Signal) {
+	now := e.now()
+	skew := e.config.ClockSkew
+
+	var direction string
+	switch {
+	case signal.Timestamp.IsZero():
+		return
+	case skew.FutureTolerance > 0 && signal.Timestamp.After(now.Add(skew.FutureTolerance)):
+		direction = "future"
+	case skew.MaxAge > 0 && signal.Timestamp.Before(now.Add(-skew.MaxAge)):
+		direction = "past"
+	default:
+		return
+	}
+
+	metrics.ClockSkewDetected.WithLabelValues(signal.Provider, direction).Inc()
+	e.logger.Warn("Clock skew detected in signal timestamp",
+		zap.String("symbol", signal.Symbol),
+		zap.String("provider", signal.Provider),
+		zap.String("direction", direction),
+		zap.Time("timestamp", signal.Timestamp),
+		zap.Duration("skew", signal.Timestamp.Sub(now)))
+	signal.Timestamp = now
+}
+
+func (e *Engine) Start(ctx context.Context) error {
+	e.drainMu.Lock()
+	defer e.drainMu.Unlock()
+
 	if e.isRunning {
 		return fmt.Errorf("engine already running")
 	}
 
 	e.isRunning = true
+	e.draining = false
+	e.stop = make(chan struct{})
 	e.logger.Info("Trading engine started")
 
-	go e.run(ctx)
+	go e.run(ctx, e.stop)
 	return nil
 }
 
-func (e *Engine) Stop() error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	if !e.isRunning {
+// Stop drains the engine: it stops accepting new signals and trades, waits
+// for in-flight executions to finish and persists every position before
+// returning. If ctx ends first the executions still in flight are logged as
+// abandoned, positions are persisted as they stand and ctx's error is
+// returned.
+func (e *Engine) Stop(ctx context.Context) error {
+	e.drainMu.Lock()
+	if !e.isRunning || e.draining {
+		e.drainMu.Unlock()
 		return fmt.Errorf("engine not running")
 	}
-
+	e.draining = true
 	close(e.stop)
+	e.drainMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		e.inflight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		e.logAbandoned()
+		err = fmt.Errorf("engine stopped before in-flight trades finished: %w", ctx.Err())
+	}
+
+	e.savePositions()
+
+	e.drainMu.Lock()
 	e.isRunning = false
+	e.drainMu.Unlock()
 	e.logger.Info("Trading engine stopped")
-	return nil
+	return err
 }
 
-func (e *Engine) run(ctx context.Context) {
+func (e *Engine) run(ctx context.Context, stop <-chan struct{}) {
 	ticker := time.NewTicker(e.config.UpdateInterval)
 	defer ticker.Stop()
 
+	var equityTick <-chan time.Time
+	if e.config.Equity.Interval > 0 {
+		equityTicker := time.NewTicker(e.config.Equity.Interval)
+		defer equityTicker.Stop()
+		equityTick = equityTicker.C
+	}
+
+	var fundingTick <-chan time.Time
+	if e.funding != nil && e.config.Funding.Interval > 0 {
+		fundingTicker := time.NewTicker(e.config.Funding.Interval)
+		defer fundingTicker.Stop()
+		fundingTick = fundingTicker.C
+	}
+
+	var watchdogTick <-chan time.Time
+	if e.config.Watchdog.Interval > 0 {
+		watchdogTicker := time.NewTicker(e.config.Watchdog.Interval)
+		defer watchdogTicker.Stop()
+		watchdogTick = watchdogTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-e.stop:
+		case <-stop:
 			return
+		case <-watchdogTick:
+			e.checkExecutors()
 		case <-ticker.C:
 			e.updatePositions(ctx)
+			e.checkScheduledClose(ctx)
+			e.checkSummary(ctx)
+		case <-equityTick:
+			e.snapshotEquity()
+		case now := <-fundingTick:
+			e.applyFunding(ctx, now)
 		}
 	}
 }
 
 func (e *Engine) updatePositions(ctx context.Context) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	for _, pos := range e.positions {
-		if err := e.storage.SavePosition(pos); err != nil {
-			e.logger.Error("Failed to save position",
-				zap.String("symbol", pos.Symbol),
-				zap.Error(err))
-		}
-	}
+	e.savePositions()
 }
 
 func (e *Engine) PlaceOrder(ctx context.Context, order *types.Order) error {
 	if err := e.validateOrder(order); err != nil {
 		return err
 	}
+	if err := e.holdConditional(order); err != nil {
+		return err
+	}
 
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	var key string
+	if e.dedup != nil {
+		key = dedupKey(order)
+		if original, ok := e.dedup.lookup(key, e.now()); ok {
+			metrics.DuplicateOrders.Inc()
+			e.logger.Warn("Duplicate order submission, returning original",
+				zap.String("order_id", original.ID),
+				zap.String("client_request_id", order.ClientRequestID))
+			*order = *original
+			return nil
+		}
+	}
+
 	e.orders[order.ID] = order
 	if err := e.storage.SaveOrder(order); err != nil {
 		delete(e.orders, order.ID)
 		return fmt.Errorf("failed to save order: %w", err)
 	}
+	if e.dedup != nil {
+		e.dedup.remember(key, order, e.now())
+	}
 
 	return nil
 }
@@ -199,6 +689,58 @@ func (e *Engine) CancelOrder(ctx context.Context, orderID string) error {
 	return e.storage.SaveOrder(order)
 }
 
+// ReplaceOrder amends the price and size of a resting order in place instead
+// of cancelling it and placing a new one. Where the order's venue supports
+// amendments the change is forwarded to it so the venue can keep the
+// order's queue position; otherwise only the local order is updated.
+func (e *Engine) ReplaceOrder(ctx context.Context, orderID string, newPrice, newSize decimal.Decimal) (*types.Order, error) {
+	if !e.config.AllowReplace {
+		return nil, fmt.Errorf("order replace disabled")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, exists := e.orders[orderID]
+	if !exists {
+		return nil, fmt.Errorf("order not found: %s", orderID)
+	}
+	if order.Status != types.OrderStatusNew && order.Status != types.OrderStatusPartial {
+		return nil, fmt.Errorf("order %s is not resting: %s", orderID, order.Status)
+	}
+	if !newPrice.IsPositive() {
+		return nil, fmt.Errorf("invalid price: %v", newPrice)
+	}
+	if newSize.LessThanOrEqual(order.FilledSize) {
+		return nil, fmt.Errorf("new size %v must exceed filled size %v", newSize, order.FilledSize)
+	}
+
+	replaced := *order
+	replaced.Price = newPrice
+	replaced.Size = newSize
+	replaced.UpdatedAt = e.now()
+	if err := e.validateOrder(&replaced); err != nil {
+		return nil, err
+	}
+
+	if exec, ok := e.executors[order.Provider]; ok {
+		if amender, ok := exec.(interface {
+			AmendOrder(ctx context.Context, order *types.Order) error
+		}); ok {
+			if err := amender.AmendOrder(ctx, &replaced); err != nil {
+				return nil, fmt.Errorf("failed to amend order at venue: %w", err)
+			}
+		}
+	}
+
+	if err := e.storage.SaveOrder(&replaced); err != nil {
+		return nil, fmt.Errorf("failed to save order: %w", err)
+	}
+	e.orders[orderID] = &replaced
+
+	return &replaced, nil
+}
+
 func (e *Engine) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -243,6 +785,27 @@ func (e *Engine) GetPositions(ctx context.Context) ([]*types.Position, error) {
 	return positions, nil
 }
 
+// GetPortfolioSummary aggregates the open positions into a portfolio summary
+// in the configured base currency
+func (e *Engine) GetPortfolioSummary(ctx context.Context) (types.PortfolioSummary, error) {
+	positions, err := e.GetPositions(ctx)
+	if err != nil {
+		return types.PortfolioSummary{}, err
+	}
+
+	portfolio := &types.Portfolio{Positions: positions, Config: e.config.Portfolio}
+	summary := portfolio.Summary()
+	if e.config.Portfolio.PublishDelta {
+		// Reset so closed positions drop out of the gauge
+		metrics.PositionDelta.Reset()
+		for symbol, delta := range summary.Deltas {
+			metrics.PositionDelta.WithLabelValues(symbol).Set(delta.InexactFloat64())
+		}
+		metrics.PortfolioDelta.Set(summary.Delta.InexactFloat64())
+	}
+	return summary, nil
+}
+
 func (e *Engine) validateOrder(order *types.Order) error {
 	size := order.Size
 	maxSize := decimal.NewFromFloat(e.config.MaxOrderSize)
@@ -297,16 +860,12 @@ func (e *Engine) SubscribeOrderBook(ctx context.Context, symbol string) (<-chan
 }
 
 func (e *Engine) ExecuteTrade(ctx context.Context, trade *types.Trade) error {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
-
 	if trade.Provider == "" {
 		return fmt.Errorf("trade provider not specified")
 	}
 
-	executor, ok := e.executors[trade.Provider]
-	if !ok {
-		return fmt.Errorf("executor %s not found", trade.Provider)
+	if trade.DecisionPrice.IsZero() {
+		trade.DecisionPrice = trade.Price
 	}
 
 	signal := &types.Signal{
@@ -318,9 +877,91 @@ func (e *Engine) ExecuteTrade(ctx context.Context, trade *types.Trade) error {
 		Timestamp:  trade.Timestamp,
 	}
 
-	if err := executor.ExecuteTrade(ctx, signal); err != nil {
+	done, err := e.beginExecution(signal)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := e.executeTrade(ctx, trade, signal); err != nil {
+		return err
+	}
+	e.bookFill(trade)
+	return nil
+}
+
+// executeTrade routes and executes the signal made from trade, recording
+// the venue and what it filled on trade. Like processSignal it does not
+// hold the engine lock while the executor trades.
+func (e *Engine) executeTrade(ctx context.Context, trade *types.Trade, signal *types.Signal) error {
+	held := e.executorSymbols(signal)
+	exposures := e.venueExposures(signal)
+	venue, executor, release, err := e.routeTrade(signal, held, exposures)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	filled, err := executeSignal(ctx, executor, signal)
+	if err != nil {
 		return fmt.Errorf("failed to execute trade: %w", err)
 	}
 
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	trade.Provider = venue
+	trade.FilledSize = filled.FilledSize
+	trade.AvgFillPrice = filled.AvgFillPrice
+	trade.Liquidity = filled.Liquidity
+	if trade.Liquidity == "" && e.restingOrder(trade.OrderID) {
+		trade.Liquidity = types.LiquidityMaker
+	}
+	trade.Fee = e.calculateFee(trade)
+
 	return nil
 }
+
+// routeTrade routes signal and checks the position limit under the read
+// lock, returning its venue, executor and reservation release
+func (e *Engine) routeTrade(signal *types.Signal, held map[string]bool, exposures map[string]decimal.Decimal) (string, executor.TradingExecutor, func(), error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	venue, executor, release, err := e.routeVenue(signal, exposures)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	signal.Venue = venue
+
+	if err := e.checkMaxPositions(signal, held); err != nil {
+		release()
+		return "", nil, nil, err
+	}
+	return venue, executor, release, nil
+}
+
+// restingOrder reports whether id is a limit order in the engine's book,
+// whose fills added liquidity while it rested. Callers hold the read lock.
+func (e *Engine) restingOrder(id string) bool {
+	order, ok := e.orders[id]
+	return ok && order.Type == types.OrderTypeLimit
+}
+
+// calculateFee returns the signed fee for a filled trade. Maker fills are
+// credited the configured rebate, taker fills pay the taker fee, which
+// defaults to the flat commission when no fee schedule is configured.
+// Fills without a reported liquidity role are taken as taker fills.
+func (e *Engine) calculateFee(trade *types.Trade) decimal.Decimal {
+	fees := e.config.Fees
+	if fees.TakerFee.IsZero() {
+		fees.TakerFee = decimal.NewFromFloat(e.config.Commission)
+	}
+
+	role := trade.Liquidity
+	if role == "" {
+		role = types.LiquidityTaker
+	}
+
+	return fees.Commission(role, trade.FillPrice().Mul(trade.Filled()))
+}
@@ -0,0 +1,186 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type recordingExecutor struct {
+	signals []*types.Signal
+}
+
+func (r *recordingExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
+	r.signals = append(r.signals, signal)
+	return nil
+}
+
+func (r *recordingExecutor) GetPosition(symbol string) *types.Position { return nil }
+
+func (r *recordingExecutor) GetPositions() map[string]*types.Position { return nil }
+
+func (r *recordingExecutor) Start() error { return nil }
+
+func (r *recordingExecutor) Stop() error { return nil }
+
+func TestEngine_ProcessSignalClampsFutureTimestamp(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		ClockSkew: ClockSkewConfig{
+			FutureTolerance: 2 * time.Second,
+			MaxAge:          time.Hour,
+		},
+	}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	exec := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	signal := &types.Signal{
+		Symbol:    "TEST",
+		Type:      types.SignalTypeBuy,
+		Price:     decimal.NewFromFloat(1.0),
+		Provider:  "pump.fun",
+		Timestamp: now.Add(5 * time.Minute),
+	}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal))
+	assert.Len(t, exec.signals, 1)
+	assert.True(t, exec.signals[0].Timestamp.Equal(now))
+
+	// Within tolerance the timestamp is left untouched
+	withinTolerance := now.Add(time.Second)
+	signal = &types.Signal{
+		Symbol:    "TEST",
+		Type:      types.SignalTypeBuy,
+		Price:     decimal.NewFromFloat(1.0),
+		Provider:  "pump.fun",
+		Timestamp: withinTolerance,
+	}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal))
+	assert.True(t, exec.signals[1].Timestamp.Equal(withinTolerance))
+}
+
+func TestEngine_ReplaySignals(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStorage()
+
+	// Capture signals as the pricing engine tap would
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, store.SaveSignal(context.Background(), &types.Signal{
+			Symbol:    "TEST",
+			Type:      types.SignalTypeBuy,
+			Price:     decimal.NewFromInt(int64(100 + i)),
+			Provider:  "pump.fun",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	engine := NewEngine(Config{}, zap.NewNop(), new(MockStorage))
+	engine.SetSignalStore(store)
+	exec := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	err := engine.ReplaySignals(context.Background(), start.Add(time.Minute), start.Add(3*time.Minute))
+	assert.NoError(t, err)
+
+	assert.Len(t, exec.signals, 3)
+	for i, signal := range exec.signals {
+		assert.True(t, decimal.NewFromInt(int64(101+i)).Equal(signal.Price))
+		assert.True(t, start.Add(time.Duration(i+1)*time.Minute).Equal(signal.Timestamp))
+	}
+}
+
+func TestEngine_ReplaceOrder(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	engine := NewEngine(Config{
+		MaxOrderSize: 100,
+		MinOrderSize: 1,
+		AllowReplace: true,
+	}, zap.NewNop(), store)
+
+	order := &types.Order{
+		ID:       "order-1",
+		Symbol:   "TEST",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(1.5),
+		Size:     decimal.NewFromInt(10),
+		Status:   types.OrderStatusNew,
+		Provider: "pump.fun",
+	}
+	assert.NoError(t, engine.PlaceOrder(context.Background(), order))
+
+	_, err := engine.ReplaceOrder(context.Background(), "order-1", decimal.NewFromFloat(1.4), decimal.NewFromInt(8))
+	assert.NoError(t, err)
+
+	stored, err := store.GetOrder("order-1")
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(1.4).Equal(stored.Price))
+	assert.True(t, decimal.NewFromInt(8).Equal(stored.Size))
+	assert.Equal(t, types.OrderStatusNew, stored.Status)
+
+	// Invalid parameters leave the order untouched
+	_, err = engine.ReplaceOrder(context.Background(), "order-1", decimal.NewFromFloat(1.4), decimal.NewFromInt(500))
+	assert.Error(t, err)
+	current, err := engine.GetOrder(context.Background(), "order-1")
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(8).Equal(current.Size))
+}
+
+func TestEngine_ProcessSignalEnforcesMaxPositions(t *testing.T) {
+	engine := NewEngine(Config{MaxPositions: 2}, zap.NewNop(), new(MockStorage))
+	exec := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	engine.positions["BONK"] = types.NewPosition("BONK", decimal.NewFromInt(10), decimal.NewFromInt(1))
+	engine.positions["WIF"] = types.NewPosition("WIF", decimal.NewFromInt(5), decimal.NewFromInt(2))
+
+	signal := func(symbol string, side types.SignalType) *types.Signal {
+		return &types.Signal{
+			Symbol:   symbol,
+			Type:     side,
+			Amount:   decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(1),
+			Provider: "pump.fun",
+		}
+	}
+
+	rejections := testutil.ToFloat64(metrics.MaxPositionsRejections)
+	err := engine.ProcessSignal(context.Background(), signal("POPCAT", types.SignalTypeBuy))
+	assert.ErrorContains(t, err, "POPCAT")
+	assert.Empty(t, exec.signals)
+	assert.Equal(t, rejections+1, testutil.ToFloat64(metrics.MaxPositionsRejections))
+
+	// Adding to or selling out of an open position is still allowed
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal("BONK", types.SignalTypeBuy)))
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal("WIF", types.SignalTypeSell)))
+	assert.Len(t, exec.signals, 2)
+}
+
+func TestEngine_MaxPositionsCountsExecutorPositions(t *testing.T) {
+	engine := NewEngine(Config{MaxPositions: 2}, zap.NewNop(), new(MockStorage))
+	exec := &bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(10)},
+		"WIF":  {Symbol: "WIF", Size: decimal.NewFromInt(5)},
+	}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	signal := func(symbol string) *types.Signal {
+		return &types.Signal{Symbol: symbol, Type: types.SignalTypeBuy, Provider: "pump.fun",
+			Amount: decimal.NewFromInt(1), Price: decimal.NewFromInt(1)}
+	}
+
+	// Positions the executor holds fill the limit even when the engine's
+	// book has not seen them
+	assert.ErrorContains(t, engine.ProcessSignal(context.Background(), signal("POPCAT")), "POPCAT")
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal("WIF")))
+	assert.Len(t, exec.signals, 1)
+}
@@ -0,0 +1,83 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// EquityConfig configures periodic per-strategy equity snapshots
+type EquityConfig struct {
+	Interval    time.Duration   `yaml:"interval"`     // Snapshot interval, zero disables snapshots
+	InitialCash decimal.Decimal `yaml:"initial_cash"` // Starting cash allocated to each strategy
+	// TurnoverWindow is the trailing period turnover and fee drag are
+	// measured over, 24 hours by default
+	TurnoverWindow time.Duration `yaml:"turnover_window"`
+}
+
+// snapshotEquity persists the current equity of every registered executor.
+// Equity is the strategy's cash (initial allocation plus realized PnL) plus
+// the unrealized PnL of its open positions. Executors are asked for their
+// positions without the engine lock, since one may be blocked on a trade in
+// flight.
+func (e *Engine) snapshotEquity() {
+	e.mu.RLock()
+	now := e.now()
+	executors := e.copyExecutors()
+	cash := make(map[string]decimal.Decimal, len(executors))
+	for name := range executors {
+		cash[name] = e.config.Equity.InitialCash.Add(e.realized[name])
+	}
+	var shadow *types.EquityPoint
+	if e.shadow != nil {
+		shadow = e.shadowEquity(now)
+	}
+	e.mu.RUnlock()
+
+	points := make([]*types.EquityPoint, 0, len(executors)+1)
+	for name, exec := range executors {
+		unrealized := decimal.Zero
+		for _, pos := range exec.GetPositions() {
+			unrealized = unrealized.Add(pos.UnrealizedPnL)
+		}
+		points = append(points, &types.EquityPoint{
+			Strategy:      name,
+			Cash:          cash[name],
+			UnrealizedPnL: unrealized,
+			Equity:        cash[name].Add(unrealized),
+			Timestamp:     now,
+		})
+	}
+	if shadow != nil {
+		points = append(points, shadow)
+	}
+
+	for _, point := range points {
+		if err := e.storage.SaveEquityPoint(point); err != nil {
+			e.logger.Error("Failed to save equity point",
+				zap.String("strategy", point.Strategy),
+				zap.Error(err))
+		}
+	}
+
+	e.publishTurnover()
+
+	if e.config.Allocation.Enabled {
+		if err := e.RefreshAllocations(); err != nil {
+			e.logger.Error("Failed to refresh strategy allocations", zap.Error(err))
+		}
+	}
+}
+
+// GetEquityCurve returns the persisted equity curve of strategy between from and to
+func (e *Engine) GetEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error) {
+	points, err := e.storage.LoadEquityCurve(strategy, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load equity curve: %w", err)
+	}
+	return points, nil
+}
@@ -0,0 +1,37 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_SnapshotEquityQueriesExecutorsUnlocked(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		Equity: EquityConfig{InitialCash: decimal.NewFromInt(1000)},
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	engine.now = func() time.Time { return now }
+
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"SOL": {Symbol: "SOL", Size: decimal.NewFromInt(2), UnrealizedPnL: decimal.NewFromInt(-20)},
+	}}}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	engine.RecordRealizedPnL("pump.fun", decimal.NewFromInt(50))
+
+	engine.snapshotEquity()
+	assert.False(t, exec.locked)
+
+	curve, err := engine.GetEquityCurve("pump.fun", now.Add(-time.Minute), now.Add(time.Minute))
+	require.NoError(t, err)
+	if assert.Len(t, curve, 1) {
+		assert.True(t, decimal.NewFromInt(1030).Equal(curve[0].Equity), curve[0].Equity.String())
+	}
+}
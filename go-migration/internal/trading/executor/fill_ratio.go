@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// FillRatioConfig tracks the ratio of filled to submitted order size, per
+// symbol and overall
+type FillRatioConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Window  int  `yaml:"window"` // Recent orders per symbol the ratio covers, zero for all orders
+}
+
+// fillSample is one submitted order and how much of it filled
+type fillSample struct {
+	submitted decimal.Decimal
+	filled    decimal.Decimal
+}
+
+// fillRatios keeps recent orders per symbol and publishes their fill ratios
+type fillRatios struct {
+	window int
+	orders map[string][]fillSample
+	mu     sync.Mutex
+}
+
+func newFillRatios(config FillRatioConfig) *fillRatios {
+	window := config.Window
+	if window < 0 {
+		window = 0
+	}
+	return &fillRatios{
+		window: window,
+		orders: make(map[string][]fillSample),
+	}
+}
+
+// record adds an order of submitted size of which filled was filled, zero
+// when the venue rejected it, and returns the symbol's and the overall ratio
+func (r *fillRatios) record(symbol string, submitted, filled decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if !submitted.IsPositive() {
+		return decimal.Zero, decimal.Zero
+	}
+	filled = decimal.Min(decimal.Max(filled, decimal.Zero), submitted)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	samples := append(r.orders[symbol], fillSample{submitted: submitted, filled: filled})
+	if r.window > 0 && len(samples) > r.window {
+		samples = samples[len(samples)-r.window:]
+	}
+	r.orders[symbol] = samples
+
+	symbolRatio := ratioOf(samples)
+	var all []fillSample
+	for _, s := range r.orders {
+		all = append(all, s...)
+	}
+	overall := ratioOf(all)
+
+	metrics.OrderFillRatio.WithLabelValues(symbol).Set(symbolRatio.InexactFloat64())
+	metrics.OrderFillRatioOverall.Set(overall.InexactFloat64())
+	return symbolRatio, overall
+}
+
+func ratioOf(samples []fillSample) decimal.Decimal {
+	submitted, filled := decimal.Zero, decimal.Zero
+	for _, s := range samples {
+		submitted = submitted.Add(s.submitted)
+		filled = filled.Add(s.filled)
+	}
+	if submitted.IsZero() {
+		return decimal.Zero
+	}
+	return filled.Div(submitted)
+}
+
+// SetFillRatio enables fill ratio tracking for trades executed from now on
+func (e *RealtimeExecutor) SetFillRatio(config FillRatioConfig) {
+	e.fillRatios = nil
+	if config.Enabled {
+		e.fillRatios = newFillRatios(config)
+	}
+}
@@ -78,7 +78,8 @@ func (e *GMGNExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) e
 		return fmt.Errorf("risk validation failed: %w", err)
 	}
 
-	quote, err := e.provider.GetQuote(ctx, signal.TokenIn, signal.TokenOut, signal.Amount)
+	tokenIn, tokenOut := swapTokens(signal)
+	quote, err := e.provider.GetQuote(ctx, tokenIn, tokenOut, signal.Amount)
 	if err != nil {
 		metrics.GMGNTradeExecutions.WithLabelValues("quote_failed").Inc()
 		return fmt.Errorf("failed to get quote: %w", err)
@@ -118,6 +119,15 @@ func (e *GMGNExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) e
 	}
 }
 
+// swapTokens returns the tokens signal swaps between: buys pay SOL for
+// the signal's token and sells swap it back into SOL.
+func swapTokens(signal *types.Signal) (tokenIn, tokenOut string) {
+	if signal.Type == types.SignalTypeSell {
+		return signal.Symbol, "SOL"
+	}
+	return "SOL", signal.Symbol
+}
+
 func (e *GMGNExecutor) updatePosition(signal *types.Signal, size decimal.Decimal) {
 	position, exists := e.positions[signal.Symbol]
 	if !exists {
@@ -4,6 +4,7 @@ import (
     "context"
     "fmt"
     "sync"
+    "time"
 
     "github.com/shopspring/decimal"
     "go.uber.org/zap"
@@ -22,6 +23,8 @@ type PumpExecutor struct {
     positions  map[string]*types.Position
     apiKey     string
     isRunning  bool
+    limits     *limitBook
+    latency    FillLatencyRecorder
 }
 
 func NewPumpExecutor(logger *zap.Logger, provider *pump.Provider, riskMgr types.RiskManager, config *types.PumpTradingConfig, apiKey string) *PumpExecutor {
@@ -32,6 +35,7 @@ func NewPumpExecutor(logger *zap.Logger, provider *pump.Provider, riskMgr types.
         positions: make(map[string]*types.Position),
         apiKey:    apiKey,
         config:    config,
+        limits:    newLimitBook(),
     }
 }
 
@@ -43,14 +47,19 @@ func (e *PumpExecutor) Start() error {
         return fmt.Errorf("executor already running")
     }
 
-    if e.apiKey == "" {
+    if e.apiKey == "" && !e.config.PaperMode {
         metrics.APIErrors.WithLabelValues("api_key_missing").Inc()
         return fmt.Errorf("API key not configured")
     }
 
+    if err := validateLadder(e.config); err != nil {
+        return err
+    }
+
     e.isRunning = true
     e.logger.Info("pump.fun executor started",
-        zap.Bool("api_key_configured", true),
+        zap.Bool("api_key_configured", e.apiKey != ""),
+        zap.Bool("paper", e.config.PaperMode),
         zap.String("provider", "pump.fun"))
     return nil
 }
@@ -64,75 +73,122 @@ func (e *PumpExecutor) Stop() error {
     }
 
     e.isRunning = false
+    e.limits.stopWatching()
     return nil
 }
 
 func (e *PumpExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
+    _, err := e.ExecuteTradeFill(ctx, signal)
+    return err
+}
+
+// ExecuteTradeFill executes signal like ExecuteTrade and returns the trade
+// it made
+func (e *PumpExecutor) ExecuteTradeFill(ctx context.Context, signal *types.Signal) (*types.Trade, error) {
+    trade, err := e.executeFill(ctx, signal, types.LiquidityTaker)
+    if err == nil {
+        e.recordFillLatency(ctx, signal)
+    }
+    return trade, err
+}
+
+// executeFill executes signal and returns the trade it made, reporting the
+// given liquidity role: maker for resting limit orders, taker otherwise
+func (e *PumpExecutor) executeFill(ctx context.Context, signal *types.Signal, role types.LiquidityRole) (*types.Trade, error) {
     e.mu.Lock()
     defer e.mu.Unlock()
 
     if !e.isRunning {
-        return fmt.Errorf("executor not running")
+        return nil, fmt.Errorf("executor not running")
     }
 
-    if err := e.verifyAPIKey(); err != nil {
-        metrics.APIErrors.WithLabelValues("api_key_verification").Inc()
-        return fmt.Errorf("API key verification failed: %w", err)
+    if !e.config.PaperMode {
+        if err := e.verifyAPIKey(); err != nil {
+            metrics.APIErrors.WithLabelValues("api_key_verification").Inc()
+            return nil, fmt.Errorf("API key verification failed: %w", err)
+        }
     }
 
-    size, err := e.riskMgr.CalculatePositionSize(signal.Symbol, signal.Price)
+    size, err := e.positionSize(ctx, signal)
     if err != nil {
         metrics.PumpTradeExecutions.WithLabelValues("size_calculation_failed").Inc()
-        return fmt.Errorf("position size calculation failed: %w", err)
+        return nil, fmt.Errorf("position size calculation failed: %w", err)
     }
 
     if err := e.riskMgr.ValidatePosition(signal.Symbol, size); err != nil {
         metrics.PumpTradeExecutions.WithLabelValues("risk_rejected").Inc()
-        return fmt.Errorf("risk validation failed: %w", err)
+        return nil, fmt.Errorf("risk validation failed: %w", err)
+    }
+
+    if err := e.checkPortfolioRisk(ctx, signal, size); err != nil {
+        metrics.PumpTradeExecutions.WithLabelValues("risk_rejected").Inc()
+        return nil, fmt.Errorf("risk validation failed: %w", err)
+    }
+
+    if !e.config.PaperMode {
+        if err := e.checkCurveSlippage(ctx, signal, size); err != nil {
+            return nil, err
+        }
     }
 
     stopLossPercent := decimal.NewFromFloat(0.15)
+    if e.config.Risk.StopLossPercent.IsPositive() {
+        stopLossPercent = e.config.Risk.StopLossPercent
+    }
     stopLoss := signal.Price.Mul(decimal.NewFromFloat(1).Sub(stopLossPercent))
-
-    takeProfitLevels := []struct {
-        price    decimal.Decimal
-        quantity decimal.Decimal
-    }{
-        {signal.Price.Mul(decimal.NewFromFloat(2.0)), size.Mul(decimal.NewFromFloat(0.20))},
-        {signal.Price.Mul(decimal.NewFromFloat(3.0)), size.Mul(decimal.NewFromFloat(0.25))},
-        {signal.Price.Mul(decimal.NewFromFloat(5.0)), size.Mul(decimal.NewFromFloat(0.20))},
+    if stops, ok := e.riskMgr.(interface{ StopLossPrice(decimal.Decimal) decimal.Decimal }); ok {
+        stopLoss = stops.StopLossPrice(signal.Price)
     }
 
-    takeProfits := make([]decimal.Decimal, len(takeProfitLevels))
-    for i, level := range takeProfitLevels {
-        takeProfits[i] = level.price
+    takeProfits := e.takeProfitPrices(signal.Price)
+    trade, err := e.fill(ctx, signal, size, &stopLoss, takeProfits, e.takeProfitSizes(size))
+    if err != nil {
+        return nil, err
     }
+    trade.Liquidity = role
+    return trade, nil
+}
 
-    if err := e.provider.ExecuteOrder(ctx, signal.Symbol, signal.Type, size, signal.Price, &stopLoss, takeProfits); err != nil {
+// fill sends signal to the venue for size, or simulates it in paper mode,
+// books the fill into the position and returns the trade it made. Callers
+// hold the lock.
+func (e *PumpExecutor) fill(ctx context.Context, signal *types.Signal, size decimal.Decimal, stopLoss *decimal.Decimal, takeProfits, takeProfitSizes []decimal.Decimal) (*types.Trade, error) {
+    fillPrice := signal.Price
+    status := "success"
+    if e.config.PaperMode {
+        fillPrice = e.PaperFillPrice(signal)
+        status = "paper"
+    } else if err := e.provider.ExecuteOrder(ctx, signal.Symbol, signal.Type, size, signal.Price, stopLoss, takeProfits, takeProfitSizes); err != nil {
         metrics.PumpTradeExecutions.WithLabelValues("failed").Inc()
-        return fmt.Errorf("trade execution failed: %w", err)
+        return nil, fmt.Errorf("trade execution failed: %w", err)
     }
 
     // Update position tracking
     position, exists := e.positions[signal.Symbol]
     if !exists {
-        position = types.NewPosition(signal.Symbol, decimal.Zero, signal.Price)
+        position = types.NewPosition(signal.Symbol, decimal.Zero, fillPrice)
         e.positions[signal.Symbol] = position
     }
 
     if signal.Type == types.SignalTypeBuy {
         oldSize := position.Size
         position.Size = position.Size.Add(signal.Amount)
-        position.EntryPrice = position.EntryPrice.Mul(oldSize).Add(signal.Price.Mul(signal.Amount)).Div(position.Size)
+        position.EntryPrice = position.EntryPrice.Mul(oldSize).Add(fillPrice.Mul(signal.Amount)).Div(position.Size)
     } else {
+        // Partial sells such as take profits accumulate their PnL on the
+        // position, and the whole trade's result is recorded once it closes
+        position.RealizedPnL = position.RealizedPnL.Add(fillPrice.Sub(position.EntryPrice).Mul(signal.Amount))
         position.Size = position.Size.Sub(signal.Amount)
         if position.Size.LessThanOrEqual(decimal.Zero) {
             delete(e.positions, signal.Symbol)
+            if recorder, ok := e.riskMgr.(interface{ RecordTradeResult(string, decimal.Decimal) }); ok {
+                recorder.RecordTradeResult(signal.Symbol, position.RealizedPnL)
+            }
         }
     }
 
     // Record metrics
-    metrics.PumpTradeExecutions.WithLabelValues("success").Inc()
+    metrics.PumpTradeExecutions.WithLabelValues(status).Inc()
     metrics.TokenVolume.WithLabelValues("pump.fun", signal.Symbol).Add(signal.Amount.InexactFloat64())
     metrics.PumpPositionSize.WithLabelValues(signal.Symbol).Set(position.Size.InexactFloat64())
     
@@ -145,11 +201,163 @@ func (e *PumpExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) e
         zap.String("symbol", signal.Symbol),
         zap.String("type", string(signal.Type)),
         zap.String("size", signal.Amount.String()),
-        zap.String("price", signal.Price.String()))
+        zap.String("price", fillPrice.String()),
+        zap.Bool("paper", e.config.PaperMode))
+
+    side := types.OrderSideBuy
+    if signal.Type == types.SignalTypeSell {
+        side = types.OrderSideSell
+    }
+    trade := &types.Trade{
+        Symbol:        signal.Symbol,
+        Side:          side,
+        Price:         signal.Price,
+        DecisionPrice: signal.Price,
+        Size:          signal.Amount,
+        AvgFillPrice:  fillPrice,
+        Liquidity:     types.LiquidityTaker,
+        Provider:      "pump.fun",
+        Status:        types.OrderStatusFilled,
+        Timestamp:     time.Now(),
+    }
+    if stopLoss != nil {
+        trade.StopLoss = *stopLoss
+    }
+    return trade, nil
+}
+
+// validateLadder checks that the take-profit batch sizes pair up with the
+// levels and sell at most the whole position between them
+func validateLadder(config *types.PumpTradingConfig) error {
+    levels, batches := config.Risk.TakeProfitLevels, config.Risk.BatchSizes
+    if len(batches) > 0 && len(batches) != len(levels) {
+        return fmt.Errorf("%d take-profit batch sizes for %d levels", len(batches), len(levels))
+    }
+
+    total := decimal.Zero
+    for i, batch := range batches {
+        if batch.IsNegative() {
+            return fmt.Errorf("take-profit batch size %d is negative: %s", i, batch)
+        }
+        total = total.Add(batch)
+    }
+    if total.GreaterThan(decimal.NewFromInt(1)) {
+        return fmt.Errorf("take-profit batch sizes sum to %s, more than the whole position", total)
+    }
+    for i, level := range levels {
+        if !level.IsPositive() {
+            return fmt.Errorf("take-profit level %d must be positive, got %s", i, level)
+        }
+    }
+    return nil
+}
+
+// takeProfitPrices returns the take-profit ladder for an entry at price,
+// each configured level being a multiple of the entry price
+// regimeSizer is implemented by risk managers that scale position size for
+// the current correlation regime
+type regimeSizer interface {
+    CalculateRegimeSize(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error)
+}
+
+// portfolioRisk is implemented by risk managers that cap the book as a
+// whole by portfolio beta and correlation cluster exposure
+type portfolioRisk interface {
+    CheckPortfolioBeta(ctx context.Context, positions []*types.Position, symbol string, size, price decimal.Decimal) error
+    CheckClusterExposure(ctx context.Context, positions []*types.Position, symbol string, size, price decimal.Decimal) error
+}
+
+// positionSize sizes signal through the risk manager, scaled for the
+// correlation regime when the risk manager supports it
+func (e *PumpExecutor) positionSize(ctx context.Context, signal *types.Signal) (decimal.Decimal, error) {
+    if regime, ok := e.riskMgr.(regimeSizer); ok {
+        return regime.CalculateRegimeSize(ctx, signal.Symbol, signal.Price)
+    }
+    return e.riskMgr.CalculatePositionSize(signal.Symbol, signal.Price)
+}
+
+// checkPortfolioRisk applies the risk manager's portfolio beta and cluster
+// exposure caps to a buy of size. Callers hold e.mu.
+func (e *PumpExecutor) checkPortfolioRisk(ctx context.Context, signal *types.Signal, size decimal.Decimal) error {
+    portfolio, ok := e.riskMgr.(portfolioRisk)
+    if !ok || signal.Type != types.SignalTypeBuy {
+        return nil
+    }
+
+    positions := make([]*types.Position, 0, len(e.positions))
+    for _, position := range e.positions {
+        positions = append(positions, position)
+    }
+    if err := portfolio.CheckPortfolioBeta(ctx, positions, signal.Symbol, size, signal.Price); err != nil {
+        return err
+    }
+    return portfolio.CheckClusterExposure(ctx, positions, signal.Symbol, size, signal.Price)
+}
+
+func (e *PumpExecutor) takeProfitPrices(price decimal.Decimal) []decimal.Decimal {
+    levels := e.config.Risk.TakeProfitLevels
+    takeProfits := make([]decimal.Decimal, len(levels))
+    for i, level := range levels {
+        takeProfits[i] = price.Mul(level)
+    }
+    return takeProfits
+}
+
+// takeProfitSizes returns the amount of an entry of size to sell at each
+// take-profit level, each configured batch size being a fraction of the
+// entry. Without batch sizes the venue decides how much to sell.
+func (e *PumpExecutor) takeProfitSizes(size decimal.Decimal) []decimal.Decimal {
+    batches := e.config.Risk.BatchSizes
+    if len(batches) == 0 {
+        return nil
+    }
+    sizes := make([]decimal.Decimal, len(batches))
+    for i, batch := range batches {
+        sizes[i] = size.Mul(batch)
+    }
+    return sizes
+}
+
+// checkCurveSlippage rejects trades whose expected average fill along the
+// token's bonding curve is further than Risk.MaxExecutionSlippage from the
+// signal price. Trades are also rejected when no estimate can be made,
+// since the slippage is then unknown.
+func (e *PumpExecutor) checkCurveSlippage(ctx context.Context, signal *types.Signal, size decimal.Decimal) error {
+    tolerance := e.config.Risk.MaxExecutionSlippage
+    if !tolerance.IsPositive() || !signal.Price.IsPositive() {
+        return nil
+    }
+
+    estimate, err := e.provider.EstimateFillPrice(ctx, signal.Symbol, signal.Type, size)
+    if err != nil {
+        metrics.PumpTradeExecutions.WithLabelValues("slippage_estimate_failed").Inc()
+        return fmt.Errorf("failed to estimate slippage: %w", err)
+    }
+
+    slippage := estimate.Sub(signal.Price).Abs().Div(signal.Price)
+    if slippage.GreaterThan(tolerance) {
+        metrics.PumpTradeExecutions.WithLabelValues("slippage_rejected").Inc()
+        return fmt.Errorf("estimated slippage %s exceeds maximum %s for %s", slippage.StringFixed(4), tolerance, signal.Symbol)
+    }
 
+    e.logger.Debug("estimated bonding curve fill",
+        zap.String("symbol", signal.Symbol),
+        zap.String("price", signal.Price.String()),
+        zap.String("estimate", estimate.String()),
+        zap.String("slippage", slippage.String()))
     return nil
 }
 
+// PaperFillPrice simulates a fill for signal, moving the price against the
+// trade by PaperSlippage
+func (e *PumpExecutor) PaperFillPrice(signal *types.Signal) decimal.Decimal {
+    slippage := e.config.PaperSlippage
+    if signal.Type == types.SignalTypeSell {
+        slippage = slippage.Neg()
+    }
+    return signal.Price.Mul(decimal.NewFromInt(1).Add(slippage))
+}
+
 func (e *PumpExecutor) GetPosition(symbol string) *types.Position {
     e.mu.RLock()
     defer e.mu.RUnlock()
@@ -167,6 +375,48 @@ func (e *PumpExecutor) GetPositions() map[string]*types.Position {
     return positions
 }
 
+// ClosePosition sells the whole position in symbol at its last known price.
+// It reports false without an open position.
+func (e *PumpExecutor) ClosePosition(ctx context.Context, symbol string) (bool, error) {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+
+    if !e.isRunning {
+        return false, fmt.Errorf("executor not running")
+    }
+
+    position, ok := e.positions[symbol]
+    if !ok {
+        return false, nil
+    }
+    size, price := position.Size, position.CurrentPrice
+    if !price.IsPositive() {
+        price = position.EntryPrice
+    }
+
+    if !e.config.PaperMode {
+        if err := e.verifyAPIKey(); err != nil {
+            metrics.APIErrors.WithLabelValues("api_key_verification").Inc()
+            return false, fmt.Errorf("API key verification failed: %w", err)
+        }
+    }
+
+    // An exit sells what is held, so the entry gates (risk sizing and the
+    // slippage tolerance) do not apply
+    signal := &types.Signal{
+        Symbol:    symbol,
+        Type:      types.SignalTypeSell,
+        Amount:    size,
+        Price:     price,
+        Provider:  "pump.fun",
+        Timestamp: time.Now(),
+    }
+    if _, err := e.fill(ctx, signal, size, nil, nil, nil); err != nil {
+        return false, fmt.Errorf("failed to close %s: %w", symbol, err)
+    }
+    return true, nil
+}
+
 func (e *PumpExecutor) verifyAPIKey() error {
     if e.apiKey == "" {
         return fmt.Errorf("API key not configured")
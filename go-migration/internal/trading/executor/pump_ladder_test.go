@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func ladderConfig(levels, batches []float64) *types.PumpTradingConfig {
+	config := &types.PumpTradingConfig{PaperMode: true}
+	for _, level := range levels {
+		config.Risk.TakeProfitLevels = append(config.Risk.TakeProfitLevels, decimal.NewFromFloat(level))
+	}
+	for _, batch := range batches {
+		config.Risk.BatchSizes = append(config.Risk.BatchSizes, decimal.NewFromFloat(batch))
+	}
+	return config
+}
+
+func TestPumpExecutor_TakeProfitLadder(t *testing.T) {
+	e := NewPumpExecutor(zap.NewNop(), nil, nil, ladderConfig([]float64{1.5, 2, 4}, []float64{0.3, 0.3, 0.4}), "")
+	require.NoError(t, e.Start())
+
+	takeProfits := e.takeProfitPrices(decimal.NewFromInt(10))
+	require.Len(t, takeProfits, 3)
+	for i, want := range []int64{15, 20, 40} {
+		assert.True(t, decimal.NewFromInt(want).Equal(takeProfits[i]), takeProfits[i].String())
+	}
+}
+
+func TestPumpExecutor_LadderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *types.PumpTradingConfig
+		wantErr bool
+	}{
+		{"batches sum to one", ladderConfig([]float64{2, 3}, []float64{0.5, 0.5}), false},
+		{"no batch sizes", ladderConfig([]float64{2, 3}, nil), false},
+		{"batches over the position", ladderConfig([]float64{2, 3}, []float64{0.6, 0.5}), true},
+		{"batch count mismatch", ladderConfig([]float64{2, 3, 5}, []float64{0.5, 0.5}), true},
+		{"negative batch", ladderConfig([]float64{2, 3}, []float64{1.2, -0.4}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewPumpExecutor(zap.NewNop(), nil, nil, tt.config, "")
+			if tt.wantErr {
+				assert.Error(t, e.Start())
+			} else {
+				assert.NoError(t, e.Start())
+			}
+		})
+	}
+}
+
+func TestPumpExecutor_SendsTakeProfitSizes(t *testing.T) {
+	var payload struct {
+		TakeProfit      []decimal.Decimal `json:"take_profit"`
+		TakeProfitSizes []decimal.Decimal `json:"take_profit_sizes"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.Write([]byte(`{"data":{"tx_hash":"0x1","status":"confirmed"}}`))
+	}))
+	defer server.Close()
+
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil)
+	riskMgr.On("ValidatePosition", "PEPE", mock.Anything).Return(nil)
+
+	config := ladderConfig([]float64{2, 4}, []float64{0.25, 0.75})
+	config.PaperMode = false
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, zap.NewNop())
+	e := NewPumpExecutor(zap.NewNop(), provider, riskMgr, config, strings.Repeat("k", 88))
+	require.NoError(t, e.Start())
+
+	buy := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(1)}
+	require.NoError(t, e.ExecuteTrade(context.Background(), buy))
+
+	// Each level sells its batch of the entry
+	require.Len(t, payload.TakeProfitSizes, 2)
+	assert.True(t, decimal.NewFromFloat(2.5).Equal(payload.TakeProfitSizes[0]), payload.TakeProfitSizes[0].String())
+	assert.True(t, decimal.NewFromFloat(7.5).Equal(payload.TakeProfitSizes[1]), payload.TakeProfitSizes[1].String())
+	require.Len(t, payload.TakeProfit, 2)
+	assert.True(t, decimal.NewFromInt(4).Equal(payload.TakeProfit[1]), payload.TakeProfit[1].String())
+}
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// FillLatencyRecorder receives the time from each signal to its fill
+type FillLatencyRecorder interface {
+	RecordFillLatency(ctx context.Context, symbol string, latency time.Duration)
+}
+
+// SetFillLatencyRecorder sets where the latency of taker fills is reported.
+// Resting limit orders are left out since they wait on the market by design.
+func (e *PumpExecutor) SetFillLatencyRecorder(recorder FillLatencyRecorder) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.latency = recorder
+}
+
+// recordFillLatency reports the time since signal was generated. It runs
+// outside e.mu since the recorder may dispatch alerts.
+func (e *PumpExecutor) recordFillLatency(ctx context.Context, signal *types.Signal) {
+	e.mu.RLock()
+	recorder := e.latency
+	e.mu.RUnlock()
+
+	if recorder == nil || signal.Timestamp.IsZero() {
+		return
+	}
+	recorder.RecordFillLatency(ctx, signal.Symbol, time.Since(signal.Timestamp))
+}
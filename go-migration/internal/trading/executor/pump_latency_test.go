@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type recordedLatency struct {
+	symbols   []string
+	latencies []time.Duration
+}
+
+func (r *recordedLatency) RecordFillLatency(ctx context.Context, symbol string, latency time.Duration) {
+	r.symbols = append(r.symbols, symbol)
+	r.latencies = append(r.latencies, latency)
+}
+
+func TestPumpExecutor_RecordsFillLatency(t *testing.T) {
+	e := newRiskedPaperExecutor(t, risk.Limits{MaxPositionSize: decimal.NewFromInt(1000)}, staticReturns{})
+	recorder := &recordedLatency{}
+	e.SetFillLatencyRecorder(recorder)
+	ctx := context.Background()
+
+	_, err := e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(10), Timestamp: time.Now().Add(-2 * time.Second)})
+	require.NoError(t, err)
+	require.Len(t, recorder.latencies, 1)
+	assert.Equal(t, "BONK", recorder.symbols[0])
+	assert.GreaterOrEqual(t, recorder.latencies[0], 2*time.Second)
+
+	// Signals without a timestamp have nothing to measure from
+	_, err = e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeSell, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(10)})
+	require.NoError(t, err)
+	assert.Len(t, recorder.latencies, 1)
+}
@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// limitOrder is a signal parked until the price crosses its limit
+type limitOrder struct {
+	id      string
+	signal  types.Signal
+	limit   decimal.Decimal
+	expires time.Time // Zero when the order never expires
+	filling bool      // Set while its fill runs, so it cannot be cancelled
+}
+
+// triggered reports whether price crosses the limit: at or below it for a
+// buy, at or above it for a sell
+func (o *limitOrder) triggered(price decimal.Decimal) bool {
+	if o.signal.Type == types.SignalTypeSell {
+		return price.GreaterThanOrEqual(o.limit)
+	}
+	return price.LessThanOrEqual(o.limit)
+}
+
+// limitBook holds parked limit orders by symbol and id, with one price
+// subscription per symbol that has orders
+type limitBook struct {
+	orders    map[string]map[string]*limitOrder
+	watchers  map[string]context.CancelFunc
+	seq       int
+	now       func() time.Time
+	subscribe func(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error)
+	mu        sync.Mutex
+}
+
+func newLimitBook() *limitBook {
+	return &limitBook{
+		orders:   make(map[string]map[string]*limitOrder),
+		watchers: make(map[string]context.CancelFunc),
+		now:      time.Now,
+	}
+}
+
+// ExecuteLimitOrder parks signal until the price of its symbol crosses
+// limitPrice, then executes it at the crossing price as a maker fill.
+// It returns the order id used to cancel it.
+func (e *PumpExecutor) ExecuteLimitOrder(ctx context.Context, signal *types.Signal, limitPrice decimal.Decimal) (string, error) {
+	e.mu.RLock()
+	running := e.isRunning
+	e.mu.RUnlock()
+	if !running {
+		return "", fmt.Errorf("executor not running")
+	}
+	if !limitPrice.IsPositive() {
+		return "", fmt.Errorf("limit price must be positive, got %s", limitPrice)
+	}
+
+	b := e.limits
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	order := &limitOrder{
+		id:     fmt.Sprintf("%s-%d", signal.Symbol, b.seq),
+		signal: *signal,
+		limit:  limitPrice,
+	}
+	if ttl := e.config.LimitOrderTTL; ttl > 0 {
+		order.expires = b.now().Add(ttl)
+	}
+
+	if _, watching := b.watchers[signal.Symbol]; !watching {
+		if err := e.watchLimitPrices(signal.Symbol); err != nil {
+			return "", fmt.Errorf("failed to subscribe to %s prices: %w", signal.Symbol, err)
+		}
+	}
+	if b.orders[signal.Symbol] == nil {
+		b.orders[signal.Symbol] = make(map[string]*limitOrder)
+	}
+	b.orders[signal.Symbol][order.id] = order
+
+	e.logger.Info("limit order placed",
+		zap.String("id", order.id),
+		zap.String("symbol", signal.Symbol),
+		zap.String("type", string(signal.Type)),
+		zap.String("limit", limitPrice.String()))
+	return order.id, nil
+}
+
+// CancelLimitOrder removes a parked limit order. An order whose fill is
+// already running cannot be cancelled.
+func (e *PumpExecutor) CancelLimitOrder(symbol, id string) error {
+	b := e.limits
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	order, ok := b.orders[symbol][id]
+	if !ok {
+		return fmt.Errorf("no limit order %s for %s", id, symbol)
+	}
+	if order.filling {
+		return fmt.Errorf("limit order %s for %s is filling", id, symbol)
+	}
+	b.remove(symbol, id)
+	metrics.PumpTradeExecutions.WithLabelValues("limit_cancelled").Inc()
+	return nil
+}
+
+// watchLimitPrices subscribes to symbol's price updates until its last
+// limit order is gone. Callers hold the book lock.
+func (e *PumpExecutor) watchLimitPrices(symbol string) error {
+	b := e.limits
+	subscribe := b.subscribe
+	if subscribe == nil {
+		subscribe = e.provider.SubscribePrices
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := subscribe(ctx, []string{symbol})
+	if err != nil {
+		cancel()
+		return err
+	}
+	b.watchers[symbol] = cancel
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if update.Symbol == symbol {
+					e.onLimitPrice(symbol, update.Price)
+				}
+			case <-ticker.C:
+				b.mu.Lock()
+				e.expireLimitOrders(symbol)
+				b.mu.Unlock()
+			}
+		}
+	}()
+	return nil
+}
+
+// onLimitPrice expires stale orders for symbol and executes those whose
+// limit price crosses. Orders are marked filling under the book lock before
+// it is released for their fills, so they cannot be cancelled, expired or
+// filled twice meanwhile. An order leaves the book only once its fill
+// succeeds, so a failed fill stays parked for the next crossing. Fills run on
+// their own context because removing the symbol's last order cancels the
+// watcher's.
+func (e *PumpExecutor) onLimitPrice(symbol string, price decimal.Decimal) {
+	b := e.limits
+	b.mu.Lock()
+	e.expireLimitOrders(symbol)
+	var fills []*limitOrder
+	for _, order := range b.orders[symbol] {
+		if !order.filling && order.triggered(price) {
+			order.filling = true
+			fills = append(fills, order)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, order := range fills {
+		signal := order.signal
+		signal.Price = price
+		signal.Timestamp = b.now()
+		if _, err := e.executeFill(context.Background(), &signal, types.LiquidityMaker); err != nil {
+			b.mu.Lock()
+			order.filling = false
+			b.mu.Unlock()
+			metrics.PumpTradeExecutions.WithLabelValues("limit_failed").Inc()
+			e.logger.Error("limit order execution failed",
+				zap.String("id", order.id),
+				zap.String("symbol", symbol),
+				zap.Error(err))
+			continue
+		}
+
+		b.mu.Lock()
+		if _, ok := b.orders[symbol][order.id]; ok {
+			b.remove(symbol, order.id)
+		}
+		b.mu.Unlock()
+		metrics.PumpTradeExecutions.WithLabelValues("limit_filled").Inc()
+		e.logger.Info("limit order filled",
+			zap.String("id", order.id),
+			zap.String("symbol", symbol),
+			zap.String("limit", order.limit.String()),
+			zap.String("price", price.String()))
+	}
+}
+
+// expireLimitOrders drops symbol's orders past their TTL, other than those
+// filling. Callers hold the book lock.
+func (e *PumpExecutor) expireLimitOrders(symbol string) {
+	b := e.limits
+	now := b.now()
+	for id, order := range b.orders[symbol] {
+		if !order.filling && !order.expires.IsZero() && now.After(order.expires) {
+			b.remove(symbol, id)
+			metrics.PumpTradeExecutions.WithLabelValues("limit_expired").Inc()
+			e.logger.Info("limit order expired",
+				zap.String("id", id),
+				zap.String("symbol", symbol))
+		}
+	}
+}
+
+// remove deletes an order, ending the symbol's price subscription with its
+// last order. Callers hold the lock.
+func (b *limitBook) remove(symbol, id string) {
+	delete(b.orders[symbol], id)
+	if len(b.orders[symbol]) > 0 {
+		return
+	}
+	delete(b.orders, symbol)
+	if cancel, ok := b.watchers[symbol]; ok {
+		cancel()
+		delete(b.watchers, symbol)
+	}
+}
+
+// stopWatching ends every price subscription, leaving orders parked
+func (b *limitBook) stopWatching() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for symbol, cancel := range b.watchers {
+		cancel()
+		delete(b.watchers, symbol)
+	}
+}
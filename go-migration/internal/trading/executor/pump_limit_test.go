@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestPumpExecutor_LimitOrder(t *testing.T) {
+	var trades int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&trades, 1)
+		w.Write([]byte(`{"data":{"tx_hash":"0xabc","status":"confirmed"}}`))
+	}))
+	defer server.Close()
+
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil)
+	riskMgr.On("ValidatePosition", "PEPE", mock.Anything).Return(nil)
+
+	logger := zap.NewNop()
+	config := &types.PumpTradingConfig{LimitOrderTTL: time.Minute}
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	e := NewPumpExecutor(logger, provider, riskMgr, config, strings.Repeat("k", 88))
+	assert.NoError(t, e.Start())
+
+	prices := make(chan *types.PriceUpdate)
+	e.limits.subscribe = func(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
+		return prices, nil
+	}
+	feed := func(price float64) {
+		prices <- &types.PriceUpdate{Symbol: "PEPE", Price: decimal.NewFromFloat(price)}
+	}
+
+	filled := testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("limit_filled"))
+	expired := testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("limit_expired"))
+	signal := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10)}
+
+	_, err := e.ExecuteLimitOrder(context.Background(), signal, decimal.NewFromInt(90))
+	assert.NoError(t, err)
+
+	// Above the limit the order stays parked
+	feed(95)
+	feed(91)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&trades))
+
+	// Crossing the limit executes it at the crossing price
+	feed(89)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&trades) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("limit_filled")) == filled+1
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, decimal.NewFromInt(89).Equal(e.GetPosition("PEPE").EntryPrice))
+
+	// An order past its TTL expires instead of filling
+	config.LimitOrderTTL = 10 * time.Millisecond
+	_, err = e.ExecuteLimitOrder(context.Background(), signal, decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	feed(40)
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("limit_expired")) == expired+1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&trades))
+}
+
+func TestPumpExecutor_CancelLimitOrder(t *testing.T) {
+	config := &types.PumpTradingConfig{}
+	e := NewPumpExecutor(zap.NewNop(), nil, &types.MockRiskManager{}, config, strings.Repeat("k", 88))
+	assert.NoError(t, e.Start())
+	e.limits.subscribe = func(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
+		return make(chan *types.PriceUpdate), nil
+	}
+
+	signal := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(1)}
+	id, err := e.ExecuteLimitOrder(context.Background(), signal, decimal.NewFromInt(90))
+	assert.NoError(t, err)
+	assert.Len(t, e.limits.watchers, 1)
+
+	assert.NoError(t, e.CancelLimitOrder("PEPE", id))
+	assert.Empty(t, e.limits.orders)
+	assert.Empty(t, e.limits.watchers)
+	assert.Error(t, e.CancelLimitOrder("PEPE", id))
+}
+
+func TestPumpExecutor_CancelFillingLimitOrder(t *testing.T) {
+	received, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		w.Write([]byte(`{"data":{"tx_hash":"0xabc","status":"confirmed"}}`))
+	}))
+	defer server.Close()
+
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil)
+	riskMgr.On("ValidatePosition", "PEPE", mock.Anything).Return(nil)
+
+	logger := zap.NewNop()
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	e := NewPumpExecutor(logger, provider, riskMgr, &types.PumpTradingConfig{}, strings.Repeat("k", 88))
+	assert.NoError(t, e.Start())
+
+	prices := make(chan *types.PriceUpdate)
+	e.limits.subscribe = func(ctx context.Context, symbols []string) (<-chan *types.PriceUpdate, error) {
+		return prices, nil
+	}
+
+	signal := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10)}
+	id, err := e.ExecuteLimitOrder(context.Background(), signal, decimal.NewFromInt(90))
+	assert.NoError(t, err)
+
+	// Once its fill is running the order can no longer be cancelled
+	prices <- &types.PriceUpdate{Symbol: "PEPE", Price: decimal.NewFromInt(89)}
+	<-received
+	err = e.CancelLimitOrder("PEPE", id)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "filling")
+	}
+
+	close(release)
+	assert.Eventually(t, func() bool { return e.GetPosition("PEPE") != nil }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		e.limits.mu.Lock()
+		defer e.limits.mu.Unlock()
+		return len(e.limits.orders) == 0
+	}, time.Second, 10*time.Millisecond)
+}
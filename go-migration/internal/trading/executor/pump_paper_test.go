@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestPumpExecutor_PaperMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("paper trade reached the venue: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil)
+	riskMgr.On("ValidatePosition", "PEPE", decimal.NewFromInt(10)).Return(nil)
+
+	logger := zap.NewNop()
+	config := &types.PumpTradingConfig{PaperMode: true, PaperSlippage: decimal.NewFromFloat(0.01)}
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	// No API key is needed when nothing is sent to the venue
+	e := NewPumpExecutor(logger, provider, riskMgr, config, "")
+	assert.NoError(t, e.Start())
+
+	paper := testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("paper"))
+
+	buy := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}
+	trade, err := e.ExecuteTradeFill(context.Background(), buy)
+	assert.NoError(t, err)
+
+	// Buys fill above the signal price by the slippage
+	if assert.NotNil(t, trade) {
+		assert.True(t, decimal.NewFromInt(101).Equal(trade.FillPrice()))
+		assert.True(t, decimal.NewFromInt(100).Equal(trade.DecisionPrice))
+		assert.Equal(t, types.LiquidityTaker, trade.Liquidity)
+	}
+	position := e.GetPosition("PEPE")
+	if assert.NotNil(t, position) {
+		assert.True(t, decimal.NewFromInt(10).Equal(position.Size))
+		assert.True(t, decimal.NewFromInt(101).Equal(position.EntryPrice))
+	}
+
+	sell := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeSell, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(110)}
+	assert.NoError(t, e.ExecuteTrade(context.Background(), sell))
+	assert.Nil(t, e.GetPosition("PEPE"))
+
+	assert.Equal(t, paper+2, testutil.ToFloat64(metrics.PumpTradeExecutions.WithLabelValues("paper")))
+	assert.True(t, decimal.NewFromFloat(108.9).Equal(e.PaperFillPrice(sell)))
+	riskMgr.AssertExpectations(t)
+}
+
+func TestPumpExecutor_ClosePositionSkipsEntryGates(t *testing.T) {
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil).Once()
+	riskMgr.On("ValidatePosition", "PEPE", decimal.NewFromInt(10)).Return(nil).Once()
+
+	config := &types.PumpTradingConfig{PaperMode: true}
+	e := NewPumpExecutor(zap.NewNop(), nil, riskMgr, config, "")
+	assert.NoError(t, e.Start())
+
+	closed, err := e.ClosePosition(context.Background(), "PEPE")
+	assert.NoError(t, err)
+	assert.False(t, closed)
+
+	buy := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}
+	assert.NoError(t, e.ExecuteTrade(context.Background(), buy))
+
+	// The close sells the held size without sizing or validating it again
+	closed, err = e.ClosePosition(context.Background(), "PEPE")
+	assert.NoError(t, err)
+	assert.True(t, closed)
+	assert.Nil(t, e.GetPosition("PEPE"))
+	riskMgr.AssertExpectations(t)
+}
+
+// resultRecorder records the trade results the executor reports
+type resultRecorder struct {
+	*types.MockRiskManager
+	results []decimal.Decimal
+}
+
+func (r *resultRecorder) RecordTradeResult(symbol string, pnl decimal.Decimal) {
+	r.results = append(r.results, pnl)
+}
+
+func TestPumpExecutor_RecordsResultOnFullClose(t *testing.T) {
+	riskMgr := &resultRecorder{MockRiskManager: &types.MockRiskManager{}}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil)
+	riskMgr.On("ValidatePosition", "PEPE", mock.Anything).Return(nil)
+
+	e := NewPumpExecutor(zap.NewNop(), nil, riskMgr, &types.PumpTradingConfig{PaperMode: true}, "")
+	assert.NoError(t, e.Start())
+	ctx := context.Background()
+	signal := func(side types.SignalType, amount, price int64) *types.Signal {
+		return &types.Signal{Symbol: "PEPE", Type: side, Amount: decimal.NewFromInt(amount), Price: decimal.NewFromInt(price)}
+	}
+
+	assert.NoError(t, e.ExecuteTrade(ctx, signal(types.SignalTypeBuy, 10, 100)))
+
+	// A partial take profit is not a trade result of its own
+	assert.NoError(t, e.ExecuteTrade(ctx, signal(types.SignalTypeSell, 4, 120)))
+	assert.Empty(t, riskMgr.results)
+
+	// Closing the rest records the PnL of both sells together
+	assert.NoError(t, e.ExecuteTrade(ctx, signal(types.SignalTypeSell, 6, 110)))
+	if assert.Len(t, riskMgr.results, 1) {
+		assert.True(t, decimal.NewFromInt(140).Equal(riskMgr.results[0]), riskMgr.results[0].String())
+	}
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type staticReturns map[string][]float64
+
+func (s staticReturns) Returns(ctx context.Context, symbol string) ([]float64, error) {
+	r, ok := s[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no returns for %s", symbol)
+	}
+	return r, nil
+}
+
+func newRiskedPaperExecutor(t *testing.T, limits risk.Limits, returns risk.ReturnsSource) *PumpExecutor {
+	manager := risk.NewManager(limits, zap.NewNop())
+	manager.SetReturnsSource(returns)
+	provider := pump.NewProvider(pump.Config{BaseURL: "http://127.0.0.1:0", TimeoutSec: 1}, zap.NewNop())
+	e := NewPumpExecutor(zap.NewNop(), provider, manager, &types.PumpTradingConfig{PaperMode: true}, "")
+	require.NoError(t, e.Start())
+	return e
+}
+
+func TestPumpExecutor_PortfolioBetaCap(t *testing.T) {
+	e := newRiskedPaperExecutor(t, risk.Limits{
+		MaxPositionSize: decimal.NewFromInt(1000),
+		MaxBeta:         decimal.NewFromFloat(1.5),
+		BetaReference:   "SOL",
+	}, staticReturns{
+		"SOL":  {0.01, -0.02, 0.03, 0.01},
+		"BONK": {0.01, -0.02, 0.03, 0.01},
+		"PEPE": {0.04, -0.08, 0.12, 0.04},
+	})
+	ctx := context.Background()
+
+	_, err := e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	require.NoError(t, err)
+
+	// A full-size beta 4 position would take the book past the cap
+	_, err = e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	assert.ErrorContains(t, err, "portfolio beta")
+	assert.Nil(t, e.GetPosition("PEPE"))
+
+	// Sells are never held back by the cap
+	_, err = e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeSell, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+}
+
+func TestPumpExecutor_ClusterExposureCap(t *testing.T) {
+	e := newRiskedPaperExecutor(t, risk.Limits{
+		MaxPositionSize:    decimal.NewFromInt(1000),
+		MaxClusterExposure: decimal.NewFromInt(1500),
+		ClusterThreshold:   0.8,
+	}, staticReturns{
+		"BONK": {0.01, -0.02, 0.03, 0.01},
+		"PEPE": {0.02, -0.04, 0.06, 0.02},
+		"WIF":  {-0.01, 0.03, -0.02, 0.01},
+	})
+	ctx := context.Background()
+
+	_, err := e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	require.NoError(t, err)
+
+	// PEPE moves with BONK, so a full-size position overfills their cluster
+	_, err = e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	assert.ErrorContains(t, err, "cluster")
+	assert.Nil(t, e.GetPosition("PEPE"))
+
+	// WIF sits in a cluster of its own
+	_, err = e.ExecuteTradeFill(ctx, &types.Signal{Symbol: "WIF", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(100), Price: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+}
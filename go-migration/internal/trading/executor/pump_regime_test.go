@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestPumpExecutor_RegimeScalesSize(t *testing.T) {
+	// PEPE tracks a falling SOL, so size is halved
+	e := newRiskedPaperExecutor(t, risk.Limits{
+		MaxPositionSize:   decimal.NewFromInt(1000),
+		Benchmark:         "SOL",
+		RegimeCorrelation: 0.8,
+		RegimeScale:       decimal.NewFromFloat(0.5),
+	}, staticReturns{
+		"SOL":  {-0.01, -0.02, 0.01, -0.03},
+		"PEPE": {-0.02, -0.04, 0.02, -0.06},
+	})
+
+	size, err := e.positionSize(context.Background(), &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Price: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(5).Equal(size), size.String())
+
+	// SOL itself trades at full size
+	size, err = e.positionSize(context.Background(), &types.Signal{Symbol: "SOL", Type: types.SignalTypeBuy, Price: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(10).Equal(size), size.String())
+}
@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestPumpExecutor_RejectsCurveSlippage(t *testing.T) {
+	var orders int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/gmgn/quote" {
+			w.Write([]byte(`{"data":{"symbol":"PEPE","current_price":"100","slope":"1"}}`))
+			return
+		}
+		atomic.AddInt32(&orders, 1)
+		w.Write([]byte(`{"data":{"tx_hash":"0x1","status":"confirmed"}}`))
+	}))
+	defer server.Close()
+
+	riskMgr := &types.MockRiskManager{}
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(10), nil).Once()
+	riskMgr.On("CalculatePositionSize", "PEPE", mock.Anything).Return(decimal.NewFromInt(2), nil).Once()
+	riskMgr.On("ValidatePosition", "PEPE", mock.Anything).Return(nil)
+
+	logger := zap.NewNop()
+	// A wide sizing budget does not loosen the execution tolerance
+	config := &types.PumpTradingConfig{MaxSlippage: decimal.NewFromFloat(0.5)}
+	config.Risk.MaxExecutionSlippage = decimal.NewFromFloat(0.02)
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	e := NewPumpExecutor(logger, provider, riskMgr, config, strings.Repeat("k", 88))
+	require.NoError(t, e.Start())
+
+	buy := func() *types.Signal {
+		return &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}
+	}
+
+	// Ten tokens up a curve of slope one fill around 105, five percent over spot
+	err := e.ExecuteTrade(context.Background(), buy())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum")
+	assert.Zero(t, atomic.LoadInt32(&orders))
+
+	// Two tokens fill around 101, within the two percent tolerance
+	require.NoError(t, e.ExecuteTrade(context.Background(), buy()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&orders))
+}
+
+func TestBondingCurve_AverageFillPrice(t *testing.T) {
+	curve := &types.BondingCurve{BasePrice: decimal.NewFromInt(50), Slope: decimal.NewFromFloat(0.5), Supply: 100}
+
+	buy, err := curve.AverageFillPrice(types.SignalTypeBuy, decimal.NewFromInt(20))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(105).Equal(buy), buy.String())
+
+	sell, err := curve.AverageFillPrice(types.SignalTypeSell, decimal.NewFromInt(20))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(95).Equal(sell), sell.String())
+
+	_, err = curve.AverageFillPrice(types.SignalTypeSell, decimal.NewFromInt(1000))
+	assert.Error(t, err)
+}
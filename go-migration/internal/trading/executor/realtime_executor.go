@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -12,19 +13,35 @@ import (
 	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
 	"github.com/kwanRoshi/B/go-migration/internal/metrics"
 	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/strategy"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 type RealtimeExecutor struct {
-	logger    *zap.Logger
-	provider  *pump.Provider
-	riskMgr   *risk.Manager
-	apiKey    string
-	positions sync.Map
-	trades    chan *types.Trade
-	stop      chan struct{}
+	logger     *zap.Logger
+	provider   *pump.Provider
+	riskMgr    *risk.Manager
+	apiKey     string
+	positions  sync.Map
+	trades     chan *types.Trade
+	stop       chan struct{}
+	atr        *strategy.ATRTracker
+	atrLadder  types.ATRTakeProfitConfig
+	fillRatios *fillRatios
+	trailing   decimal.Decimal
+
+	// The processing loop beats heartbeat (unix nanoseconds) on every pass.
+	// RestartLoop bumps loopGen so a hung loop exits once it unblocks.
+	heartbeat atomic.Int64
+	loopGen   atomic.Int64
+	loopCtx   context.Context
+	updates   <-chan *types.PriceUpdate
+	loopMu    sync.Mutex
 }
 
+// heartbeatInterval is how often an idle processing loop still beats
+const heartbeatInterval = time.Second
+
 func NewRealtimeExecutor(logger *zap.Logger, provider *pump.Provider, riskMgr *risk.Manager, apiKey string) *RealtimeExecutor {
 	return &RealtimeExecutor{
 		logger:    logger,
@@ -36,27 +53,81 @@ func NewRealtimeExecutor(logger *zap.Logger, provider *pump.Provider, riskMgr *r
 	}
 }
 
+// SetTrailingStop makes the stop of positions opened from now on trail
+// percent below the highest price seen, never falling below the hard stop
+func (e *RealtimeExecutor) SetTrailingStop(percent decimal.Decimal) {
+	e.trailing = percent
+}
+
+// SetATRTakeProfit replaces the fixed take profit multipliers with levels
+// at ATR multiples above entry, using an ATR built from the price feed
+func (e *RealtimeExecutor) SetATRTakeProfit(config types.ATRTakeProfitConfig) {
+	e.atrLadder = config
+	e.atr = nil
+	if config.Enabled {
+		e.atr = strategy.NewATRTracker(config.Period)
+	}
+}
+
 func (e *RealtimeExecutor) Start(ctx context.Context) error {
 	updates, err := e.provider.SubscribePrices(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to prices: %w", err)
 	}
 
-	go func() {
-		for {
-			select {
-			case update := <-updates:
-				e.handlePriceUpdate(ctx, update)
-			case trade := <-e.trades:
-				e.ExecuteTrade(ctx, trade)
-			case <-e.stop:
-				return
-			case <-ctx.Done():
-				return
-			}
+	e.loopMu.Lock()
+	e.loopCtx, e.updates = ctx, updates
+	gen := e.loopGen.Add(1)
+	e.loopMu.Unlock()
+
+	e.beat()
+	go e.loop(ctx, updates, gen)
+	return nil
+}
+
+// loop processes price updates and queued trades until stopped or replaced
+// by a newer generation
+func (e *RealtimeExecutor) loop(ctx context.Context, updates <-chan *types.PriceUpdate, gen int64) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for e.loopGen.Load() == gen {
+		e.beat()
+		select {
+		case update := <-updates:
+			e.handlePriceUpdate(ctx, update)
+		case trade := <-e.trades:
+			e.ExecuteTrade(ctx, trade)
+		case <-ticker.C:
+		case <-e.stop:
+			return
+		case <-ctx.Done():
+			return
 		}
-	}()
+	}
+}
+
+func (e *RealtimeExecutor) beat() {
+	e.heartbeat.Store(time.Now().UnixNano())
+}
 
+// LastHeartbeat returns when the processing loop last made progress
+func (e *RealtimeExecutor) LastHeartbeat() time.Time {
+	return time.Unix(0, e.heartbeat.Load())
+}
+
+// RestartLoop starts a fresh processing loop. The stalled one exits as soon
+// as whatever it is blocked on returns, without processing anything else.
+func (e *RealtimeExecutor) RestartLoop() error {
+	e.loopMu.Lock()
+	defer e.loopMu.Unlock()
+
+	if e.updates == nil {
+		return fmt.Errorf("executor not started")
+	}
+	gen := e.loopGen.Add(1)
+	e.beat()
+	go e.loop(e.loopCtx, e.updates, gen)
 	return nil
 }
 
@@ -76,6 +147,17 @@ func (e *RealtimeExecutor) ExecuteTrade(ctx context.Context, trade *types.Trade)
 		return fmt.Errorf("risk validation failed: %w", err)
 	}
 
+	if trade.Side == types.OrderSideBuy {
+		if err := e.riskMgr.CheckPortfolioBeta(ctx, e.openPositions(), trade.Symbol, trade.Size, trade.Price); err != nil {
+			metrics.APIKeyUsage.WithLabelValues("pump.fun", "risk_failure").Inc()
+			return fmt.Errorf("risk validation failed: %w", err)
+		}
+		if err := e.riskMgr.CheckClusterExposure(ctx, e.openPositions(), trade.Symbol, trade.Size, trade.Price); err != nil {
+			metrics.APIKeyUsage.WithLabelValues("pump.fun", "risk_failure").Inc()
+			return fmt.Errorf("risk validation failed: %w", err)
+		}
+	}
+
 	var signalType types.SignalType
 	if trade.Side == types.OrderSideBuy {
 		signalType = types.SignalTypeBuy
@@ -84,20 +166,81 @@ func (e *RealtimeExecutor) ExecuteTrade(ctx context.Context, trade *types.Trade)
 	}
 
 	// Execute trade with stop loss and take profit levels
-	if err := e.provider.ExecuteOrder(ctx, trade.Symbol, signalType, trade.Size, trade.Price, &trade.StopLoss, trade.TakeProfit); err != nil {
+	fill, err := e.provider.ExecuteOrderFill(ctx, trade.Symbol, signalType, trade.Size, trade.Price, &trade.StopLoss, trade.TakeProfit)
+	if err != nil {
 		metrics.APIKeyUsage.WithLabelValues("pump.fun", "failure").Inc()
 		metrics.PumpTradeExecutions.WithLabelValues("failure").Inc()
+		if e.fillRatios != nil {
+			e.fillRatios.record(trade.Symbol, trade.Size, decimal.Zero)
+		}
 		return fmt.Errorf("trade execution failed: %w", err)
 	}
 
 	metrics.APIKeyUsage.WithLabelValues("pump.fun", "success").Inc()
 	metrics.PumpTradeExecutions.WithLabelValues("success").Inc()
 
+	trade.FilledSize = decimal.Min(fill.Size, trade.Size)
+	trade.AvgFillPrice = fill.AvgPrice
+	if e.fillRatios != nil {
+		e.fillRatios.record(trade.Symbol, trade.Size, trade.FilledSize)
+	}
+	trade.Status = types.OrderStatusFilled
+	if trade.FilledSize.LessThan(trade.Size) {
+		trade.Status = types.OrderStatusPartial
+		e.requeueRemainder(trade)
+	}
+
 	e.updatePosition(trade)
 	return nil
 }
 
+// requeueRemainder resubmits the unfilled part of a partially filled trade.
+// A remainder is only requeued once; if it fills partially again the rest
+// is dropped.
+func (e *RealtimeExecutor) requeueRemainder(trade *types.Trade) {
+	metrics.PumpPartialFills.Inc()
+	remaining := trade.Size.Sub(trade.FilledSize)
+	if trade.Requeued {
+		e.logger.Warn("dropping unfilled remainder of requeued trade",
+			zap.String("symbol", trade.Symbol),
+			zap.String("remaining", remaining.String()))
+		return
+	}
+
+	remainder := *trade
+	remainder.Size = remaining
+	remainder.FilledSize = decimal.Zero
+	remainder.AvgFillPrice = decimal.Zero
+	remainder.Status = types.OrderStatusNew
+	remainder.Requeued = true
+
+	select {
+	case e.trades <- &remainder:
+		e.logger.Info("requeued partial fill remainder",
+			zap.String("symbol", trade.Symbol),
+			zap.String("filled", trade.FilledSize.String()),
+			zap.String("remaining", remaining.String()))
+	default:
+		e.logger.Warn("trade queue full, dropping partial fill remainder",
+			zap.String("symbol", trade.Symbol),
+			zap.String("remaining", remaining.String()))
+	}
+}
+
+func (e *RealtimeExecutor) openPositions() []*types.Position {
+	var positions []*types.Position
+	e.positions.Range(func(key, value interface{}) bool {
+		positions = append(positions, value.(*types.Position))
+		return true
+	})
+	return positions
+}
+
 func (e *RealtimeExecutor) handlePriceUpdate(ctx context.Context, update *types.PriceUpdate) {
+	if e.atr != nil {
+		e.atr.Record(update.Symbol, update.Price)
+	}
+
 	e.positions.Range(func(key, value interface{}) bool {
 		symbol := key.(string)
 		position := value.(*types.Position)
@@ -133,9 +276,17 @@ func (e *RealtimeExecutor) updatePositionPrice(ctx context.Context, position *ty
 }
 
 func (e *RealtimeExecutor) checkTakeProfitAndStopLoss(ctx context.Context, position *types.Position, price decimal.Decimal) {
-	// Stop Loss check (10% below entry)
-	stopLossPrice := position.EntryPrice.Mul(decimal.NewFromFloat(0.9))
+	// Stop Loss check (10% below entry, or trailing above it)
+	hardStop := position.EntryPrice.Mul(decimal.NewFromFloat(0.9))
+	stopLossPrice := trailStop(position, price, hardStop)
 	if price.LessThanOrEqual(stopLossPrice) {
+		metrics.PumpStopLossTriggers.Inc()
+		e.logger.Info("Stop loss triggered",
+			zap.String("symbol", position.Symbol),
+			zap.String("price", price.String()),
+			zap.String("stop_loss", stopLossPrice.String()),
+			zap.String("high_water_mark", position.HighWaterMark.String()),
+			zap.String("trailed", stopLossPrice.Sub(hardStop).String()))
 		if err := e.closePosition(ctx, position, price); err != nil {
 			e.logger.Error("Failed to execute stop loss",
 				zap.String("symbol", position.Symbol),
@@ -144,6 +295,13 @@ func (e *RealtimeExecutor) checkTakeProfitAndStopLoss(ctx context.Context, posit
 		return
 	}
 
+	if e.atr != nil {
+		if atr, ok := e.atr.ATR(position.Symbol); ok {
+			e.takeATRProfits(ctx, position, price, atr)
+			return
+		}
+	}
+
 	// Take Profit levels (20% at 2x, 25% at 3x, 20% at 5x)
 	profitLevels := []struct {
 		multiplier decimal.Decimal
@@ -168,6 +326,41 @@ func (e *RealtimeExecutor) checkTakeProfitAndStopLoss(ctx context.Context, posit
 	}
 }
 
+// trailStop raises position's high water mark to price and returns its
+// stop: TrailingStopPercent below the high water mark, never lower than the
+// hard stop or than the stop already reached
+func trailStop(position *types.Position, price, hardStop decimal.Decimal) decimal.Decimal {
+	if !position.TrailingStopPercent.IsPositive() {
+		return hardStop
+	}
+	if price.GreaterThan(position.HighWaterMark) {
+		position.HighWaterMark = price
+	}
+
+	trailing := position.HighWaterMark.Mul(decimal.NewFromInt(1).Sub(position.TrailingStopPercent))
+	position.StopLoss = decimal.Max(hardStop, trailing, position.StopLoss)
+	return position.StopLoss
+}
+
+// takeATRProfits sells each ATR level once when price reaches it
+func (e *RealtimeExecutor) takeATRProfits(ctx context.Context, position *types.Position, price, atr decimal.Decimal) {
+	for _, level := range e.atrLadder.Levels {
+		targetPrice := level.Target(position.EntryPrice, atr)
+		if price.LessThan(targetPrice) || position.HasTakenProfitAt(level.Multiple) {
+			continue
+		}
+		takeSize := position.Size.Mul(level.Percentage)
+		if err := e.takeProfits(ctx, position, price, takeSize); err != nil {
+			e.logger.Error("Failed to take profits",
+				zap.String("symbol", position.Symbol),
+				zap.String("target_price", targetPrice.String()),
+				zap.Error(err))
+			continue
+		}
+		position.MarkTakenProfitAt(level.Multiple)
+	}
+}
+
 func (e *RealtimeExecutor) closePosition(ctx context.Context, position *types.Position, price decimal.Decimal) error {
 	trade := &types.Trade{
 		Symbol:    position.Symbol,
@@ -230,26 +423,33 @@ func (e *RealtimeExecutor) takeProfits(ctx context.Context, position *types.Posi
 }
 
 func (e *RealtimeExecutor) updatePosition(trade *types.Trade) {
+	size, price := trade.Filled(), trade.FillPrice()
+	if size.IsZero() {
+		return
+	}
+
 	value, ok := e.positions.Load(trade.Symbol)
 	if !ok {
 		position := &types.Position{
-			Symbol:     trade.Symbol,
-			Size:       trade.Size,
-			EntryPrice: trade.Price,
-			UpdatedAt: time.Now(),
+			Symbol:              trade.Symbol,
+			Size:                size,
+			EntryPrice:          price,
+			UpdatedAt:           time.Now(),
+			HighWaterMark:       price,
+			TrailingStopPercent: e.trailing,
 		}
 		e.positions.Store(trade.Symbol, position)
-		metrics.PumpPositionSize.WithLabelValues(trade.Symbol).Set(trade.Size.InexactFloat64())
+		metrics.PumpPositionSize.WithLabelValues(trade.Symbol).Set(size.InexactFloat64())
 		return
 	}
 
 	position := value.(*types.Position)
 	if trade.Side == types.OrderSideSell {
-		position.Size = position.Size.Sub(trade.Size)
+		position.Size = position.Size.Sub(size)
 	} else {
 		oldValue := position.Size.Mul(position.EntryPrice)
-		newValue := trade.Size.Mul(trade.Price)
-		totalSize := position.Size.Add(trade.Size)
+		newValue := size.Mul(price)
+		totalSize := position.Size.Add(size)
 		
 		if !totalSize.IsZero() {
 			position.EntryPrice = oldValue.Add(newValue).Div(totalSize)
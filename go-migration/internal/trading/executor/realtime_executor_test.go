@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/market/pump"
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestRealtimeExecutor_PartialFills(t *testing.T) {
+	fills := []string{
+		`{"data":{"tx_hash":"0x1","status":"confirmed","filled_amount":"6","avg_price":"101"}}`,
+		`{"data":{"tx_hash":"0x2","status":"confirmed","filled_amount":"2","avg_price":"103"}}`,
+	}
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Write([]byte(fills[n-1]))
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	riskMgr := risk.NewManager(risk.Limits{MaxPositionSize: decimal.NewFromInt(100)}, logger)
+	e := NewRealtimeExecutor(logger, provider, riskMgr, "key")
+
+	partials := testutil.ToFloat64(metrics.PumpPartialFills)
+	trade := &types.Trade{Symbol: "PEPE", Side: types.OrderSideBuy, Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}
+	require.NoError(t, e.ExecuteTrade(context.Background(), trade))
+
+	assert.Equal(t, types.OrderStatusPartial, trade.Status)
+	assert.True(t, decimal.NewFromInt(6).Equal(trade.FilledSize))
+	assert.True(t, decimal.NewFromInt(101).Equal(trade.AvgFillPrice))
+	assert.Equal(t, partials+1, testutil.ToFloat64(metrics.PumpPartialFills))
+
+	// The position holds only what filled, at the fill price
+	value, ok := e.positions.Load("PEPE")
+	require.True(t, ok)
+	position := value.(*types.Position)
+	assert.True(t, decimal.NewFromInt(6).Equal(position.Size))
+	assert.True(t, decimal.NewFromInt(101).Equal(position.EntryPrice))
+
+	// The remainder is queued once
+	require.Len(t, e.trades, 1)
+	remainder := <-e.trades
+	assert.True(t, remainder.Requeued)
+	assert.True(t, decimal.NewFromInt(4).Equal(remainder.Size))
+
+	// A partially filled remainder averages into the entry and is not requeued
+	require.NoError(t, e.ExecuteTrade(context.Background(), remainder))
+	assert.Len(t, e.trades, 0)
+	assert.Equal(t, partials+2, testutil.ToFloat64(metrics.PumpPartialFills))
+	assert.True(t, decimal.NewFromInt(8).Equal(position.Size))
+	assert.True(t, decimal.NewFromFloat(101.5).Equal(position.EntryPrice))
+}
+
+func TestRealtimeExecutor_UpdatePositionWithoutFillDetails(t *testing.T) {
+	e := NewRealtimeExecutor(zap.NewNop(), nil, nil, "key")
+	e.updatePosition(&types.Trade{Symbol: "PEPE", Side: types.OrderSideBuy, Size: decimal.NewFromInt(5), Price: decimal.NewFromInt(10)})
+	e.updatePosition(&types.Trade{Symbol: "PEPE", Side: types.OrderSideSell, Size: decimal.NewFromInt(5), FilledSize: decimal.NewFromInt(2)})
+
+	value, ok := e.positions.Load("PEPE")
+	require.True(t, ok)
+	position := value.(*types.Position)
+	assert.True(t, decimal.NewFromInt(3).Equal(position.Size))
+	assert.True(t, decimal.NewFromInt(10).Equal(position.EntryPrice))
+}
+
+func TestRealtimeExecutor_FillRatio(t *testing.T) {
+	fills := []string{
+		`{"data":{"tx_hash":"0x1","status":"confirmed","filled_amount":"6","avg_price":"100"}}`,
+		`{"data":{"tx_hash":"0x2","status":"confirmed"}}`,
+	}
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Write([]byte(fills[n-1]))
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	provider := pump.NewProvider(pump.Config{BaseURL: server.URL, TimeoutSec: 5}, logger)
+	riskMgr := risk.NewManager(risk.Limits{MaxPositionSize: decimal.NewFromInt(100)}, logger)
+	e := NewRealtimeExecutor(logger, provider, riskMgr, "key")
+	e.SetFillRatio(FillRatioConfig{Enabled: true, Window: 10})
+
+	ctx := context.Background()
+	require.NoError(t, e.ExecuteTrade(ctx, &types.Trade{Symbol: "PART", Side: types.OrderSideBuy, Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}))
+	require.NoError(t, e.ExecuteTrade(ctx, &types.Trade{Symbol: "FULL", Side: types.OrderSideBuy, Size: decimal.NewFromInt(5), Price: decimal.NewFromInt(100)}))
+
+	assert.Equal(t, 0.6, testutil.ToFloat64(metrics.OrderFillRatio.WithLabelValues("PART")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.OrderFillRatio.WithLabelValues("FULL")))
+	assert.InDelta(t, 11.0/15.0, testutil.ToFloat64(metrics.OrderFillRatioOverall), 1e-9)
+}
+
+func TestFillRatios_Window(t *testing.T) {
+	r := newFillRatios(FillRatioConfig{Enabled: true, Window: 2})
+	r.record("PEPE", decimal.NewFromInt(10), decimal.Zero)
+	r.record("PEPE", decimal.NewFromInt(10), decimal.NewFromInt(10))
+	ratio, _ := r.record("PEPE", decimal.NewFromInt(10), decimal.NewFromInt(5))
+
+	// The rejected first order has left the window
+	assert.True(t, decimal.NewFromFloat(0.75).Equal(ratio))
+}
+
+func TestTrailStop(t *testing.T) {
+	position := &types.Position{
+		Symbol:              "PEPE",
+		EntryPrice:          decimal.NewFromInt(100),
+		HighWaterMark:       decimal.NewFromInt(100),
+		TrailingStopPercent: decimal.NewFromFloat(0.05),
+	}
+	hardStop := decimal.NewFromInt(90)
+
+	// Near entry the trailing stop would sit above the hard stop already
+	assert.True(t, decimal.NewFromInt(95).Equal(trailStop(position, decimal.NewFromInt(100), hardStop)))
+
+	// It ratchets up with the price
+	assert.True(t, decimal.NewFromInt(114).Equal(trailStop(position, decimal.NewFromInt(120), hardStop)))
+	assert.True(t, decimal.NewFromInt(120).Equal(position.HighWaterMark))
+
+	// and never back down
+	assert.True(t, decimal.NewFromInt(114).Equal(trailStop(position, decimal.NewFromInt(115), hardStop)))
+
+	// A wide trail is floored at the hard stop
+	wide := &types.Position{EntryPrice: decimal.NewFromInt(100), TrailingStopPercent: decimal.NewFromFloat(0.5)}
+	assert.True(t, hardStop.Equal(trailStop(wide, decimal.NewFromInt(110), hardStop)))
+
+	// Without a trailing percent the hard stop applies
+	static := &types.Position{EntryPrice: decimal.NewFromInt(100)}
+	assert.True(t, hardStop.Equal(trailStop(static, decimal.NewFromInt(150), hardStop)))
+}
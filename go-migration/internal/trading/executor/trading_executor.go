@@ -13,3 +13,10 @@ type TradingExecutor interface {
 	Start() error
 	Stop() error
 }
+
+// FillReporter is implemented by executors that report the trade each
+// executed signal made, so callers book what actually filled rather than
+// assuming the signal filled in full at its price
+type FillReporter interface {
+	ExecuteTradeFill(ctx context.Context, signal *types.Signal) (*types.Trade, error)
+}
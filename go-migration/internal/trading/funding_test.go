@@ -0,0 +1,55 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// lockingFunding returns a fixed rate after taking the engine's write lock,
+// which deadlocks if the engine holds its lock while fetching rates
+type lockingFunding struct {
+	engine *Engine
+	rate   decimal.Decimal
+}
+
+func (f *lockingFunding) FundingRate(ctx context.Context, symbol string, at time.Time) (decimal.Decimal, error) {
+	f.engine.mu.Lock()
+	f.engine.mu.Unlock()
+	return f.rate, nil
+}
+
+func TestEngine_ApplyFundingFetchesRatesUnlocked(t *testing.T) {
+	engine := NewEngine(Config{
+		Funding: types.FundingConfig{Interval: time.Hour, Symbols: []string{"BONK-PERP"}},
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	engine.SetFundingProvider(&lockingFunding{engine: engine, rate: decimal.NewFromFloat(0.001)})
+
+	require.NoError(t, engine.ApplyFill(&types.Trade{Symbol: "BONK-PERP", Side: types.OrderSideBuy,
+		Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}))
+	require.NoError(t, engine.ApplyFill(&types.Trade{Symbol: "WIF", Side: types.OrderSideBuy,
+		Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)}))
+
+	done := make(chan struct{})
+	go func() {
+		engine.applyFunding(context.Background(), time.Now())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("applyFunding held the engine lock while fetching rates")
+	}
+
+	// The long pays 0.1% of its notional of 1000, unfunded symbols pay nothing
+	assert.True(t, engine.positions["BONK-PERP"].RealizedPnL.Equal(decimal.NewFromInt(-1)), "realized %s", engine.positions["BONK-PERP"].RealizedPnL)
+	assert.True(t, engine.positions["WIF"].RealizedPnL.IsZero())
+}
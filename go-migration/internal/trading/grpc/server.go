@@ -8,6 +8,7 @@ import (
 
 	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"go.uber.org/zap"
 
 	pb "github.com/kwanRoshi/B/go-migration/proto"
@@ -73,6 +74,13 @@ func (s *Server) PlaceOrder(ctx context.Context, req *pb.Order) (*pb.OrderRespon
 		CreatedAt: time.Unix(req.CreatedAt, 0),
 		UpdatedAt: time.Unix(req.UpdatedAt, 0),
 	}
+	// Retrying clients send the same idempotency key so a timed out
+	// submission is not placed twice
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get("idempotency-key"); len(keys) > 0 {
+			order.ClientRequestID = keys[0]
+		}
+	}
 
 	if err := s.service.PlaceOrder(ctx, order); err != nil {
 		return nil, fmt.Errorf("failed to place order: %w", err)
@@ -95,6 +103,28 @@ func (s *Server) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*
 	}, nil
 }
 
+func (s *Server) ReplaceOrder(ctx context.Context, req *pb.ReplaceOrderRequest) (*pb.OrderResponse, error) {
+	price, err := decimal.NewFromString(req.Price)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
+
+	size, err := decimal.NewFromString(req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size: %w", err)
+	}
+
+	order, err := s.service.ReplaceOrder(ctx, req.OrderId, price, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace order: %w", err)
+	}
+
+	return &pb.OrderResponse{
+		OrderId: order.ID,
+		Status:  "success",
+	}, nil
+}
+
 func (s *Server) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
 	order, err := s.service.GetOrder(ctx, req.OrderId)
 	if err != nil {
@@ -213,6 +243,22 @@ func (s *Server) GetPositions(ctx context.Context, req *pb.GetPositionsRequest)
 	return &pb.PositionList{Positions: pbPositions}, nil
 }
 
+func (s *Server) GetPortfolioSummary(ctx context.Context, req *pb.GetPortfolioSummaryRequest) (*pb.PortfolioSummary, error) {
+	summary, err := s.service.GetPortfolioSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio summary: %w", err)
+	}
+
+	return &pb.PortfolioSummary{
+		Base:          summary.Base,
+		Positions:     int32(summary.Positions),
+		TotalNotional: summary.TotalNotional.String(),
+		AvgEntryPrice: summary.AvgEntryPrice.String(),
+		AvgMarkPrice:  summary.AvgMarkPrice.String(),
+		UnrealizedPnl: summary.UnrealizedPnL.String(),
+	}, nil
+}
+
 func (s *Server) SubscribeOrderBook(req *pb.SubscribeOrderBookRequest, stream pb.TradingService_SubscribeOrderBookServer) error {
 	ctx := stream.Context()
 	updates, err := s.service.SubscribeOrderBook(ctx, req.Symbol)
@@ -229,16 +275,16 @@ func (s *Server) SubscribeOrderBook(req *pb.SubscribeOrderBookRequest, stream pb
 		pbUpdate.Bids = make([]*pb.PriceLevel, len(update.Bids))
 		for i, bid := range update.Bids {
 			pbUpdate.Bids[i] = &pb.PriceLevel{
-				Price: fmt.Sprintf("%f", bid.Price),
-				Size:  fmt.Sprintf("%f", bid.Amount),
+				Price: bid.Price.String(),
+				Size:  bid.Amount.String(),
 			}
 		}
 
 		pbUpdate.Asks = make([]*pb.PriceLevel, len(update.Asks))
 		for i, ask := range update.Asks {
 			pbUpdate.Asks[i] = &pb.PriceLevel{
-				Price: fmt.Sprintf("%f", ask.Price),
-				Size:  fmt.Sprintf("%f", ask.Amount),
+				Price: ask.Price.String(),
+				Size:  ask.Amount.String(),
 			}
 		}
 
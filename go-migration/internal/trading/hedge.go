@@ -0,0 +1,121 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// HedgeConfig configures the market-neutral hedging overlay
+type HedgeConfig struct {
+	Enabled    bool            `yaml:"enabled"`
+	Instrument string          `yaml:"instrument"` // Symbol hedges are placed in
+	Provider   string          `yaml:"provider"`   // Executor hedges are routed through
+	Band       decimal.Decimal `yaml:"band"`       // Absolute net exposure tolerated without hedging
+	AutoPlace  bool            `yaml:"auto_place"` // Place suggested hedges instead of only reporting them
+}
+
+// HedgeSuggestion is the hedge needed to bring the book back to neutral
+type HedgeSuggestion struct {
+	Instrument  string          `json:"instrument"`
+	NetExposure decimal.Decimal `json:"net_exposure"`
+	Side        types.OrderSide `json:"side"`
+	Size        decimal.Decimal `json:"size"`
+	Price       decimal.Decimal `json:"price"`
+}
+
+// NetExposure returns the signed notional exposure across all executor
+// positions, hedge positions included. Positions are marked at their current
+// price, falling back to entry when no mark is available. Executors are
+// asked without the engine lock, since one may be blocked on a trade in
+// flight.
+func (e *Engine) NetExposure() decimal.Decimal {
+	e.mu.RLock()
+	executors := e.copyExecutors()
+	e.mu.RUnlock()
+
+	exposure := decimal.Zero
+	for _, exec := range executors {
+		for _, pos := range exec.GetPositions() {
+			price := pos.CurrentPrice
+			if price.IsZero() {
+				price = pos.EntryPrice
+			}
+			exposure = exposure.Add(pos.Size.Mul(price))
+		}
+	}
+	return exposure
+}
+
+// SuggestHedge computes the hedge in the configured instrument, priced at
+// hedgePrice, that offsets the current net exposure. It returns nil when the
+// exposure is already inside the band.
+func (e *Engine) SuggestHedge(hedgePrice decimal.Decimal) (*HedgeSuggestion, error) {
+	if !hedgePrice.IsPositive() {
+		return nil, fmt.Errorf("invalid hedge price: %s", hedgePrice)
+	}
+
+	exposure := e.NetExposure()
+	metrics.NetExposure.Set(exposure.InexactFloat64())
+	if exposure.Abs().LessThanOrEqual(e.config.Hedge.Band) {
+		metrics.SuggestedHedgeSize.WithLabelValues(e.config.Hedge.Instrument).Set(0)
+		return nil, nil
+	}
+
+	side := types.OrderSideSell
+	if exposure.IsNegative() {
+		side = types.OrderSideBuy
+	}
+
+	size := exposure.Abs().Div(hedgePrice)
+	metrics.SuggestedHedgeSize.WithLabelValues(e.config.Hedge.Instrument).Set(exposure.Neg().Div(hedgePrice).InexactFloat64())
+
+	return &HedgeSuggestion{
+		Instrument:  e.config.Hedge.Instrument,
+		NetExposure: exposure,
+		Side:        side,
+		Size:        size,
+		Price:       hedgePrice,
+	}, nil
+}
+
+// Hedge computes a hedge suggestion and, when auto placement is enabled,
+// executes it through the configured provider
+func (e *Engine) Hedge(ctx context.Context, hedgePrice decimal.Decimal) (*HedgeSuggestion, error) {
+	if !e.config.Hedge.Enabled {
+		return nil, fmt.Errorf("hedging is disabled")
+	}
+
+	suggestion, err := e.SuggestHedge(hedgePrice)
+	if err != nil || suggestion == nil {
+		return nil, err
+	}
+
+	e.logger.Info("Hedge suggested",
+		zap.String("instrument", suggestion.Instrument),
+		zap.String("net_exposure", suggestion.NetExposure.String()),
+		zap.String("side", string(suggestion.Side)),
+		zap.String("size", suggestion.Size.String()))
+
+	if !e.config.Hedge.AutoPlace {
+		return suggestion, nil
+	}
+
+	trade := &types.Trade{
+		Symbol:    suggestion.Instrument,
+		Side:      suggestion.Side,
+		Size:      suggestion.Size,
+		Price:     suggestion.Price,
+		Provider:  e.config.Hedge.Provider,
+		Timestamp: e.now(),
+	}
+	if err := e.ExecuteTrade(ctx, trade); err != nil {
+		return suggestion, fmt.Errorf("failed to place hedge: %w", err)
+	}
+	return suggestion, nil
+}
@@ -0,0 +1,57 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type bookExecutor struct {
+	recordingExecutor
+	positions map[string]*types.Position
+}
+
+func (b *bookExecutor) GetPositions() map[string]*types.Position { return b.positions }
+
+func TestEngine_SuggestHedgeForNetLongBook(t *testing.T) {
+	engine := NewEngine(Config{
+		Hedge: HedgeConfig{
+			Enabled:    true,
+			Instrument: "SOL-PERP",
+			Provider:   "pump.fun",
+			Band:       decimal.NewFromInt(100),
+			AutoPlace:  true,
+		},
+	}, zap.NewNop(), new(MockStorage))
+
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(1000), CurrentPrice: decimal.NewFromFloat(1.5)},
+		"PUMP": {Symbol: "PUMP", Size: decimal.NewFromInt(-200), EntryPrice: decimal.NewFromInt(2)},
+	}}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	// 1000*1.5 - 200*2 = 1100 net long
+	assert.True(t, decimal.NewFromInt(1100).Equal(engine.NetExposure()))
+	assert.False(t, exec.locked)
+
+	suggestion, err := engine.Hedge(context.Background(), decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	assert.Equal(t, "SOL-PERP", suggestion.Instrument)
+	assert.Equal(t, types.OrderSideSell, suggestion.Side)
+	assert.True(t, decimal.NewFromInt(22).Equal(suggestion.Size), suggestion.Size.String())
+
+	assert.Len(t, exec.signals, 1)
+	assert.Equal(t, "SOL-PERP", exec.signals[0].Symbol)
+	assert.Equal(t, types.SignalType(types.OrderSideSell), exec.signals[0].Type)
+
+	// Once the hedge is on the book the exposure is inside the band
+	exec.positions["SOL-PERP"] = &types.Position{Symbol: "SOL-PERP", Size: decimal.NewFromInt(-22), CurrentPrice: decimal.NewFromInt(50)}
+	suggestion, err = engine.SuggestHedge(decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	assert.Nil(t, suggestion)
+}
@@ -3,7 +3,6 @@ package interfaces
 import (
 	"context"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
-	"github.com/shopspring/decimal"
 )
 
 type Executor interface {
@@ -11,9 +10,5 @@ type Executor interface {
 	GetRiskManager() RiskManager
 }
 
-type RiskManager interface {
-	ValidatePosition(symbol string, size decimal.Decimal) error
-	CalculatePositionSize(symbol string, price decimal.Decimal) (decimal.Decimal, error)
-	UpdateStopLoss(symbol string, price decimal.Decimal) error
-	CheckTakeProfit(symbol string, price decimal.Decimal) (bool, decimal.Decimal)
-}
+// RiskManager is the risk manager executors expose to strategies
+type RiskManager = types.PumpRiskManager
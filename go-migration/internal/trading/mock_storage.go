@@ -1,6 +1,8 @@
 package trading
 
 import (
+	"time"
+
 	"github.com/kwanRoshi/B/go-migration/internal/types"
 	"github.com/stretchr/testify/mock"
 )
@@ -38,3 +40,13 @@ func (m *MockStorage) GetPositions(userID string) ([]*types.Position, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]*types.Position), args.Error(1)
 }
+
+func (m *MockStorage) SaveEquityPoint(point *types.EquityPoint) error {
+	args := m.Called(point)
+	return args.Error(0)
+}
+
+func (m *MockStorage) LoadEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error) {
+	args := m.Called(strategy, from, to)
+	return args.Get(0).([]*types.EquityPoint), args.Error(1)
+}
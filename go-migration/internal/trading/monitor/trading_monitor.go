@@ -73,7 +73,9 @@ func (m *TradingMonitor) UpdatePosition(symbol string, position *types.Position)
 	m.lastUpdate = time.Now()
 
 	m.metrics.PositionSize.WithLabelValues(symbol).Set(position.Size.InexactFloat64())
-	m.metrics.PositionValue.WithLabelValues(symbol).Set(position.Value().InexactFloat64())
+	m.metrics.RiskExposure.WithLabelValues(symbol).Set(position.Value.InexactFloat64())
+	metrics.SymbolSeries.Track(symbol, m.metrics.PositionSize, symbol)
+	metrics.SymbolSeries.Track(symbol, m.metrics.RiskExposure, symbol)
 }
 
 func (m *TradingMonitor) AddTrade(trade *types.Trade) {
@@ -81,8 +83,7 @@ func (m *TradingMonitor) AddTrade(trade *types.Trade) {
 	defer m.mu.Unlock()
 
 	m.trades[trade.Symbol] = append(m.trades[trade.Symbol], trade)
-	m.metrics.TradeCount.WithLabelValues(trade.Symbol).Inc()
-	m.metrics.TradeVolume.WithLabelValues(trade.Symbol).Add(trade.Size.InexactFloat64())
+	m.metrics.TradeExecutions.WithLabelValues(string(trade.Status)).Inc()
 }
 
 func (m *TradingMonitor) updateMetrics() {
@@ -91,13 +92,13 @@ func (m *TradingMonitor) updateMetrics() {
 
 	totalValue := decimal.Zero
 	for symbol, pos := range m.positions {
-		value := pos.Value()
-		totalValue = totalValue.Add(value)
-		
-		m.metrics.PositionPnL.WithLabelValues(symbol).Set(pos.UnrealizedPnL().InexactFloat64())
+		totalValue = totalValue.Add(pos.Value)
+
+		m.metrics.UnrealizedPnL.WithLabelValues(symbol).Set(pos.UnrealizedPnL.InexactFloat64())
+		metrics.SymbolSeries.Track(symbol, m.metrics.UnrealizedPnL, symbol)
 	}
 
-	m.metrics.TotalValue.Set(totalValue.InexactFloat64())
+	m.logger.Debug("Position values updated", zap.String("total_value", totalValue.String()))
 	m.metrics.LastUpdate.Set(float64(m.lastUpdate.Unix()))
 }
 
@@ -105,11 +106,15 @@ func (m *TradingMonitor) checkTradeStatus() {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	for symbol, trades := range m.trades {
+	pending := 0
+	for _, trades := range m.trades {
 		for _, trade := range trades {
-			if trade.Status == types.TradeStatusPending {
-				m.metrics.PendingTrades.WithLabelValues(symbol).Inc()
+			if trade.Status == types.OrderStatusPending {
+				pending++
 			}
 		}
 	}
+	if pending > 0 {
+		m.logger.Debug("Trades pending", zap.Int("count", pending))
+	}
 }
@@ -0,0 +1,157 @@
+package trading
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ApplyFill nets an executed trade into the position in its symbol.
+// Negative sizes are short positions. A fill on the side of the position
+// grows it at the size-weighted entry price. An opposite fill reduces it and
+// moves the PnL of the reduced size into RealizedPnL; a position reduced to
+// zero is closed, and any remainder opens a new position at the fill price.
+// Each fill's fee is deducted from the RealizedPnL of the position it lands
+// in, and the PnL a reducing fill realizes is net of its fee.
+// Realized PnL is recorded against the trade's provider through
+// RecordRealizedPnL, and closes through RecordClosedTrade, so it outlives
+// the positions it was realized on.
+func (e *Engine) ApplyFill(trade *types.Trade) error {
+	size, price, err := signedFill(trade)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	reduces := false
+	if pos, ok := e.positions[trade.Symbol]; ok && pos.Size.Sign() == -size.Sign() {
+		reduces = true
+	}
+	pnl, closed := e.netFill(e.positions, trade, size, price)
+	var persist *types.Position
+	if closed != nil {
+		persist = e.positions[trade.Symbol]
+		if persist == nil {
+			persist = flatPosition(closed)
+		}
+	}
+	e.mu.Unlock()
+
+	switch {
+	case closed != nil:
+		metrics.PositionsClosed.Inc()
+		e.RecordClosedTrade(trade.Provider, closed, pnl)
+		e.reportOutcome(trade.Symbol, closed.RealizedPnL)
+	case reduces:
+		e.RecordRealizedPnL(trade.Provider, pnl)
+	}
+
+	// A closed position leaves the book, so storage is updated here rather
+	// than by savePositions: with the position it flipped into, or with the
+	// closed position at zero size
+	if persist != nil {
+		if err := e.storage.SavePosition(persist); err != nil {
+			e.logger.Error("Failed to save closed position",
+				zap.String("symbol", trade.Symbol),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// flatPosition returns a copy of a closed position at zero size
+func flatPosition(closed *types.Position) *types.Position {
+	return &types.Position{
+		UserID:       closed.UserID,
+		Symbol:       closed.Symbol,
+		EntryPrice:   closed.EntryPrice,
+		CurrentPrice: closed.CurrentPrice,
+		RealizedPnL:  closed.RealizedPnL,
+		CreatedAt:    closed.CreatedAt,
+		UpdatedAt:    closed.UpdatedAt,
+		StopLoss:     closed.StopLoss,
+	}
+}
+
+// signedFill returns the filled size of trade, negative for sells, and its
+// fill price
+func signedFill(trade *types.Trade) (decimal.Decimal, decimal.Decimal, error) {
+	size := trade.Filled()
+	if !size.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("trade %s has no filled size", trade.ID)
+	}
+	switch trade.Side {
+	case types.OrderSideBuy:
+	case types.OrderSideSell:
+		size = size.Neg()
+	default:
+		return decimal.Zero, decimal.Zero, fmt.Errorf("trade %s has unknown side %q", trade.ID, trade.Side)
+	}
+	return size, trade.FillPrice(), nil
+}
+
+// netFill nets a fill of signed size at price into the position in the
+// trade's symbol among positions. It returns the PnL the fill realized and
+// the position it closed, if any.
+func (e *Engine) netFill(positions map[string]*types.Position, trade *types.Trade, size, price decimal.Decimal) (decimal.Decimal, *types.Position) {
+	pos, exists := positions[trade.Symbol]
+	if !exists || pos.Size.IsZero() {
+		pos = e.openPosition(trade, size, price)
+		pos.RealizedPnL = trade.Fee.Neg()
+		positions[trade.Symbol] = pos
+		return decimal.Zero, nil
+	}
+
+	if pos.Size.Sign() == size.Sign() {
+		total := pos.Size.Add(size)
+		pos.EntryPrice = pos.EntryPrice.Mul(pos.Size).Add(price.Mul(size)).Div(total)
+		pos.Size = total
+		pos.RealizedPnL = pos.RealizedPnL.Sub(trade.Fee)
+		e.markPosition(pos, price)
+		return decimal.Zero, nil
+	}
+
+	reduced := decimal.Min(pos.Size.Abs(), size.Abs())
+	pnl := price.Sub(pos.EntryPrice).Mul(reduced)
+	if pos.Size.IsNegative() {
+		pnl = pnl.Neg()
+	}
+	pnl = pnl.Sub(trade.Fee)
+	pos.RealizedPnL = pos.RealizedPnL.Add(pnl)
+
+	remaining := pos.Size.Add(size)
+	if remaining.Sign() == pos.Size.Sign() {
+		pos.Size = remaining
+		e.markPosition(pos, price)
+		return pnl, nil
+	}
+
+	delete(positions, trade.Symbol)
+	if !remaining.IsZero() {
+		positions[trade.Symbol] = e.openPosition(trade, remaining, price)
+	}
+	return pnl, pos
+}
+
+// openPosition starts a position of size at price for the trade's user,
+// with the trade's stop as its initial stop
+func (e *Engine) openPosition(trade *types.Trade, size, price decimal.Decimal) *types.Position {
+	pos := types.NewPosition(trade.Symbol, size, price)
+	pos.UserID = trade.UserID
+	pos.StopLoss = trade.StopLoss
+	pos.CreatedAt = e.now()
+	pos.UpdatedAt = pos.CreatedAt
+	return pos
+}
+
+// markPosition revalues pos at the latest fill price
+func (e *Engine) markPosition(pos *types.Position, price decimal.Decimal) {
+	pos.CurrentPrice = price
+	pos.Value = pos.Size.Mul(price)
+	pos.UnrealizedPnL = price.Sub(pos.EntryPrice).Mul(pos.Size)
+	pos.UpdatedAt = e.now()
+}
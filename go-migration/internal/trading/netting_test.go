@@ -0,0 +1,194 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func fill(side types.OrderSide, size, price float64) *types.Trade {
+	return &types.Trade{
+		ID:     "t",
+		Symbol: "BONK",
+		Side:   side,
+		Size:   decimal.NewFromFloat(size),
+		Price:  decimal.NewFromFloat(price),
+	}
+}
+
+func TestEngine_ApplyFillLongFlatShort(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+	closed := testutil.ToFloat64(metrics.PositionsClosed)
+
+	// Two buys average the entry price
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 10, 1.0)))
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 10, 2.0)))
+	pos := engine.positions["BONK"]
+	assert.True(t, pos.Size.Equal(decimal.NewFromInt(20)), "size %s", pos.Size)
+	assert.True(t, pos.EntryPrice.Equal(decimal.NewFromFloat(1.5)), "entry %s", pos.EntryPrice)
+
+	// A partial sell realizes the PnL of the reduced size only
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 5, 2.5)))
+	assert.True(t, pos.Size.Equal(decimal.NewFromInt(15)), "size %s", pos.Size)
+	assert.True(t, pos.EntryPrice.Equal(decimal.NewFromFloat(1.5)), "entry %s", pos.EntryPrice)
+	assert.True(t, pos.RealizedPnL.Equal(decimal.NewFromInt(5)), "realized %s", pos.RealizedPnL)
+
+	// Selling the rest goes flat and closes the position
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 15, 1.0)))
+	assert.True(t, pos.RealizedPnL.Equal(decimal.NewFromFloat(-2.5)), "realized %s", pos.RealizedPnL)
+	assert.NotContains(t, engine.positions, "BONK")
+	assert.Equal(t, closed+1, testutil.ToFloat64(metrics.PositionsClosed))
+	// The realized PnL outlives the closed position
+	assert.True(t, engine.realized[""].Equal(decimal.NewFromFloat(-2.5)), "realized %s", engine.realized[""])
+
+	// A sell from flat opens a short
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 4, 3.0)))
+	short := engine.positions["BONK"]
+	assert.True(t, short.Size.Equal(decimal.NewFromInt(-4)), "size %s", short.Size)
+	assert.True(t, short.EntryPrice.Equal(decimal.NewFromInt(3)), "entry %s", short.EntryPrice)
+
+	// Covering part of the short below entry is a gain
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 2, 2.0)))
+	assert.True(t, short.Size.Equal(decimal.NewFromInt(-2)), "size %s", short.Size)
+	assert.True(t, short.RealizedPnL.Equal(decimal.NewFromInt(2)), "realized %s", short.RealizedPnL)
+	assert.Equal(t, closed+1, testutil.ToFloat64(metrics.PositionsClosed))
+}
+
+func TestEngine_ApplyFillFlipsThroughFlat(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+	closed := testutil.ToFloat64(metrics.PositionsClosed)
+
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 10, 1.0)))
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 15, 2.0)))
+
+	// The long closes and the remainder opens a fresh short at the fill price
+	assert.Equal(t, closed+1, testutil.ToFloat64(metrics.PositionsClosed))
+	short := engine.positions["BONK"]
+	require.NotNil(t, short)
+	assert.True(t, short.Size.Equal(decimal.NewFromInt(-5)), "size %s", short.Size)
+	assert.True(t, short.EntryPrice.Equal(decimal.NewFromInt(2)), "entry %s", short.EntryPrice)
+	assert.True(t, short.RealizedPnL.IsZero(), "realized %s", short.RealizedPnL)
+}
+
+func TestEngine_ApplyFillPersistsClosedPositions(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	engine := NewEngine(Config{}, zap.NewNop(), store)
+
+	// A closed position is stored at zero size
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 10, 1.0)))
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 10, 2.0)))
+	stored, err := store.GetPosition("BONK")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Size.IsZero(), "size %s", stored.Size)
+	assert.True(t, stored.RealizedPnL.Equal(decimal.NewFromInt(10)), "realized %s", stored.RealizedPnL)
+
+	// One that flips is replaced by the position it flipped into
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideBuy, 10, 1.0)))
+	require.NoError(t, engine.ApplyFill(fill(types.OrderSideSell, 15, 2.0)))
+	stored, err = store.GetPosition("BONK")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Size.Equal(decimal.NewFromInt(-5)), "size %s", stored.Size)
+}
+
+func TestEngine_ApplyFillRejectsUnknownSide(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+	assert.Error(t, engine.ApplyFill(fill("hold", 1, 1.0)))
+	assert.Error(t, engine.ApplyFill(fill(types.OrderSideBuy, 0, 1.0)))
+	assert.Empty(t, engine.positions)
+}
+
+func TestEngine_ProcessSignalBooksFills(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+	require.NoError(t, engine.RegisterExecutor("pump.fun", &recordingExecutor{}))
+
+	signal := func(side types.SignalType, price int64) *types.Signal {
+		return &types.Signal{Symbol: "BONK", Type: side, Provider: "pump.fun",
+			Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(price)}
+	}
+	require.NoError(t, engine.ProcessSignal(context.Background(), signal(types.SignalTypeBuy, 1)))
+	pos := engine.positions["BONK"]
+	require.NotNil(t, pos)
+	assert.True(t, pos.Size.Equal(decimal.NewFromInt(10)), "size %s", pos.Size)
+
+	// Closing the position credits the venue's realized PnL
+	require.NoError(t, engine.ProcessSignal(context.Background(), signal(types.SignalTypeSell, 3)))
+	assert.NotContains(t, engine.positions, "BONK")
+	assert.True(t, engine.realized["pump.fun"].Equal(decimal.NewFromInt(20)), "realized %s", engine.realized["pump.fun"])
+	assert.Equal(t, 1, engine.period.trades)
+
+	// Both fills count towards the venue's shortfall and turnover
+	assert.Equal(t, 2, engine.GetShortfall("pump.fun").Trades)
+	assert.Len(t, engine.fills["pump.fun"], 2)
+}
+
+// liquidityExecutor reports its fills at the signal price with a fixed
+// liquidity role
+type liquidityExecutor struct {
+	recordingExecutor
+	role types.LiquidityRole
+}
+
+func (l *liquidityExecutor) ExecuteTradeFill(ctx context.Context, signal *types.Signal) (*types.Trade, error) {
+	side := types.OrderSideBuy
+	if signal.Type == types.SignalTypeSell {
+		side = types.OrderSideSell
+	}
+	return &types.Trade{Symbol: signal.Symbol, Side: side, Price: signal.Price, Size: signal.Amount,
+		Liquidity: l.role, Status: types.OrderStatusFilled}, nil
+}
+
+func TestEngine_FillFeesNetIntoRealizedPnL(t *testing.T) {
+	engine := NewEngine(Config{
+		Fees: types.FeeSchedule{TakerFee: decimal.NewFromFloat(0.01), MakerRebate: decimal.NewFromFloat(0.002)},
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	taker := &liquidityExecutor{role: types.LiquidityTaker}
+	maker := &liquidityExecutor{role: types.LiquidityMaker}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", taker))
+	require.NoError(t, engine.RegisterExecutor("limits", maker))
+	ctx := context.Background()
+
+	// The taker entry pays 1% of its notional of 10
+	require.NoError(t, engine.ProcessSignal(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "pump.fun",
+		Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(1)}))
+	pos := engine.positions["BONK"]
+	require.NotNil(t, pos)
+	assert.True(t, pos.RealizedPnL.Equal(decimal.NewFromFloat(-0.1)), "realized %s", pos.RealizedPnL)
+
+	// The resting exit is credited the rebate on its notional of 30
+	require.NoError(t, engine.ProcessSignal(ctx, &types.Signal{Symbol: "BONK", Type: types.SignalTypeSell, Provider: "limits",
+		Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(3)}))
+	assert.NotContains(t, engine.positions, "BONK")
+	assert.True(t, engine.realized["limits"].Equal(decimal.NewFromFloat(20.06)), "realized %s", engine.realized["limits"])
+	assert.True(t, pos.RealizedPnL.Equal(decimal.NewFromFloat(19.96)), "realized %s", pos.RealizedPnL)
+}
+
+func TestEngine_ExecuteTradeRestingLimitIsMaker(t *testing.T) {
+	engine := NewEngine(Config{
+		Fees: types.FeeSchedule{TakerFee: decimal.NewFromFloat(0.01), MakerRebate: decimal.NewFromFloat(0.002)},
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	require.NoError(t, engine.RegisterExecutor("pump.fun", &recordingExecutor{}))
+	engine.orders["resting"] = &types.Order{ID: "resting", Symbol: "BONK", Type: types.OrderTypeLimit}
+	ctx := context.Background()
+
+	limit := &types.Trade{OrderID: "resting", Symbol: "BONK", Side: types.OrderSideBuy, Provider: "pump.fun",
+		Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(2)}
+	require.NoError(t, engine.ExecuteTrade(ctx, limit))
+	assert.Equal(t, types.LiquidityMaker, limit.Liquidity)
+	assert.True(t, limit.Fee.Equal(decimal.NewFromFloat(-0.04)), "fee %s", limit.Fee)
+
+	market := &types.Trade{Symbol: "BONK", Side: types.OrderSideBuy, Provider: "pump.fun",
+		Size: decimal.NewFromInt(10), Price: decimal.NewFromInt(2)}
+	require.NoError(t, engine.ExecuteTrade(ctx, market))
+	assert.True(t, market.Fee.Equal(decimal.NewFromFloat(0.2)), "fee %s", market.Fee)
+}
@@ -0,0 +1,64 @@
+package trading
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// SignalOutcomes receives, for each position that closes, the signal that
+// opened it and whether the position closed at a profit
+type SignalOutcomes interface {
+	RecordSignalOutcome(signal *types.Signal, profitable bool)
+}
+
+// SetSignalOutcomes sets where the outcomes of the signals that open
+// positions are reported
+func (e *Engine) SetSignalOutcomes(outcomes SignalOutcomes) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.outcomes = outcomes
+	if e.entries == nil {
+		e.entries = make(map[string]types.Signal)
+	}
+}
+
+// noteEntry remembers signal as the entry of the position in its symbol
+// when its fill opened one, so the outcome can be reported once it closes
+func (e *Engine) noteEntry(signal *types.Signal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.outcomes == nil {
+		return
+	}
+	pos, ok := e.positions[signal.Symbol]
+	if !ok || pos.Size.IsZero() {
+		return
+	}
+	if _, ok := e.entries[signal.Symbol]; !ok {
+		e.entries[signal.Symbol] = copySignal(signal)
+	}
+}
+
+// reportOutcome reports the outcome of the entry signal of a position in
+// symbol that closed with pnl
+func (e *Engine) reportOutcome(symbol string, pnl decimal.Decimal) {
+	e.mu.Lock()
+	outcomes := e.outcomes
+	entry, ok := e.entries[symbol]
+	delete(e.entries, symbol)
+	e.mu.Unlock()
+
+	if outcomes == nil || !ok {
+		return
+	}
+	outcomes.RecordSignalOutcome(&entry, pnl.IsPositive())
+}
+
+// copySignal returns a copy of signal that shares no memory with it
+func copySignal(signal *types.Signal) types.Signal {
+	copied := *signal
+	copied.Indicators = append([]types.Indicator(nil), signal.Indicators...)
+	return copied
+}
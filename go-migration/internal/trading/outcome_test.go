@@ -0,0 +1,58 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type outcome struct {
+	source     string
+	profitable bool
+}
+
+type recordingOutcomes struct {
+	outcomes []outcome
+}
+
+func (r *recordingOutcomes) RecordSignalOutcome(signal *types.Signal, profitable bool) {
+	r.outcomes = append(r.outcomes, outcome{signal.Source, profitable})
+}
+
+func TestEngine_ReportsEntrySignalOutcome(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", &recordingExecutor{}))
+	outcomes := &recordingOutcomes{}
+	engine.SetSignalOutcomes(outcomes)
+
+	trade := func(side types.SignalType, amount, price float64, source string) {
+		assert.NoError(t, engine.ProcessSignal(context.Background(), &types.Signal{
+			Symbol:   "SOL",
+			Type:     side,
+			Amount:   decimal.NewFromFloat(amount),
+			Price:    decimal.NewFromFloat(price),
+			Provider: "pump.fun",
+			Source:   source,
+		}))
+	}
+
+	// Adding to and reducing the position keeps the signal that opened it
+	trade(types.SignalTypeBuy, 10, 100, "rsi")
+	trade(types.SignalTypeBuy, 10, 100, "macd")
+	trade(types.SignalTypeSell, 5, 105, "ema")
+	assert.Empty(t, outcomes.outcomes)
+	trade(types.SignalTypeSell, 15, 110, "ema")
+	assert.Equal(t, []outcome{{"rsi", true}}, outcomes.outcomes)
+
+	// A fill that flips the position closes it and opens the next one
+	trade(types.SignalTypeBuy, 10, 100, "macd")
+	trade(types.SignalTypeSell, 20, 90, "bb")
+	trade(types.SignalTypeBuy, 10, 95, "rsi")
+	assert.Equal(t, []outcome{{"rsi", true}, {"macd", false}, {"bb", false}}, outcomes.outcomes)
+}
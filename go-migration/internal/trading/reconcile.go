@@ -0,0 +1,128 @@
+package trading
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// PositionConflictMode selects how positions in one symbol held by more than
+// one executor are reported
+type PositionConflictMode string
+
+const (
+	// PositionConflictMerge nets them into a single position, the default
+	PositionConflictMerge PositionConflictMode = "merge"
+	// PositionConflictFlag reports each executor's position unmerged and
+	// returns the conflict for an operator to resolve
+	PositionConflictFlag PositionConflictMode = "flag"
+)
+
+// PositionConflict is a symbol held by more than one executor
+type PositionConflict struct {
+	Symbol    string   `json:"symbol"`
+	Executors []string `json:"executors"`
+}
+
+// ExecutorPositions returns the positions held across all executors,
+// reconciling symbols held by more than one per Config.PositionConflicts so
+// reports do not count them twice. Merged positions net the sizes and take
+// the size-weighted entry of the positions on the net side; the conflicts
+// found are returned in either mode. Negative sizes are short positions.
+// Executors are asked without the engine lock, since one may be blocked on
+// a trade in flight.
+func (e *Engine) ExecutorPositions() ([]*types.Position, []PositionConflict) {
+	e.mu.RLock()
+	executors := e.copyExecutors()
+	e.mu.RUnlock()
+
+	names := make([]string, 0, len(executors))
+	for name := range executors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bySymbol := make(map[string][]*types.Position)
+	holders := make(map[string][]string)
+	var symbols []string
+	for _, name := range names {
+		for symbol, pos := range executors[name].GetPositions() {
+			if _, seen := bySymbol[symbol]; !seen {
+				symbols = append(symbols, symbol)
+			}
+			bySymbol[symbol] = append(bySymbol[symbol], pos)
+			holders[symbol] = append(holders[symbol], name)
+		}
+	}
+	sort.Strings(symbols)
+
+	var positions []*types.Position
+	var conflicts []PositionConflict
+	for _, symbol := range symbols {
+		held := bySymbol[symbol]
+		if len(held) == 1 {
+			positions = append(positions, held[0])
+			continue
+		}
+
+		conflicts = append(conflicts, PositionConflict{Symbol: symbol, Executors: holders[symbol]})
+		e.logger.Warn("Symbol held by more than one executor",
+			zap.String("symbol", symbol),
+			zap.Strings("executors", holders[symbol]),
+			zap.String("mode", string(e.positionConflictMode())))
+		if e.positionConflictMode() == PositionConflictFlag {
+			positions = append(positions, held...)
+			continue
+		}
+		positions = append(positions, mergePositions(symbol, held))
+	}
+	metrics.PositionConflicts.Set(float64(len(conflicts)))
+	return positions, conflicts
+}
+
+func (e *Engine) positionConflictMode() PositionConflictMode {
+	if e.config.PositionConflicts == "" {
+		return PositionConflictMerge
+	}
+	return e.config.PositionConflicts
+}
+
+// mergePositions nets held into one position in symbol. Its entry is the
+// size-weighted entry of the positions on the side of the net, which the
+// opposite side only reduces, and its PnL the sum of theirs.
+func mergePositions(symbol string, held []*types.Position) *types.Position {
+	net := decimal.Zero
+	for _, pos := range held {
+		net = net.Add(pos.Size)
+	}
+
+	merged := &types.Position{Symbol: symbol, Size: net}
+	sideSize, sideCost := decimal.Zero, decimal.Zero
+	for _, pos := range held {
+		if pos.Size.Sign() == net.Sign() {
+			sideSize = sideSize.Add(pos.Size)
+			sideCost = sideCost.Add(pos.Size.Mul(pos.EntryPrice))
+		}
+		merged.RealizedPnL = merged.RealizedPnL.Add(pos.RealizedPnL)
+		merged.UnrealizedPnL = merged.UnrealizedPnL.Add(pos.UnrealizedPnL)
+		if pos.UpdatedAt.After(merged.UpdatedAt) || merged.CurrentPrice.IsZero() {
+			merged.CurrentPrice = pos.CurrentPrice
+			merged.UpdatedAt = pos.UpdatedAt
+		}
+		if merged.CreatedAt.IsZero() || pos.CreatedAt.Before(merged.CreatedAt) {
+			merged.CreatedAt = pos.CreatedAt
+		}
+	}
+	if !sideSize.IsZero() {
+		merged.EntryPrice = sideCost.Div(sideSize)
+	}
+	if merged.CurrentPrice.IsZero() {
+		merged.CurrentPrice = merged.EntryPrice
+	}
+	merged.Value = net.Mul(merged.CurrentPrice)
+	return merged
+}
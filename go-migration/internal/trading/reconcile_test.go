@@ -0,0 +1,71 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func conflictingEngine(t *testing.T, mode PositionConflictMode) *Engine {
+	engine := NewEngine(Config{PositionConflicts: mode}, zap.NewNop(), new(MockStorage))
+	require.NoError(t, engine.RegisterExecutor("pump.fun", &bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(100), EntryPrice: decimal.NewFromInt(1), RealizedPnL: decimal.NewFromInt(5)},
+		"WIF":  {Symbol: "WIF", Size: decimal.NewFromInt(10), EntryPrice: decimal.NewFromInt(2)},
+	}}))
+	require.NoError(t, engine.RegisterExecutor("gmgn", &bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(300), EntryPrice: decimal.NewFromInt(2), RealizedPnL: decimal.NewFromInt(-1)},
+	}}))
+	return engine
+}
+
+func TestEngine_ExecutorPositionsMergesConflicts(t *testing.T) {
+	positions, conflicts := conflictingEngine(t, "").ExecutorPositions()
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, PositionConflict{Symbol: "BONK", Executors: []string{"gmgn", "pump.fun"}}, conflicts[0])
+
+	require.Len(t, positions, 2)
+	bonk := positions[0]
+	assert.Equal(t, "BONK", bonk.Symbol)
+	assert.True(t, bonk.Size.Equal(decimal.NewFromInt(400)), "size %s", bonk.Size)
+	// (100*1 + 300*2) / 400
+	assert.True(t, bonk.EntryPrice.Equal(decimal.NewFromFloat(1.75)), "entry %s", bonk.EntryPrice)
+	assert.True(t, bonk.RealizedPnL.Equal(decimal.NewFromInt(4)), "realized %s", bonk.RealizedPnL)
+	assert.Equal(t, "WIF", positions[1].Symbol)
+}
+
+func TestEngine_ExecutorPositionsFlagsConflicts(t *testing.T) {
+	positions, conflicts := conflictingEngine(t, PositionConflictFlag).ExecutorPositions()
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "BONK", conflicts[0].Symbol)
+	assert.Len(t, positions, 3)
+}
+
+func TestEngine_ExecutorPositionsQueriesExecutorsUnlocked(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), new(MockStorage))
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"WIF": {Symbol: "WIF", Size: decimal.NewFromInt(10), EntryPrice: decimal.NewFromInt(2)},
+	}}}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	positions, _ := engine.ExecutorPositions()
+	assert.Len(t, positions, 1)
+	assert.False(t, exec.locked)
+}
+
+func TestMergePositions_OppositeSidesNet(t *testing.T) {
+	merged := mergePositions("BONK", []*types.Position{
+		{Symbol: "BONK", Size: decimal.NewFromInt(300), EntryPrice: decimal.NewFromInt(2)},
+		{Symbol: "BONK", Size: decimal.NewFromInt(-100), EntryPrice: decimal.NewFromInt(3)},
+	})
+
+	// The short only reduces the long, which keeps its entry
+	assert.True(t, merged.Size.Equal(decimal.NewFromInt(200)), "size %s", merged.Size)
+	assert.True(t, merged.EntryPrice.Equal(decimal.NewFromInt(2)), "entry %s", merged.EntryPrice)
+}
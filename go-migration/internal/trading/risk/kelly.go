@@ -0,0 +1,113 @@
+package risk
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// KellyFraction returns the full Kelly bet fraction f* = W - (1-W)/R for a
+// win rate W and a payoff ratio R (average win / average loss).
+func KellyFraction(winRate, payoff decimal.Decimal) decimal.Decimal {
+	if payoff.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	one := decimal.NewFromInt(1)
+	return winRate.Sub(one.Sub(winRate).Div(payoff))
+}
+
+type tradeStats struct {
+	wins      int
+	losses    int
+	grossWin  decimal.Decimal
+	grossLoss decimal.Decimal
+}
+
+// KellySizer tracks per-symbol trade outcomes and derives a clamped,
+// fractional Kelly bet from them.
+type KellySizer struct {
+	config types.KellyConfig
+	stats  map[string]*tradeStats
+	mu     sync.RWMutex
+}
+
+func NewKellySizer(config types.KellyConfig) *KellySizer {
+	return &KellySizer{
+		config: config,
+		stats:  make(map[string]*tradeStats),
+	}
+}
+
+// RecordTrade records the realized PnL of a closed trade for symbol.
+func (s *KellySizer) RecordTrade(symbol string, pnl decimal.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.stats[symbol]
+	if !ok {
+		st = &tradeStats{}
+		s.stats[symbol] = st
+	}
+
+	if pnl.IsPositive() {
+		st.wins++
+		st.grossWin = st.grossWin.Add(pnl)
+	} else {
+		st.losses++
+		st.grossLoss = st.grossLoss.Add(pnl.Abs())
+	}
+}
+
+// Fraction returns the bet fraction for symbol. The second return value is
+// false until MinTrades closed trades have been recorded, in which case the
+// caller should fall back to its default sizing.
+func (s *KellySizer) Fraction(symbol string) (decimal.Decimal, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st, ok := s.stats[symbol]
+	if !ok {
+		return decimal.Zero, false
+	}
+
+	total := st.wins + st.losses
+	if total == 0 || total < s.config.MinTrades {
+		return decimal.Zero, false
+	}
+
+	winRate := decimal.NewFromInt(int64(st.wins)).Div(decimal.NewFromInt(int64(total)))
+
+	var f decimal.Decimal
+	switch {
+	case st.losses == 0:
+		// No losses yet, the payoff ratio is unbounded and f* tends to W
+		f = winRate
+	case st.wins == 0:
+		f = decimal.Zero
+	default:
+		avgWin := st.grossWin.Div(decimal.NewFromInt(int64(st.wins)))
+		avgLoss := st.grossLoss.Div(decimal.NewFromInt(int64(st.losses)))
+		if avgLoss.IsZero() {
+			f = winRate
+		} else {
+			f = KellyFraction(winRate, avgWin.Div(avgLoss))
+		}
+	}
+
+	return s.clamp(f), true
+}
+
+func (s *KellySizer) clamp(f decimal.Decimal) decimal.Decimal {
+	if !s.config.Fraction.IsZero() {
+		f = f.Mul(s.config.Fraction)
+	}
+	if f.IsNegative() {
+		return decimal.Zero
+	}
+	if !s.config.MaxFraction.IsZero() && f.GreaterThan(s.config.MaxFraction) {
+		return s.config.MaxFraction
+	}
+	return f
+}
@@ -0,0 +1,59 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestKellyFraction(t *testing.T) {
+	// W = 0.6, R = 2 => f* = 0.6 - 0.4/2 = 0.4
+	f := KellyFraction(decimal.NewFromFloat(0.6), decimal.NewFromFloat(2))
+	assert.True(t, decimal.NewFromFloat(0.4).Equal(f), "got %s", f)
+
+	// Negative edge: W = 0.3, R = 1 => f* = -0.4
+	f = KellyFraction(decimal.NewFromFloat(0.3), decimal.NewFromFloat(1))
+	assert.True(t, decimal.NewFromFloat(-0.4).Equal(f), "got %s", f)
+}
+
+func TestKellySizer_Fraction(t *testing.T) {
+	sizer := NewKellySizer(types.KellyConfig{
+		Enabled:     true,
+		Fraction:    decimal.NewFromInt(1),
+		MaxFraction: decimal.NewFromFloat(0.25),
+		MinTrades:   5,
+	})
+
+	// Not enough history yet
+	for i := 0; i < 4; i++ {
+		sizer.RecordTrade("TEST", decimal.NewFromInt(20))
+	}
+	_, ok := sizer.Fraction("TEST")
+	assert.False(t, ok)
+
+	// 3 wins of 20, 2 losses of 10: W = 0.6, R = 2 => f* = 0.4, clamped to 0.25
+	sizer = NewKellySizer(types.KellyConfig{
+		Enabled:     true,
+		Fraction:    decimal.NewFromInt(1),
+		MaxFraction: decimal.NewFromFloat(0.25),
+		MinTrades:   5,
+	})
+	for i := 0; i < 3; i++ {
+		sizer.RecordTrade("TEST", decimal.NewFromInt(20))
+	}
+	for i := 0; i < 2; i++ {
+		sizer.RecordTrade("TEST", decimal.NewFromInt(-10))
+	}
+	f, ok := sizer.Fraction("TEST")
+	assert.True(t, ok)
+	assert.True(t, decimal.NewFromFloat(0.25).Equal(f), "got %s", f)
+
+	// Half-Kelly stays under the clamp: 0.4 * 0.5 = 0.2
+	sizer.config.Fraction = decimal.NewFromFloat(0.5)
+	f, ok = sizer.Fraction("TEST")
+	assert.True(t, ok)
+	assert.True(t, decimal.NewFromFloat(0.2).Equal(f), "got %s", f)
+}
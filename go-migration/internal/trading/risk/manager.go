@@ -14,14 +14,28 @@ import (
 type Manager struct {
 	logger *zap.Logger
 	config *types.RiskConfig
+	kelly  *KellySizer
 	mu     sync.RWMutex
 }
 
 func NewRiskManager(config *types.RiskConfig, logger *zap.Logger) *Manager {
-	return &Manager{
+	m := &Manager{
 		logger: logger,
 		config: config,
 	}
+	if config.Kelly.Enabled {
+		m.kelly = NewKellySizer(config.Kelly)
+	}
+	return m
+}
+
+// RecordTradeResult feeds the realized PnL of a closed trade into the
+// Kelly sizer, if enabled.
+func (m *Manager) RecordTradeResult(symbol string, pnl decimal.Decimal) {
+	if m.kelly == nil {
+		return
+	}
+	m.kelly.RecordTrade(symbol, pnl)
 }
 
 func (m *Manager) ValidatePosition(symbol string, size decimal.Decimal) error {
@@ -67,6 +81,14 @@ func (m *Manager) CalculatePositionSize(symbol string, price decimal.Decimal) (d
 		}
 	}
 
+	// Scale by the Kelly bet once enough history exists for the symbol
+	if m.kelly != nil {
+		if fraction, ok := m.kelly.Fraction(symbol); ok {
+			size = size.Mul(fraction)
+			metrics.GMGNRiskLimits.WithLabelValues("kelly_fraction").Set(fraction.InexactFloat64())
+		}
+	}
+
 	metrics.GMGNRiskLimits.WithLabelValues("position_size").Set(size.InexactFloat64())
 	return size, nil
 }
@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// Reload applies the runtime-tunable parameters of update: position size
+// limits, concentration, stop loss and take profit levels. The update is
+// validated as a whole and nothing is applied if any value is invalid.
+// Open positions are untouched; new values apply from the next trade.
+func (m *Manager) Reload(update types.RiskConfig) error {
+	if err := ValidateReload(update); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logChange("max_position_size", m.config.MaxPositionSize, update.MaxPositionSize)
+	m.logChange("min_position_size", m.config.MinPositionSize, update.MinPositionSize)
+	m.logChange("max_concentration", m.config.MaxConcentration, update.MaxConcentration)
+	m.logChange("stop_loss.initial", m.config.StopLoss.Initial, update.StopLoss.Initial)
+	m.logChange("stop_loss.trailing", m.config.StopLoss.Trailing, update.StopLoss.Trailing)
+
+	m.config.MaxPositionSize = update.MaxPositionSize
+	m.config.MinPositionSize = update.MinPositionSize
+	m.config.MaxConcentration = update.MaxConcentration
+	m.config.StopLoss = update.StopLoss
+	if len(update.TakeProfitLevels) > 0 {
+		m.config.TakeProfitLevels = update.TakeProfitLevels
+	}
+	return nil
+}
+
+// StopLossPrice returns the initial stop for a new position entered at price
+func (m *Manager) StopLossPrice(price decimal.Decimal) decimal.Decimal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return price.Mul(decimal.NewFromInt(1).Sub(m.config.StopLoss.Initial))
+}
+
+func (m *Manager) logChange(name string, from, to decimal.Decimal) {
+	if from.Equal(to) {
+		return
+	}
+	m.logger.Info("Risk parameter reloaded",
+		zap.String("parameter", name),
+		zap.String("from", from.String()),
+		zap.String("to", to.String()))
+}
+
+// ValidateReload reports whether Reload would accept update, so callers
+// reloading several components can check them all before applying any
+func ValidateReload(update types.RiskConfig) error {
+	if err := validateReload(update); err != nil {
+		return fmt.Errorf("invalid risk parameters: %w", err)
+	}
+	return nil
+}
+
+func validateReload(c types.RiskConfig) error {
+	one := decimal.NewFromInt(1)
+	switch {
+	case !c.MaxPositionSize.IsPositive():
+		return fmt.Errorf("max_position_size must be positive, got %s", c.MaxPositionSize)
+	case c.MinPositionSize.IsNegative() || c.MinPositionSize.GreaterThan(c.MaxPositionSize):
+		return fmt.Errorf("min_position_size %s must be between 0 and max_position_size %s", c.MinPositionSize, c.MaxPositionSize)
+	case c.MaxConcentration.IsNegative() || c.MaxConcentration.GreaterThan(one):
+		return fmt.Errorf("max_concentration must be a fraction between 0 and 1, got %s", c.MaxConcentration)
+	case !c.StopLoss.Initial.IsPositive() || !c.StopLoss.Initial.LessThan(one):
+		return fmt.Errorf("stop_loss.initial must be a fraction between 0 and 1, got %s", c.StopLoss.Initial)
+	case c.StopLoss.Trailing.IsNegative() || !c.StopLoss.Trailing.LessThan(one):
+		return fmt.Errorf("stop_loss.trailing must be a fraction between 0 and 1, got %s", c.StopLoss.Trailing)
+	}
+	for _, level := range c.TakeProfitLevels {
+		if !level.Multiplier.GreaterThan(one) {
+			return fmt.Errorf("take profit multiplier must be above 1, got %s", level.Multiplier)
+		}
+	}
+	return nil
+}
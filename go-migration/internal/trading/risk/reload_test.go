@@ -0,0 +1,36 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestManager_ReloadStopLoss(t *testing.T) {
+	config := &types.RiskConfig{
+		MaxPositionSize: decimal.NewFromInt(1000),
+		MinPositionSize: decimal.NewFromInt(10),
+	}
+	config.StopLoss.Initial = decimal.NewFromFloat(0.02)
+	m := NewRiskManager(config, zap.NewNop())
+	entry := decimal.NewFromInt(100)
+
+	assert.True(t, decimal.NewFromInt(98).Equal(m.StopLossPrice(entry)))
+
+	update := *config
+	update.StopLoss.Initial = decimal.NewFromFloat(0.05)
+	assert.NoError(t, m.Reload(update))
+	assert.True(t, decimal.NewFromInt(95).Equal(m.StopLossPrice(entry)))
+
+	// An invalid update is rejected whole, keeping the running values
+	update.StopLoss.Initial = decimal.NewFromFloat(1.5)
+	update.MaxPositionSize = decimal.NewFromInt(5000)
+	assert.Error(t, ValidateReload(update))
+	assert.Error(t, m.Reload(update))
+	assert.True(t, decimal.NewFromInt(95).Equal(m.StopLossPrice(entry)))
+	assert.True(t, decimal.NewFromInt(1000).Equal(m.config.MaxPositionSize))
+}
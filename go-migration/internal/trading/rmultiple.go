@@ -0,0 +1,36 @@
+package trading
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// RecordClosedTrade records the realized PnL of a closed position along with
+// its R-multiple, measured against the risk between the position's entry
+// and its initial stop. Positions without a stop only contribute PnL.
+func (e *Engine) RecordClosedTrade(strategy string, position *types.Position, pnl decimal.Decimal) {
+	e.RecordRealizedPnL(strategy, pnl)
+
+	if position.StopLoss.IsZero() {
+		return
+	}
+	risk := types.InitialRisk(position.EntryPrice.InexactFloat64(), position.StopLoss.InexactFloat64(), position.Size.InexactFloat64())
+	r, ok := types.RMultiple(pnl.InexactFloat64(), risk)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	e.rMultiples[strategy] = append(e.rMultiples[strategy], r)
+	e.mu.Unlock()
+}
+
+// GetRStats returns the R-multiple distribution and expectancy of the
+// trades strategy has closed
+func (e *Engine) GetRStats(strategy string) *types.RStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return types.NewRStats(e.rMultiples[strategy])
+}
@@ -0,0 +1,63 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_RecordClosedTradeTracksRMultiple(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+
+	position := &types.Position{
+		Symbol:     "SOL",
+		Size:       decimal.NewFromInt(10),
+		EntryPrice: decimal.NewFromInt(100),
+		StopLoss:   decimal.NewFromInt(98),
+	}
+	engine.RecordClosedTrade("pump.fun", position, decimal.NewFromInt(40))
+	engine.RecordClosedTrade("pump.fun", position, decimal.NewFromInt(-20))
+
+	// Without a stop there is no risk unit to measure against
+	engine.RecordClosedTrade("pump.fun", &types.Position{Symbol: "BONK", Size: decimal.NewFromInt(1)}, decimal.NewFromInt(5))
+
+	stats := engine.GetRStats("pump.fun")
+	assert.Equal(t, 2, stats.Trades)
+	assert.InDelta(t, 0.5, stats.Expectancy, 1e-9)
+	assert.Equal(t, 1, stats.Distribution["+2R"])
+	assert.Equal(t, 1, stats.Distribution["-1R"])
+	assert.True(t, decimal.NewFromInt(25).Equal(engine.realized["pump.fun"]))
+}
+
+func TestEngine_ApplyFillRecordsClosedTrades(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), storage.NewMemoryStorage())
+
+	entry := fill(types.OrderSideBuy, 10, 1.0)
+	entry.Provider = "pump.fun"
+	entry.StopLoss = decimal.NewFromFloat(0.5)
+	require.NoError(t, engine.ApplyFill(entry))
+
+	// A partial reduction realizes PnL without closing the trade
+	reduce := fill(types.OrderSideSell, 4, 2.0)
+	reduce.Provider = "pump.fun"
+	require.NoError(t, engine.ApplyFill(reduce))
+	assert.Equal(t, 0, engine.GetRStats("pump.fun").Trades)
+
+	exit := fill(types.OrderSideSell, 6, 2.0)
+	exit.Provider = "pump.fun"
+	require.NoError(t, engine.ApplyFill(exit))
+
+	// The close is measured against the risk of the size it closed
+	stats := engine.GetRStats("pump.fun")
+	assert.Equal(t, 1, stats.Trades)
+	assert.InDelta(t, 2.0, stats.Expectancy, 1e-9)
+	assert.True(t, decimal.NewFromInt(10).Equal(engine.realized["pump.fun"]), "realized %s", engine.realized["pump.fun"])
+	assert.Equal(t, 2, engine.period.trades)
+	assert.Equal(t, 2, engine.period.wins)
+}
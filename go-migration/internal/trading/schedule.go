@@ -0,0 +1,98 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ScheduledCloseConfig flattens positions once a day at a fixed UTC time,
+// for strategies that must be out of the market before a daily event
+type ScheduledCloseConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	At      time.Duration `yaml:"at"`      // Time of day in UTC, as an offset from midnight
+	Symbols []string      `yaml:"symbols"` // Symbols to close, empty closes every position
+}
+
+// Applies reports whether symbol is closed by the schedule
+func (c ScheduledCloseConfig) Applies(symbol string) bool {
+	if len(c.Symbols) == 0 {
+		return true
+	}
+	for _, s := range c.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScheduledClose closes the scheduled positions once the configured
+// time of day has passed, at most once per day. An engine started after
+// the close time closes on its first check that day.
+func (e *Engine) checkScheduledClose(ctx context.Context) {
+	if !e.config.ScheduledClose.Enabled {
+		return
+	}
+
+	now := e.now().UTC()
+	closeAt := now.Truncate(24 * time.Hour).Add(e.config.ScheduledClose.At)
+	if now.Before(closeAt) {
+		return
+	}
+
+	e.mu.Lock()
+	if !e.lastClose.Before(closeAt) {
+		e.mu.Unlock()
+		return
+	}
+	e.lastClose = closeAt
+	executors := e.copyExecutors()
+	e.mu.Unlock()
+
+	// Executors are asked without the engine lock, since one may be
+	// blocked on a trade in flight
+	var closes []*types.Trade
+	for name, exec := range executors {
+		for symbol, pos := range exec.GetPositions() {
+			if pos.Size.IsZero() || !e.config.ScheduledClose.Applies(symbol) {
+				continue
+			}
+			side := types.OrderSideSell
+			if pos.Size.IsNegative() {
+				side = types.OrderSideBuy
+			}
+			closes = append(closes, &types.Trade{
+				Symbol:    symbol,
+				Side:      side,
+				Size:      pos.Size.Abs(),
+				Price:     pos.CurrentPrice,
+				Provider:  name,
+				Timestamp: now,
+			})
+		}
+	}
+
+	for _, trade := range closes {
+		e.logger.Info("Scheduled close",
+			zap.String("provider", trade.Provider),
+			zap.String("symbol", trade.Symbol),
+			zap.String("side", string(trade.Side)),
+			zap.String("size", trade.Size.String()),
+			zap.Time("scheduled_at", closeAt))
+
+		if err := e.ExecuteTrade(ctx, trade); err != nil {
+			metrics.ScheduledCloses.WithLabelValues(trade.Provider, "failed").Inc()
+			e.logger.Error("Failed to execute scheduled close",
+				zap.String("provider", trade.Provider),
+				zap.String("symbol", trade.Symbol),
+				zap.Error(err))
+			continue
+		}
+		metrics.ScheduledCloses.WithLabelValues(trade.Provider, "closed").Inc()
+	}
+}
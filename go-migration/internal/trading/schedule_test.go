@@ -0,0 +1,96 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_ScheduledClose(t *testing.T) {
+	now := time.Date(2025, 2, 1, 13, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		ScheduledClose: ScheduledCloseConfig{
+			Enabled: true,
+			At:      13*time.Hour + 30*time.Minute,
+			Symbols: []string{"BONK", "PUMP"},
+		},
+	}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	exec := &bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(1000), CurrentPrice: decimal.NewFromFloat(1.5)},
+		"PUMP": {Symbol: "PUMP", Size: decimal.NewFromInt(-200), CurrentPrice: decimal.NewFromInt(2)},
+		"WIF":  {Symbol: "WIF", Size: decimal.NewFromInt(50), CurrentPrice: decimal.NewFromInt(3)},
+	}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	// Before the scheduled time nothing is closed
+	engine.checkScheduledClose(context.Background())
+	assert.Empty(t, exec.signals)
+
+	now = now.Add(45 * time.Minute)
+	engine.checkScheduledClose(context.Background())
+
+	closes := make(map[string]*types.Signal)
+	for _, signal := range exec.signals {
+		closes[signal.Symbol] = signal
+	}
+	assert.Len(t, closes, 2)
+	if assert.Contains(t, closes, "BONK") {
+		assert.Equal(t, types.SignalType(types.OrderSideSell), closes["BONK"].Type)
+		assert.True(t, decimal.NewFromInt(1000).Equal(closes["BONK"].Amount))
+	}
+	if assert.Contains(t, closes, "PUMP") {
+		assert.Equal(t, types.SignalType(types.OrderSideBuy), closes["PUMP"].Type)
+		assert.True(t, decimal.NewFromInt(200).Equal(closes["PUMP"].Amount))
+	}
+
+	// The schedule fires once per day
+	now = now.Add(time.Hour)
+	engine.checkScheduledClose(context.Background())
+	assert.Len(t, exec.signals, 2)
+
+	now = now.Add(24 * time.Hour)
+	engine.checkScheduledClose(context.Background())
+	assert.Len(t, exec.signals, 4)
+}
+
+// unlockedExecutor is a bookExecutor that records whether it was asked for
+// its positions while the engine lock was held
+type unlockedExecutor struct {
+	bookExecutor
+	engine *Engine
+	locked bool
+}
+
+func (u *unlockedExecutor) GetPositions() map[string]*types.Position {
+	if u.engine.mu.TryLock() {
+		u.engine.mu.Unlock()
+	} else {
+		u.locked = true
+	}
+	return u.positions
+}
+
+func TestEngine_ScheduledCloseQueriesExecutorsUnlocked(t *testing.T) {
+	now := time.Date(2025, 2, 1, 14, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		ScheduledClose: ScheduledCloseConfig{Enabled: true, At: 13 * time.Hour},
+	}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"BONK": {Symbol: "BONK", Size: decimal.NewFromInt(1000), CurrentPrice: decimal.NewFromFloat(1.5)},
+	}}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	engine.checkScheduledClose(context.Background())
+	assert.Len(t, exec.signals, 1)
+	assert.False(t, exec.locked)
+}
@@ -2,8 +2,10 @@ package trading
 
 import (
 	"context"
+	"time"
 
 	"github.com/kwanRoshi/B/go-migration/internal/types"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +33,11 @@ func (s *Service) CancelOrder(ctx context.Context, orderID string) error {
 	return s.engine.CancelOrder(ctx, orderID)
 }
 
+// ReplaceOrder amends the price and size of a resting order
+func (s *Service) ReplaceOrder(ctx context.Context, orderID string, newPrice, newSize decimal.Decimal) (*types.Order, error) {
+	return s.engine.ReplaceOrder(ctx, orderID, newPrice, newSize)
+}
+
 // GetOrder implements TradingEngine interface
 func (s *Service) GetOrder(ctx context.Context, orderID string) (*types.Order, error) {
 	return s.engine.GetOrder(ctx, orderID)
@@ -68,6 +75,16 @@ func (s *Service) GetPositions(ctx context.Context) ([]*types.Position, error) {
 	return s.engine.GetPositions(ctx)
 }
 
+// GetPortfolioSummary returns the aggregated portfolio summary
+func (s *Service) GetPortfolioSummary(ctx context.Context) (types.PortfolioSummary, error) {
+	return s.engine.GetPortfolioSummary(ctx)
+}
+
+// GetEquityCurve returns the persisted equity curve of strategy between from and to
+func (s *Service) GetEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error) {
+	return s.engine.GetEquityCurve(strategy, from, to)
+}
+
 // GetOrderBook implements TradingEngine interface
 func (s *Service) GetOrderBook(ctx context.Context, symbol string) (*types.OrderBook, error) {
 	return nil, nil // TODO: Implement get order book
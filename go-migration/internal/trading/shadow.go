@@ -0,0 +1,163 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/executor"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// ShadowStrategy is the strategy the shadow account's equity curve is
+// stored under, for comparison with the live executors' curves
+const ShadowStrategy = "shadow"
+
+// ShadowConfig mirrors every signal into a paper executor running alongside
+// the live ones, so the decisions of a strategy can be compared with live
+// results without risking capital
+type ShadowConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	ShadowOnly []string `yaml:"shadow_only"` // Symbols traded only in the shadow, never live
+}
+
+// ShadowFill is a hypothetical fill made by the shadow account
+type ShadowFill struct {
+	Time   time.Time       `json:"time"`
+	Symbol string          `json:"symbol"`
+	Side   types.OrderSide `json:"side"`
+	Size   decimal.Decimal `json:"size"`
+	Price  decimal.Decimal `json:"price"`
+	Live   bool            `json:"live"` // Whether the signal also traded live
+}
+
+// paperFiller is implemented by paper executors that simulate their fill
+// price, such as PumpExecutor
+type paperFiller interface {
+	PaperFillPrice(signal *types.Signal) decimal.Decimal
+}
+
+// shadowAccount is the paper executor signals are mirrored into and the
+// fills and positions it made
+type shadowAccount struct {
+	exec      executor.TradingExecutor
+	fills     []ShadowFill
+	positions map[string]*types.Position
+	realized  decimal.Decimal
+	mu        sync.Mutex
+}
+
+// SetShadowExecutor sets the paper executor signals are mirrored into when
+// Config.Shadow is enabled, typically a PumpExecutor in paper mode
+func (e *Engine) SetShadowExecutor(exec executor.TradingExecutor) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shadow = &shadowAccount{
+		exec:      exec,
+		positions: make(map[string]*types.Position),
+	}
+}
+
+// shadowing reports whether signals are mirrored into a shadow account
+func (e *Engine) shadowing() bool {
+	return e.config.Shadow.Enabled && e.shadow != nil
+}
+
+// shadowOnly reports whether symbol trades only in the shadow account
+func (e *Engine) shadowOnly(symbol string) bool {
+	if !e.shadowing() {
+		return false
+	}
+	for _, s := range e.config.Shadow.ShadowOnly {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorToShadow executes a copy of signal in the shadow account and
+// records its hypothetical fill. live reports whether the signal also
+// traded live. Callers hold the read lock.
+func (e *Engine) mirrorToShadow(ctx context.Context, signal *types.Signal, live bool) error {
+	mirrored := *signal
+	if err := e.shadow.exec.ExecuteTrade(ctx, &mirrored); err != nil {
+		return fmt.Errorf("shadow execution failed: %w", err)
+	}
+
+	price := signal.Price
+	if paper, ok := e.shadow.exec.(paperFiller); ok {
+		price = paper.PaperFillPrice(signal)
+	}
+	side := types.OrderSideBuy
+	if signal.Type == types.SignalTypeSell {
+		side = types.OrderSideSell
+	}
+	fill := ShadowFill{
+		Time:   e.now(),
+		Symbol: signal.Symbol,
+		Side:   side,
+		Size:   signal.Amount,
+		Price:  price,
+		Live:   live,
+	}
+
+	e.shadow.mu.Lock()
+	defer e.shadow.mu.Unlock()
+	e.shadow.fills = append(e.shadow.fills, fill)
+	trade := &types.Trade{Symbol: fill.Symbol, Side: fill.Side, Size: fill.Size, Price: fill.Price}
+	size, price, err := signedFill(trade)
+	if err != nil {
+		return err
+	}
+	realized, _ := e.netFill(e.shadow.positions, trade, size, price)
+	e.shadow.realized = e.shadow.realized.Add(realized)
+
+	e.logger.Debug("Recorded shadow fill",
+		zap.String("symbol", fill.Symbol),
+		zap.String("side", string(fill.Side)),
+		zap.String("size", fill.Size.String()),
+		zap.String("price", fill.Price.String()),
+		zap.Bool("live", live))
+	return nil
+}
+
+// ShadowFills returns the hypothetical fills made by the shadow account
+func (e *Engine) ShadowFills() []ShadowFill {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.shadow == nil {
+		return nil
+	}
+
+	e.shadow.mu.Lock()
+	defer e.shadow.mu.Unlock()
+	fills := make([]ShadowFill, len(e.shadow.fills))
+	copy(fills, e.shadow.fills)
+	return fills
+}
+
+// shadowEquity returns the shadow account's equity point at now: the
+// initial cash plus its realized PnL and the unrealized PnL of its positions
+// at their last fill. Callers hold the read lock.
+func (e *Engine) shadowEquity(now time.Time) *types.EquityPoint {
+	e.shadow.mu.Lock()
+	defer e.shadow.mu.Unlock()
+
+	cash := e.config.Equity.InitialCash.Add(e.shadow.realized)
+	unrealized := decimal.Zero
+	for _, pos := range e.shadow.positions {
+		unrealized = unrealized.Add(pos.UnrealizedPnL)
+	}
+	return &types.EquityPoint{
+		Strategy:      ShadowStrategy,
+		Cash:          cash,
+		UnrealizedPnL: unrealized,
+		Equity:        cash.Add(unrealized),
+		Timestamp:     now,
+	}
+}
@@ -0,0 +1,70 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_ShadowOnlySymbolNeverTradesLive(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := new(MockStorage)
+	engine := NewEngine(Config{
+		Shadow: ShadowConfig{Enabled: true, ShadowOnly: []string{"PEPE"}},
+		Equity: EquityConfig{InitialCash: decimal.NewFromInt(1000)},
+	}, zap.NewNop(), store)
+	engine.now = func() time.Time { return now }
+
+	live := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", live))
+	shadow := &recordingExecutor{}
+	engine.SetShadowExecutor(shadow)
+
+	buy := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(2), Provider: "pump.fun", Timestamp: now}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), buy))
+	sell := &types.Signal{Symbol: "PEPE", Type: types.SignalTypeSell, Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(3), Provider: "pump.fun", Timestamp: now}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), sell))
+
+	assert.Empty(t, live.signals)
+	assert.Len(t, shadow.signals, 2)
+	fills := engine.ShadowFills()
+	if assert.Len(t, fills, 2) {
+		assert.Equal(t, types.OrderSideBuy, fills[0].Side)
+		assert.Equal(t, types.OrderSideSell, fills[1].Side)
+		assert.False(t, fills[1].Live)
+	}
+
+	// The shadow curve carries the round trip's 10 of realized PnL
+	store.On("SaveEquityPoint", mock.MatchedBy(func(p *types.EquityPoint) bool {
+		return p.Strategy == ShadowStrategy && p.Equity.Equal(decimal.NewFromInt(1010))
+	})).Return(nil).Once()
+	store.On("SaveEquityPoint", mock.Anything).Return(nil)
+	engine.snapshotEquity()
+	store.AssertExpectations(t)
+}
+
+func TestEngine_ShadowMirrorsLiveSignals(t *testing.T) {
+	engine := NewEngine(Config{Shadow: ShadowConfig{Enabled: true}}, zap.NewNop(), new(MockStorage))
+
+	live := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", live))
+	shadow := &recordingExecutor{}
+	engine.SetShadowExecutor(shadow)
+
+	signal := &types.Signal{Symbol: "DOGE", Type: types.SignalTypeBuy, Amount: decimal.NewFromInt(5), Price: decimal.NewFromInt(1), Provider: "pump.fun", Timestamp: time.Now()}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal))
+
+	assert.Len(t, live.signals, 1)
+	assert.Len(t, shadow.signals, 1)
+	fills := engine.ShadowFills()
+	if assert.Len(t, fills, 1) {
+		assert.True(t, fills[0].Live)
+	}
+}
@@ -0,0 +1,51 @@
+package trading
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// RecordFill records a fill for strategy, accumulating its implementation
+// shortfall against the trade's decision price and counting it towards the
+// strategy's turnover. The fill is measured at the trade's average fill
+// price and filled size, and Fee is the fees paid for it.
+func (e *Engine) RecordFill(strategy string, trade *types.Trade) error {
+	if !trade.DecisionPrice.IsPositive() {
+		return fmt.Errorf("trade %s has no decision price", trade.ID)
+	}
+
+	e.mu.Lock()
+	stats, ok := e.shortfall[strategy]
+	if !ok {
+		stats = &types.ShortfallStats{}
+		e.shortfall[strategy] = stats
+	}
+	shortfall := stats.Add(trade)
+	e.recordTurnover(strategy, trade)
+	bps := stats.Bps()
+	e.mu.Unlock()
+
+	metrics.ImplementationShortfall.WithLabelValues(strategy).Set(bps.InexactFloat64())
+	e.logger.Debug("Recorded fill",
+		zap.String("strategy", strategy),
+		zap.String("symbol", trade.Symbol),
+		zap.String("decision_price", trade.DecisionPrice.String()),
+		zap.String("fill_price", trade.FillPrice().String()),
+		zap.String("shortfall", shortfall.String()))
+	return nil
+}
+
+// GetShortfall returns the aggregate implementation shortfall of strategy
+func (e *Engine) GetShortfall(strategy string) types.ShortfallStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if stats, ok := e.shortfall[strategy]; ok {
+		return *stats
+	}
+	return types.ShortfallStats{}
+}
@@ -0,0 +1,50 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_RecordFillShortfall(t *testing.T) {
+	engine := NewEngine(Config{}, zap.NewNop(), new(MockStorage))
+
+	// Bought 10 at 101 after deciding at 100, paying 2 in fees
+	assert.NoError(t, engine.RecordFill("pump.fun", &types.Trade{
+		ID:            "fill-1",
+		Symbol:        "TEST",
+		Side:          types.OrderSideBuy,
+		DecisionPrice: decimal.NewFromInt(100),
+		Price:         decimal.NewFromInt(101),
+		Size:          decimal.NewFromInt(10),
+		Fee:           decimal.NewFromInt(2),
+	}))
+
+	stats := engine.GetShortfall("pump.fun")
+	assert.Equal(t, 1, stats.Trades)
+	assert.True(t, decimal.NewFromInt(12).Equal(stats.Shortfall), stats.Shortfall.String())
+	assert.True(t, decimal.NewFromInt(120).Equal(stats.Bps()), stats.Bps().String())
+
+	// Sold 10 at 99 after deciding at 100, fee-free
+	assert.NoError(t, engine.RecordFill("pump.fun", &types.Trade{
+		ID:            "fill-2",
+		Symbol:        "TEST",
+		Side:          types.OrderSideSell,
+		DecisionPrice: decimal.NewFromInt(100),
+		Price:         decimal.NewFromInt(99),
+		Size:          decimal.NewFromInt(10),
+	}))
+
+	stats = engine.GetShortfall("pump.fun")
+	assert.Equal(t, 2, stats.Trades)
+	assert.True(t, decimal.NewFromInt(22).Equal(stats.Shortfall), stats.Shortfall.String())
+	assert.True(t, decimal.NewFromInt(110).Equal(stats.Bps()), stats.Bps().String())
+
+	// Fills without a decision price cannot be measured
+	assert.Error(t, engine.RecordFill("pump.fun", &types.Trade{ID: "fill-3", Price: decimal.NewFromInt(1)}))
+	assert.Zero(t, engine.GetShortfall("gmgn").Trades)
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
@@ -13,6 +15,8 @@ type MemoryStorage struct {
 	positions map[string]*types.Position
 	trades    map[string][]*types.Trade
 	orders    map[string]*types.Order
+	signals   []types.Signal
+	equity    map[string][]*types.EquityPoint
 }
 
 func (s *MemoryStorage) GetOrder(orderID string) (*types.Order, error) {
@@ -26,6 +30,7 @@ func NewMemoryStorage() *MemoryStorage {
 		positions: make(map[string]*types.Position),
 		trades:    make(map[string][]*types.Trade),
 		orders:    make(map[string]*types.Order),
+		equity:    make(map[string][]*types.EquityPoint),
 	}
 }
 
@@ -103,3 +108,64 @@ func (s *MemoryStorage) SaveOrder(order *types.Order) error {
 	s.orders[order.ID] = order
 	return nil
 }
+
+// SaveSignal stores a copy of signal, so later changes to it by the caller
+// do not alter the captured signal
+func (s *MemoryStorage) SaveSignal(ctx context.Context, signal *types.Signal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signals = append(s.signals, copySignal(signal))
+	return nil
+}
+
+// LoadSignals returns copies of the stored signals between from and to, so
+// replaying them cannot alter what is stored
+func (s *MemoryStorage) LoadSignals(ctx context.Context, from, to time.Time) ([]*types.Signal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var signals []*types.Signal
+	for i := range s.signals {
+		signal := &s.signals[i]
+		if signal.Timestamp.Before(from) || signal.Timestamp.After(to) {
+			continue
+		}
+		loaded := copySignal(signal)
+		signals = append(signals, &loaded)
+	}
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].Timestamp.Before(signals[j].Timestamp)
+	})
+	return signals, nil
+}
+
+func (s *MemoryStorage) SaveEquityPoint(point *types.EquityPoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.equity[point.Strategy] = append(s.equity[point.Strategy], point)
+	return nil
+}
+
+func (s *MemoryStorage) LoadEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var points []*types.EquityPoint
+	for _, point := range s.equity[strategy] {
+		if point.Timestamp.Before(from) || point.Timestamp.After(to) {
+			continue
+		}
+		points = append(points, point)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	return points, nil
+}
+
+// copySignal returns a copy of signal that shares no memory with it
+func copySignal(signal *types.Signal) types.Signal {
+	copied := *signal
+	copied.Indicators = append([]types.Indicator(nil), signal.Indicators...)
+	return copied
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestMemoryStorage_EquityCurve(t *testing.T) {
+	s := NewMemoryStorage()
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 6; i++ {
+		cash := decimal.NewFromInt(1000)
+		unrealized := decimal.NewFromInt(int64(i * 10))
+		assert.NoError(t, s.SaveEquityPoint(&types.EquityPoint{
+			Strategy:      "pump.fun",
+			Cash:          cash,
+			UnrealizedPnL: unrealized,
+			Equity:        cash.Add(unrealized),
+			Timestamp:     start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+	assert.NoError(t, s.SaveEquityPoint(&types.EquityPoint{
+		Strategy:  "gmgn",
+		Equity:    decimal.NewFromInt(500),
+		Timestamp: start.Add(2 * time.Hour),
+	}))
+
+	curve, err := s.LoadEquityCurve("pump.fun", start.Add(time.Hour), start.Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, curve, 4)
+	for i, point := range curve {
+		assert.Equal(t, "pump.fun", point.Strategy)
+		assert.True(t, start.Add(time.Duration(i+1)*time.Hour).Equal(point.Timestamp))
+		assert.True(t, decimal.NewFromInt(int64(1000+(i+1)*10)).Equal(point.Equity))
+	}
+
+	curve, err = s.LoadEquityCurve("pump.fun", start.Add(10*time.Hour), start.Add(12*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, curve)
+}
@@ -0,0 +1,70 @@
+package strategy
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// ATRTracker maintains a rolling average true range per symbol. Only trade
+// prices are available from the feeds, so the true range of each bar is
+// approximated by the absolute move between successive prices.
+type ATRTracker struct {
+	period int
+	last   map[string]decimal.Decimal
+	ranges map[string][]decimal.Decimal
+	mu     sync.Mutex
+}
+
+func NewATRTracker(period int) *ATRTracker {
+	if period <= 0 {
+		period = 14
+	}
+	return &ATRTracker{
+		period: period,
+		last:   make(map[string]decimal.Decimal),
+		ranges: make(map[string][]decimal.Decimal),
+	}
+}
+
+// Record adds a price observation for symbol
+func (t *ATRTracker) Record(symbol string, price decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.last[symbol]; ok {
+		ranges := append(t.ranges[symbol], price.Sub(last).Abs())
+		if len(ranges) > t.period {
+			ranges = ranges[len(ranges)-t.period:]
+		}
+		t.ranges[symbol] = ranges
+	}
+	t.last[symbol] = price
+}
+
+// ATR returns the average true range for symbol and whether enough history
+// has been recorded to fill the period
+func (t *ATRTracker) ATR(symbol string) (decimal.Decimal, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ranges := t.ranges[symbol]
+	if len(ranges) < t.period {
+		return decimal.Zero, false
+	}
+
+	var total decimal.Decimal
+	for _, r := range ranges {
+		total = total.Add(r)
+	}
+	return total.Div(decimal.NewFromInt(int64(len(ranges)))), true
+}
+
+// Reset discards the history for symbol
+func (t *ATRTracker) Reset(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.last, symbol)
+	delete(t.ranges, symbol)
+}
@@ -0,0 +1,66 @@
+package strategy
+
+import (
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// atrLadder returns the ATR take profit levels and the current ATR for
+// symbol, once the ladder is enabled and the ATR has a full period
+func (r *PumpRiskManager) atrLadder(symbol string) ([]types.ATRProfitLevel, decimal.Decimal, bool) {
+	if !r.config.ATRTakeProfit.Enabled || r.atr == nil {
+		return nil, decimal.Zero, false
+	}
+	atr, ok := r.atr.ATR(symbol)
+	if !ok {
+		return nil, decimal.Zero, false
+	}
+	return r.config.ATRTakeProfit.Levels, atr, true
+}
+
+// checkATRTakeProfit triggers the first untaken ATR level price has reached.
+// Levels are keyed by their ATR multiple so each sells once per position.
+func (r *PumpRiskManager) checkATRTakeProfit(symbol string, position *types.Position, price decimal.Decimal, levels []types.ATRProfitLevel, atr decimal.Decimal) (bool, decimal.Decimal) {
+	for _, level := range levels {
+		target := level.Target(position.EntryPrice, atr)
+		if price.LessThan(target) || position.HasTakenProfitAt(level.Multiple) {
+			continue
+		}
+		r.logger.Info("ATR take profit triggered",
+			zap.String("symbol", symbol),
+			zap.String("price", price.String()),
+			zap.String("target", target.String()),
+			zap.String("atr", atr.String()),
+			zap.String("multiple", level.Multiple.String()),
+			zap.String("percentage", level.Percentage.String()))
+		position.MarkTakenProfitAt(level.Multiple)
+		return true, level.Percentage
+	}
+	return false, decimal.Zero
+}
+
+// TakeProfitTargets returns the take profit prices currently in force for
+// symbol's position: the ATR ladder once warm, otherwise the fixed levels
+func (r *PumpRiskManager) TakeProfitTargets(symbol string) []decimal.Decimal {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	position := r.positions[symbol]
+	if position == nil {
+		return nil
+	}
+
+	var targets []decimal.Decimal
+	if levels, atr, ok := r.atrLadder(symbol); ok {
+		for _, level := range levels {
+			targets = append(targets, level.Target(position.EntryPrice, atr))
+		}
+		return targets
+	}
+	for _, level := range r.config.TakeProfitLevels {
+		targets = append(targets, position.EntryPrice.Mul(level.Multiplier))
+	}
+	return targets
+}
@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func volatilityStopConfig() *types.RiskConfig {
+	config := &types.RiskConfig{
+		MaxPositionSize: decimal.NewFromInt(1000),
+		MinPositionSize: decimal.NewFromInt(1),
+	}
+	config.StopLoss.Initial = decimal.NewFromFloat(0.1)
+	config.StopLoss.Trailing = decimal.NewFromFloat(0.05)
+	config.VolatilityStop = types.VolatilityStopConfig{
+		Enabled:    true,
+		Period:     4,
+		Multiplier: decimal.NewFromInt(2),
+	}
+	return config
+}
+
+func stopDistance(t *testing.T, swing float64) decimal.Decimal {
+	r := NewPumpRiskManager(zap.NewNop(), volatilityStopConfig())
+	entry := decimal.NewFromInt(100)
+	r.UpdatePosition("PUMP", &types.Position{Symbol: "PUMP", Size: decimal.NewFromInt(10), EntryPrice: entry})
+
+	// Alternate around entry so every move has the same true range
+	for i := 0; i < 5; i++ {
+		price := entry
+		if i%2 == 1 {
+			price = entry.Add(decimal.NewFromFloat(swing))
+		}
+		assert.NoError(t, r.UpdateStopLoss("PUMP", price))
+	}
+	return entry.Sub(r.stopLosses["PUMP"])
+}
+
+func TestATRTracker(t *testing.T) {
+	tracker := NewATRTracker(3)
+	for _, p := range []float64{10, 11, 9} {
+		tracker.Record("PUMP", decimal.NewFromFloat(p))
+	}
+	_, ok := tracker.ATR("PUMP")
+	assert.False(t, ok)
+
+	tracker.Record("PUMP", decimal.NewFromFloat(12))
+	atr, ok := tracker.ATR("PUMP")
+	assert.True(t, ok)
+	assert.True(t, decimal.NewFromInt(2).Equal(atr), atr.String())
+
+	// Older moves roll out of the window
+	tracker.Record("PUMP", decimal.NewFromFloat(12))
+	atr, _ = tracker.ATR("PUMP")
+	assert.True(t, decimal.NewFromFloat(5).Div(decimal.NewFromInt(3)).Equal(atr), atr.String())
+}
+
+func TestPumpRiskManager_VolatilityStopWidensWithATR(t *testing.T) {
+	calm := stopDistance(t, 1)
+	volatile := stopDistance(t, 5)
+
+	assert.True(t, decimal.NewFromInt(2).Equal(calm), calm.String())
+	assert.True(t, decimal.NewFromInt(10).Equal(volatile), volatile.String())
+	assert.True(t, volatile.GreaterThan(calm))
+}
+
+func TestPumpRiskManager_VolatilityStopFallsBackUntilWarm(t *testing.T) {
+	r := NewPumpRiskManager(zap.NewNop(), volatilityStopConfig())
+	r.UpdatePosition("PUMP", &types.Position{Symbol: "PUMP", Size: decimal.NewFromInt(10), EntryPrice: decimal.NewFromInt(100)})
+
+	// Without enough history the percentage stop applies
+	assert.NoError(t, r.UpdateStopLoss("PUMP", decimal.NewFromInt(100)))
+	assert.True(t, decimal.NewFromInt(90).Equal(r.stopLosses["PUMP"]))
+}
+
+// takeProfitTargets warms the ATR with moves of swing around entry and
+// returns the resulting ATR take profit ladder
+func takeProfitTargets(t *testing.T, swing float64) (*PumpRiskManager, []decimal.Decimal) {
+	config := &types.RiskConfig{
+		MaxPositionSize: decimal.NewFromInt(1000),
+		MinPositionSize: decimal.NewFromInt(1),
+		TakeProfitLevels: []types.ProfitLevel{
+			{Multiplier: decimal.NewFromInt(2), Percentage: decimal.NewFromFloat(0.5)},
+		},
+	}
+	config.StopLoss.Initial = decimal.NewFromFloat(0.5)
+	config.StopLoss.Trailing = decimal.NewFromFloat(0.5)
+	config.ATRTakeProfit = types.ATRTakeProfitConfig{
+		Enabled: true,
+		Period:  4,
+		Levels: []types.ATRProfitLevel{
+			{Multiple: decimal.NewFromInt(2), Percentage: decimal.NewFromFloat(0.25)},
+			{Multiple: decimal.NewFromInt(4), Percentage: decimal.NewFromFloat(0.5)},
+		},
+	}
+	r := NewPumpRiskManager(zap.NewNop(), config)
+	entry := decimal.NewFromInt(100)
+	r.UpdatePosition("PUMP", &types.Position{Symbol: "PUMP", Size: decimal.NewFromInt(10), EntryPrice: entry})
+
+	// Until the ATR is warm the fixed multiplier levels apply
+	assert.True(t, decimal.NewFromInt(200).Equal(r.TakeProfitTargets("PUMP")[0]))
+
+	for i := 0; i < 5; i++ {
+		price := entry
+		if i%2 == 1 {
+			price = entry.Add(decimal.NewFromFloat(swing))
+		}
+		assert.NoError(t, r.UpdateStopLoss("PUMP", price))
+	}
+	return r, r.TakeProfitTargets("PUMP")
+}
+
+func TestPumpRiskManager_ATRTakeProfitLadderWidensWithATR(t *testing.T) {
+	calmRisk, calm := takeProfitTargets(t, 1)
+	volatileRisk, volatile := takeProfitTargets(t, 5)
+
+	assert.True(t, decimal.NewFromInt(102).Equal(calm[0]), calm[0].String())
+	assert.True(t, decimal.NewFromInt(104).Equal(calm[1]), calm[1].String())
+	assert.True(t, decimal.NewFromInt(110).Equal(volatile[0]), volatile[0].String())
+	assert.True(t, decimal.NewFromInt(120).Equal(volatile[1]), volatile[1].String())
+
+	// The same rally reaches the first calm level but not the volatile one
+	price := decimal.NewFromInt(105)
+	hit, pct := calmRisk.CheckTakeProfit("PUMP", price)
+	assert.True(t, hit)
+	assert.True(t, decimal.NewFromFloat(0.25).Equal(pct))
+	hit, _ = volatileRisk.CheckTakeProfit("PUMP", price)
+	assert.False(t, hit)
+
+	// Each level sells once
+	hit, pct = calmRisk.CheckTakeProfit("PUMP", price)
+	assert.True(t, hit)
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(pct))
+	hit, _ = calmRisk.CheckTakeProfit("PUMP", price)
+	assert.False(t, hit)
+}
@@ -0,0 +1,80 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// BondingCurveSource provides the bonding curve a token trades on
+type BondingCurveSource interface {
+	GetBondingCurve(ctx context.Context, symbol string) (*types.BondingCurve, error)
+}
+
+// SetBondingCurveSource enables slippage-aware sizing against the curves
+// returned by source when MaxSlippage is configured
+func (s *PumpStrategy) SetBondingCurveSource(source BondingCurveSource) {
+	s.curves = source
+}
+
+// CalculateFairPositionSize returns the largest buy on symbol's bonding
+// curve whose cost stays within MaxPositionSize and whose expected average
+// fill stays within MaxSlippage of the current curve price. Without a curve
+// source or slippage budget it falls back to CalculatePositionSize.
+func (s *PumpStrategy) CalculateFairPositionSize(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	if s.curves == nil || !s.config.MaxSlippage.IsPositive() {
+		return s.CalculatePositionSize(price)
+	}
+
+	curve, err := s.curves.GetBondingCurve(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, NewPumpStrategyError(OpCalculatePosition, symbol, "failed to get bonding curve", err)
+	}
+	size, err := fairCurveSize(curve, s.config.Risk.MaxPositionSize, s.config.MaxSlippage)
+	if err != nil {
+		return decimal.Zero, NewPumpStrategyError(OpCalculatePosition, symbol, "failed to size on bonding curve", err)
+	}
+
+	s.logger.Debug("slippage-aware position size",
+		zap.String("symbol", symbol),
+		zap.String("size", size.String()),
+		zap.String("slope", curve.Slope.String()))
+	return size, nil
+}
+
+// fairCurveSize sizes a buy on a linear curve p(x) = base + slope*x. Buying
+// q tokens from price p0 fills at an average of p0 + slope*q/2, so the
+// slippage budget allows q <= 2*budget*p0/slope and the notional budget
+// allows q with slope/2*q^2 + p0*q <= notional.
+func fairCurveSize(curve *types.BondingCurve, notional, budget decimal.Decimal) (decimal.Decimal, error) {
+	p0 := curve.CurrentPrice
+	if !p0.IsPositive() {
+		p0 = curve.BasePrice.Add(curve.Slope.Mul(decimal.NewFromInt(curve.Supply)))
+	}
+	if !p0.IsPositive() {
+		return decimal.Zero, fmt.Errorf("curve price must be positive")
+	}
+
+	if !curve.Slope.IsPositive() {
+		return notional.Div(p0), nil
+	}
+
+	bySlippage := budget.Mul(p0).Mul(decimal.NewFromInt(2)).Div(curve.Slope)
+
+	p, k, m := p0.InexactFloat64(), curve.Slope.InexactFloat64(), notional.InexactFloat64()
+	byNotional := decimal.NewFromFloat((math.Sqrt(p*p+2*k*m) - p) / k)
+
+	size := decimal.Min(bySlippage, byNotional)
+	if remaining := curve.MaxSupply - curve.Supply; curve.MaxSupply > 0 && remaining >= 0 {
+		size = decimal.Min(size, decimal.NewFromInt(remaining))
+	}
+	if curve.MaxBuySize.IsPositive() {
+		size = decimal.Min(size, curve.MaxBuySize)
+	}
+	return size, nil
+}
@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type staticCurves map[string]*types.BondingCurve
+
+func (c staticCurves) GetBondingCurve(ctx context.Context, symbol string) (*types.BondingCurve, error) {
+	return c[symbol], nil
+}
+
+func TestPumpStrategy_FairPositionSizeOnSteepCurve(t *testing.T) {
+	config := &types.PumpTradingConfig{MaxSlippage: decimal.NewFromFloat(0.02)}
+	config.Risk.MaxPositionSize = decimal.NewFromInt(100)
+	s := NewPumpStrategy(config, nil, zap.NewNop())
+
+	// p(x) = 0.001 + 0.0001x at supply 1000, so the current price is 0.101
+	curve := &types.BondingCurve{
+		Symbol:    "STEEP",
+		BasePrice: decimal.NewFromFloat(0.001),
+		Slope:     decimal.NewFromFloat(0.0001),
+		Supply:    1000,
+	}
+	s.SetBondingCurveSource(staticCurves{"STEEP": curve})
+	price := decimal.NewFromFloat(0.101)
+
+	naive, err := s.CalculatePositionSize(price)
+	assert.NoError(t, err)
+
+	size, err := s.CalculateFairPositionSize(context.Background(), "STEEP", price)
+	assert.NoError(t, err)
+	assert.True(t, size.LessThan(naive), "fair %s, naive %s", size, naive)
+
+	// The average fill over the bought range stays within the 2% budget
+	avg := price.Add(curve.Slope.Mul(size).Div(decimal.NewFromInt(2)))
+	slippage := avg.Sub(price).Div(price)
+	assert.InDelta(t, 0.02, slippage.InexactFloat64(), 1e-9)
+
+	// A flat curve has no price impact, leaving the naive size
+	curve.Slope = decimal.Zero
+	curve.CurrentPrice = price
+	size, err = s.CalculateFairPositionSize(context.Background(), "STEEP", price)
+	assert.NoError(t, err)
+	assert.True(t, size.Equal(naive), "fair %s, naive %s", size, naive)
+}
@@ -2,6 +2,8 @@ package strategy
 
 import (
 	"time"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 type PumpFunConfig struct {
@@ -32,7 +34,7 @@ type PumpFunConfig struct {
 
 func NewDefaultPumpFunConfig() *PumpFunConfig {
 	return &PumpFunConfig{
-		MaxMarketCap: 30000.0,
+		MaxMarketCap: types.DefaultMaxMarketCap.InexactFloat64(),
 		MinVolume:    1000.0,
 		WebSocket: struct {
 			ReconnectTimeout time.Duration `yaml:"reconnect_timeout"`
@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/risk"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/interfaces"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// regimeRiskManager sizes through a portfolio risk manager's regime scale
+type regimeRiskManager struct {
+	*types.MockRiskManager
+	regime *risk.Manager
+}
+
+func (r *regimeRiskManager) CalculateRegimeSize(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	return r.regime.CalculateRegimeSize(ctx, symbol, price)
+}
+
+type benchmarkReturns map[string][]float64
+
+func (b benchmarkReturns) Returns(ctx context.Context, symbol string) ([]float64, error) {
+	return b[symbol], nil
+}
+
+func TestPumpStrategy_RegimeScalesSize(t *testing.T) {
+	regime := risk.NewManager(risk.Limits{
+		MaxPositionSize:   decimal.NewFromInt(1000),
+		Benchmark:         "SOL",
+		RegimeCorrelation: 0.8,
+		RegimeScale:       decimal.NewFromFloat(0.5),
+	}, zap.NewNop())
+	// PEPE/SOL tracks a falling SOL, so size is halved
+	regime.SetReturnsSource(benchmarkReturns{
+		"SOL":      {-0.01, -0.02, 0.01, -0.03},
+		"PEPE/SOL": {-0.02, -0.04, 0.02, -0.06},
+	})
+	executor := &mockPumpExecutor{}
+	riskMgr := &regimeRiskManager{MockRiskManager: &types.MockRiskManager{}, regime: regime}
+	s := NewPumpStrategy(&types.PumpTradingConfig{
+		MaxMarketCap: decimal.NewFromInt(30000),
+		MinVolume:    decimal.NewFromInt(1000),
+	}, &regimeExecutor{mockPumpExecutor: executor, riskMgr: riskMgr}, zap.NewNop())
+
+	executor.On("ExecuteTrade", mock.Anything, mock.Anything).Return(nil).Once()
+	err := s.ProcessUpdate(&types.TokenUpdate{
+		Symbol:    "PEPE/SOL",
+		Price:     100,
+		MarketCap: 20000,
+		Volume:    2000,
+		Timestamp: time.Now(),
+	})
+	assert.NoError(t, err)
+
+	riskMgr.AssertNotCalled(t, "CalculatePositionSize", mock.Anything, mock.Anything)
+	if position := s.positions["PEPE/SOL"]; assert.NotNil(t, position) {
+		assert.True(t, decimal.NewFromInt(5).Equal(position.Size), position.Size.String())
+	}
+}
+
+// regimeExecutor hands the strategy a regime-aware risk manager
+type regimeExecutor struct {
+	*mockPumpExecutor
+	riskMgr interfaces.RiskManager
+}
+
+func (e *regimeExecutor) GetRiskManager() interfaces.RiskManager {
+	return e.riskMgr
+}
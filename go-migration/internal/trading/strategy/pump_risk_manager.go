@@ -16,6 +16,7 @@ type PumpRiskManager struct {
 	config     *types.RiskConfig
 	positions  map[string]*types.Position
 	stopLosses map[string]decimal.Decimal
+	atr        *ATRTracker
 	mu         sync.RWMutex
 }
 
@@ -44,12 +45,18 @@ func NewPumpRiskManager(logger *zap.Logger, config *types.RiskConfig) *PumpRiskM
 		metrics.TokenVolume.WithLabelValues("pump.fun", fmt.Sprintf("take_profit_%sx", level.Multiplier.String())).Set(level.Percentage.InexactFloat64())
 	}
 
-	return &PumpRiskManager{
+	r := &PumpRiskManager{
 		logger:     logger,
 		config:     config,
 		positions:  make(map[string]*types.Position),
 		stopLosses: make(map[string]decimal.Decimal),
 	}
+	if config.VolatilityStop.Enabled {
+		r.atr = NewATRTracker(config.VolatilityStop.Period)
+	} else if config.ATRTakeProfit.Enabled {
+		r.atr = NewATRTracker(config.ATRTakeProfit.Period)
+	}
+	return r
 }
 
 func (r *PumpRiskManager) ValidatePosition(symbol string, size decimal.Decimal) error {
@@ -96,6 +103,14 @@ func (r *PumpRiskManager) UpdateStopLoss(symbol string, price decimal.Decimal) e
 		return NewPumpStrategyError(OpUpdateStopLoss, symbol, "no position found", nil)
 	}
 
+	if r.atr != nil {
+		r.atr.Record(symbol, price)
+		if atr, ok := r.atr.ATR(symbol); ok && r.config.VolatilityStop.Enabled {
+			r.setVolatilityStop(symbol, position, atr)
+			return nil
+		}
+	}
+
 	currentStopLoss := r.stopLosses[symbol]
 	if currentStopLoss.IsZero() {
 		// Initial stop loss
@@ -130,6 +145,26 @@ func (r *PumpRiskManager) UpdateStopLoss(symbol string, price decimal.Decimal) e
 	return nil
 }
 
+// setVolatilityStop places the stop a multiple of ATR below entry. It is
+// recomputed on every update so the stop widens and tightens with volatility.
+func (r *PumpRiskManager) setVolatilityStop(symbol string, position *types.Position, atr decimal.Decimal) {
+	stopLoss := position.EntryPrice.Sub(atr.Mul(r.config.VolatilityStop.Multiplier))
+	if stopLoss.IsNegative() {
+		stopLoss = decimal.Zero
+	}
+	if stopLoss.Equal(r.stopLosses[symbol]) {
+		return
+	}
+
+	r.stopLosses[symbol] = stopLoss
+	metrics.TokenVolume.WithLabelValues("pump.fun", symbol+"_stop_loss").Set(stopLoss.InexactFloat64())
+	r.logger.Debug("volatility stop loss updated",
+		zap.String("symbol", symbol),
+		zap.String("entry_price", position.EntryPrice.String()),
+		zap.String("atr", atr.String()),
+		zap.String("stop_loss", stopLoss.String()))
+}
+
 func (r *PumpRiskManager) CheckTakeProfit(symbol string, price decimal.Decimal) (bool, decimal.Decimal) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -151,6 +186,10 @@ func (r *PumpRiskManager) CheckTakeProfit(symbol string, price decimal.Decimal)
 		return true, decimal.NewFromInt(1)
 	}
 
+	if levels, atr, ok := r.atrLadder(symbol); ok {
+		return r.checkATRTakeProfit(symbol, position, price, levels, atr)
+	}
+
 	// Check take profit levels
 	for _, level := range r.config.TakeProfitLevels {
 		targetPrice := position.EntryPrice.Mul(level.Multiplier)
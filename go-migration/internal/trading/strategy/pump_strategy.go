@@ -22,28 +22,52 @@ type PumpStrategy struct {
 	mu          sync.RWMutex
 	isRunning   bool
 	updateChan  chan *types.TokenUpdate
+	wash        *WashTradingDetector
+	curves      BondingCurveSource
 }
 
 func NewPumpStrategy(config *types.PumpTradingConfig, executor interfaces.Executor, logger *zap.Logger) *PumpStrategy {
-	return &PumpStrategy{
+	s := &PumpStrategy{
 		logger:     logger,
 		config:     config,
 		executor:   executor,
 		positions:  make(map[string]*types.Position),
 		updateChan: make(chan *types.TokenUpdate, 1000),
 	}
+	if config.WashTrading.Enabled {
+		s.wash = NewWashTradingDetector(config.WashTrading)
+	}
+	return s
 }
 
 func (s *PumpStrategy) Evaluate(ctx context.Context, token *types.TokenMarketInfo) (bool, error) {
 	if token.MarketCap.GreaterThan(s.config.MaxMarketCap) {
 		return false, nil
 	}
-	if token.Volume.LessThan(s.config.MinVolume) {
+	volume := s.discountWashVolume(token.Symbol, token.Price.InexactFloat64(), token.Volume)
+	if volume.LessThan(s.config.MinVolume) {
 		return false, nil
 	}
 	return true, nil
 }
 
+// discountWashVolume removes suspected wash-trading volume when detection is enabled
+func (s *PumpStrategy) discountWashVolume(symbol string, price float64, volume decimal.Decimal) decimal.Decimal {
+	if s.wash == nil {
+		return volume
+	}
+	adjusted, suspect := s.wash.Adjust(symbol, price, volume.InexactFloat64())
+	metrics.SuspectedWashVolume.WithLabelValues("pump.fun", symbol).Set(suspect)
+	metrics.SymbolSeries.Track(symbol, metrics.SuspectedWashVolume, "pump.fun", symbol)
+	if suspect > 0 {
+		s.logger.Debug("discounted suspected wash volume",
+			zap.String("symbol", symbol),
+			zap.Float64("suspect", suspect),
+			zap.Float64("adjusted", adjusted))
+	}
+	return decimal.NewFromFloat(adjusted)
+}
+
 func (s *PumpStrategy) CalculatePositionSize(price decimal.Decimal) (decimal.Decimal, error) {
 	maxSize := s.config.Risk.MaxPositionSize
 	minSize := s.config.Risk.MinPositionSize
@@ -115,7 +139,7 @@ func (s *PumpStrategy) ProcessUpdate(update *types.TokenUpdate) error {
 		return nil
 	}
 
-	volume := decimal.NewFromFloat(update.Volume)
+	volume := s.discountWashVolume(update.Symbol, update.Price, decimal.NewFromFloat(update.Volume))
 	if volume.LessThan(s.config.MinVolume) {
 		metrics.APIErrors.WithLabelValues("pump_insufficient_volume").Inc()
 		return nil
@@ -141,7 +165,7 @@ func (s *PumpStrategy) ProcessUpdate(update *types.TokenUpdate) error {
 				Provider:  "pump.fun",
 				Timestamp: time.Now(),
 			}
-			if err := s.ExecuteTrade(context.Background(), signal); err != nil {
+			if err := s.executeTrade(signal); err != nil {
 				metrics.APIErrors.WithLabelValues("pump_execute_trade").Inc()
 				return NewPumpStrategyError(OpExecuteTrade, update.Symbol, "failed to execute take profit", err)
 			}
@@ -149,10 +173,21 @@ func (s *PumpStrategy) ProcessUpdate(update *types.TokenUpdate) error {
 		return nil
 	}
 
-	size, err := s.executor.GetRiskManager().CalculatePositionSize(update.Symbol, price)
+	size, err := s.positionSize(update.Symbol, price)
 	if err != nil {
 		return NewPumpStrategyError(OpCalculatePosition, update.Symbol, "failed to calculate position size", err)
 	}
+	if s.curves != nil && s.config.MaxSlippage.IsPositive() {
+		fair, err := s.CalculateFairPositionSize(context.Background(), update.Symbol, price)
+		if err != nil {
+			return err
+		}
+		size = decimal.Min(size, fair)
+	}
+	if update.Decimals > 0 {
+		// Token amounts cannot be finer than the mint's smallest unit
+		size = size.Truncate(int32(update.Decimals))
+	}
 
 	signal := &types.Signal{
 		Symbol:    update.Symbol,
@@ -163,13 +198,33 @@ func (s *PumpStrategy) ProcessUpdate(update *types.TokenUpdate) error {
 		Timestamp: time.Now(),
 	}
 
-	return s.ExecuteTrade(context.Background(), signal)
+	return s.executeTrade(signal)
+}
+
+// regimeSizer is implemented by risk managers that scale position size for
+// the current correlation regime
+type regimeSizer interface {
+	CalculateRegimeSize(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error)
+}
+
+// positionSize sizes a new position through the executor's risk manager,
+// scaled for the correlation regime when the risk manager supports it
+func (s *PumpStrategy) positionSize(symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	riskMgr := s.executor.GetRiskManager()
+	if regime, ok := riskMgr.(regimeSizer); ok {
+		return regime.CalculateRegimeSize(context.Background(), symbol, price)
+	}
+	return riskMgr.CalculatePositionSize(symbol, price)
 }
 
 func (s *PumpStrategy) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.executeTrade(signal)
+}
 
+// executeTrade books signal into its position. Callers hold the lock.
+func (s *PumpStrategy) executeTrade(signal *types.Signal) error {
 	position := s.positions[signal.Symbol]
 	if position == nil {
 		position = &types.Position{
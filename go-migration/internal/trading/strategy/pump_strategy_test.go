@@ -5,106 +5,39 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kwanRoshi/B/go-migration/internal/trading/interfaces"
 	"github.com/kwanRoshi/B/go-migration/internal/types"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
 
-type mockPumpTrader struct {
+type mockPumpExecutor struct {
 	mock.Mock
+	riskMgr *types.MockRiskManager
 }
 
-func (m *mockPumpTrader) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
+func (m *mockPumpExecutor) ExecuteTrade(ctx context.Context, signal *types.Signal) error {
 	args := m.Called(ctx, signal)
 	return args.Error(0)
 }
 
-func (m *mockPumpTrader) GetPosition(symbol string) *types.Position {
-	args := m.Called(symbol)
-	return args.Get(0).(*types.Position)
-}
-
-func (m *mockPumpTrader) GetPositions() map[string]*types.Position {
-	args := m.Called()
-	return args.Get(0).(map[string]*types.Position)
-}
-
-func (m *mockPumpTrader) Start() error {
-	args := m.Called()
-	return args.Error(0)
-}
-
-func (m *mockPumpTrader) Stop() error {
-	args := m.Called()
-	return args.Error(0)
-}
-
-type mockPumpMarketData struct {
-	mock.Mock
-}
-
-func (m *mockPumpMarketData) GetTokenPrice(ctx context.Context, symbol string) (float64, error) {
-	args := m.Called(ctx, symbol)
-	return args.Get(0).(float64), args.Error(1)
-}
-
-func (m *mockPumpMarketData) GetTokenVolume(ctx context.Context, symbol string) (float64, error) {
-	args := m.Called(ctx, symbol)
-	return args.Get(0).(float64), args.Error(1)
-}
-
-func (m *mockPumpMarketData) GetBondingCurve(ctx context.Context, symbol string) (*types.TokenBondingCurve, error) {
-	args := m.Called(ctx, symbol)
-	return args.Get(0).(*types.TokenBondingCurve), args.Error(1)
-}
-
-func (m *mockPumpMarketData) SubscribeNewTokens(ctx context.Context) (<-chan *types.TokenInfo, error) {
-	args := m.Called(ctx)
-	return args.Get(0).(<-chan *types.TokenInfo), args.Error(1)
-}
-
-func (m *mockPumpMarketData) GetTokenUpdates() <-chan *types.TokenUpdate {
-	args := m.Called()
-	return args.Get(0).(<-chan *types.TokenUpdate)
-}
-
-type mockPumpRiskManager struct {
-	mock.Mock
-}
-
-func (m *mockPumpRiskManager) ValidatePosition(symbol string, size float64) error {
-	args := m.Called(symbol, size)
-	return args.Error(0)
-}
-
-func (m *mockPumpRiskManager) CalculatePositionSize(symbol string, price float64) (float64, error) {
-	args := m.Called(symbol, price)
-	return args.Get(0).(float64), args.Error(1)
-}
-
-func (m *mockPumpRiskManager) UpdateStopLoss(symbol string, price float64) error {
-	args := m.Called(symbol, price)
-	return args.Error(0)
-}
-
-func (m *mockPumpRiskManager) CheckTakeProfit(symbol string, price float64) (bool, float64) {
-	args := m.Called(symbol, price)
-	return args.Bool(0), args.Get(1).(float64)
+func (m *mockPumpExecutor) GetRiskManager() interfaces.RiskManager {
+	return m.riskMgr
 }
 
 func TestPumpStrategy_ProcessUpdate(t *testing.T) {
 	logger := zap.NewNop()
 	config := &types.PumpTradingConfig{
-		MaxMarketCap: 30000.0,
-		MinVolume:    1000.0,
+		MaxMarketCap: decimal.NewFromFloat(30000.0),
+		MinVolume:    decimal.NewFromFloat(1000.0),
 	}
 
-	trader := new(mockPumpTrader)
-	marketData := new(mockPumpMarketData)
-	riskMgr := new(mockPumpRiskManager)
+	riskMgr := &types.MockRiskManager{}
+	executor := &mockPumpExecutor{riskMgr: riskMgr}
 
-	strategy := NewPumpStrategy(logger, config, riskMgr, marketData, trader)
+	strategy := NewPumpStrategy(config, executor, logger)
 
 	t.Run("should process valid token update", func(t *testing.T) {
 		update := &types.TokenUpdate{
@@ -115,23 +48,22 @@ func TestPumpStrategy_ProcessUpdate(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		riskMgr.On("CalculatePositionSize", update.Symbol, update.Price).Return(1.0, nil)
-		trader.On("ExecuteTrade", mock.Anything, mock.MatchedBy(func(signal *types.Signal) bool {
-			return signal.Symbol == update.Symbol &&
-				signal.Price == update.Price &&
-				signal.Side == types.OrderSideBuy
-		})).Return(nil)
+		riskMgr.On("CalculatePositionSize", update.Symbol, decimal.NewFromFloat(update.Price)).Return(decimal.NewFromFloat(1.0), nil).Once()
 
 		err := strategy.ProcessUpdate(update)
 		assert.NoError(t, err)
 
 		riskMgr.AssertExpectations(t)
-		trader.AssertExpectations(t)
+		position := strategy.positions[update.Symbol]
+		if assert.NotNil(t, position) {
+			assert.True(t, decimal.NewFromFloat(1.0).Equal(position.Size))
+			assert.True(t, decimal.NewFromFloat(100.0).Equal(position.EntryPrice))
+		}
 	})
 
 	t.Run("should skip update with high market cap", func(t *testing.T) {
 		update := &types.TokenUpdate{
-			Symbol:    "TEST/SOL",
+			Symbol:    "HIGH/SOL",
 			Price:     100.0,
 			MarketCap: 40000.0,
 			Volume:    2000.0,
@@ -141,13 +73,13 @@ func TestPumpStrategy_ProcessUpdate(t *testing.T) {
 		err := strategy.ProcessUpdate(update)
 		assert.NoError(t, err)
 
-		riskMgr.AssertNotCalled(t, "CalculatePositionSize")
-		trader.AssertNotCalled(t, "ExecuteTrade")
+		riskMgr.AssertNotCalled(t, "CalculatePositionSize", update.Symbol, mock.Anything)
+		assert.Nil(t, strategy.positions[update.Symbol])
 	})
 
 	t.Run("should skip update with low volume", func(t *testing.T) {
 		update := &types.TokenUpdate{
-			Symbol:    "TEST/SOL",
+			Symbol:    "THIN/SOL",
 			Price:     100.0,
 			MarketCap: 20000.0,
 			Volume:    500.0,
@@ -157,8 +89,8 @@ func TestPumpStrategy_ProcessUpdate(t *testing.T) {
 		err := strategy.ProcessUpdate(update)
 		assert.NoError(t, err)
 
-		riskMgr.AssertNotCalled(t, "CalculatePositionSize")
-		trader.AssertNotCalled(t, "ExecuteTrade")
+		riskMgr.AssertNotCalled(t, "CalculatePositionSize", update.Symbol, mock.Anything)
+		assert.Nil(t, strategy.positions[update.Symbol])
 	})
 
 	t.Run("should handle existing position", func(t *testing.T) {
@@ -169,26 +101,19 @@ func TestPumpStrategy_ProcessUpdate(t *testing.T) {
 			Volume:    2000.0,
 			Timestamp: time.Now(),
 		}
+		price := decimal.NewFromFloat(update.Price)
 
-		position := &types.Position{
-			Symbol: update.Symbol,
-			Size:   1.0,
-		}
-		strategy.positions[update.Symbol] = position
-
-		riskMgr.On("UpdateStopLoss", update.Symbol, update.Price).Return(nil)
-		riskMgr.On("CheckTakeProfit", update.Symbol, update.Price).Return(true, 0.2)
-		trader.On("ExecuteTrade", mock.Anything, mock.MatchedBy(func(signal *types.Signal) bool {
-			return signal.Symbol == update.Symbol &&
-				signal.Price == update.Price &&
-				signal.Side == types.OrderSideSell &&
-				signal.Size == position.Size*0.2
-		})).Return(nil)
+		riskMgr.On("UpdateStopLoss", update.Symbol, price).Return(nil).Once()
+		riskMgr.On("CheckTakeProfit", update.Symbol, price).Return(true, decimal.NewFromFloat(0.2)).Once()
 
 		err := strategy.ProcessUpdate(update)
 		assert.NoError(t, err)
 
 		riskMgr.AssertExpectations(t)
-		trader.AssertExpectations(t)
+		// A fifth of the position is sold at the take profit
+		position := strategy.positions[update.Symbol]
+		if assert.NotNil(t, position) {
+			assert.True(t, decimal.NewFromFloat(0.8).Equal(position.Size), position.Size.String())
+		}
 	})
 }
@@ -0,0 +1,96 @@
+package strategy
+
+import (
+	"math"
+	"sync"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type washState struct {
+	price      float64
+	volume     float64
+	increments []float64
+	suspect    float64
+}
+
+// WashTradingDetector discounts volume that looks like wash trading before
+// it reaches the MinVolume filter. Two heuristics are applied to successive
+// cumulative volume readings for a token: a volume spike with negligible
+// price movement, and a run of identical volume increments.
+type WashTradingDetector struct {
+	config types.WashTradingConfig
+	state  map[string]*washState
+	mu     sync.Mutex
+}
+
+func NewWashTradingDetector(config types.WashTradingConfig) *WashTradingDetector {
+	if config.Discount <= 0 || config.Discount > 1 {
+		config.Discount = 1
+	}
+	return &WashTradingDetector{
+		config: config,
+		state:  make(map[string]*washState),
+	}
+}
+
+// Adjust records a price/volume reading for symbol and returns the volume
+// with suspect volume discounted, along with the cumulative suspect volume
+func (d *WashTradingDetector) Adjust(symbol string, price, volume float64) (float64, float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	st, ok := d.state[symbol]
+	if !ok {
+		d.state[symbol] = &washState{price: price, volume: volume}
+		return volume, 0
+	}
+
+	delta := volume - st.volume
+	if delta > 0 {
+		suspect := false
+
+		if d.config.SpikeMultiplier > 0 && st.volume > 0 && st.price > 0 &&
+			volume >= st.volume*d.config.SpikeMultiplier &&
+			math.Abs(price-st.price)/st.price < d.config.MaxPriceMove {
+			suspect = true
+		}
+
+		if d.config.RepeatCount > 1 {
+			st.increments = append(st.increments, delta)
+			if len(st.increments) > d.config.RepeatCount {
+				st.increments = st.increments[len(st.increments)-d.config.RepeatCount:]
+			}
+			if len(st.increments) == d.config.RepeatCount && d.repetitive(st.increments) {
+				suspect = true
+			}
+		}
+
+		if suspect {
+			st.suspect += delta
+		}
+	} else if delta < 0 {
+		// Volume window rolled over, start afresh
+		st.increments = nil
+		st.suspect = 0
+	}
+
+	st.price = price
+	st.volume = volume
+
+	adjusted := volume - st.suspect*d.config.Discount
+	if adjusted < 0 {
+		adjusted = 0
+	}
+	return adjusted, st.suspect
+}
+
+func (d *WashTradingDetector) repetitive(increments []float64) bool {
+	first := increments[0]
+	for _, inc := range increments[1:] {
+		if math.Abs(inc-first) > first*d.config.SizeTolerance {
+			return false
+		}
+	}
+	return true
+}
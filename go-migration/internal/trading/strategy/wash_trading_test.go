@@ -0,0 +1,92 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func washConfig() types.WashTradingConfig {
+	return types.WashTradingConfig{
+		Enabled:         true,
+		SpikeMultiplier: 3,
+		MaxPriceMove:    0.01,
+		RepeatCount:     4,
+		SizeTolerance:   0.001,
+		Discount:        1,
+	}
+}
+
+func TestWashTradingDetector_SpikeWithoutPriceMove(t *testing.T) {
+	detector := NewWashTradingDetector(washConfig())
+
+	adjusted, suspect := detector.Adjust("WASH", 1.0, 500)
+	assert.Equal(t, 500.0, adjusted)
+	assert.Zero(t, suspect)
+
+	// Volume jumps 10x while the price barely moves
+	adjusted, suspect = detector.Adjust("WASH", 1.0001, 5000)
+	assert.InDelta(t, 4500.0, suspect, 1e-9)
+	assert.InDelta(t, 500.0, adjusted, 1e-9)
+
+	// A spike backed by a real price move is left alone
+	detector.Adjust("REAL", 1.0, 500)
+	adjusted, suspect = detector.Adjust("REAL", 1.5, 5000)
+	assert.Zero(t, suspect)
+	assert.Equal(t, 5000.0, adjusted)
+}
+
+func TestWashTradingDetector_RepetitiveIncrements(t *testing.T) {
+	detector := NewWashTradingDetector(washConfig())
+
+	volume := 100.0
+	price := 1.0
+	detector.Adjust("WASH", price, volume)
+	var adjusted, suspect float64
+	for i := 0; i < 4; i++ {
+		volume += 250
+		price *= 1.05
+		adjusted, suspect = detector.Adjust("WASH", price, volume)
+	}
+
+	// Only the increment completing the run of identical sizes is discounted
+	assert.InDelta(t, 250.0, suspect, 1e-9)
+	assert.InDelta(t, volume-250, adjusted, 1e-9)
+}
+
+func TestPumpStrategy_WashVolumeBelowEntryThreshold(t *testing.T) {
+	config := &types.PumpTradingConfig{
+		MaxMarketCap: decimal.NewFromInt(1000000),
+		MinVolume:    decimal.NewFromInt(1000),
+		WashTrading:  washConfig(),
+	}
+	s := NewPumpStrategy(config, nil, zap.NewNop())
+
+	token := &types.TokenMarketInfo{
+		Symbol:    "WASH",
+		MarketCap: decimal.NewFromInt(50000),
+		Price:     decimal.NewFromFloat(1.0),
+		Volume:    decimal.NewFromInt(500),
+	}
+	ok, err := s.Evaluate(context.Background(), token)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Raw volume clears MinVolume but the washed portion is discounted
+	token.Price = decimal.NewFromFloat(1.0001)
+	token.Volume = decimal.NewFromInt(5000)
+	ok, err = s.Evaluate(context.Background(), token)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Without detection the same volume passes the filter
+	config.WashTrading.Enabled = false
+	ok, err = NewPumpStrategy(config, nil, zap.NewNop()).Evaluate(context.Background(), token)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
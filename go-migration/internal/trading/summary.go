@@ -0,0 +1,198 @@
+package trading
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// SummaryConfig emits a performance summary at the end of every period,
+// aligned to UTC so a 24h interval ends at midnight. Summaries are always
+// logged and also posted as JSON to WebhookURL when set.
+type SummaryConfig struct {
+	Enabled    bool          `yaml:"enabled"`
+	Interval   time.Duration `yaml:"interval"`    // Period length, one day by default
+	WebhookURL string        `yaml:"webhook_url"` // Optional destination for each summary
+}
+
+// PerformanceSummary reports trading performance over one period
+type PerformanceSummary struct {
+	PeriodStart  time.Time       `json:"period_start"`
+	PeriodEnd    time.Time       `json:"period_end"`
+	RealizedPnL  decimal.Decimal `json:"realized_pnl"`
+	Trades       int             `json:"trades"` // Fills that realized PnL, closing or reducing a position
+	WinRate      float64         `json:"win_rate"`
+	OpenExposure decimal.Decimal `json:"open_exposure"` // Gross value of open positions
+	Drawdown     float64         `json:"drawdown"`      // Fraction below peak equity
+}
+
+// SummarySink delivers performance summaries
+type SummarySink interface {
+	SendSummary(ctx context.Context, summary *PerformanceSummary) error
+}
+
+// periodStats accumulates the closed trades of the current summary period
+type periodStats struct {
+	start    time.Time
+	realized decimal.Decimal
+	trades   int
+	wins     int
+	peak     decimal.Decimal // Highest equity seen across periods
+}
+
+// SetSummarySink sets where performance summaries are delivered, replacing
+// any webhook configured in SummaryConfig
+func (e *Engine) SetSummarySink(sink SummarySink) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.summarySink = sink
+}
+
+// recordPeriodTrade adds the PnL a trade realized to the current summary
+// period. Every fill that reduces or closes a position counts as a trade.
+// Callers hold e.mu.
+func (e *Engine) recordPeriodTrade(pnl decimal.Decimal) {
+	e.period.realized = e.period.realized.Add(pnl)
+	e.period.trades++
+	if pnl.IsPositive() {
+		e.period.wins++
+	}
+}
+
+// summaryInterval returns the configured summary period, one day by default
+func (e *Engine) summaryInterval() time.Duration {
+	if e.config.Summary.Interval > 0 {
+		return e.config.Summary.Interval
+	}
+	return 24 * time.Hour
+}
+
+// checkSummary tracks peak equity and emits the summary of the period that
+// ended once the engine clock crosses a period boundary. The first check
+// only starts the period.
+func (e *Engine) checkSummary(ctx context.Context) {
+	if !e.config.Summary.Enabled {
+		return
+	}
+
+	now := e.now().UTC()
+	boundary := now.Truncate(e.summaryInterval())
+
+	equity, exposure := e.portfolioEquity()
+
+	e.mu.Lock()
+	if equity.GreaterThan(e.period.peak) {
+		e.period.peak = equity
+	}
+	if e.period.start.IsZero() {
+		e.period.start = boundary
+	}
+	if e.period.start.Equal(boundary) {
+		e.mu.Unlock()
+		return
+	}
+
+	summary := &PerformanceSummary{
+		PeriodStart:  e.period.start,
+		PeriodEnd:    boundary,
+		RealizedPnL:  e.period.realized,
+		Trades:       e.period.trades,
+		OpenExposure: exposure,
+	}
+	if e.period.trades > 0 {
+		summary.WinRate = float64(e.period.wins) / float64(e.period.trades)
+	}
+	if e.period.peak.IsPositive() {
+		summary.Drawdown = e.period.peak.Sub(equity).Div(e.period.peak).InexactFloat64()
+	}
+	e.period = periodStats{start: boundary, peak: e.period.peak}
+	sink := e.summarySink
+	e.mu.Unlock()
+
+	e.logger.Info("Performance summary",
+		zap.Time("period_start", summary.PeriodStart),
+		zap.Time("period_end", summary.PeriodEnd),
+		zap.String("realized_pnl", summary.RealizedPnL.String()),
+		zap.Int("trades", summary.Trades),
+		zap.Float64("win_rate", summary.WinRate),
+		zap.String("open_exposure", summary.OpenExposure.String()),
+		zap.Float64("drawdown", summary.Drawdown))
+
+	if sink == nil {
+		return
+	}
+	if err := sink.SendSummary(ctx, summary); err != nil {
+		e.logger.Error("Failed to send performance summary", zap.Error(err))
+	}
+}
+
+// portfolioEquity returns the equity of every executor together, its cash
+// plus unrealized PnL, and the gross value of their open positions.
+// Executors are asked without the engine lock, since one may be blocked on
+// a trade in flight.
+func (e *Engine) portfolioEquity() (decimal.Decimal, decimal.Decimal) {
+	e.mu.RLock()
+	executors := e.copyExecutors()
+	equity := decimal.Zero
+	for name := range executors {
+		equity = equity.Add(e.config.Equity.InitialCash).Add(e.realized[name])
+	}
+	e.mu.RUnlock()
+
+	exposure := decimal.Zero
+	for _, exec := range executors {
+		for _, pos := range exec.GetPositions() {
+			price := pos.CurrentPrice
+			if price.IsZero() {
+				price = pos.EntryPrice
+			}
+			equity = equity.Add(pos.UnrealizedPnL)
+			exposure = exposure.Add(pos.Size.Mul(price).Abs())
+		}
+	}
+	return equity, exposure
+}
+
+// webhookSummarySink posts summaries as JSON to an HTTP endpoint
+type webhookSummarySink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSummarySink creates a sink posting summaries to url
+func NewWebhookSummarySink(url string) SummarySink {
+	return &webhookSummarySink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *webhookSummarySink) SendSummary(ctx context.Context, summary *PerformanceSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send summary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("summary endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
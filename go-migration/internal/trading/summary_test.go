@@ -0,0 +1,116 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+type recordingSink struct {
+	summaries []*PerformanceSummary
+}
+
+func (r *recordingSink) SendSummary(ctx context.Context, summary *PerformanceSummary) error {
+	r.summaries = append(r.summaries, summary)
+	return nil
+}
+
+func TestEngine_DailySummaryAtMidnight(t *testing.T) {
+	now := time.Date(2025, 2, 1, 22, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		Summary: SummaryConfig{Enabled: true},
+		Equity:  EquityConfig{InitialCash: decimal.NewFromInt(1000)},
+	}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	exec := &bookExecutor{positions: map[string]*types.Position{
+		"SOL": {Symbol: "SOL", Size: decimal.NewFromInt(2), EntryPrice: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(90), UnrealizedPnL: decimal.NewFromInt(-20)},
+	}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	sink := &recordingSink{}
+	engine.SetSummarySink(sink)
+
+	// The first check starts the period; equity peaks at 980
+	engine.checkSummary(context.Background())
+	engine.RecordRealizedPnL("pump.fun", decimal.NewFromInt(50))
+	engine.RecordRealizedPnL("pump.fun", decimal.NewFromInt(-100))
+	engine.checkSummary(context.Background())
+	assert.Empty(t, sink.summaries)
+
+	// Crossing midnight emits the day's summary
+	now = now.Add(3 * time.Hour)
+	engine.checkSummary(context.Background())
+	if assert.Len(t, sink.summaries, 1) {
+		summary := sink.summaries[0]
+		assert.True(t, summary.PeriodStart.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, summary.PeriodEnd.Equal(time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, decimal.NewFromInt(-50).Equal(summary.RealizedPnL))
+		assert.Equal(t, 2, summary.Trades)
+		assert.Equal(t, 0.5, summary.WinRate)
+		assert.True(t, decimal.NewFromInt(180).Equal(summary.OpenExposure))
+		assert.InDelta(t, 50.0/980, summary.Drawdown, 1e-9)
+	}
+
+	// The next period starts empty
+	now = now.Add(24 * time.Hour)
+	engine.checkSummary(context.Background())
+	if assert.Len(t, sink.summaries, 2) {
+		assert.Zero(t, sink.summaries[1].Trades)
+	}
+}
+
+func TestEngine_SummaryCountsBookedFills(t *testing.T) {
+	now := time.Date(2025, 2, 1, 22, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{Summary: SummaryConfig{Enabled: true}}, zap.NewNop(), storage.NewMemoryStorage())
+	engine.now = func() time.Time { return now }
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", &recordingExecutor{}))
+	sink := &recordingSink{}
+	engine.SetSummarySink(sink)
+	engine.checkSummary(context.Background())
+
+	// A scale-out in two fills realizes PnL twice
+	signal := func(side types.SignalType, amount, price int64) *types.Signal {
+		return &types.Signal{Symbol: "BONK", Type: side, Provider: "pump.fun",
+			Amount: decimal.NewFromInt(amount), Price: decimal.NewFromInt(price)}
+	}
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal(types.SignalTypeBuy, 10, 2)))
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal(types.SignalTypeSell, 4, 3)))
+	assert.NoError(t, engine.ProcessSignal(context.Background(), signal(types.SignalTypeSell, 6, 1)))
+
+	now = now.Add(3 * time.Hour)
+	engine.checkSummary(context.Background())
+	if assert.Len(t, sink.summaries, 1) {
+		summary := sink.summaries[0]
+		assert.True(t, decimal.NewFromInt(-2).Equal(summary.RealizedPnL), "realized %s", summary.RealizedPnL)
+		assert.Equal(t, 2, summary.Trades)
+		assert.Equal(t, 0.5, summary.WinRate)
+	}
+}
+
+func TestEngine_SummaryQueriesExecutorsUnlocked(t *testing.T) {
+	now := time.Date(2025, 2, 1, 22, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{Summary: SummaryConfig{Enabled: true}}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"SOL": {Symbol: "SOL", Size: decimal.NewFromInt(2), CurrentPrice: decimal.NewFromInt(90)},
+	}}}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	sink := &recordingSink{}
+	engine.SetSummarySink(sink)
+
+	engine.checkSummary(context.Background())
+	now = now.Add(3 * time.Hour)
+	engine.checkSummary(context.Background())
+	if assert.Len(t, sink.summaries, 1) {
+		assert.True(t, decimal.NewFromInt(180).Equal(sink.summaries[0].OpenExposure))
+	}
+	assert.False(t, exec.locked)
+}
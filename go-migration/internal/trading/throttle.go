@@ -0,0 +1,117 @@
+package trading
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ThrottleConfig configures the loss-velocity trade throttle
+type ThrottleConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Window           time.Duration `yaml:"window"`             // Rolling window realized PnL is measured over
+	MaxLossVelocity  float64       `yaml:"max_loss_velocity"`  // Realized loss per minute that engages the throttle
+	MinTradeInterval time.Duration `yaml:"min_trade_interval"` // Minimum spacing between trades while engaged
+}
+
+type pnlEvent struct {
+	pnl decimal.Decimal
+	at  time.Time
+}
+
+// LossThrottle slows down trading for a strategy whose realized PnL is
+// falling faster than the configured velocity, to avoid revenge-trading
+// after a burst of losses
+type LossThrottle struct {
+	config    ThrottleConfig
+	events    map[string][]pnlEvent
+	lastTrade map[string]time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func NewLossThrottle(config ThrottleConfig) *LossThrottle {
+	return &LossThrottle{
+		config:    config,
+		events:    make(map[string][]pnlEvent),
+		lastTrade: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// RecordPnL records a realized PnL event for strategy
+func (t *LossThrottle) RecordPnL(strategy string, pnl decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events[strategy] = append(t.prune(strategy), pnlEvent{pnl: pnl, at: t.now()})
+}
+
+// LossVelocity returns the realized loss per minute over the rolling window.
+// Positive values mean the strategy is losing money.
+func (t *LossThrottle) LossVelocity(strategy string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.lossVelocity(strategy)
+}
+
+// Allow reports whether strategy may trade now. When the throttle is engaged
+// and the minimum interval has not elapsed, it returns the remaining wait.
+func (t *LossThrottle) Allow(strategy string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if v := t.lossVelocity(strategy); v == 0 || v < t.config.MaxLossVelocity {
+		return 0, true
+	}
+
+	last, ok := t.lastTrade[strategy]
+	if !ok {
+		return 0, true
+	}
+
+	elapsed := t.now().Sub(last)
+	if elapsed >= t.config.MinTradeInterval {
+		return 0, true
+	}
+	return t.config.MinTradeInterval - elapsed, false
+}
+
+// MarkTrade records that strategy has just traded
+func (t *LossThrottle) MarkTrade(strategy string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastTrade[strategy] = t.now()
+}
+
+func (t *LossThrottle) lossVelocity(strategy string) float64 {
+	if t.config.Window <= 0 {
+		return 0
+	}
+
+	var total decimal.Decimal
+	for _, event := range t.prune(strategy) {
+		total = total.Add(event.pnl)
+	}
+	if !total.IsNegative() {
+		return 0
+	}
+	return total.Neg().InexactFloat64() / t.config.Window.Minutes()
+}
+
+// prune drops events that have fallen out of the rolling window
+func (t *LossThrottle) prune(strategy string) []pnlEvent {
+	cutoff := t.now().Add(-t.config.Window)
+	events := t.events[strategy]
+
+	i := 0
+	for i < len(events) && events[i].at.Before(cutoff) {
+		i++
+	}
+	events = events[i:]
+	t.events[strategy] = events
+	return events
+}
@@ -0,0 +1,53 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLossThrottle_RapidLossesDelayTrades(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewLossThrottle(ThrottleConfig{
+		Enabled:          true,
+		Window:           10 * time.Minute,
+		MaxLossVelocity:  5, // 5 units lost per minute
+		MinTradeInterval: 2 * time.Minute,
+	})
+	throttle.now = func() time.Time { return now }
+
+	// Before any losses trades are always allowed
+	throttle.MarkTrade("pump.fun")
+	_, ok := throttle.Allow("pump.fun")
+	assert.True(t, ok)
+
+	// Lose 100 within the window => 10 per minute, above the threshold
+	for i := 0; i < 5; i++ {
+		throttle.RecordPnL("pump.fun", decimal.NewFromInt(-20))
+	}
+	assert.InDelta(t, 10.0, throttle.LossVelocity("pump.fun"), 1e-9)
+
+	throttle.MarkTrade("pump.fun")
+	now = now.Add(30 * time.Second)
+	wait, ok := throttle.Allow("pump.fun")
+	assert.False(t, ok)
+	assert.Equal(t, 90*time.Second, wait)
+
+	// Once the minimum interval has passed the next trade goes through
+	now = now.Add(90 * time.Second)
+	_, ok = throttle.Allow("pump.fun")
+	assert.True(t, ok)
+
+	// Other strategies are unaffected
+	_, ok = throttle.Allow("gmgn")
+	assert.True(t, ok)
+
+	// When the losses age out of the window the throttle disengages
+	throttle.MarkTrade("pump.fun")
+	now = now.Add(11 * time.Minute)
+	assert.Zero(t, throttle.LossVelocity("pump.fun"))
+	_, ok = throttle.Allow("pump.fun")
+	assert.True(t, ok)
+}
@@ -0,0 +1,111 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// holdConditional validates a conditional order and marks it pending so it
+// rests inactive until its trigger is crossed
+func (e *Engine) holdConditional(order *types.Order) error {
+	if !order.IsConditional() {
+		return nil
+	}
+	if !e.config.AllowConditional {
+		return fmt.Errorf("conditional orders disabled")
+	}
+	if order.TriggerDirection != types.TriggerAbove && order.TriggerDirection != types.TriggerBelow {
+		return fmt.Errorf("invalid trigger direction: %q", order.TriggerDirection)
+	}
+
+	order.Status = types.OrderStatusPending
+	return nil
+}
+
+// OnPrice activates pending conditional orders in symbol whose trigger is
+// crossed by price and submits each through ProcessSignal, so a triggered
+// order passes the same admission checks and is booked like any other
+// signal. Orders without a limit price are submitted at price. An order
+// whose submission fails is rejected rather than left pending.
+func (e *Engine) OnPrice(ctx context.Context, symbol string, price decimal.Decimal) error {
+	var errs []error
+	for _, trigger := range e.triggerOrders(symbol, price) {
+		if err := e.ProcessSignal(ctx, trigger.signal); err != nil {
+			errs = append(errs, fmt.Errorf("order %s: %w", trigger.order.ID, err))
+			e.mu.Lock()
+			trigger.order.Status = types.OrderStatusRejected
+			trigger.order.UpdatedAt = e.now()
+			e.saveTriggered(trigger.order)
+			e.mu.Unlock()
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to activate %d triggered orders: %v", len(errs), errs)
+	}
+	return nil
+}
+
+// triggeredOrder is a conditional order activated by a price and the signal
+// that submits it
+type triggeredOrder struct {
+	order  *types.Order
+	signal *types.Signal
+}
+
+// triggerOrders marks the pending orders in symbol whose trigger is crossed
+// by price active and returns them with their signals. Activating under the
+// lock keeps a concurrent price update from submitting an order twice.
+func (e *Engine) triggerOrders(symbol string, price decimal.Decimal) []triggeredOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var triggered []triggeredOrder
+	for _, order := range e.orders {
+		if order.Status != types.OrderStatusPending || order.Symbol != symbol || !order.Triggered(price) {
+			continue
+		}
+
+		order.Status = types.OrderStatusNew
+		order.UpdatedAt = e.now()
+		e.saveTriggered(order)
+
+		limit := order.Price
+		if limit.IsZero() {
+			limit = price
+		}
+
+		e.logger.Info("Conditional order triggered",
+			zap.String("order_id", order.ID),
+			zap.String("symbol", order.Symbol),
+			zap.String("trigger_price", order.TriggerPrice.String()),
+			zap.String("price", price.String()))
+
+		triggered = append(triggered, triggeredOrder{
+			order: order,
+			signal: &types.Signal{
+				Provider:  order.Provider,
+				Symbol:    order.Symbol,
+				Type:      types.SignalType(order.Side),
+				Amount:    order.Size,
+				Price:     limit,
+				Timestamp: order.UpdatedAt,
+			},
+		})
+	}
+	return triggered
+}
+
+// saveTriggered persists a triggered order's status. Callers hold e.mu.
+func (e *Engine) saveTriggered(order *types.Order) {
+	if err := e.storage.SaveOrder(order); err != nil {
+		e.logger.Error("Failed to save triggered order",
+			zap.String("order_id", order.ID),
+			zap.Error(err))
+	}
+}
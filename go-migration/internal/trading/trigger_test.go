@@ -0,0 +1,120 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_BuyStopActivatesOnTrigger(t *testing.T) {
+	engine := NewEngine(Config{
+		MaxOrderSize:     100,
+		MinOrderSize:     1,
+		AllowConditional: true,
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	exec := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	buyStop := func(id string) *types.Order {
+		return &types.Order{
+			ID:               id,
+			Symbol:           "TEST",
+			Side:             types.OrderSideBuy,
+			Type:             types.OrderTypeMarket,
+			Size:             decimal.NewFromInt(10),
+			Provider:         "pump.fun",
+			TriggerPrice:     decimal.NewFromFloat(1.5),
+			TriggerDirection: types.TriggerAbove,
+		}
+	}
+	assert.NoError(t, engine.PlaceOrder(context.Background(), buyStop("stop-1")))
+	assert.NoError(t, engine.PlaceOrder(context.Background(), buyStop("stop-2")))
+
+	// Below the trigger nothing is submitted
+	assert.NoError(t, engine.OnPrice(context.Background(), "TEST", decimal.NewFromFloat(1.4)))
+	assert.Empty(t, exec.signals)
+	order, err := engine.GetOrder(context.Background(), "stop-1")
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusPending, order.Status)
+
+	// A pending order can be cancelled before it triggers
+	assert.NoError(t, engine.CancelOrder(context.Background(), "stop-2"))
+
+	// Other symbols crossing the trigger do not activate it
+	assert.NoError(t, engine.OnPrice(context.Background(), "OTHER", decimal.NewFromFloat(2)))
+	assert.Empty(t, exec.signals)
+
+	assert.NoError(t, engine.OnPrice(context.Background(), "TEST", decimal.NewFromFloat(1.6)))
+	if assert.Len(t, exec.signals, 1) {
+		assert.Equal(t, types.SignalTypeBuy, exec.signals[0].Type)
+		assert.True(t, decimal.NewFromInt(10).Equal(exec.signals[0].Amount))
+		assert.True(t, decimal.NewFromFloat(1.6).Equal(exec.signals[0].Price))
+	}
+	assert.Equal(t, types.OrderStatusNew, order.Status)
+
+	// The fill is booked like any other signal
+	position, err := engine.GetPosition(context.Background(), "TEST")
+	if assert.NoError(t, err) {
+		assert.True(t, decimal.NewFromInt(10).Equal(position.Size), position.Size.String())
+	}
+
+	// Once active the order is not submitted again
+	assert.NoError(t, engine.OnPrice(context.Background(), "TEST", decimal.NewFromFloat(1.7)))
+	assert.Len(t, exec.signals, 1)
+}
+
+func TestEngine_TriggeredOrderPassesAdmission(t *testing.T) {
+	engine := NewEngine(Config{
+		MaxOrderSize:     100,
+		MinOrderSize:     1,
+		MaxPositions:     1,
+		AllowConditional: true,
+	}, zap.NewNop(), storage.NewMemoryStorage())
+	exec := &recordingExecutor{}
+	assert.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+	assert.NoError(t, engine.ProcessSignal(context.Background(), &types.Signal{
+		Provider: "pump.fun",
+		Symbol:   "HELD",
+		Type:     types.SignalTypeBuy,
+		Amount:   decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(1),
+	}))
+
+	assert.NoError(t, engine.PlaceOrder(context.Background(), &types.Order{
+		ID:               "stop-1",
+		Symbol:           "TEST",
+		Side:             types.OrderSideBuy,
+		Type:             types.OrderTypeMarket,
+		Size:             decimal.NewFromInt(10),
+		Provider:         "pump.fun",
+		TriggerPrice:     decimal.NewFromFloat(1.5),
+		TriggerDirection: types.TriggerAbove,
+	}))
+
+	// The position cap holds back the triggered buy, which is rejected
+	assert.Error(t, engine.OnPrice(context.Background(), "TEST", decimal.NewFromFloat(1.6)))
+	assert.Len(t, exec.signals, 1)
+	order, err := engine.GetOrder(context.Background(), "stop-1")
+	assert.NoError(t, err)
+	assert.Equal(t, types.OrderStatusRejected, order.Status)
+}
+
+func TestEngine_ConditionalOrdersDisabled(t *testing.T) {
+	engine := NewEngine(Config{MaxOrderSize: 100, MinOrderSize: 1}, zap.NewNop(), storage.NewMemoryStorage())
+
+	err := engine.PlaceOrder(context.Background(), &types.Order{
+		ID:               "stop-1",
+		Symbol:           "TEST",
+		Side:             types.OrderSideBuy,
+		Size:             decimal.NewFromInt(10),
+		TriggerPrice:     decimal.NewFromFloat(1.5),
+		TriggerDirection: types.TriggerAbove,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// defaultTurnoverWindow is the trailing period live turnover is measured
+// over when EquityConfig.TurnoverWindow is unset
+const defaultTurnoverWindow = 24 * time.Hour
+
+// turnoverFill is the notional and fee of a fill counted towards turnover
+type turnoverFill struct {
+	at       time.Time
+	notional float64
+	fee      float64
+}
+
+func (e *Engine) turnoverWindow() time.Duration {
+	if e.config.Equity.TurnoverWindow > 0 {
+		return e.config.Equity.TurnoverWindow
+	}
+	return defaultTurnoverWindow
+}
+
+// recordTurnover counts trade towards strategy's turnover and drops fills
+// that have left the window. Callers hold e.mu.
+func (e *Engine) recordTurnover(strategy string, trade *types.Trade) {
+	at := trade.Timestamp
+	if at.IsZero() {
+		at = e.now()
+	}
+	fill := turnoverFill{
+		at:       at,
+		notional: trade.FillPrice().Mul(trade.Filled().Abs()).InexactFloat64(),
+		fee:      trade.Fee.InexactFloat64(),
+	}
+	e.fills[strategy] = append(e.pruneFills(strategy), fill)
+}
+
+// pruneFills drops strategy's fills older than the turnover window and
+// returns the rest. Callers hold e.mu.
+func (e *Engine) pruneFills(strategy string) []turnoverFill {
+	cutoff := e.now().Add(-e.turnoverWindow())
+	fills := e.fills[strategy]
+	i := 0
+	for i < len(fills) && fills[i].at.Before(cutoff) {
+		i++
+	}
+	e.fills[strategy] = fills[i:]
+	return e.fills[strategy]
+}
+
+// GetTurnover returns strategy's turnover and fee drag over the trailing
+// turnover window, measured against its equity snapshots in the window.
+// Without snapshots the strategy's current cash stands in for its equity.
+func (e *Engine) GetTurnover(strategy string) (*types.TurnoverStats, error) {
+	e.mu.Lock()
+	now := e.now()
+	fills := e.pruneFills(strategy)
+	var notional, fees float64
+	for _, fill := range fills {
+		notional += fill.notional
+		fees += fill.fee
+	}
+	cash := e.config.Equity.InitialCash.Add(e.realized[strategy])
+	e.mu.Unlock()
+
+	points, err := e.storage.LoadEquityCurve(strategy, now.Add(-e.turnoverWindow()), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load equity curve: %w", err)
+	}
+	equity := make([]float64, 0, len(points))
+	for _, point := range points {
+		equity = append(equity, point.Equity.InexactFloat64())
+	}
+	if len(equity) == 0 {
+		equity = append(equity, cash.InexactFloat64())
+	}
+	return types.NewTurnoverStats(len(fills), notional, fees, equity), nil
+}
+
+// publishTurnover updates the turnover metrics of every strategy with fills
+// in the window
+func (e *Engine) publishTurnover() {
+	e.mu.RLock()
+	strategies := make([]string, 0, len(e.fills))
+	for strategy := range e.fills {
+		strategies = append(strategies, strategy)
+	}
+	e.mu.RUnlock()
+
+	for _, strategy := range strategies {
+		stats, err := e.GetTurnover(strategy)
+		if err != nil {
+			e.logger.Error("Failed to compute turnover", zap.String("strategy", strategy), zap.Error(err))
+			continue
+		}
+		metrics.StrategyTurnover.WithLabelValues(strategy).Set(stats.Turnover)
+		metrics.StrategyFeeDrag.WithLabelValues(strategy).Set(stats.FeeDrag)
+	}
+}
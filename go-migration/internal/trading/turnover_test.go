@@ -0,0 +1,68 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestEngine_GetTurnover(t *testing.T) {
+	now := time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC)
+	storage := new(MockStorage)
+	storage.On("LoadEquityCurve", "pump.fun", now.Add(-24*time.Hour), now).Return([]*types.EquityPoint{
+		{Strategy: "pump.fun", Equity: decimal.NewFromInt(900), Timestamp: now.Add(-12 * time.Hour)},
+		{Strategy: "pump.fun", Equity: decimal.NewFromInt(1100), Timestamp: now.Add(-time.Hour)},
+	}, nil)
+
+	engine := NewEngine(Config{}, zap.NewNop(), storage)
+	engine.now = func() time.Time { return now }
+
+	fill := func(id string, price, size, fee int64, at time.Time) *types.Trade {
+		return &types.Trade{
+			ID:            id,
+			Symbol:        "TEST",
+			Side:          types.OrderSideBuy,
+			DecisionPrice: decimal.NewFromInt(price),
+			Price:         decimal.NewFromInt(price),
+			Size:          decimal.NewFromInt(size),
+			Fee:           decimal.NewFromInt(fee),
+			Timestamp:     at,
+		}
+	}
+
+	// The first fill falls outside the 24h window
+	require.NoError(t, engine.RecordFill("pump.fun", fill("old", 10, 100, 5, now.Add(-30*time.Hour))))
+	require.NoError(t, engine.RecordFill("pump.fun", fill("a", 10, 150, 3, now.Add(-6*time.Hour))))
+	require.NoError(t, engine.RecordFill("pump.fun", fill("b", 5, 100, 2, now.Add(-time.Hour))))
+
+	stats, err := engine.GetTurnover("pump.fun")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Trades)
+	assert.InDelta(t, 2000.0, stats.Notional, 1e-9)
+	assert.InDelta(t, 5.0, stats.Fees, 1e-9)
+	assert.InDelta(t, 1000.0, stats.AvgEquity, 1e-9)
+	assert.InDelta(t, 2.0, stats.Turnover, 1e-9)
+	assert.InDelta(t, 0.005, stats.FeeDrag, 1e-9)
+}
+
+func TestEngine_GetTurnoverWithoutSnapshots(t *testing.T) {
+	storage := new(MockStorage)
+	storage.On("LoadEquityCurve", "gmgn", mock.Anything, mock.Anything).Return([]*types.EquityPoint{}, nil)
+
+	engine := NewEngine(Config{
+		Equity: EquityConfig{InitialCash: decimal.NewFromInt(500), TurnoverWindow: time.Hour},
+	}, zap.NewNop(), storage)
+
+	stats, err := engine.GetTurnover("gmgn")
+	require.NoError(t, err)
+	assert.Zero(t, stats.Trades)
+	assert.InDelta(t, 500.0, stats.AvgEquity, 1e-9)
+	assert.Zero(t, stats.Turnover)
+}
@@ -0,0 +1,159 @@
+package trading
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/executor"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// VenueLimitConfig caps the notional exposure held at any single venue to
+// bound counterparty risk
+type VenueLimitConfig struct {
+	Enabled  bool                       `yaml:"enabled"`
+	Default  decimal.Decimal            `yaml:"default"`  // Cap for venues without their own, zero for none
+	Limits   map[string]decimal.Decimal `yaml:"limits"`   // Cap per venue
+	Fallback []string                   `yaml:"fallback"` // Venues tried in order when a buy would breach its venue's cap
+}
+
+// limit returns the cap for venue, zero when it is uncapped
+func (c VenueLimitConfig) limit(venue string) decimal.Decimal {
+	if l, ok := c.Limits[venue]; ok {
+		return l
+	}
+	return c.Default
+}
+
+// venueReservations holds the notional of routed buys that have not
+// finished executing, so concurrent signals cannot all pass a venue's cap
+// before any of them shows up in its positions
+type venueReservations struct {
+	pending map[string]decimal.Decimal
+	mu      sync.Mutex
+}
+
+// VenueExposure returns the absolute notional exposure of positions held at
+// venue, marked at their current price or entry when no mark is available
+func (e *Engine) VenueExposure(venue string) decimal.Decimal {
+	e.mu.RLock()
+	exec, ok := e.executors[venue]
+	e.mu.RUnlock()
+
+	if !ok {
+		return decimal.Zero
+	}
+	return venueExposure(venue, exec)
+}
+
+func venueExposure(venue string, exec executor.TradingExecutor) decimal.Decimal {
+	exposure := decimal.Zero
+	for _, pos := range exec.GetPositions() {
+		price := pos.CurrentPrice
+		if price.IsZero() {
+			price = pos.EntryPrice
+		}
+		exposure = exposure.Add(pos.Size.Mul(price).Abs())
+	}
+	metrics.VenueExposure.WithLabelValues(venue).Set(exposure.InexactFloat64())
+	return exposure
+}
+
+// venueExposures returns the exposure of signal's venue and its fallback
+// venues when signal is subject to the venue caps, and nil otherwise.
+// Executors are asked without the engine lock, since one may be blocked on
+// a trade in flight.
+func (e *Engine) venueExposures(signal *types.Signal) map[string]decimal.Decimal {
+	limits := e.config.VenueLimits
+	if !limits.Enabled || signal.Type != types.SignalTypeBuy {
+		return nil
+	}
+
+	e.mu.RLock()
+	executors := e.copyExecutors()
+	e.mu.RUnlock()
+
+	exposures := make(map[string]decimal.Decimal, len(limits.Fallback)+1)
+	for _, venue := range append([]string{signal.Provider}, limits.Fallback...) {
+		if exec, ok := executors[venue]; ok {
+			exposures[venue] = venueExposure(venue, exec)
+		}
+	}
+	return exposures
+}
+
+// routeVenue returns the venue and executor signal should trade on. Buys
+// that would take their venue past its cap, given the venue exposures
+// gathered by venueExposures, go to the first fallback venue with room,
+// and are rejected when there is none. The notional of a routed buy is
+// reserved against its venue's cap until the returned release is called
+// once the execution is over. Callers hold the read lock.
+func (e *Engine) routeVenue(signal *types.Signal, exposures map[string]decimal.Decimal) (string, executor.TradingExecutor, func(), error) {
+	venue := signal.Provider
+	exec, ok := e.executors[venue]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("executor %s not found", venue)
+	}
+
+	limits := e.config.VenueLimits
+	if !limits.Enabled || signal.Type != types.SignalTypeBuy {
+		return venue, exec, func() {}, nil
+	}
+
+	notional := signal.Amount.Mul(signal.Price).Abs()
+	e.reservations.mu.Lock()
+	defer e.reservations.mu.Unlock()
+
+	if e.venueHasRoom(venue, exposures[venue], notional) {
+		return venue, exec, e.reserve(venue, notional), nil
+	}
+
+	for _, fallback := range limits.Fallback {
+		if fallback == venue {
+			continue
+		}
+		if alt, ok := e.executors[fallback]; ok && e.venueHasRoom(fallback, exposures[fallback], notional) {
+			metrics.VenueRejections.WithLabelValues(venue, "rerouted").Inc()
+			e.logger.Info("venue at exposure cap, rerouting",
+				zap.String("symbol", signal.Symbol),
+				zap.String("venue", venue),
+				zap.String("fallback", fallback))
+			return fallback, alt, e.reserve(fallback, notional), nil
+		}
+	}
+
+	metrics.VenueRejections.WithLabelValues(venue, "rejected").Inc()
+	return "", nil, nil, fmt.Errorf("venue %s exposure cap %s reached and no fallback venue has room", venue, limits.limit(venue))
+}
+
+// venueHasRoom reports whether adding notional keeps venue, holding
+// exposure in its positions, within its cap, counting the buys already
+// reserved against it. Callers hold the reservations lock.
+func (e *Engine) venueHasRoom(venue string, exposure, notional decimal.Decimal) bool {
+	limit := e.config.VenueLimits.limit(venue)
+	exposure = exposure.Add(e.reservations.pending[venue])
+	return !limit.IsPositive() || exposure.Add(notional).LessThanOrEqual(limit)
+}
+
+// reserve holds notional against venue's cap and returns the func that
+// releases it. Callers hold the reservations lock.
+func (e *Engine) reserve(venue string, notional decimal.Decimal) func() {
+	e.reservations.pending[venue] = e.reservations.pending[venue].Add(notional)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.reservations.mu.Lock()
+			defer e.reservations.mu.Unlock()
+			left := e.reservations.pending[venue].Sub(notional)
+			if left.IsPositive() {
+				e.reservations.pending[venue] = left
+			} else {
+				delete(e.reservations.pending, venue)
+			}
+		})
+	}
+}
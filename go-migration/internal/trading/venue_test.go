@@ -0,0 +1,120 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+// holdingExecutor records signals and reports fixed positions
+type holdingExecutor struct {
+	recordingExecutor
+	positions map[string]*types.Position
+}
+
+func (h *holdingExecutor) GetPositions() map[string]*types.Position { return h.positions }
+
+func TestEngine_VenueExposureCap(t *testing.T) {
+	engine := NewEngine(Config{
+		VenueLimits: VenueLimitConfig{
+			Enabled:  true,
+			Default:  decimal.NewFromInt(1000),
+			Limits:   map[string]decimal.Decimal{"pump.fun": decimal.NewFromInt(500)},
+			Fallback: []string{"gmgn"},
+		},
+	}, zap.NewNop(), new(MockStorage))
+
+	// pump.fun holds 450 of its 500 cap
+	full := &holdingExecutor{positions: map[string]*types.Position{
+		"PEPE": {Symbol: "PEPE", Size: decimal.NewFromInt(45), EntryPrice: decimal.NewFromInt(12), CurrentPrice: decimal.NewFromInt(10)},
+	}}
+	other := &holdingExecutor{}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", full))
+	require.NoError(t, engine.RegisterExecutor("gmgn", other))
+
+	ctx := context.Background()
+	buy := func(amount int64) *types.Signal {
+		return &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "pump.fun",
+			Amount: decimal.NewFromInt(amount), Price: decimal.NewFromInt(10)}
+	}
+
+	// A buy within the remaining room stays on its venue
+	assert.NoError(t, engine.ProcessSignal(ctx, buy(5)))
+	assert.Len(t, full.signals, 1)
+	assert.Equal(t, 450.0, testutil.ToFloat64(metrics.VenueExposure.WithLabelValues("pump.fun")))
+
+	// One that would breach the cap is routed to the fallback venue
+	signal := buy(10)
+	assert.NoError(t, engine.ProcessSignal(ctx, signal))
+	assert.Len(t, full.signals, 1)
+	assert.Len(t, other.signals, 1)
+	assert.Equal(t, "gmgn", other.signals[0].Venue)
+	assert.Equal(t, "pump.fun", other.signals[0].Provider)
+	assert.Empty(t, signal.Venue)
+
+	// Sells reduce exposure and are never capped
+	assert.NoError(t, engine.ProcessSignal(ctx, &types.Signal{Symbol: "PEPE", Type: types.SignalTypeSell, Provider: "pump.fun",
+		Amount: decimal.NewFromInt(45), Price: decimal.NewFromInt(10)}))
+	assert.Len(t, full.signals, 2)
+
+	// With the fallback full too the buy is rejected
+	other.positions = map[string]*types.Position{
+		"WIF": {Symbol: "WIF", Size: decimal.NewFromInt(100), EntryPrice: decimal.NewFromInt(10)},
+	}
+	err := engine.ProcessSignal(ctx, buy(10))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exposure cap")
+	assert.Len(t, other.signals, 1)
+	assert.Equal(t, 1000.0, testutil.ToFloat64(metrics.VenueExposure.WithLabelValues("gmgn")))
+}
+
+func TestEngine_VenueCapReservesInFlightBuys(t *testing.T) {
+	engine := NewEngine(Config{
+		VenueLimits: VenueLimitConfig{Enabled: true, Default: decimal.NewFromInt(100)},
+	}, zap.NewNop(), new(MockStorage))
+	exec := &blockingExecutor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	buy := func() *types.Signal {
+		return &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "pump.fun",
+			Amount: decimal.NewFromInt(6), Price: decimal.NewFromInt(10)}
+	}
+
+	// The first buy is still executing, so it is not yet in the positions
+	errs := make(chan error, 1)
+	go func() { errs <- engine.ProcessSignal(context.Background(), buy()) }()
+	<-exec.started
+
+	// Its reservation keeps a second buy from passing the cap meanwhile
+	err := engine.ProcessSignal(context.Background(), buy())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exposure cap")
+
+	close(exec.release)
+	assert.NoError(t, <-errs)
+	assert.Empty(t, engine.reservations.pending)
+}
+
+func TestEngine_VenueCapQueriesExecutorsUnlocked(t *testing.T) {
+	engine := NewEngine(Config{
+		VenueLimits: VenueLimitConfig{Enabled: true, Default: decimal.NewFromInt(500)},
+	}, zap.NewNop(), new(MockStorage))
+	exec := &unlockedExecutor{engine: engine, bookExecutor: bookExecutor{positions: map[string]*types.Position{
+		"PEPE": {Symbol: "PEPE", Size: decimal.NewFromInt(45), EntryPrice: decimal.NewFromInt(10)},
+	}}}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", exec))
+
+	err := engine.ProcessSignal(context.Background(), &types.Signal{Symbol: "BONK", Type: types.SignalTypeBuy, Provider: "pump.fun",
+		Amount: decimal.NewFromInt(10), Price: decimal.NewFromInt(10)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exposure cap")
+	assert.False(t, exec.locked)
+}
@@ -0,0 +1,70 @@
+package trading
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// WatchdogConfig restarts executor processing loops that stop making
+// progress
+type WatchdogConfig struct {
+	Interval  time.Duration `yaml:"interval"`  // How often loops are checked, zero disables the watchdog
+	Threshold time.Duration `yaml:"threshold"` // Heartbeat age after which a loop counts as stalled
+}
+
+// ExecutorLoop is an executor processing loop the watchdog can supervise
+type ExecutorLoop interface {
+	LastHeartbeat() time.Time
+	RestartLoop() error
+}
+
+// WatchExecutor supervises loop under name. Registered executors that
+// implement ExecutorLoop are watched automatically.
+func (e *Engine) WatchExecutor(name string, loop ExecutorLoop) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.loops[name] = loop
+}
+
+// checkExecutors reports the liveness of every watched loop and restarts
+// those whose heartbeat is older than the threshold
+func (e *Engine) checkExecutors() {
+	e.mu.RLock()
+	loops := make(map[string]ExecutorLoop, len(e.loops))
+	for name, loop := range e.loops {
+		loops[name] = loop
+	}
+	for name, exec := range e.executors {
+		if loop, ok := exec.(ExecutorLoop); ok {
+			loops[name] = loop
+		}
+	}
+	e.mu.RUnlock()
+
+	now := e.now()
+	for name, loop := range loops {
+		age := now.Sub(loop.LastHeartbeat())
+		if age <= e.config.Watchdog.Threshold {
+			metrics.ExecutorAlive.WithLabelValues(name).Set(1)
+			continue
+		}
+
+		metrics.ExecutorAlive.WithLabelValues(name).Set(0)
+		e.logger.Error("CRITICAL: executor loop stalled, restarting",
+			zap.String("executor", name),
+			zap.Duration("since_heartbeat", age),
+			zap.Duration("threshold", e.config.Watchdog.Threshold))
+
+		if err := loop.RestartLoop(); err != nil {
+			metrics.ExecutorRestarts.WithLabelValues(name, "failed").Inc()
+			e.logger.Error("Failed to restart executor loop",
+				zap.String("executor", name),
+				zap.Error(err))
+			continue
+		}
+		metrics.ExecutorRestarts.WithLabelValues(name, "restarted").Inc()
+	}
+}
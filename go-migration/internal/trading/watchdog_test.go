@@ -0,0 +1,62 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/metrics"
+)
+
+// fakeLoop reports a fixed heartbeat until restarted
+type fakeLoop struct {
+	heartbeat time.Time
+	restarts  int
+	restarted time.Time
+}
+
+func (f *fakeLoop) LastHeartbeat() time.Time { return f.heartbeat }
+
+func (f *fakeLoop) RestartLoop() error {
+	f.restarts++
+	f.heartbeat = f.restarted
+	return nil
+}
+
+// loopingExecutor is a registered executor with a supervised loop
+type loopingExecutor struct {
+	recordingExecutor
+	fakeLoop
+}
+
+func TestEngine_WatchdogRestartsStalledLoop(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Config{
+		Watchdog: WatchdogConfig{Interval: time.Second, Threshold: 30 * time.Second},
+	}, zap.NewNop(), new(MockStorage))
+	engine.now = func() time.Time { return now }
+
+	// One loop hung a minute ago, the other beat just now
+	stalled := &loopingExecutor{fakeLoop: fakeLoop{heartbeat: now.Add(-time.Minute), restarted: now}}
+	healthy := &fakeLoop{heartbeat: now.Add(-time.Second)}
+	require.NoError(t, engine.RegisterExecutor("pump.fun", stalled))
+	engine.WatchExecutor("realtime", healthy)
+
+	restarts := testutil.ToFloat64(metrics.ExecutorRestarts.WithLabelValues("pump.fun", "restarted"))
+	engine.checkExecutors()
+
+	assert.Equal(t, 1, stalled.restarts)
+	assert.Zero(t, healthy.restarts)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ExecutorAlive.WithLabelValues("pump.fun")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ExecutorAlive.WithLabelValues("realtime")))
+	assert.Equal(t, restarts+1, testutil.ToFloat64(metrics.ExecutorRestarts.WithLabelValues("pump.fun", "restarted")))
+
+	// The restarted loop beats again and is reported alive
+	engine.checkExecutors()
+	assert.Equal(t, 1, stalled.restarts)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ExecutorAlive.WithLabelValues("pump.fun")))
+}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"fmt"
 	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -16,3 +18,32 @@ type BondingCurve struct {
 	MaxBuySize   decimal.Decimal `json:"max_buy_size"`
 	MinBuySize   decimal.Decimal `json:"min_buy_size"`
 }
+
+// SpotPrice returns the curve's current price, derived from the base price,
+// slope and supply when the API did not report one
+func (c *BondingCurve) SpotPrice() decimal.Decimal {
+	if c.CurrentPrice.IsPositive() {
+		return c.CurrentPrice
+	}
+	return c.BasePrice.Add(c.Slope.Mul(decimal.NewFromInt(c.Supply)))
+}
+
+// AverageFillPrice returns the expected average price of trading amount
+// tokens along the linear curve p(x) = base + slope*x. A buy walks the
+// price up and fills at p0 + slope*amount/2, a sell walks it down.
+func (c *BondingCurve) AverageFillPrice(side SignalType, amount decimal.Decimal) (decimal.Decimal, error) {
+	spot := c.SpotPrice()
+	if !spot.IsPositive() {
+		return decimal.Zero, fmt.Errorf("curve price must be positive")
+	}
+
+	move := c.Slope.Mul(amount.Abs()).Div(decimal.NewFromInt(2))
+	if side == SignalTypeSell {
+		move = move.Neg()
+	}
+	price := spot.Add(move)
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("selling %s exhausts the curve", amount)
+	}
+	return price, nil
+}
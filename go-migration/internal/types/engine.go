@@ -26,6 +26,7 @@ const (
 type OrderStatus string
 
 const (
+	OrderStatusPending  OrderStatus = "pending" // Conditional order waiting for its trigger
 	OrderStatusNew      OrderStatus = "new"
 	OrderStatusPartial  OrderStatus = "partial"
 	OrderStatusFilled   OrderStatus = "filled"
@@ -0,0 +1,16 @@
+package types
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EquityPoint is a snapshot of a strategy's equity at a point in time
+type EquityPoint struct {
+	Strategy      string          `json:"strategy" bson:"strategy"`
+	Cash          decimal.Decimal `json:"cash" bson:"cash"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl" bson:"unrealized_pnl"`
+	Equity        decimal.Decimal `json:"equity" bson:"equity"`
+	Timestamp     time.Time       `json:"timestamp" bson:"timestamp"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// LiquidityRole identifies whether a fill added or removed liquidity
+type LiquidityRole string
+
+const (
+	LiquidityTaker LiquidityRole = "taker"
+	LiquidityMaker LiquidityRole = "maker"
+)
+
+// FeeSchedule represents venue fees charged on taker fills and rebates
+// paid on maker fills
+type FeeSchedule struct {
+	TakerFee    decimal.Decimal `yaml:"taker_fee" json:"taker_fee"`
+	MakerRebate decimal.Decimal `yaml:"maker_rebate" json:"maker_rebate"`
+}
+
+// Commission returns the signed commission for a fill of the given notional.
+// Maker fills return a negative commission, i.e. a rebate credited to the account.
+func (f FeeSchedule) Commission(role LiquidityRole, notional decimal.Decimal) decimal.Decimal {
+	if role == LiquidityMaker {
+		return notional.Abs().Mul(f.MakerRebate).Neg()
+	}
+	return notional.Abs().Mul(f.TakerFee)
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FundingProvider supplies periodic funding rates for perpetual contracts
+type FundingProvider interface {
+	// FundingRate returns the funding rate for symbol at the funding time at
+	FundingRate(ctx context.Context, symbol string, at time.Time) (decimal.Decimal, error)
+}
+
+// FundingConfig enables funding accounting for the listed symbols
+type FundingConfig struct {
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	Symbols  []string      `yaml:"symbols" json:"symbols"`
+}
+
+// Applies reports whether funding is accounted for symbol
+func (c FundingConfig) Applies(symbol string) bool {
+	if c.Interval <= 0 {
+		return false
+	}
+	for _, s := range c.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// FundingPayment returns the funding paid by a position of the given notional.
+// With a positive rate longs pay shorts, so a positive result is a cost to
+// the position and a negative result is funding received.
+func FundingPayment(long bool, notional, rate decimal.Decimal) decimal.Decimal {
+	payment := notional.Abs().Mul(rate)
+	if !long {
+		return payment.Neg()
+	}
+	return payment
+}
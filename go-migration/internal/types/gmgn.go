@@ -1,6 +1,8 @@
 package types
 
 import (
+	"context"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -8,6 +10,7 @@ type Quote struct {
 	TokenIn     string          `json:"token_in"`
 	TokenOut    string          `json:"token_out"`
 	Amount      decimal.Decimal `json:"amount"`
+	OutAmount   decimal.Decimal `json:"out_amount"`
 	RawTx       string          `json:"raw_tx"`
 	BlockHeight int             `json:"block_height"`
 	Blockhash   string          `json:"blockhash"`
@@ -25,6 +28,42 @@ type TransactionStatus struct {
 	Expired bool `json:"expired"`
 }
 
+// TxSigner signs a quoted unsigned transaction with the trading wallet and
+// returns the signed transaction in the same encoding
+type TxSigner func(ctx context.Context, rawTx string) (string, error)
+
+// SwapLeg is one hop of a multi-leg route. A zero Amount swaps the quoted
+// output of the previous leg.
+type SwapLeg struct {
+	TokenIn  string          `json:"token_in"`
+	TokenOut string          `json:"token_out"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// LegResult is the quote and submission of one leg
+type LegResult struct {
+	Leg   SwapLeg            `json:"leg"`
+	Quote *Quote             `json:"quote"`
+	Tx    *TransactionResult `json:"tx,omitempty"`
+}
+
+type MultiLegStatus string
+
+const (
+	MultiLegConfirmed  MultiLegStatus = "confirmed"
+	MultiLegPending    MultiLegStatus = "pending"
+	MultiLegFailed     MultiLegStatus = "failed"
+	MultiLegRolledBack MultiLegStatus = "rolled_back"
+)
+
+// MultiLegResult reports a multi-leg swap. BundleID is set when the legs
+// were submitted as one atomic bundle.
+type MultiLegResult struct {
+	BundleID string         `json:"bundle_id,omitempty"`
+	Legs     []LegResult    `json:"legs"`
+	Status   MultiLegStatus `json:"status"`
+}
+
 type GMGNProvider interface {
 	GetQuote(ctx context.Context, tokenIn, tokenOut string, amount decimal.Decimal) (*Quote, error)
 	SubmitTransaction(ctx context.Context, signedTx string) (*TransactionResult, error)
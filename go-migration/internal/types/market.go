@@ -21,15 +21,31 @@ type PriceLevel struct {
 	Extra     map[string]interface{} `json:"extra,omitempty"`
 }
 
-// PriceHistory maintains a circular buffer of price levels
+// PriceHistory maintains a circular buffer of price levels. With retention
+// configured, ticks older than RawAge are compacted into candles instead of
+// being dropped, so long-running symbols keep a downsampled tail.
 type PriceHistory struct {
 	Symbol    string
 	Levels    []*PriceLevel
 	Size      int
 	LastIndex int
+	Candles   []*PriceLevel // Compacted candles, oldest first
+	retention RetentionConfig
 	mu        sync.RWMutex
 }
 
+// RetentionConfig configures tiered retention of a PriceHistory
+type RetentionConfig struct {
+	RawAge         time.Duration `json:"raw_age"`         // Ticks newer than this are kept at full resolution
+	CandleInterval time.Duration `json:"candle_interval"` // Width of the candles older ticks are compacted into
+	MaxCandles     int           `json:"max_candles"`     // Candles retained, defaults to the raw history size
+}
+
+// Enabled reports whether tiered retention is configured
+func (c RetentionConfig) Enabled() bool {
+	return c.RawAge > 0 && c.CandleInterval > 0
+}
+
 // NewPriceHistory creates a new price history
 func NewPriceHistory(size int) *PriceHistory {
 	return &PriceHistory{
@@ -38,15 +54,88 @@ func NewPriceHistory(size int) *PriceHistory {
 	}
 }
 
+// SetRetention enables tiered retention
+func (h *PriceHistory) SetRetention(config RetentionConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if config.MaxCandles <= 0 {
+		config.MaxCandles = h.Size
+	}
+	h.retention = config
+}
+
 // Add adds a new price level to the history
 func (h *PriceHistory) Add(level *PriceLevel) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.LastIndex = (h.LastIndex + 1) % h.Size
+	if h.retention.Enabled() {
+		h.compact(level.Timestamp.Add(-h.retention.RawAge))
+		// Fold the tick about to be overwritten rather than losing it
+		if evicted := h.Levels[h.LastIndex]; evicted != nil {
+			h.fold(evicted)
+		}
+	}
 	h.Levels[h.LastIndex] = level
 }
 
+// compact moves raw ticks older than cutoff into candles, oldest first
+func (h *PriceHistory) compact(cutoff time.Time) {
+	for i := 0; i < h.Size; i++ {
+		index := (h.LastIndex + i) % h.Size
+		level := h.Levels[index]
+		if level == nil {
+			continue
+		}
+		if !level.Timestamp.Before(cutoff) {
+			return
+		}
+		h.fold(level)
+		h.Levels[index] = nil
+	}
+}
+
+// fold merges a raw tick into the candle covering its timestamp. Candles
+// carry the close in Price and the open, high and low in Extra.
+func (h *PriceHistory) fold(level *PriceLevel) {
+	bucket := level.Timestamp.Truncate(h.retention.CandleInterval)
+
+	if n := len(h.Candles); n > 0 && h.Candles[n-1].Timestamp.Equal(bucket) {
+		candle := h.Candles[n-1]
+		volume := candle.Volume + level.Volume
+		if volume > 0 {
+			candle.VWAP = (candle.VWAP*candle.Volume + level.Price*level.Volume) / volume
+		}
+		candle.Volume = volume
+		candle.Price = level.Price
+		if level.Price > candle.Extra["high"].(float64) {
+			candle.Extra["high"] = level.Price
+		}
+		if level.Price < candle.Extra["low"].(float64) {
+			candle.Extra["low"] = level.Price
+		}
+		return
+	}
+
+	h.Candles = append(h.Candles, &PriceLevel{
+		Symbol:    level.Symbol,
+		Price:     level.Price,
+		Volume:    level.Volume,
+		VWAP:      level.Price,
+		Timestamp: bucket,
+		Extra: map[string]interface{}{
+			"open": level.Price,
+			"high": level.Price,
+			"low":  level.Price,
+		},
+	})
+	if len(h.Candles) > h.retention.MaxCandles {
+		h.Candles = h.Candles[len(h.Candles)-h.retention.MaxCandles:]
+	}
+}
+
 // Last returns the most recent price level
 func (h *PriceHistory) Last() *PriceLevel {
 	h.mu.RLock()
@@ -66,12 +155,12 @@ func (h *PriceHistory) Get(index int) *PriceLevel {
 	return h.Levels[index]
 }
 
-// Len returns the number of valid price levels
+// Len returns the number of valid price levels, compacted candles included
 func (h *PriceHistory) Len() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	count := 0
+	count := len(h.Candles)
 	for _, level := range h.Levels {
 		if level != nil {
 			count++
@@ -80,7 +169,8 @@ func (h *PriceHistory) Len() int {
 	return count
 }
 
-// Range iterates over price levels from newest to oldest
+// Range iterates over price levels from newest to oldest. Raw ticks are
+// visited first, followed by compacted candles.
 func (h *PriceHistory) Range(fn func(level *PriceLevel) bool) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -92,7 +182,13 @@ func (h *PriceHistory) Range(fn func(level *PriceLevel) bool) {
 			continue
 		}
 		if !fn(level) {
-			break
+			return
+		}
+	}
+
+	for i := len(h.Candles) - 1; i >= 0; i-- {
+		if !fn(h.Candles[i]) {
+			return
 		}
 	}
 }
@@ -106,6 +202,7 @@ func (h *PriceHistory) Clear() {
 		h.Levels[i] = nil
 	}
 	h.LastIndex = 0
+	h.Candles = nil
 }
 
 // LegacyMarketSignal represents an older version of market trading signal
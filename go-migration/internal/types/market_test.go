@@ -0,0 +1,73 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceHistory_TieredRetention(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	history := NewPriceHistory(100)
+	history.SetRetention(RetentionConfig{
+		RawAge:         time.Minute,
+		CandleInterval: 5 * time.Minute,
+		MaxCandles:     12,
+	})
+
+	// Ten hours of one tick per second
+	n := 10 * 60 * 60
+	for i := 0; i < n; i++ {
+		history.Add(&PriceLevel{
+			Price:     float64(i),
+			Volume:    1,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	// Memory is bounded by the raw buffer plus the candle cap
+	assert.LessOrEqual(t, history.Len(), 100+12)
+	assert.Len(t, history.Candles, 12)
+
+	// The last minute, inclusive of its boundary, is kept tick by tick
+	var raw []*PriceLevel
+	history.Range(func(level *PriceLevel) bool {
+		if level.Extra != nil {
+			return false
+		}
+		raw = append(raw, level)
+		return true
+	})
+	assert.Len(t, raw, 61)
+	for i, level := range raw {
+		assert.Equal(t, float64(n-1-i), level.Price)
+	}
+
+	// Older data is visited as 5 minute candles, newest first
+	var candles []*PriceLevel
+	history.Range(func(level *PriceLevel) bool {
+		if level.Extra != nil {
+			candles = append(candles, level)
+		}
+		return true
+	})
+	assert.Len(t, candles, 12)
+	for i := 1; i < len(candles); i++ {
+		assert.Equal(t, 5*time.Minute, candles[i-1].Timestamp.Sub(candles[i].Timestamp))
+	}
+
+	full := candles[1]
+	assert.Equal(t, 300.0, full.Volume)
+	assert.Equal(t, full.Extra["low"].(float64)+299, full.Extra["high"].(float64))
+	assert.Equal(t, full.Extra["high"], full.Price)
+}
+
+func TestPriceHistory_NoRetentionDropsOldTicks(t *testing.T) {
+	history := NewPriceHistory(10)
+	for i := 0; i < 50; i++ {
+		history.Add(&PriceLevel{Price: float64(i), Timestamp: time.Unix(int64(i), 0)})
+	}
+	assert.Equal(t, 10, history.Len())
+	assert.Empty(t, history.Candles)
+}
@@ -16,6 +16,41 @@ type Order struct {
 	FilledSize decimal.Decimal `json:"filled_size" bson:"filled_size"`
 	Status    OrderStatus     `json:"status" bson:"status"`
 	Provider  string          `json:"provider" bson:"provider"`
+	// ClientTag is an optional client order id forwarded to venues that
+	// accept one, and echoed back so venue records can be reconciled
+	ClientTag string          `json:"client_tag,omitempty" bson:"client_tag,omitempty"`
+	// ClientRequestID is an idempotency key: resubmitting an order with the
+	// same key within the engine's dedup window returns the original order
+	ClientRequestID string `json:"client_request_id,omitempty" bson:"client_request_id,omitempty"`
+	// TriggerPrice makes the order conditional: it is held inactive until
+	// the market crosses the trigger in TriggerDirection
+	TriggerPrice     decimal.Decimal  `json:"trigger_price,omitempty" bson:"trigger_price,omitempty"`
+	TriggerDirection TriggerDirection `json:"trigger_direction,omitempty" bson:"trigger_direction,omitempty"`
 	CreatedAt time.Time       `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time       `json:"updated_at" bson:"updated_at"`
 }
+
+// TriggerDirection is the side from which the market must cross a
+// conditional order's trigger price
+type TriggerDirection string
+
+const (
+	TriggerAbove TriggerDirection = "above" // Activate once price rises to the trigger, e.g. buy-stop
+	TriggerBelow TriggerDirection = "below" // Activate once price falls to the trigger, e.g. sell-stop
+)
+
+// IsConditional reports whether the order waits for a trigger
+func (o *Order) IsConditional() bool {
+	return o.TriggerPrice.IsPositive()
+}
+
+// Triggered reports whether price crosses the order's trigger
+func (o *Order) Triggered(price decimal.Decimal) bool {
+	switch o.TriggerDirection {
+	case TriggerAbove:
+		return price.GreaterThanOrEqual(o.TriggerPrice)
+	case TriggerBelow:
+		return price.LessThanOrEqual(o.TriggerPrice)
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"fmt"
+	"sort"
 	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -17,4 +20,100 @@ type OrderBook struct {
 	Bids       []OrderBookLevel `json:"bids"`
 	Asks       []OrderBookLevel `json:"asks"`
 	UpdateTime time.Time        `json:"update_time"`
+	MidPrice   decimal.Decimal  `json:"mid_price"` // Set by Normalize, zero while a side is empty
+	Spread     decimal.Decimal  `json:"spread"`    // Best ask minus best bid, set by Normalize
+}
+
+// AggregateOrderBooks merges the books of several venues into one
+// normalized book for symbol, keeping at most maxLevels price levels a
+// side. The result carries the latest update time of the inputs.
+func AggregateOrderBooks(symbol string, maxLevels int, books ...*OrderBook) *OrderBook {
+	merged := &OrderBook{Symbol: symbol}
+	for _, book := range books {
+		if book == nil {
+			continue
+		}
+		merged.Bids = append(merged.Bids, book.Bids...)
+		merged.Asks = append(merged.Asks, book.Asks...)
+		if book.UpdateTime.After(merged.UpdateTime) {
+			merged.UpdateTime = book.UpdateTime
+		}
+	}
+	merged.Normalize(maxLevels)
+	return merged
+}
+
+// Normalize sums levels quoted at the same price, sorts bids from the best
+// (highest) and asks from the best (lowest), keeps the top maxLevels of each
+// side and sets MidPrice and Spread. A maxLevels of zero keeps every level.
+func (b *OrderBook) Normalize(maxLevels int) {
+	b.Bids = mergeLevels(b.Bids, maxLevels, func(x, y decimal.Decimal) bool { return x.GreaterThan(y) })
+	b.Asks = mergeLevels(b.Asks, maxLevels, func(x, y decimal.Decimal) bool { return x.LessThan(y) })
+
+	b.MidPrice, b.Spread = decimal.Zero, decimal.Zero
+	if len(b.Bids) > 0 && len(b.Asks) > 0 {
+		bid, ask := b.Bids[0].Price, b.Asks[0].Price
+		b.MidPrice = bid.Add(ask).Div(decimal.NewFromInt(2))
+		b.Spread = ask.Sub(bid)
+	}
+}
+
+// EstimateVWAP walks the asks for a buy or the bids for a sell, best level
+// first as left by Normalize, until size is filled. It returns the volume
+// weighted average price and the filled size, which is less than size when
+// the book runs out. An error is returned only when nothing can be filled.
+func (b *OrderBook) EstimateVWAP(isBuy bool, size float64) (vwap float64, filled float64, err error) {
+	if size <= 0 {
+		return 0, 0, fmt.Errorf("size must be positive, got %v", size)
+	}
+	levels := b.Bids
+	if isBuy {
+		levels = b.Asks
+	}
+
+	var notional float64
+	for _, level := range levels {
+		if filled >= size {
+			break
+		}
+		amount := level.Amount.InexactFloat64()
+		if amount <= 0 {
+			continue
+		}
+		take := size - filled
+		if amount < take {
+			take = amount
+		}
+		filled += take
+		notional += take * level.Price.InexactFloat64()
+	}
+	if filled == 0 {
+		return 0, 0, fmt.Errorf("no liquidity in %s order book", b.Symbol)
+	}
+	return notional / filled, filled, nil
+}
+
+// mergeLevels sums same-price levels, drops empty ones and returns the
+// first maxLevels in better order
+func mergeLevels(levels []OrderBookLevel, maxLevels int, better func(x, y decimal.Decimal) bool) []OrderBookLevel {
+	merged := make([]OrderBookLevel, 0, len(levels))
+	index := make(map[string]int, len(levels))
+	for _, level := range levels {
+		if !level.Amount.IsPositive() {
+			continue
+		}
+		key := level.Price.String()
+		if i, ok := index[key]; ok {
+			merged[i].Amount = merged[i].Amount.Add(level.Amount)
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, level)
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return better(merged[i].Price, merged[j].Price) })
+	if maxLevels > 0 && len(merged) > maxLevels {
+		merged = merged[:maxLevels]
+	}
+	return merged
 }
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bookLevel(price, amount string) OrderBookLevel {
+	return OrderBookLevel{Price: decimal.RequireFromString(price), Amount: decimal.RequireFromString(amount)}
+}
+
+func TestAggregateOrderBooks_MergesAndTruncates(t *testing.T) {
+	early := time.Unix(1700000000, 0)
+	raydium := &OrderBook{
+		Bids:       []OrderBookLevel{bookLevel("99", "1"), bookLevel("100", "2"), bookLevel("98", "5")},
+		Asks:       []OrderBookLevel{bookLevel("101", "1"), bookLevel("103", "4")},
+		UpdateTime: early,
+	}
+	orca := &OrderBook{
+		Bids:       []OrderBookLevel{bookLevel("100.0", "3"), bookLevel("97", "1")},
+		Asks:       []OrderBookLevel{bookLevel("102", "2"), bookLevel("101", "0.5"), bookLevel("104", "0")},
+		UpdateTime: early.Add(time.Second),
+	}
+
+	book := AggregateOrderBooks("SOL", 2, raydium, nil, orca)
+	assert.Equal(t, "SOL", book.Symbol)
+	assert.Equal(t, early.Add(time.Second), book.UpdateTime)
+
+	require.Len(t, book.Bids, 2)
+	assert.True(t, book.Bids[0].Price.Equal(decimal.NewFromInt(100)))
+	assert.True(t, book.Bids[0].Amount.Equal(decimal.NewFromInt(5)))
+	assert.True(t, book.Bids[1].Price.Equal(decimal.NewFromInt(99)))
+
+	require.Len(t, book.Asks, 2)
+	assert.True(t, book.Asks[0].Price.Equal(decimal.NewFromInt(101)))
+	assert.True(t, book.Asks[0].Amount.Equal(decimal.NewFromFloat(1.5)))
+	assert.True(t, book.Asks[1].Price.Equal(decimal.NewFromInt(102)))
+
+	assert.True(t, book.MidPrice.Equal(decimal.NewFromFloat(100.5)))
+	assert.True(t, book.Spread.Equal(decimal.NewFromInt(1)))
+}
+
+func TestOrderBook_NormalizeUnboundedAndOneSided(t *testing.T) {
+	book := &OrderBook{Bids: []OrderBookLevel{bookLevel("1", "1"), bookLevel("3", "1"), bookLevel("2", "1")}}
+	book.Normalize(0)
+
+	require.Len(t, book.Bids, 3)
+	assert.True(t, book.Bids[2].Price.Equal(decimal.NewFromInt(1)))
+	// Without asks there is no spread to report
+	assert.True(t, book.MidPrice.IsZero())
+	assert.True(t, book.Spread.IsZero())
+}
+
+func TestOrderBook_EstimateVWAP(t *testing.T) {
+	book := AggregateOrderBooks("SOL", 0,
+		&OrderBook{
+			Bids: []OrderBookLevel{bookLevel("99", "2"), bookLevel("98", "3")},
+			Asks: []OrderBookLevel{bookLevel("101", "1")},
+		},
+		&OrderBook{Asks: []OrderBookLevel{bookLevel("102", "2")}},
+	)
+
+	vwap, filled, err := book.EstimateVWAP(true, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, filled)
+	assert.InDelta(t, 101.5, vwap, 1e-9)
+
+	vwap, filled, err = book.EstimateVWAP(false, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, filled)
+	assert.InDelta(t, (99*2+98*2)/4.0, vwap, 1e-9)
+
+	// The book only holds three units of asks
+	vwap, filled, err = book.EstimateVWAP(true, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, filled)
+	assert.InDelta(t, (101+102*2)/3.0, vwap, 1e-9)
+
+	_, _, err = (&OrderBook{Symbol: "SOL"}).EstimateVWAP(true, 1)
+	assert.ErrorContains(t, err, "no liquidity")
+	_, _, err = book.EstimateVWAP(true, 0)
+	assert.Error(t, err)
+}
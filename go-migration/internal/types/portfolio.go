@@ -0,0 +1,100 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PortfolioConfig sets the currency portfolio summaries are reported in
+type PortfolioConfig struct {
+	Base  string                     `yaml:"base" json:"base"`
+	Rates map[string]decimal.Decimal `yaml:"rates" json:"rates"` // Value of one unit of each quote asset in Base
+	// PublishDelta exports per-position and portfolio delta as metrics
+	// whenever a summary is computed
+	PublishDelta bool `yaml:"publish_delta" json:"publish_delta"`
+}
+
+// Portfolio is a set of open positions valued in a common base currency
+type Portfolio struct {
+	Positions []*Position
+	Config    PortfolioConfig
+}
+
+// PortfolioSummary aggregates a portfolio's positions in the base currency.
+// Average prices are weighted by each position's exposure, its absolute
+// notional at the current mark.
+type PortfolioSummary struct {
+	Base          string          `json:"base"`
+	Positions     int             `json:"positions"`
+	TotalNotional decimal.Decimal `json:"total_notional"`
+	AvgEntryPrice decimal.Decimal `json:"avg_entry_price"`
+	AvgMarkPrice  decimal.Decimal `json:"avg_mark_price"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	// Delta is the signed directional notional of the portfolio, long
+	// exposure less short, with each symbol's contribution in Deltas
+	Delta  decimal.Decimal            `json:"delta"`
+	Deltas map[string]decimal.Decimal `json:"deltas"`
+}
+
+// Delta returns the position's directional notional sensitivity, its
+// signed size times the current mark, or the entry price when unmarked.
+// Negative sizes are short positions. For spot this is the position's
+// notional; derivatives will scale it by their own delta.
+func (p *Position) Delta() decimal.Decimal {
+	mark := p.CurrentPrice
+	if mark.IsZero() {
+		mark = p.EntryPrice
+	}
+	return p.Size.Mul(mark)
+}
+
+// Summary computes the exposure-weighted average entry and mark, total
+// notional and aggregate unrealized PnL of the portfolio
+func (p *Portfolio) Summary() PortfolioSummary {
+	summary := PortfolioSummary{
+		Base:      p.Config.Base,
+		Positions: len(p.Positions),
+		Deltas:    make(map[string]decimal.Decimal, len(p.Positions)),
+	}
+
+	var weightedEntry, weightedMark decimal.Decimal
+	for _, pos := range p.Positions {
+		rate := p.rate(pos.Symbol)
+		entry := pos.EntryPrice.Mul(rate)
+		mark := pos.CurrentPrice.Mul(rate)
+		if mark.IsZero() {
+			mark = entry
+		}
+
+		exposure := pos.Size.Abs().Mul(mark)
+		summary.TotalNotional = summary.TotalNotional.Add(exposure)
+		summary.UnrealizedPnL = summary.UnrealizedPnL.Add(pos.UnrealizedPnL.Mul(rate))
+		weightedEntry = weightedEntry.Add(entry.Mul(exposure))
+		weightedMark = weightedMark.Add(mark.Mul(exposure))
+
+		delta := pos.Delta().Mul(rate)
+		summary.Deltas[pos.Symbol] = summary.Deltas[pos.Symbol].Add(delta)
+		summary.Delta = summary.Delta.Add(delta)
+	}
+
+	if summary.TotalNotional.IsPositive() {
+		summary.AvgEntryPrice = weightedEntry.Div(summary.TotalNotional)
+		summary.AvgMarkPrice = weightedMark.Div(summary.TotalNotional)
+	}
+	return summary
+}
+
+// rate returns the conversion into the base currency for the asset symbol
+// is quoted in. Symbols without a quote suffix, or quoted in the base
+// itself, are already in the base currency.
+func (p *Portfolio) rate(symbol string) decimal.Decimal {
+	i := strings.LastIndex(symbol, "/")
+	if i < 0 || symbol[i+1:] == p.Config.Base {
+		return decimal.NewFromInt(1)
+	}
+	if rate, ok := p.Config.Rates[symbol[i+1:]]; ok {
+		return rate
+	}
+	return decimal.NewFromInt(1)
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortfolio_Summary(t *testing.T) {
+	portfolio := &Portfolio{
+		Positions: []*Position{
+			{
+				Symbol:        "WIF/USDC",
+				Size:          decimal.NewFromInt(100),
+				EntryPrice:    decimal.NewFromInt(2),
+				CurrentPrice:  decimal.NewFromInt(3),
+				UnrealizedPnL: decimal.NewFromInt(100),
+			},
+			{
+				// 0.01 SOL at 100 USDC/SOL: entry 1, mark 1.5 in USDC
+				Symbol:        "BONK/SOL",
+				Size:          decimal.NewFromInt(400),
+				EntryPrice:    decimal.NewFromFloat(0.01),
+				CurrentPrice:  decimal.NewFromFloat(0.015),
+				UnrealizedPnL: decimal.NewFromInt(2),
+			},
+		},
+		Config: PortfolioConfig{
+			Base:  "USDC",
+			Rates: map[string]decimal.Decimal{"SOL": decimal.NewFromInt(100)},
+		},
+	}
+
+	summary := portfolio.Summary()
+
+	// Exposures are 300 and 600 USDC, so BONK carries twice WIF's weight
+	assert.Equal(t, "USDC", summary.Base)
+	assert.Equal(t, 2, summary.Positions)
+	assert.True(t, decimal.NewFromInt(900).Equal(summary.TotalNotional))
+	assert.True(t, decimal.NewFromInt(400).Div(decimal.NewFromInt(300)).Equal(summary.AvgEntryPrice), summary.AvgEntryPrice.String())
+	assert.True(t, decimal.NewFromInt(2).Equal(summary.AvgMarkPrice), summary.AvgMarkPrice.String())
+	assert.True(t, decimal.NewFromInt(300).Equal(summary.UnrealizedPnL))
+
+	assert.True(t, (&Portfolio{}).Summary().AvgEntryPrice.IsZero())
+}
+
+func TestPortfolio_Delta(t *testing.T) {
+	portfolio := &Portfolio{
+		Positions: []*Position{
+			// Long 100 WIF marked at 3 USDC
+			{Symbol: "WIF/USDC", Size: decimal.NewFromInt(100), EntryPrice: decimal.NewFromInt(2), CurrentPrice: decimal.NewFromInt(3)},
+			// Short 400 BONK at 0.01 SOL, unmarked, with SOL at 100 USDC
+			{Symbol: "BONK/SOL", Size: decimal.NewFromInt(-400), EntryPrice: decimal.NewFromFloat(0.01)},
+		},
+		Config: PortfolioConfig{
+			Base:  "USDC",
+			Rates: map[string]decimal.Decimal{"SOL": decimal.NewFromInt(100)},
+		},
+	}
+
+	summary := portfolio.Summary()
+
+	assert.True(t, decimal.NewFromInt(300).Equal(summary.Deltas["WIF/USDC"]), summary.Deltas["WIF/USDC"].String())
+	assert.True(t, decimal.NewFromInt(-400).Equal(summary.Deltas["BONK/SOL"]), summary.Deltas["BONK/SOL"].String())
+	// The short outweighs the long, so the book is net short 100 USDC
+	assert.True(t, decimal.NewFromInt(-100).Equal(summary.Delta), summary.Delta.String())
+	// while the gross notional counts both sides
+	assert.True(t, decimal.NewFromInt(700).Equal(summary.TotalNotional))
+}
@@ -19,6 +19,10 @@ type Position struct {
 	UpdatedAt     time.Time         `json:"updated_at" bson:"updated_at"`
 	StopLoss      decimal.Decimal   `json:"stop_loss" bson:"stop_loss"`
 	TakeProfit    []decimal.Decimal `json:"take_profit" bson:"take_profit"`
+	// HighWaterMark is the highest price seen while the position was open,
+	// which a trailing stop of TrailingStopPercent follows
+	HighWaterMark       decimal.Decimal `json:"high_water_mark" bson:"high_water_mark"`
+	TrailingStopPercent decimal.Decimal `json:"trailing_stop_percent" bson:"trailing_stop_percent"`
 
 	// Track which profit levels have been taken
 	takenProfits map[string]bool
@@ -56,6 +60,9 @@ func (p *Position) HasTakenProfitAt(level decimal.Decimal) bool {
 func (p *Position) MarkTakenProfitAt(level decimal.Decimal) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	if p.takenProfits == nil {
+		p.takenProfits = make(map[string]bool)
+	}
 	p.takenProfits[level.String()] = true
 }
 
@@ -6,19 +6,49 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// DefaultMaxMarketCap is the market cap above which pump.fun tokens are
+// ignored when no cap is configured
+var DefaultMaxMarketCap = decimal.NewFromInt(30000)
+
 type PumpTradingConfig struct {
 	MaxMarketCap decimal.Decimal `yaml:"max_market_cap"`
 	MinVolume    decimal.Decimal `yaml:"min_volume"`
 	WebSocket    WSConfig        `yaml:"websocket"`
+	WashTrading  WashTradingConfig `yaml:"wash_trading"`
+	// MaxSlippage bounds the expected average fill above the current
+	// bonding curve price, as a fraction. Zero sizes without the curve.
+	MaxSlippage  decimal.Decimal   `yaml:"max_slippage"`
+	// LimitOrderTTL expires parked limit orders that have not triggered
+	// within it. Zero keeps them until cancelled.
+	LimitOrderTTL time.Duration  `yaml:"limit_order_ttl"`
+	// PaperMode simulates fills locally instead of sending orders to the
+	// venue. Paper fills move PaperSlippage against the trade.
+	PaperMode     bool            `yaml:"paper_mode"`
+	PaperSlippage decimal.Decimal `yaml:"paper_slippage"`
 	Risk         struct {
 		MaxPositionSize   decimal.Decimal   `yaml:"max_position_size"`
 		MinPositionSize   decimal.Decimal   `yaml:"min_position_size"`
 		StopLossPercent   decimal.Decimal   `yaml:"stop_loss_percent"`
 		TakeProfitLevels  []decimal.Decimal `yaml:"take_profit_levels"`
 		BatchSizes        []decimal.Decimal `yaml:"batch_sizes"`
+		// MaxExecutionSlippage rejects orders whose expected average fill
+		// along the bonding curve is further than this fraction from the
+		// signal price. It is separate from MaxSlippage, which budgets
+		// sizing. Zero disables the check.
+		MaxExecutionSlippage decimal.Decimal `yaml:"max_execution_slippage"`
 	} `yaml:"risk"`
 }
 
+// WashTradingConfig configures detection of artificially inflated volume
+type WashTradingConfig struct {
+	Enabled         bool    `yaml:"enabled"`
+	SpikeMultiplier float64 `yaml:"spike_multiplier"` // Volume growth ratio between updates treated as a spike
+	MaxPriceMove    float64 `yaml:"max_price_move"`   // Relative price move below which a spike is suspect
+	RepeatCount     int     `yaml:"repeat_count"`     // Consecutive identical volume increments treated as suspect
+	SizeTolerance   float64 `yaml:"size_tolerance"`   // Relative tolerance when comparing increments
+	Discount        float64 `yaml:"discount"`         // Fraction of suspect volume removed (0-1)
+}
+
 type WSConfig struct {
 	ReconnectTimeout time.Duration `yaml:"reconnect_timeout"`
 	PingInterval     time.Duration `yaml:"ping_interval"`
@@ -28,6 +58,9 @@ type WSConfig struct {
 	MaxRetries      int           `yaml:"max_retries"`
 	APIKey          string        `yaml:"api_key"`
 	DialTimeout     time.Duration `yaml:"dial_timeout"`
+	// JitterFraction randomizes that fraction of each reconnect backoff so
+	// clients dropped together do not reconnect together. 1 is full jitter.
+	JitterFraction  float64       `yaml:"jitter_fraction"`
 }
 
 type StopLossConfig struct {
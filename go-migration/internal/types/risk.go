@@ -30,6 +30,47 @@ type RiskConfig struct {
 		Trailing decimal.Decimal `yaml:"trailing"`
 	} `yaml:"stop_loss"`
 	TakeProfitLevels   []ProfitLevel   `yaml:"take_profit_levels"`
+	Kelly              KellyConfig     `yaml:"kelly"`
+	VolatilityStop     VolatilityStopConfig `yaml:"volatility_stop"`
+	ATRTakeProfit      ATRTakeProfitConfig  `yaml:"atr_take_profit"`
+}
+
+// KellyConfig controls Kelly-criterion position sizing
+type KellyConfig struct {
+	Enabled     bool            `yaml:"enabled"`
+	Fraction    decimal.Decimal `yaml:"fraction"`     // Multiplier on the full Kelly bet (e.g. 0.5 = half-Kelly)
+	MaxFraction decimal.Decimal `yaml:"max_fraction"` // Upper clamp on the resulting bet fraction
+	MinTrades   int             `yaml:"min_trades"`   // Closed trades required per symbol before Kelly applies
+}
+
+// VolatilityStopConfig places the stop loss a multiple of the average true
+// range below entry instead of a fixed percentage
+type VolatilityStopConfig struct {
+	Enabled    bool            `yaml:"enabled"`
+	Period     int             `yaml:"period"`     // Number of price moves averaged into the ATR
+	Multiplier decimal.Decimal `yaml:"multiplier"` // Stop distance in multiples of ATR
+}
+
+// ATRTakeProfitConfig places take profit levels at multiples of the average
+// true range above entry instead of fixed price multipliers, so the ladder
+// widens for volatile tokens
+type ATRTakeProfitConfig struct {
+	Enabled bool             `yaml:"enabled"`
+	Period  int              `yaml:"period"` // Number of price moves averaged into the ATR
+	Levels  []ATRProfitLevel `yaml:"levels"`
+}
+
+// ATRProfitLevel sells Percentage of the position once price reaches
+// Multiple ATRs above entry
+type ATRProfitLevel struct {
+	Multiple   decimal.Decimal `yaml:"multiple"`
+	Percentage decimal.Decimal `yaml:"percentage"`
+}
+
+// Target returns the level's take profit price for a position entered at
+// entry while the ATR is atr
+func (l ATRProfitLevel) Target(entry, atr decimal.Decimal) decimal.Decimal {
+	return entry.Add(atr.Mul(l.Multiple))
 }
 
 // Using ProfitLevel from profit_level.go
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// InitialRisk returns the amount put at risk by a position: the distance
+// from entry to the initial stop times the position size
+func InitialRisk(entry, stop, size float64) float64 {
+	return math.Abs(entry-stop) * math.Abs(size)
+}
+
+// RMultiple expresses a trade's PnL in units of its initial risk. It reports
+// false when the trade had no defined risk.
+func RMultiple(pnl, risk float64) (float64, bool) {
+	if risk <= 0 {
+		return 0, false
+	}
+	return pnl / risk, true
+}
+
+// RStats summarizes a set of R-multiples
+type RStats struct {
+	Trades       int            `json:"trades"`
+	Expectancy   float64        `json:"expectancy"`   // Mean R per trade
+	Distribution map[string]int `json:"distribution"` // Trade counts per 1R-wide bucket, keyed by the bucket's lower bound
+}
+
+// NewRStats computes the expectancy and distribution of rs
+func NewRStats(rs []float64) *RStats {
+	stats := &RStats{
+		Trades:       len(rs),
+		Distribution: make(map[string]int),
+	}
+	if len(rs) == 0 {
+		return stats
+	}
+
+	var total float64
+	for _, r := range rs {
+		total += r
+		stats.Distribution[fmt.Sprintf("%+dR", int(math.Floor(r)))]++
+	}
+	stats.Expectancy = total / float64(len(rs))
+	return stats
+}
@@ -0,0 +1,40 @@
+package types
+
+import "github.com/shopspring/decimal"
+
+// ImplementationShortfall returns the execution cost of a fill relative to
+// the decision price: the adverse price move times size, plus fees.
+// Positive values are costs, negative values mean the fill beat the decision.
+func ImplementationShortfall(side OrderSide, decision, fill, size, fee decimal.Decimal) decimal.Decimal {
+	move := fill.Sub(decision)
+	if side == OrderSideSell {
+		move = move.Neg()
+	}
+	return move.Mul(size.Abs()).Add(fee)
+}
+
+// ShortfallStats aggregates implementation shortfall over a set of fills
+type ShortfallStats struct {
+	Trades    int             `json:"trades"`
+	Shortfall decimal.Decimal `json:"shortfall"` // Total execution cost
+	Notional  decimal.Decimal `json:"notional"`  // Total notional at the decision price
+}
+
+// Add accumulates the shortfall of trade at its average fill price and
+// filled size. The trade must carry a decision price.
+func (s *ShortfallStats) Add(trade *Trade) decimal.Decimal {
+	size := trade.Filled()
+	shortfall := ImplementationShortfall(trade.Side, trade.DecisionPrice, trade.FillPrice(), size, trade.Fee)
+	s.Trades++
+	s.Shortfall = s.Shortfall.Add(shortfall)
+	s.Notional = s.Notional.Add(trade.DecisionPrice.Mul(size.Abs()))
+	return shortfall
+}
+
+// Bps returns the aggregate shortfall in basis points of decision notional
+func (s *ShortfallStats) Bps() decimal.Decimal {
+	if s.Notional.IsZero() {
+		return decimal.Zero
+	}
+	return s.Shortfall.Div(s.Notional).Mul(decimal.NewFromInt(10000))
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"time"
 	"github.com/shopspring/decimal"
 )
@@ -23,6 +24,17 @@ type Signal struct {
 	Confidence float64        `json:"confidence"`
 	Indicators []Indicator    `json:"indicators,omitempty"`
 	Size       decimal.Decimal `json:"size"`
+	// Source names the indicator that generated the signal
+	Source     string         `json:"source,omitempty"`
+	// Venue is the executor the engine routed the signal to, which differs
+	// from Provider when a venue exposure cap reroutes it
+	Venue      string         `json:"venue,omitempty"`
+}
+
+// SignalStore persists emitted signals so they can be replayed later
+type SignalStore interface {
+	SaveSignal(ctx context.Context, signal *Signal) error
+	LoadSignals(ctx context.Context, from, to time.Time) ([]*Signal, error)
 }
 
 type TradeStatus string
@@ -18,4 +18,18 @@ type TokenUpdate struct {
 	} `json:"price_change"`
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Populated from token metadata when enrichment is enabled.
+	// Decimals is zero when metadata is unavailable.
+	Decimals  int       `json:"decimals,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// TokenMetadata holds static token attributes not carried by market updates
+type TokenMetadata struct {
+	Symbol      string    `json:"symbol"`
+	Name        string    `json:"name"`
+	Decimals    int       `json:"decimals"`
+	MintAddress string    `json:"mint_address"`
+	CreatedAt   time.Time `json:"created_at"`
 }
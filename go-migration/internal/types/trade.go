@@ -12,12 +12,39 @@ type Trade struct {
 	Symbol     string            `json:"symbol" bson:"symbol"`
 	Side       OrderSide         `json:"side" bson:"side"`
 	Price      decimal.Decimal   `json:"price" bson:"price"`
+	// DecisionPrice is the price when the trade was decided, typically the
+	// signal price, used to measure implementation shortfall
+	DecisionPrice decimal.Decimal `json:"decision_price,omitempty" bson:"decision_price,omitempty"`
 	Size       decimal.Decimal   `json:"size" bson:"size"`
+	// FilledSize and AvgFillPrice are what the venue actually filled, zero
+	// until the trade executes
+	FilledSize   decimal.Decimal `json:"filled_size,omitempty" bson:"filled_size,omitempty"`
+	AvgFillPrice decimal.Decimal `json:"avg_fill_price,omitempty" bson:"avg_fill_price,omitempty"`
+	// Requeued marks the remainder of a partial fill resubmitted for execution
+	Requeued   bool              `json:"requeued,omitempty" bson:"requeued,omitempty"`
 	Quantity   decimal.Decimal   `json:"quantity" bson:"quantity"`
 	Fee        decimal.Decimal   `json:"fee" bson:"fee"`
+	Liquidity  LiquidityRole     `json:"liquidity,omitempty" bson:"liquidity,omitempty"`
 	Provider   string            `json:"provider" bson:"provider"`
 	Status     OrderStatus       `json:"status" bson:"status"`
 	Timestamp  time.Time         `json:"timestamp" bson:"timestamp"`
 	StopLoss   decimal.Decimal   `json:"stop_loss,omitempty" bson:"stop_loss,omitempty"`
 	TakeProfit []decimal.Decimal `json:"take_profit,omitempty" bson:"take_profit,omitempty"`
 }
+
+// Filled returns the executed size, or Size before fill details are known
+func (t *Trade) Filled() decimal.Decimal {
+	if t.FilledSize.IsPositive() {
+		return t.FilledSize
+	}
+	return t.Size
+}
+
+// FillPrice returns the average fill price, or Price before fill details
+// are known
+func (t *Trade) FillPrice() decimal.Decimal {
+	if t.AvgFillPrice.IsPositive() {
+		return t.AvgFillPrice
+	}
+	return t.Price
+}
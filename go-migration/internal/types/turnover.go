@@ -0,0 +1,37 @@
+package types
+
+// TurnoverStats relates how much was traded over a period, and the fees it
+// cost, to the capital employed
+type TurnoverStats struct {
+	Trades    int     `json:"trades"`
+	Notional  float64 `json:"notional"`   // Total traded notional, both sides of each round trip
+	Fees      float64 `json:"fees"`       // Total fees paid, net of rebates
+	AvgEquity float64 `json:"avg_equity"` // Mean equity over the period
+	Turnover  float64 `json:"turnover"`   // Notional / AvgEquity
+	FeeDrag   float64 `json:"fee_drag"`   // Fees / AvgEquity, the return given up to fees
+}
+
+// NewTurnoverStats computes turnover and fee drag from the notional traded
+// and fees paid over a period and equity sampled across it. Both ratios
+// are zero without positive average equity.
+func NewTurnoverStats(trades int, notional, fees float64, equity []float64) *TurnoverStats {
+	stats := &TurnoverStats{
+		Trades:   trades,
+		Notional: notional,
+		Fees:     fees,
+	}
+	if len(equity) == 0 {
+		return stats
+	}
+
+	var total float64
+	for _, value := range equity {
+		total += value
+	}
+	stats.AvgEquity = total / float64(len(equity))
+	if stats.AvgEquity > 0 {
+		stats.Turnover = notional / stats.AvgEquity
+		stats.FeeDrag = fees / stats.AvgEquity
+	}
+	return stats
+}
@@ -0,0 +1,46 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kwanRoshi/B/go-migration/internal/trading"
+	"github.com/kwanRoshi/B/go-migration/internal/trading/storage"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
+)
+
+func TestClient_GetEquityCurveFromService(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.SaveEquityPoint(&types.EquityPoint{
+			Strategy:  "pump",
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Equity:    decimal.NewFromInt(int64(1000 + i)),
+		}))
+	}
+	service := trading.NewService(trading.NewEngine(trading.Config{}, zap.NewNop(), store), zap.NewNop())
+	s := NewServer(Config{}, zap.NewNop(), service, nil)
+	client := &Client{server: s, send: make(chan []byte, 1)}
+
+	request := fmt.Sprintf(`{"type":"get_equity_curve","payload":{"strategy":"pump","from":%q}}`,
+		start.Add(30*time.Minute).Format(time.RFC3339))
+	require.NoError(t, client.handleMessage([]byte(request)))
+
+	var response struct {
+		Type    string              `json:"type"`
+		Payload []types.EquityPoint `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(<-client.send, &response))
+	assert.Equal(t, "equity_curve", response.Type)
+	require.Len(t, response.Payload, 2)
+	assert.Equal(t, "1001", response.Payload[0].Equity.String())
+	assert.Equal(t, "1002", response.Payload[1].Equity.String())
+}
@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// replayEntry is a broadcast message and its sequence number
+type replayEntry struct {
+	seq     uint64
+	message []byte
+}
+
+// broadcastMessage is a message queued on the server's broadcast channel,
+// delivered through Broadcast
+type broadcastMessage struct {
+	symbol  string
+	msgType string
+	payload interface{}
+}
+
+// replayBuffer is a fixed-size ring of the most recent broadcasts for one
+// symbol
+type replayBuffer struct {
+	seq     uint64
+	entries []replayEntry
+	next    int
+	full    bool
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{entries: make([]replayEntry, size)}
+}
+
+// add records message under the next sequence number and returns it. With
+// no capacity the message is sequenced but not kept.
+func (b *replayBuffer) add(message func(seq uint64) ([]byte, error)) ([]byte, error) {
+	data, err := message(b.seq + 1)
+	if err != nil {
+		return nil, err
+	}
+	b.seq++
+	if len(b.entries) == 0 {
+		return data, nil
+	}
+
+	b.entries[b.next] = replayEntry{seq: b.seq, message: data}
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	return data, nil
+}
+
+// since returns the buffered messages after seq, oldest first
+func (b *replayBuffer) since(seq uint64) [][]byte {
+	start, count := 0, b.next
+	if b.full {
+		start, count = b.next, len(b.entries)
+	}
+
+	var messages [][]byte
+	for i := 0; i < count; i++ {
+		entry := b.entries[(start+i)%len(b.entries)]
+		if entry.seq > seq {
+			messages = append(messages, entry.message)
+		}
+	}
+	return messages
+}
+
+// Broadcast sends a message about symbol to every client subscribed to it.
+// Messages carry a per-symbol sequence number, and the last
+// Config.ReplayBuffer of them are kept so reconnecting clients can catch up
+// by subscribing with the last sequence number they saw.
+func (s *Server) Broadcast(symbol, msgType string, payload interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buffer, ok := s.replay[symbol]
+	if !ok {
+		buffer = newReplayBuffer(s.config.ReplayBuffer)
+		s.replay[symbol] = buffer
+	}
+	data, err := buffer.add(func(seq uint64) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{
+			"type":    msgType,
+			"symbol":  symbol,
+			"seq":     seq,
+			"payload": payload,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal broadcast: %w", err)
+	}
+
+	for client := range s.clients {
+		if !client.symbols[symbol] {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			s.logger.Warn("Client send buffer full")
+		}
+	}
+	return nil
+}
+
+// subscribe adds symbol to the client's broadcasts and replays the buffered
+// messages after since. Both happen under the server lock so no broadcast
+// is missed or delivered twice in between.
+func (c *Client) subscribe(symbol string, since uint64) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.symbols[symbol] = true
+	buffer, ok := s.replay[symbol]
+	if !ok {
+		return
+	}
+	for _, message := range buffer.since(since) {
+		select {
+		case c.send <- message:
+		default:
+			s.logger.Warn("Client send buffer full, replay truncated")
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type sequenced struct {
+	Type    string `json:"type"`
+	Symbol  string `json:"symbol"`
+	Seq     uint64 `json:"seq"`
+	Payload int    `json:"payload"`
+}
+
+// readSequenced reads n messages, splitting frames the write pump batched
+func readSequenced(t *testing.T, conn *websocket.Conn, n int) []sequenced {
+	var messages []sequenced
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(messages) < n {
+		_, frame, err := conn.ReadMessage()
+		require.NoError(t, err)
+		for _, line := range bytes.Split(frame, []byte{'\n'}) {
+			var msg sequenced
+			require.NoError(t, json.Unmarshal(line, &msg))
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func seqs(messages []sequenced) []uint64 {
+	out := make([]uint64, len(messages))
+	for i, msg := range messages {
+		out[i] = msg.Seq
+	}
+	return out
+}
+
+func TestServer_ReplaysMissedBroadcasts(t *testing.T) {
+	s := NewServer(Config{
+		PingInterval:   time.Minute,
+		PongWait:       time.Minute,
+		WriteWait:      time.Second,
+		MaxMessageSize: 1024,
+		ReplayBuffer:   3,
+	}, zap.NewNop(), nil, nil)
+	go s.run()
+
+	server := httptest.NewServer(http.HandlerFunc(s.handleWebSocket))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?user_id=alice"
+
+	// Broadcasts sent while the client is away, the first of which falls
+	// out of the three message buffer
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, s.Broadcast("PEPE", "market_update", i))
+	}
+	require.NoError(t, s.Broadcast("BONK", "market_update", 0))
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The client last saw sequence 2 before disconnecting
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{
+		"type":    "subscribe",
+		"payload": map[string]interface{}{"symbol": "PEPE", "since": 2},
+	}))
+	replayed := readSequenced(t, conn, 2)
+	assert.Equal(t, []uint64{3, 4}, seqs(replayed))
+	assert.Equal(t, 3, replayed[0].Payload)
+
+	// Live broadcasts, including those queued on the broadcast channel,
+	// continue the sequence
+	s.broadcast <- broadcastMessage{symbol: "PEPE", msgType: "market_update", payload: 5}
+	live := readSequenced(t, conn, 1)
+	assert.Equal(t, []uint64{5}, seqs(live))
+	assert.Equal(t, "PEPE", live[0].Symbol)
+}
+
+func TestReplayBuffer_Disabled(t *testing.T) {
+	b := newReplayBuffer(0)
+	for i := 0; i < 2; i++ {
+		_, err := b.add(func(seq uint64) ([]byte, error) { return []byte("m"), nil })
+		require.NoError(t, err)
+	}
+	assert.Equal(t, uint64(2), b.seq)
+	assert.Empty(t, b.since(0))
+}
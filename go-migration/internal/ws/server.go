@@ -14,6 +14,7 @@ import (
 
 	"github.com/kwanRoshi/B/go-migration/internal/trading/interfaces"
 	"github.com/kwanRoshi/B/go-migration/internal/market"
+	"github.com/kwanRoshi/B/go-migration/internal/types"
 )
 
 type Config struct {
@@ -22,6 +23,9 @@ type Config struct {
 	PongWait       time.Duration `yaml:"pong_wait"`
 	WriteWait      time.Duration `yaml:"write_wait"`
 	MaxMessageSize int64         `yaml:"max_message_size"`
+	// ReplayBuffer is how many recent broadcasts are kept per symbol for
+	// replay to reconnecting clients. Zero disables replay.
+	ReplayBuffer int `yaml:"replay_buffer"`
 }
 
 type Server struct {
@@ -33,15 +37,17 @@ type Server struct {
 	clients    map[*Client]bool
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan []byte
+	broadcast  chan broadcastMessage
+	replay     map[string]*replayBuffer
 	mu         sync.RWMutex
 }
 
 type Client struct {
-	server *Server
-	conn   *websocket.Conn
-	send   chan []byte
-	userID string
+	server  *Server
+	conn    *websocket.Conn
+	send    chan []byte
+	userID  string
+	symbols map[string]bool // Symbols the client receives broadcasts for
 }
 
 func NewServer(config Config, logger *zap.Logger, engine interfaces.TradingEngine, market *market.Handler) *Server {
@@ -60,7 +66,8 @@ func NewServer(config Config, logger *zap.Logger, engine interfaces.TradingEngin
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
-		broadcast:  make(chan []byte),
+		broadcast:  make(chan broadcastMessage),
+		replay:     make(map[string]*replayBuffer),
 	}
 }
 
@@ -94,16 +101,11 @@ func (s *Server) run() {
 			s.logger.Info("Client disconnected", zap.String("user_id", client.userID))
 
 		case message := <-s.broadcast:
-			s.mu.RLock()
-			for client := range s.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(s.clients, client)
-				}
+			if err := s.Broadcast(message.symbol, message.msgType, message.payload); err != nil {
+				s.logger.Error("Failed to broadcast message",
+					zap.String("symbol", message.symbol),
+					zap.Error(err))
 			}
-			s.mu.RUnlock()
 		}
 	}
 }
@@ -122,10 +124,11 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &Client{
-		server: s,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		userID: userID,
+		server:  s,
+		conn:    conn,
+		send:    make(chan []byte, 256),
+		userID:  userID,
+		symbols: make(map[string]bool),
 	}
 
 	client.server.register <- client
@@ -286,6 +289,55 @@ func (c *Client) handleMessage(message []byte) error {
 			}
 		}()
 
+	case "subscribe":
+		var req struct {
+			Symbol string `json:"symbol"`
+			Since  uint64 `json:"since"` // Last sequence number seen, zero for everything buffered
+		}
+		if err := json.Unmarshal(msg.Payload, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+		c.subscribe(req.Symbol, req.Since)
+
+	case "get_equity_curve":
+		var req struct {
+			Strategy string    `json:"strategy"`
+			From     time.Time `json:"from"`
+			To       time.Time `json:"to"`
+		}
+		if err := json.Unmarshal(msg.Payload, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		engine, ok := c.server.engine.(interface {
+			GetEquityCurve(strategy string, from, to time.Time) ([]*types.EquityPoint, error)
+		})
+		if !ok {
+			return fmt.Errorf("equity curve not supported by trading engine")
+		}
+		if req.To.IsZero() {
+			req.To = time.Now()
+		}
+
+		points, err := engine.GetEquityCurve(req.Strategy, req.From, req.To)
+		if err != nil {
+			return fmt.Errorf("failed to load equity curve: %w", err)
+		}
+
+		data, err := json.Marshal(map[string]interface{}{
+			"type":    "equity_curve",
+			"payload": points,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal equity curve: %w", err)
+		}
+
+		select {
+		case c.send <- data:
+		default:
+			c.server.logger.Warn("Client send buffer full")
+		}
+
 	default:
 		return fmt.Errorf("unknown message type: %s", msg.Type)
 	}
@@ -19,14 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TradingService_PlaceOrder_FullMethodName         = "/trading.TradingService/PlaceOrder"
-	TradingService_CancelOrder_FullMethodName        = "/trading.TradingService/CancelOrder"
-	TradingService_GetOrder_FullMethodName           = "/trading.TradingService/GetOrder"
-	TradingService_GetOrders_FullMethodName          = "/trading.TradingService/GetOrders"
-	TradingService_ExecuteTrade_FullMethodName       = "/trading.TradingService/ExecuteTrade"
-	TradingService_GetPosition_FullMethodName        = "/trading.TradingService/GetPosition"
-	TradingService_GetPositions_FullMethodName       = "/trading.TradingService/GetPositions"
-	TradingService_SubscribeOrderBook_FullMethodName = "/trading.TradingService/SubscribeOrderBook"
+	TradingService_PlaceOrder_FullMethodName          = "/trading.TradingService/PlaceOrder"
+	TradingService_CancelOrder_FullMethodName         = "/trading.TradingService/CancelOrder"
+	TradingService_GetOrder_FullMethodName            = "/trading.TradingService/GetOrder"
+	TradingService_GetOrders_FullMethodName           = "/trading.TradingService/GetOrders"
+	TradingService_ExecuteTrade_FullMethodName        = "/trading.TradingService/ExecuteTrade"
+	TradingService_GetPosition_FullMethodName         = "/trading.TradingService/GetPosition"
+	TradingService_GetPositions_FullMethodName        = "/trading.TradingService/GetPositions"
+	TradingService_SubscribeOrderBook_FullMethodName  = "/trading.TradingService/SubscribeOrderBook"
+	TradingService_ReplaceOrder_FullMethodName        = "/trading.TradingService/ReplaceOrder"
+	TradingService_GetPortfolioSummary_FullMethodName = "/trading.TradingService/GetPortfolioSummary"
 )
 
 // TradingServiceClient is the client API for TradingService service.
@@ -41,6 +43,8 @@ type TradingServiceClient interface {
 	GetPosition(ctx context.Context, in *GetPositionRequest, opts ...grpc.CallOption) (*Position, error)
 	GetPositions(ctx context.Context, in *GetPositionsRequest, opts ...grpc.CallOption) (*PositionList, error)
 	SubscribeOrderBook(ctx context.Context, in *SubscribeOrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error)
+	ReplaceOrder(ctx context.Context, in *ReplaceOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	GetPortfolioSummary(ctx context.Context, in *GetPortfolioSummaryRequest, opts ...grpc.CallOption) (*PortfolioSummary, error)
 }
 
 type tradingServiceClient struct {
@@ -140,6 +144,26 @@ func (c *tradingServiceClient) SubscribeOrderBook(ctx context.Context, in *Subsc
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type TradingService_SubscribeOrderBookClient = grpc.ServerStreamingClient[OrderBook]
 
+func (c *tradingServiceClient) ReplaceOrder(ctx context.Context, in *ReplaceOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, TradingService_ReplaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradingServiceClient) GetPortfolioSummary(ctx context.Context, in *GetPortfolioSummaryRequest, opts ...grpc.CallOption) (*PortfolioSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PortfolioSummary)
+	err := c.cc.Invoke(ctx, TradingService_GetPortfolioSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TradingServiceServer is the server API for TradingService service.
 // All implementations must embed UnimplementedTradingServiceServer
 // for forward compatibility.
@@ -152,6 +176,8 @@ type TradingServiceServer interface {
 	GetPosition(context.Context, *GetPositionRequest) (*Position, error)
 	GetPositions(context.Context, *GetPositionsRequest) (*PositionList, error)
 	SubscribeOrderBook(*SubscribeOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error
+	ReplaceOrder(context.Context, *ReplaceOrderRequest) (*OrderResponse, error)
+	GetPortfolioSummary(context.Context, *GetPortfolioSummaryRequest) (*PortfolioSummary, error)
 	mustEmbedUnimplementedTradingServiceServer()
 }
 
@@ -186,6 +212,12 @@ func (UnimplementedTradingServiceServer) GetPositions(context.Context, *GetPosit
 func (UnimplementedTradingServiceServer) SubscribeOrderBook(*SubscribeOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeOrderBook not implemented")
 }
+func (UnimplementedTradingServiceServer) ReplaceOrder(context.Context, *ReplaceOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceOrder not implemented")
+}
+func (UnimplementedTradingServiceServer) GetPortfolioSummary(context.Context, *GetPortfolioSummaryRequest) (*PortfolioSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortfolioSummary not implemented")
+}
 func (UnimplementedTradingServiceServer) mustEmbedUnimplementedTradingServiceServer() {}
 func (UnimplementedTradingServiceServer) testEmbeddedByValue()                        {}
 
@@ -344,6 +376,42 @@ func _TradingService_SubscribeOrderBook_Handler(srv interface{}, stream grpc.Ser
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type TradingService_SubscribeOrderBookServer = grpc.ServerStreamingServer[OrderBook]
 
+func _TradingService_ReplaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).ReplaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_ReplaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).ReplaceOrder(ctx, req.(*ReplaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TradingService_GetPortfolioSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPortfolioSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TradingServiceServer).GetPortfolioSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TradingService_GetPortfolioSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TradingServiceServer).GetPortfolioSummary(ctx, req.(*GetPortfolioSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TradingService_ServiceDesc is the grpc.ServiceDesc for TradingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -379,6 +447,14 @@ var TradingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPositions",
 			Handler:    _TradingService_GetPositions_Handler,
 		},
+		{
+			MethodName: "ReplaceOrder",
+			Handler:    _TradingService_ReplaceOrder_Handler,
+		},
+		{
+			MethodName: "GetPortfolioSummary",
+			Handler:    _TradingService_GetPortfolioSummary_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{